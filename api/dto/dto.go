@@ -3,4 +3,90 @@ package dto
 type PostDTO struct {
 	Title   string `json:"title" validate:"required"`
 	Content string `json:"content" validate:"required"`
+	// Tags - comma separated post tags
+	Tags   string `json:"tags" validate:"max=500"`
+	Author string `json:"author" validate:"max=100"`
+	// Category - ID of the category the post is filed under, empty for none
+	Category string `json:"category" validate:"omitempty,mongodb"`
+	// HeroImage - key of an uploaded image, empty for none
+	HeroImage string `json:"hero_image" form:"hero_image" validate:"max=100"`
+	// AuthorEmail - address told about new comments, MAIL_NOTIFY addresses are told when empty
+	AuthorEmail      string `json:"author_email" form:"author_email" validate:"omitempty,email,max=254"`
+	CommentsDisabled bool   `json:"comments_disabled" form:"comments_disabled"`
+}
+
+type CategoryDTO struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=500"`
+	// Parent - ID of the enclosing category, empty for a top level one
+	Parent string `json:"parent" validate:"omitempty,mongodb"`
+}
+
+type SubscribeDTO struct {
+	Email  string `json:"email" validate:"required,email,max=254"`
+	Source string `json:"source" validate:"max=32"`
+	// Frequency - digest frequency, weekly when empty
+	Frequency string `json:"frequency" validate:"omitempty,oneof=daily weekly"`
+	// Timezone - IANA time zone reported by the browser, unknown names fall back to UTC
+	Timezone string `json:"timezone" validate:"max=64"`
+}
+
+type IssueDTO struct {
+	Name string `json:"name" validate:"required,max=200"`
+}
+
+type IssueDetailsDTO struct {
+	Name  string `json:"name" validate:"required,max=200"`
+	Intro string `json:"intro" validate:"max=10000"`
+	// Source, SubscribedAfter and SubscribedBefore define the segment receiving the issue
+	Source           string `json:"source" validate:"max=32"`
+	SubscribedAfter  string `json:"subscribed_after" form:"subscribed_after" validate:"omitempty,datetime=2006-01-02"`
+	SubscribedBefore string `json:"subscribed_before" form:"subscribed_before" validate:"omitempty,datetime=2006-01-02"`
+	// Topics - comma separated tags, ActiveWithinDays - days since the last subscriber activity
+	Topics           string `json:"topics" validate:"max=500"`
+	ActiveWithinDays int    `json:"active_within_days" form:"active_within_days" validate:"min=0,max=3650"`
+}
+
+type PreferencesDTO struct {
+	Frequency string `json:"frequency" validate:"required,oneof=none daily weekly"`
+	Hour      int    `json:"hour" validate:"min=0,max=23"`
+	// Topics - followed tags, empty to follow every topic
+	Topics []string `json:"topics" validate:"max=100,dive,max=50"`
+	// Categories - IDs of the followed categories, their subcategories included
+	Categories []string `json:"categories" validate:"max=100,dive,mongodb"`
+	Timezone   string   `json:"timezone" validate:"max=64"`
+	// TrackingOptOut - never track opens and clicks of the emails
+	TrackingOptOut bool `json:"tracking_opt_out" form:"tracking_opt_out"`
+}
+
+type IssuePostDTO struct {
+	PostID string `json:"post_id" form:"post_id" validate:"required,mongodb"`
+}
+
+type TestSendDTO struct {
+	Email string `json:"email" validate:"required,email,max=254"`
+}
+
+type ScheduleIssueDTO struct {
+	// ScheduledAt - value of a datetime-local input, read in Timezone
+	ScheduledAt string `json:"scheduled_at" form:"scheduled_at" validate:"omitempty,datetime=2006-01-02T15:04"`
+	Timezone    string `json:"timezone" validate:"max=64"`
+	SendNow     bool   `json:"send_now" form:"send_now"`
+}
+
+type WebhookDTO struct {
+	URL         string `json:"url" validate:"required,http_url,max=2000"`
+	Description string `json:"description" validate:"max=200"`
+	// Events - events the endpoint is notified about, every event when empty
+	Events []string `json:"events" validate:"max=10,dive,oneof=post.created post.updated post.published post.deleted"`
+	// Active - ignored when creating, new webhooks are enabled
+	Active bool `json:"active" form:"active"`
+}
+
+type CommentDTO struct {
+	Author  string `json:"author" validate:"required,max=100"`
+	Email   string `json:"email" validate:"omitempty,email,max=254"`
+	Content string `json:"content" validate:"required,max=5000"`
+	// Parent - ID of the comment replied to, empty for comments on the post itself
+	Parent string `json:"parent" validate:"omitempty,mongodb"`
 }
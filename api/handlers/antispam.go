@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+	"newsteller/internal/antispam"
+	"newsteller/internal/templates"
+	"slices"
+)
+
+// Antispam serves the challenges of protected forms shown on cached pages.
+type Antispam struct {
+	guard *antispam.Guard
+}
+
+func NewAntispam(guard *antispam.Guard) *Antispam {
+	return &Antispam{guard: guard}
+}
+
+// GET /antispam/challenge/:form, the anti-spam fields loaded into the form
+func (a *Antispam) GetChallenge(c *fiber.Ctx) error {
+	form := c.Params("form")
+	if !slices.Contains(antispam.Forms, form) {
+		return fiber.NewError(fiber.StatusNotFound, "form not found")
+	}
+
+	// every challenge is accepted once
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return sendHTML(c, templates.NewAntispamFields(a.guard.Challenge(form)))
+}
+
+// checkForm checks the anti-spam fields of the submitted form. It returns the message
+// shown for rejected submissions, empty for accepted ones, and the challenge replacing
+// the used or unusable challenge of the form, empty when the form keeps its challenge.
+func checkForm(c *fiber.Ctx, guard *antispam.Guard, form string) (string, antispam.Challenge) {
+	err := guard.Check(c.UserContext(), form, antispam.Submission{
+		IP:        c.IP(),
+		Honeypot:  c.FormValue(antispam.HoneypotField),
+		Challenge: c.FormValue(antispam.ChallengeField),
+		Proof:     c.FormValue(antispam.ProofField),
+	})
+	if err == nil {
+		return "", guard.Challenge(form)
+	}
+	zap.L().Info("rejected form submission", zap.String("form", form), zap.String("ip", c.IP()), zap.Error(err))
+
+	switch {
+	case errors.Is(err, antispam.ErrRateLimited):
+		return "You have sent this form too often. Please try again later.", antispam.Challenge{}
+	case errors.Is(err, antispam.ErrTooFast):
+		return "That was quick! Please wait a moment and send the form again.", antispam.Challenge{}
+	case errors.Is(err, antispam.ErrInvalidProof):
+		return "Please wait until the form is ready and send it again.", antispam.Challenge{}
+	case errors.Is(err, antispam.ErrExpired), errors.Is(err, antispam.ErrReplayed), errors.Is(err, antispam.ErrInvalidChallenge):
+		return "The form has expired. Please send it again.", guard.Challenge(form)
+	default:
+		return "Your submission could not be verified. Please reload the page and try again.", antispam.Challenge{}
+	}
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"newsteller/internal/assets"
+	"time"
+)
+
+type Asset struct{}
+
+func NewAsset() *Asset {
+	return &Asset{}
+}
+
+// Get sends the embedded asset. Hashed file names never change their content, so they are
+// cached for good, plain names are revalidated with the ETag.
+func (a *Asset) Get(c *fiber.Ctx) error {
+	asset, hashed, ok := assets.Lookup(c.Params("file"))
+	if !ok {
+		return fiber.NewError(fiber.StatusNotFound, "asset not found")
+	}
+
+	etag := `"` + asset.Integrity + `"`
+	c.Set(fiber.HeaderETag, etag)
+	if hashed {
+		c.Set(fiber.HeaderCacheControl, "public, max-age=31536000, immutable")
+	} else {
+		c.Set(fiber.HeaderCacheControl, "public, no-cache")
+	}
+	if notModified(c, etag, time.Time{}) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, asset.ContentType)
+	return c.Send(asset.Data)
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+	"mime"
+	"net/http"
+	"newsteller/internal/attachments"
+	"newsteller/internal/config"
+	"newsteller/internal/templates"
+	"strings"
+)
+
+// recentAttachments - number of attachments listed in the attachments panel
+const recentAttachments = 50
+
+// Attachment uploads and serves the files of posts.
+type Attachment struct {
+	attachments *attachments.Attachments
+}
+
+func NewAttachment(cfg *config.Config, c *mongo.Collection, storage attachments.Storage) *Attachment {
+	return &Attachment{attachments: attachments.New(cfg, c, storage)}
+}
+
+// POST /attachments
+func (a *Attachment) Upload(c *fiber.Ctx) error {
+	header, err := c.FormFile("file")
+	if err != nil {
+		return a.sendMessage(c, fiber.StatusBadRequest, "Please choose a file to upload.")
+	}
+	file, err := header.Open()
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	defer file.Close()
+
+	attachment, err := a.attachments.Upload(c.Context(), header.Filename, file)
+	switch {
+	case errors.Is(err, attachments.ErrEmpty):
+		return a.sendMessage(c, fiber.StatusBadRequest, "The file is empty.")
+	case errors.Is(err, attachments.ErrTooLarge):
+		return a.sendMessage(
+			c,
+			fiber.StatusRequestEntityTooLarge,
+			fmt.Sprintf("Files must be at most %s.", templates.FileSize(a.attachments.MaxSize())),
+		)
+	case errors.Is(err, attachments.ErrUnsupportedType):
+		return a.sendMessage(c, fiber.StatusUnsupportedMediaType, "This type of file is not accepted.")
+	case err != nil:
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Status(fiber.StatusCreated)
+	return sendHTML(c, templates.NewAttachmentItem(attachment))
+}
+
+// GET /attachments, the list of the attachments panel
+func (a *Attachment) List(c *fiber.Ctx) error {
+	recent, err := a.attachments.Recent(c.Context(), recentAttachments)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return sendHTML(c, templates.NewAttachmentList(recent))
+}
+
+// GET /attachments/:key
+func (a *Attachment) Get(c *fiber.Ctx) error {
+	attachment, err := a.attachments.Find(c.Context(), c.Params("key"))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return fiber.NewError(fiber.StatusNotFound, "attachment not found")
+	}
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	// the key is derived from the content, the response never changes
+	etag := `"` + attachment.Hash + `"`
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderLastModified, attachment.CreatedAt.UTC().Format(http.TimeFormat))
+	c.Set(fiber.HeaderCacheControl, "public, max-age=31536000, immutable")
+	if notModified(c, etag, attachment.CreatedAt) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	reader, err := a.attachments.Open(c.Context(), attachment)
+	if errors.Is(err, attachments.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "attachment not found")
+	}
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Set(fiber.HeaderContentType, attachment.ContentType)
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	c.Set(fiber.HeaderContentDisposition, contentDisposition(attachment.ContentType, attachment.Name))
+	return c.SendStream(reader, int(attachment.Size))
+}
+
+// DELETE /attachments/:key, the item is removed from the panel
+func (a *Attachment) Delete(c *fiber.Ctx) error {
+	attachment, err := a.attachments.Find(c.Context(), c.Params("key"))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return fiber.NewError(fiber.StatusNotFound, "attachment not found")
+	}
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	err = a.attachments.Delete(c.Context(), attachment)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.SendString("")
+}
+
+func (a *Attachment) sendMessage(c *fiber.Ctx, status int, text string) error {
+	c.Status(status)
+	return sendHTML(c, templates.NewAttachmentMessage(templates.MessageError, text))
+}
+
+// contentDisposition shows images and documents in the browser and downloads other files
+// under their uploaded name.
+func contentDisposition(contentType, name string) string {
+	disposition := "attachment"
+	if strings.HasPrefix(contentType, "image/") || contentType == "application/pdf" {
+		disposition = "inline"
+	}
+
+	if header := mime.FormatMediaType(disposition, map[string]string{"filename": name}); header != "" {
+		return header
+	}
+
+	return disposition
+}
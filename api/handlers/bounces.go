@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/internal/bounces"
+	"newsteller/internal/config"
+	"newsteller/internal/models"
+	"strings"
+)
+
+type Bounces struct {
+	cfg       *config.Config
+	processor *bounces.Processor
+}
+
+func NewBounces(cfg *config.Config, bouncesCollection *mongo.Collection, subscribers *mongo.Collection) *Bounces {
+	return &Bounces{
+		cfg:       cfg,
+		processor: bounces.NewProcessor(cfg, bouncesCollection, subscribers),
+	}
+}
+
+// POST /bounces
+// The body is a raw DSN or ARF message, e.g. forwarded by the mail provider. The webhook
+// is authenticated with the configured secret passed as the token query or a bearer token.
+func (b *Bounces) Receive(c *fiber.Ctx) error {
+	secret := b.cfg.Mail.Bounces.Secret
+	if secret == "" {
+		return fiber.ErrNotFound
+	}
+
+	token := c.Query("token")
+	if bearer, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer "); ok {
+		token = bearer
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+		return fiber.NewError(fiber.StatusUnauthorized, "invalid bounce webhook token")
+	}
+
+	count, err := b.processor.Process(c.Context(), models.BounceSourceWebhook, bytes.NewReader(c.Body()))
+	if errors.Is(err, bounces.ErrNotReport) {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{"recorded": count})
+}
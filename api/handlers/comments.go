@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/api/dto"
+	"newsteller/internal/antispam"
+	"newsteller/internal/comments"
+	"newsteller/internal/config"
+	"newsteller/internal/models"
+	"newsteller/internal/repositories"
+	"newsteller/internal/templates"
+	"slices"
+	"strings"
+)
+
+const (
+	// commentQueueLimit - comments per page of the moderation queue
+	commentQueueLimit     = 20
+	invalidCommentMessage = "Please enter your name and a comment of at most 5000 characters, and a valid email or none."
+)
+
+type Comment struct {
+	cfg      *config.Config
+	posts    *repositories.Post
+	comments *comments.Comments
+	guard    *antispam.Guard
+}
+
+func NewComment(
+	cfg *config.Config,
+	posts *mongo.Collection,
+	comments *comments.Comments,
+	guard *antispam.Guard,
+) *Comment {
+	return &Comment{
+		cfg:      cfg,
+		posts:    repositories.NewPostRepository(posts),
+		comments: comments,
+		guard:    guard,
+	}
+}
+
+// GET /posts/:id/comments, the comment section loaded into the post page
+func (cm *Comment) GetSection(c *fiber.Ctx) error {
+	post, err := cm.findPost(c)
+	if err != nil {
+		return err
+	}
+
+	thread, err := cm.comments.Thread(c.Context(), post)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	challenge := antispam.Challenge{}
+	if cm.comments.Open(post) {
+		challenge = cm.guard.Challenge(antispam.FormComment)
+	}
+
+	// every visitor gets a fresh challenge
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return sendHTML(c, templates.NewCommentSection(post, thread, cm.comments.Open(post), challenge))
+}
+
+// POST /posts/:id/comments
+func (cm *Comment) Create(c *fiber.Ctx) error {
+	post, err := cm.findPost(c)
+	if err != nil {
+		return err
+	}
+
+	var commentDTO dto.CommentDTO
+	err = c.BodyParser(&commentDTO)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	commentDTO.Author = strings.TrimSpace(commentDTO.Author)
+	commentDTO.Email = strings.TrimSpace(commentDTO.Email)
+	commentDTO.Content = strings.TrimSpace(commentDTO.Content)
+	err = validator.New(validator.WithRequiredStructEnabled()).Struct(commentDTO)
+	if err != nil {
+		return sendHTML(c, templates.NewCommentMessage(templates.MessageError, invalidCommentMessage))
+	}
+	message, challenge := checkForm(c, cm.guard, antispam.FormComment)
+	respond := func(kind templates.MessageKind, text string) error {
+		return sendHTML(c, templates.NewCommentMessage(kind, text).WithChallenge(challenge))
+	}
+	if message != "" {
+		return respond(templates.MessageError, message)
+	}
+
+	comment := &models.Comment{
+		Author:  commentDTO.Author,
+		Email:   commentDTO.Email,
+		Content: commentDTO.Content,
+	}
+	err = cm.comments.Submit(c.Context(), post, comment, commentDTO.Parent)
+	if errors.Is(err, comments.ErrClosed) {
+		return respond(templates.MessageError, "Comments are closed.")
+	}
+	if errors.Is(err, comments.ErrInvalidParent) {
+		return respond(templates.MessageError, "The comment you replied to is no longer shown.")
+	}
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	if comment.Status != models.CommentApproved {
+		// comments filed as spam are answered alike, their authors are not told
+		return respond(templates.MessageSuccess, "Thanks! Your comment is shown once it is approved.")
+	}
+	// the section reloads with the new comment
+	c.Set("HX-Trigger", "commentsChanged")
+	return c.SendStatus(fiber.StatusCreated)
+}
+
+// GET /comments/moderation?status=pending&page=1, the queue loaded into the post moderation page
+func (cm *Comment) GetQueue(c *fiber.Ctx) error {
+	status := models.CommentStatus(c.Query("status", string(models.CommentPending)))
+	if !slices.Contains(models.CommentStatuses, status) {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "unknown comment status")
+	}
+	page := max(c.QueryInt("page", 1), 1)
+
+	queue, err := cm.comments.Queue(c.Context(), status, page, commentQueueLimit)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return sendHTML(c, templates.NewCommentQueue(
+		queue.Status,
+		queue.Comments,
+		queue.Posts,
+		queue.Counts,
+		page,
+		commentQueueLimit,
+		queue.Total,
+	))
+}
+
+// POST /comments/:id/approve
+func (cm *Comment) Approve(c *fiber.Ctx) error {
+	return cm.moderate(c, models.CommentApproved)
+}
+
+// POST /comments/:id/spam
+func (cm *Comment) MarkSpam(c *fiber.Ctx) error {
+	return cm.moderate(c, models.CommentSpam)
+}
+
+func (cm *Comment) moderate(c *fiber.Ctx, status models.CommentStatus) error {
+	err := cm.comments.Moderate(c.Context(), c.Params("id"), status)
+	if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+		return fiber.NewError(fiber.StatusNotFound, "comment not found")
+	}
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Set("HX-Trigger", "commentsModerated")
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// DELETE /comments/:id
+func (cm *Comment) Delete(c *fiber.Ctx) error {
+	err := cm.comments.Delete(c.Context(), c.Params("id"))
+	if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+		return fiber.NewError(fiber.StatusNotFound, "comment not found")
+	}
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Set("HX-Trigger", "commentsModerated")
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (cm *Comment) findPost(c *fiber.Ctx) (*models.Post, error) {
+	post, err := cm.posts.FindByID(c.Context(), c.Params("id"))
+	if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+		return nil, fiber.NewError(fiber.StatusNotFound, "post not found")
+	}
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return post, nil
+}
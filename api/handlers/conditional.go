@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"newsteller/internal/cache"
+	"strings"
+	"time"
+)
+
+// sendConditional sends the cached page with validators, answering requests of clients
+// holding the same version with 304 Not Modified.
+func sendConditional(c *fiber.Ctx, page cache.Page) error {
+	sum := sha256.Sum256([]byte(page.Body))
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Set(fiber.HeaderETag, etag)
+	if !page.ModifiedAt.IsZero() {
+		c.Set(fiber.HeaderLastModified, page.ModifiedAt.UTC().Format(http.TimeFormat))
+	}
+	// clients may keep the response but must revalidate it
+	c.Set(fiber.HeaderCacheControl, "public, no-cache")
+	if notModified(c, etag, page.ModifiedAt) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, page.ContentType)
+	return c.SendString(page.Body)
+}
+
+// notModified evaluates the conditional request headers, If-None-Match takes precedence
+// over If-Modified-Since (RFC 9110, section 13.2.2).
+func notModified(c *fiber.Ctx, etag string, modifiedAt time.Time) bool {
+	if noneMatch := c.Get(fiber.HeaderIfNoneMatch); noneMatch != "" {
+		for _, candidate := range strings.Split(noneMatch, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+
+	since, err := http.ParseTime(c.Get(fiber.HeaderIfModifiedSince))
+	if err != nil || modifiedAt.IsZero() {
+		return false
+	}
+
+	// HTTP dates have no fractions of seconds
+	return !modifiedAt.Truncate(time.Second).After(since)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/internal/config"
+	"newsteller/internal/models"
+	"newsteller/internal/newsletter"
+	"newsteller/internal/repositories"
+	"newsteller/internal/templates"
+	"newsteller/internal/tokens"
+	"strings"
+	"time"
+)
+
+type Digest struct {
+	cfg      *config.Config
+	posts    *repositories.Post
+	composer *newsletter.Composer
+}
+
+func NewDigest(cfg *config.Config, posts *mongo.Collection, signer *tokens.Signer) *Digest {
+	return &Digest{
+		cfg:      cfg,
+		posts:    repositories.NewPostRepository(posts),
+		composer: newsletter.NewComposer(cfg, repositories.NewPostRepository(posts), newsletter.NewLinks(cfg, signer)),
+	}
+}
+
+// GET /digests/preview
+func (d *Digest) GetPreviewPage(c *fiber.Ctx) error {
+	frequency := models.DigestFrequency(c.Query("frequency", string(models.DigestWeekly)))
+	if frequency != models.DigestDaily && frequency != models.DigestWeekly {
+		return fiber.NewError(fiber.StatusBadRequest, "frequency must be daily or weekly")
+	}
+
+	topics, err := d.posts.Tags(c.Context())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	// preview subscriber, its unsubscribe link does not match anybody
+	subscriber := &models.Subscriber{
+		ID:              primitive.NilObjectID,
+		DigestFrequency: frequency,
+		Topics:          models.ParseTags(c.Query("topic")),
+	}
+
+	now := time.Now()
+	since := now.Add(-newsletter.PeriodLength(frequency))
+	posts, err := d.composer.Posts(c.Context(), since, now)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	// the preview subscriber follows no categories
+	posts = d.composer.DigestPosts(subscriber, posts, nil)
+
+	var email *templates.Email
+	if len(posts) > 0 {
+		email, err = d.composer.Compose(subscriber, posts)
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+	}
+
+	html, err := templates.
+		NewDigestPreview(frequency, since, email, len(posts), strings.Join(subscriber.Topics, ", "), topics).
+		GeneratePage()
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.SendString(html)
+}
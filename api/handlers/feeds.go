@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+	"net/url"
+	"newsteller/internal/cache"
+	"newsteller/internal/config"
+	"newsteller/internal/feeds"
+	"newsteller/internal/models"
+	"newsteller/internal/repositories"
+)
+
+type Feed struct {
+	cfg     *config.Config
+	posts   *repositories.Post
+	builder *feeds.Builder
+	cache   *cache.PagesCache
+}
+
+func NewFeed(cfg *config.Config, posts *mongo.Collection, cache *cache.PagesCache) *Feed {
+	return &Feed{
+		cfg:     cfg,
+		posts:   repositories.NewPostRepository(posts),
+		builder: feeds.NewBuilder(cfg),
+		cache:   cache,
+	}
+}
+
+// GET /feed.xml, /tags/:tag/feed.xml, /authors/:author/feed.xml
+func (f *Feed) GetRSS(c *fiber.Ctx) error {
+	return f.serve(c, feeds.RSS)
+}
+
+// GET /atom.xml, /tags/:tag/atom.xml, /authors/:author/atom.xml
+func (f *Feed) GetAtom(c *fiber.Ctx) error {
+	return f.serve(c, feeds.Atom)
+}
+
+// GET /feed.json, /tags/:tag/feed.json, /authors/:author/feed.json
+func (f *Feed) GetJSON(c *fiber.Ctx) error {
+	return f.serve(c, feeds.JSON)
+}
+
+// serve renders the feed once per post change and answers conditional requests with
+// 304 Not Modified, so aggregators polling the feeds cost nearly nothing.
+func (f *Feed) serve(c *fiber.Ctx, format feeds.Format) error {
+	key := cache.FeedPrefix + c.Path()
+	page, ok := f.cache.Get(key)
+	if !ok {
+		var err error
+		page, err = f.render(c, format)
+		if err != nil {
+			return err
+		}
+		f.cache.Set(key, page)
+	}
+
+	return sendConditional(c, page)
+}
+
+func (f *Feed) render(c *fiber.Ctx, format feeds.Format) (cache.Page, error) {
+	tag, err := url.PathUnescape(c.Params("tag"))
+	if err != nil {
+		return cache.Page{}, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	author, err := url.PathUnescape(c.Params("author"))
+	if err != nil {
+		return cache.Page{}, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if tags := models.ParseTags(tag); len(tags) > 0 {
+		tag = tags[0]
+	}
+
+	posts, err := f.posts.Recent(c.Context(), tag, author, f.cfg.Feeds.Items)
+	if err != nil {
+		return cache.Page{}, fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	title, description := f.cfg.SiteName, f.cfg.SiteDescription
+	switch {
+	case tag != "":
+		// unknown tags and authors are not cached, so they cannot fill the cache
+		if len(posts) == 0 {
+			return cache.Page{}, fiber.NewError(fiber.StatusNotFound, "no posts tagged "+tag)
+		}
+		title, description = f.cfg.SiteName+": "+tag, "Latest posts tagged "+tag
+	case author != "":
+		if len(posts) == 0 {
+			return cache.Page{}, fiber.NewError(fiber.StatusNotFound, "no posts by "+author)
+		}
+		title, description = f.cfg.SiteName+": "+author, "Latest posts by "+author
+	}
+
+	feed := f.builder.Build(title, description, c.Path(), posts)
+	body, err := format.Render(feed)
+	if err != nil {
+		return cache.Page{}, fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return cache.Page{
+		Body:        string(body),
+		ContentType: format.ContentType,
+		ModifiedAt:  feed.Updated,
+	}, nil
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+	"net/http"
+	"newsteller/internal/attachments"
+	"newsteller/internal/config"
+	"newsteller/internal/images"
+	"strconv"
+	"strings"
+)
+
+// Image serves the resized variants of uploaded images.
+type Image struct {
+	attachments *attachments.Attachments
+	variants    *images.Variants
+}
+
+func NewImage(cfg *config.Config, c *mongo.Collection, storage attachments.Storage) *Image {
+	files := attachments.New(cfg, c, storage)
+
+	return &Image{
+		attachments: files,
+		variants:    images.NewVariants(cfg, files),
+	}
+}
+
+// GET /images/:key/:width
+func (i *Image) GetVariant(c *fiber.Ctx) error {
+	key := c.Params("key")
+	width, err := strconv.Atoi(c.Params("width"))
+	if err != nil || !images.Resizable(key) {
+		return fiber.NewError(fiber.StatusNotFound, "image not found")
+	}
+	attachment, err := i.attachments.Find(c.Context(), key)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return fiber.NewError(fiber.StatusNotFound, "image not found")
+	}
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	webp := i.variants.WebP() && strings.Contains(c.Get(fiber.HeaderAccept), "image/webp")
+	format := images.Format(attachment.ContentType, webp)
+
+	// variants of an attachment never change, the format depends on the Accept header
+	etag := `"` + attachment.Hash + "-" + strconv.Itoa(width) + "." + format + `"`
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderLastModified, attachment.CreatedAt.UTC().Format(http.TimeFormat))
+	c.Set(fiber.HeaderCacheControl, "public, max-age=31536000, immutable")
+	if i.variants.WebP() {
+		c.Vary(fiber.HeaderAccept)
+	}
+	if notModified(c, etag, attachment.CreatedAt) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	data, err := i.variants.Variant(c.Context(), attachment, width, format)
+	if errors.Is(err, images.ErrUnsupportedWidth) || errors.Is(err, attachments.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "image not found")
+	}
+	if errors.Is(err, images.ErrTooLarge) {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Set(fiber.HeaderContentType, images.ContentType(format))
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	return c.Send(data)
+}
@@ -0,0 +1,440 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/api/dto"
+	"newsteller/internal/config"
+	"newsteller/internal/mailer"
+	"newsteller/internal/models"
+	"newsteller/internal/newsletter"
+	"newsteller/internal/repositories"
+	"newsteller/internal/templates"
+	"newsteller/internal/tokens"
+	"regexp"
+	"time"
+)
+
+type Issue struct {
+	cfg         *config.Config
+	repo        *repositories.Issue
+	subscribers *repositories.Subscriber
+	posts       *repositories.Post
+	composer    *newsletter.Composer
+	mailer      *mailer.Mailer
+}
+
+func NewIssue(
+	cfg *config.Config,
+	issues *mongo.Collection,
+	subscribers *mongo.Collection,
+	posts *mongo.Collection,
+	signer *tokens.Signer,
+	mailer *mailer.Mailer,
+) *Issue {
+	postRepo := repositories.NewPostRepository(posts)
+
+	return &Issue{
+		cfg:         cfg,
+		repo:        repositories.NewIssueRepository(issues),
+		subscribers: repositories.NewSubscriberRepository(subscribers),
+		posts:       postRepo,
+		composer:    newsletter.NewComposer(cfg, postRepo, newsletter.NewLinks(cfg, signer)),
+		mailer:      mailer,
+	}
+}
+
+// GET /issues
+func (i *Issue) GetIssuesPage(c *fiber.Ctx) error {
+	issues, err := i.repo.All(c.Context())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return sendHTML(c, templates.NewIssues(issues))
+}
+
+// POST /issues
+func (i *Issue) Create(c *fiber.Ctx) error {
+	var issueDTO dto.IssueDTO
+	err := c.BodyParser(&issueDTO)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	err = validate.Struct(issueDTO)
+	if err != nil {
+		return i.sendMessage(c, templates.MessageError, "Please enter a name of up to 200 characters.")
+	}
+
+	id, err := i.repo.Create(c.Context(), &models.Issue{
+		Name:      issueDTO.Name,
+		PostIDs:   []primitive.ObjectID{},
+		Status:    models.IssueDraft,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Set("HX-Redirect", "/issues/"+id.Hex()+"/edit")
+	return c.SendStatus(fiber.StatusCreated)
+}
+
+// GET /issues/:id/edit
+func (i *Issue) GetEditorPage(c *fiber.Ctx) error {
+	issue, err := i.findIssue(c)
+	if err != nil {
+		return err
+	}
+
+	posts, err := i.composer.IssuePosts(c.Context(), issue)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	recipients, err := i.subscribers.CountBySegment(c.Context(), issue.Segment)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return sendHTML(c, templates.NewIssueEditor(issue, posts, recipients))
+}
+
+// PUT /issues/:id
+func (i *Issue) Update(c *fiber.Ctx) error {
+	issue, err := i.findDraft(c)
+	if err != nil {
+		return err
+	}
+
+	var detailsDTO dto.IssueDetailsDTO
+	err = c.BodyParser(&detailsDTO)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	err = validate.Struct(detailsDTO)
+	if err != nil {
+		return i.sendMessage(c, templates.MessageError, "Please check the name, intro and recipient fields.")
+	}
+
+	issue.Name = detailsDTO.Name
+	issue.Intro = detailsDTO.Intro
+	issue.Segment = models.Segment{
+		Source:           detailsDTO.Source,
+		Topics:           models.ParseTags(detailsDTO.Topics),
+		ActiveWithinDays: detailsDTO.ActiveWithinDays,
+	}
+	// dates were validated above
+	if detailsDTO.SubscribedAfter != "" {
+		issue.Segment.SubscribedAfter, _ = time.Parse(time.DateOnly, detailsDTO.SubscribedAfter)
+	}
+	if detailsDTO.SubscribedBefore != "" {
+		issue.Segment.SubscribedBefore, _ = time.Parse(time.DateOnly, detailsDTO.SubscribedBefore)
+	}
+
+	err = i.repo.UpdateDraft(c.Context(), issue)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	recipients, err := i.subscribers.CountBySegment(c.Context(), issue.Segment)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return i.sendMessage(c, templates.MessageSuccess, fmt.Sprintf("Saved. The issue will reach %d subscribers.", recipients))
+}
+
+// DELETE /issues/:id
+func (i *Issue) Delete(c *fiber.Ctx) error {
+	issue, err := i.findDraft(c)
+	if err != nil {
+		return err
+	}
+
+	err = i.repo.DeleteDraft(c.Context(), issue.ID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Set("HX-Redirect", "/issues")
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GET /issues/:id/picker
+func (i *Issue) GetPostPicker(c *fiber.Ctx) error {
+	issue, err := i.findIssue(c)
+	if err != nil {
+		return err
+	}
+
+	posts, _, err := i.posts.FindPaginated(c.Context(), &repositories.PaginatedSearchQuery{
+		Page:    1,
+		Limit:   i.cfg.PostsPerPage,
+		Keyword: regexp.QuoteMeta(c.Query("keyword")),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return sendHTML(c, templates.NewIssuePostPicker(issue, posts))
+}
+
+// POST /issues/:id/posts
+func (i *Issue) AddPost(c *fiber.Ctx) error {
+	var postDTO dto.IssuePostDTO
+	err := c.BodyParser(&postDTO)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	err = validate.Struct(postDTO)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+	postID, _ := primitive.ObjectIDFromHex(postDTO.PostID)
+
+	_, err = i.posts.FindByID(c.Context(), postDTO.PostID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "post not found")
+	}
+
+	return i.changePosts(c, func(issue *models.Issue) {
+		issue.AddPost(postID)
+	})
+}
+
+// DELETE /issues/:id/posts/:postID
+func (i *Issue) RemovePost(c *fiber.Ctx) error {
+	postID, err := primitive.ObjectIDFromHex(c.Params("postID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid post id")
+	}
+
+	return i.changePosts(c, func(issue *models.Issue) {
+		issue.RemovePost(postID)
+	})
+}
+
+// POST /issues/:id/posts/:postID/move?direction=up|down
+func (i *Issue) MovePost(c *fiber.Ctx) error {
+	postID, err := primitive.ObjectIDFromHex(c.Params("postID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid post id")
+	}
+
+	var offset int
+	switch c.Query("direction") {
+	case "up":
+		offset = -1
+	case "down":
+		offset = 1
+	default:
+		return fiber.NewError(fiber.StatusBadRequest, "direction must be up or down")
+	}
+
+	return i.changePosts(c, func(issue *models.Issue) {
+		issue.MovePost(postID, offset)
+	})
+}
+
+// POST /issues/:id/test
+func (i *Issue) TestSend(c *fiber.Ctx) error {
+	issue, err := i.findIssue(c)
+	if err != nil {
+		return err
+	}
+
+	var testDTO dto.TestSendDTO
+	err = c.BodyParser(&testDTO)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	err = validate.Struct(testDTO)
+	if err != nil {
+		return i.sendMessage(c, templates.MessageError, "Please enter a valid email address.")
+	}
+
+	posts, err := i.composer.IssuePosts(c.Context(), issue)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	email, err := i.composer.ComposeIssue(issue, posts, nil)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	err = i.mailer.Enqueue(c.Context(), &mailer.Message{
+		To:      repositories.NormalizeEmail(testDTO.Email),
+		Subject: "[Test] " + email.Subject,
+		HTML:    email.HTML,
+		Text:    email.Text,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return i.sendMessage(c, templates.MessageSuccess, "Test email is on its way to "+testDTO.Email+".")
+}
+
+// POST /issues/:id/schedule
+func (i *Issue) Schedule(c *fiber.Ctx) error {
+	issue, err := i.findDraft(c)
+	if err != nil {
+		return err
+	}
+
+	var scheduleDTO dto.ScheduleIssueDTO
+	err = c.BodyParser(&scheduleDTO)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	err = validate.Struct(scheduleDTO)
+	if err != nil {
+		return i.sendMessage(c, templates.MessageError, "Please pick a valid date and time.")
+	}
+	if len(issue.PostIDs) == 0 {
+		return i.sendMessage(c, templates.MessageError, "Add at least one post before sending the issue.")
+	}
+
+	at := time.Now()
+	if !scheduleDTO.SendNow {
+		if scheduleDTO.ScheduledAt == "" {
+			return i.sendMessage(c, templates.MessageError, "Pick a date and time or send the issue now.")
+		}
+
+		location, err := time.LoadLocation(scheduleDTO.Timezone)
+		if err != nil {
+			location = time.UTC
+		}
+		at, _ = time.ParseInLocation("2006-01-02T15:04", scheduleDTO.ScheduledAt, location)
+		if at.Before(time.Now()) {
+			return i.sendMessage(c, templates.MessageError, "Pick a time in the future or send the issue now.")
+		}
+	}
+
+	err = i.repo.Schedule(c.Context(), issue.ID, at)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Set("HX-Redirect", "/issues/"+issue.ID.Hex()+"/edit")
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// POST /issues/:id/unschedule
+func (i *Issue) Unschedule(c *fiber.Ctx) error {
+	issue, err := i.findIssue(c)
+	if err != nil {
+		return err
+	}
+
+	err = i.repo.Unschedule(c.Context(), issue.ID)
+	if err != nil {
+		return i.sendMessage(c, templates.MessageError, "The issue is already being sent.")
+	}
+
+	c.Set("HX-Redirect", "/issues/"+issue.ID.Hex()+"/edit")
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// GET /newsletter
+func (i *Issue) GetArchivePage(c *fiber.Ctx) error {
+	issues, err := i.repo.FindSent(c.Context())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return sendHTML(c, templates.NewIssueArchive(issues))
+}
+
+// GET /newsletter/:id
+func (i *Issue) GetArchivedIssue(c *fiber.Ctx) error {
+	issue, err := i.findIssue(c)
+	if err != nil {
+		return err
+	}
+	if issue.Status != models.IssueSent {
+		return fiber.NewError(fiber.StatusNotFound, "issue not found")
+	}
+
+	posts, err := i.composer.IssuePosts(c.Context(), issue)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return sendHTML(c, templates.NewArchivedIssue(issue, posts))
+}
+
+// changePosts applies the change to the posts of a draft and renders the updated list.
+func (i *Issue) changePosts(c *fiber.Ctx, change func(issue *models.Issue)) error {
+	issue, err := i.findDraft(c)
+	if err != nil {
+		return err
+	}
+
+	change(issue)
+	err = i.repo.UpdateDraft(c.Context(), issue)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	posts, err := i.composer.IssuePosts(c.Context(), issue)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return sendHTML(c, templates.NewIssuePosts(issue, posts))
+}
+
+func (i *Issue) findIssue(c *fiber.Ctx) (*models.Issue, error) {
+	issue, err := i.repo.FindByID(c.Context(), c.Params("id"))
+	if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+		return nil, fiber.NewError(fiber.StatusNotFound, "issue not found")
+	}
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return issue, nil
+}
+
+// findDraft returns the issue, failing with 409 Conflict when it is not editable anymore.
+func (i *Issue) findDraft(c *fiber.Ctx) (*models.Issue, error) {
+	issue, err := i.findIssue(c)
+	if err != nil {
+		return nil, err
+	}
+	if issue.Status != models.IssueDraft {
+		return nil, fiber.NewError(fiber.StatusConflict, "only drafts can be changed")
+	}
+
+	return issue, nil
+}
+
+func (i *Issue) sendMessage(c *fiber.Ctx, kind templates.MessageKind, text string) error {
+	return sendHTML(c, templates.NewIssueMessage(kind, text))
+}
+
+func sendHTML(c *fiber.Ctx, page templates.Template) error {
+	html, err := page.GeneratePage()
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.SendString(html)
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/internal/config"
+	"newsteller/internal/ogimage"
+	"newsteller/internal/repositories"
+	"time"
+)
+
+type OGImage struct {
+	cfg       *config.Config
+	posts     *repositories.Post
+	generator *ogimage.Generator
+}
+
+func NewOGImage(cfg *config.Config, posts *mongo.Collection, store ogimage.Store) *OGImage {
+	return &OGImage{
+		cfg:       cfg,
+		posts:     repositories.NewPostRepository(posts),
+		generator: ogimage.NewGenerator(cfg, store),
+	}
+}
+
+// GET /posts/:id/og.png
+func (o *OGImage) GetImage(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if !o.cfg.OGImages.Enabled || !primitive.IsValidObjectID(id) {
+		return fiber.NewError(fiber.StatusNotFound, "image not found")
+	}
+
+	post, err := o.posts.FindByID(c.Context(), id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return fiber.NewError(fiber.StatusNotFound, "image not found")
+	}
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	data, version, err := o.generator.Image(c.Context(), post)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	etag := `"` + version + `"`
+	c.Set(fiber.HeaderETag, etag)
+	if c.Query("v") == version {
+		// versioned URLs of the OpenGraph tags never change their image
+		c.Set(fiber.HeaderCacheControl, "public, max-age=31536000, immutable")
+	} else {
+		c.Set(fiber.HeaderCacheControl, "public, no-cache")
+	}
+	if notModified(c, etag, time.Time{}) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, "image/png")
+	return c.Send(data)
+}
@@ -1,42 +1,63 @@
 package handlers
 
 import (
+	"errors"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.uber.org/zap"
 	"newsteller/internal/cache"
 	"newsteller/internal/config"
+	"newsteller/internal/images"
 	"newsteller/internal/models"
+	"newsteller/internal/ogimage"
 	"newsteller/internal/repositories"
-	"newsteller/internal/state"
+	"newsteller/internal/taxonomy"
 	"newsteller/internal/templates"
 )
 
+// tagCloudSize - number of tags in the tag cloud of the home page
+const tagCloudSize = 30
+
 type Page struct {
-	cfg   *config.Config
-	state state.State[models.Post]
-	cache *cache.PagesCache
+	cfg        *config.Config
+	posts      *repositories.Post
+	tags       *repositories.Tag
+	categories *repositories.Category
+	cache      *cache.PagesCache
 }
 
-func NewPage(cfg *config.Config, c *mongo.Collection, cache *cache.PagesCache) *Page {
+func NewPage(
+	cfg *config.Config,
+	c *mongo.Collection,
+	tags *mongo.Collection,
+	categories *mongo.Collection,
+	cache *cache.PagesCache,
+) *Page {
 	return &Page{
-		cfg:   cfg,
-		state: state.NewPostState(c),
-		cache: cache,
+		cfg:        cfg,
+		posts:      repositories.NewPostRepository(c),
+		tags:       repositories.NewTagRepository(tags),
+		categories: repositories.NewCategoryRepository(categories),
+		cache:      cache,
 	}
 }
 
 // GET /home
 func (p *Page) GetHomePage(c *fiber.Ctx) error {
-	res, _, err := p.state.FindPaginated(c.Context(), &repositories.PaginatedSearchQuery{
+	res, _, err := p.posts.FindPaginated(c.Context(), &repositories.PaginatedSearchQuery{
 		Page:  1,
 		Limit: p.cfg.PostsPerPage,
 	})
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
-	html, err := templates.NewMain(res).GeneratePage()
+	tags, err := p.posts.TagCloud(c.Context(), tagCloudSize)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	html, err := templates.NewMain(res, tags).GeneratePage()
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
@@ -52,7 +73,7 @@ func (p *Page) FindPaginated(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	res, total, err := p.state.FindPaginated(c.Context(), query)
+	res, total, err := p.posts.FindPaginated(c.Context(), query)
 	if err != nil {
 		zap.L().Error("could not get all posts", zap.Error(err))
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
@@ -81,7 +102,7 @@ func (p *Page) FindPostsList(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	res, total, err := p.state.FindPaginated(c.Context(), query)
+	res, total, err := p.posts.FindPaginated(c.Context(), query)
 	if err != nil {
 		zap.L().Error("could not get all posts", zap.Error(err))
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
@@ -103,16 +124,38 @@ func (p *Page) FindPostsList(c *fiber.Ctx) error {
 	return c.SendString(html)
 }
 
-// GET posts/:id
+// GET posts/:id, the ID may also be the current or a former slug of the post
 func (p *Page) FindPostByID(c *fiber.Ctx) error {
 	id := c.Params("id")
 	zap.L().Info("Getting post", zap.String("id", id))
-	post, err := p.state.FindByID(c.Context(), id)
+
+	var post *models.Post
+	var err error
+	if primitive.IsValidObjectID(id) {
+		post, err = p.posts.FindByID(c.Context(), id)
+	} else {
+		post, err = p.posts.FindBySlug(c.Context(), id)
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return fiber.NewError(fiber.StatusNotFound, "post not found")
+	}
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
+	if !primitive.IsValidObjectID(id) && id != post.Slug {
+		// former slugs keep working for links published before the title changed
+		return c.Redirect(post.Path(), fiber.StatusMovedPermanently)
+	}
 
-	html, err := templates.RenderSinglePost(post)
+	var image string
+	switch {
+	case post.HeroImage != "":
+		// the hero image previews the post better than a generated card
+		image = p.cfg.BaseURL + images.Path(post.HeroImage, images.OpenGraphWidth)
+	case p.cfg.OGImages.Enabled:
+		image = ogimage.URL(p.cfg, post)
+	}
+	html, err := templates.RenderSinglePost(post, site(p.cfg), image)
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
@@ -123,7 +166,11 @@ func (p *Page) FindPostByID(c *fiber.Ctx) error {
 
 // GET /posts/create
 func (p *Page) GetCreatePage(c *fiber.Ctx) error {
-	html, err := templates.NewCreatePage().GeneratePage()
+	tags, categories, err := p.findTaxonomy(c)
+	if err != nil {
+		return err
+	}
+	html, err := templates.NewCreatePage(tags, categories).GeneratePage()
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
@@ -139,7 +186,7 @@ func (p *Page) GetModerationPage(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	posts, total, err := p.state.FindPaginated(c.Context(), query)
+	posts, total, err := p.posts.FindPaginated(c.Context(), query)
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
@@ -160,13 +207,17 @@ func (p *Page) GetModerationPage(c *fiber.Ctx) error {
 // GET /posts/:id/edit
 func (p *Page) GetEditPage(c *fiber.Ctx) error {
 	id := c.Params("id")
-	post, err := p.state.FindByID(c.Context(), id)
+	post, err := p.posts.FindByID(c.Context(), id)
 	if err != nil {
 		return fiber.NewError(fiber.StatusNotFound, "post with provided id does not exist")
 	}
+	tags, categories, err := p.findTaxonomy(c)
+	if err != nil {
+		return err
+	}
 
 	html, err := templates.
-		NewEdit(post).
+		NewEdit(post, tags, categories).
 		GeneratePage()
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
@@ -176,7 +227,25 @@ func (p *Page) GetEditPage(c *fiber.Ctx) error {
 	return c.SendString(html)
 }
 
+// findTaxonomy returns the tags and the category tree offered by the post forms.
+func (p *Page) findTaxonomy(c *fiber.Ctx) ([]models.Tag, []taxonomy.Node, error) {
+	tags, err := p.tags.All(c.Context())
+	if err != nil {
+		return nil, nil, fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	categories, err := p.categories.All(c.Context())
+	if err != nil {
+		return nil, nil, fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return tags, taxonomy.Tree(categories), nil
+}
+
 func (p *Page) validatePaginationQuery(c *fiber.Ctx) (*repositories.PaginatedSearchQuery, error) {
+	return validatePaginationQuery(c, p.cfg.PostsPerPage)
+}
+
+func validatePaginationQuery(c *fiber.Ctx, defaultLimit int) (*repositories.PaginatedSearchQuery, error) {
 	var query repositories.PaginatedSearchQuery
 	err := c.QueryParser(&query)
 	if err != nil {
@@ -186,7 +255,7 @@ func (p *Page) validatePaginationQuery(c *fiber.Ctx) (*repositories.PaginatedSea
 		query.Page = 1
 	}
 	if query.Limit < 1 {
-		query.Limit = p.cfg.PostsPerPage
+		query.Limit = defaultLimit
 	}
 
 	validate := validator.New(validator.WithRequiredStructEnabled())
@@ -197,3 +266,17 @@ func (p *Page) validatePaginationQuery(c *fiber.Ctx) (*repositories.PaginatedSea
 
 	return &query, nil
 }
+
+// site returns the link preview defaults of the configuration.
+func site(cfg *config.Config) templates.Site {
+	return templates.Site{
+		Name:              cfg.SiteName,
+		BaseURL:           cfg.BaseURL,
+		Image:             cfg.SEO.Image,
+		Logo:              cfg.SEO.Logo,
+		TwitterSite:       cfg.SEO.TwitterSite,
+		Locale:            cfg.SEO.Locale,
+		DefaultAuthor:     cfg.SEO.DefaultAuthor,
+		DescriptionLength: cfg.SEO.DescriptionLength,
+	}
+}
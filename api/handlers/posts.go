@@ -1,29 +1,35 @@
 package handlers
 
 import (
+	"errors"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"newsteller/api/dto"
-	"newsteller/internal/cache"
 	"newsteller/internal/config"
+	"newsteller/internal/events"
+	"newsteller/internal/images"
 	"newsteller/internal/models"
+	"newsteller/internal/repositories"
 	"newsteller/internal/state"
+	"strings"
 	"time"
 )
 
+// Post handles writes of posts, the state publishes their events, which keep the
+// cache, the search index, webhooks and notifications in sync.
 type Post struct {
-	cfg   *config.Config
-	state state.State[models.Post]
-	cache *cache.PagesCache
+	cfg        *config.Config
+	state      state.State[models.Post]
+	categories *repositories.Category
 }
 
-func NewPost(cfg *config.Config, c *mongo.Collection, cache *cache.PagesCache) *Post {
+func NewPost(cfg *config.Config, c *mongo.Collection, categories *mongo.Collection, outbox *events.Outbox) *Post {
 	return &Post{
-		cfg:   cfg,
-		state: state.NewPostState(c),
-		cache: cache,
+		cfg:        cfg,
+		state:      state.NewPostStateWithEvents(c, outbox),
+		categories: repositories.NewCategoryRepository(categories),
 	}
 }
 
@@ -39,17 +45,31 @@ func (p *Post) Create(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
 	}
+	categoryID, err := p.findCategory(c, createPostDTO.Category)
+	if err != nil {
+		return err
+	}
+	heroImage, err := parseHeroImage(createPostDTO.HeroImage)
+	if err != nil {
+		return err
+	}
 
-	err = p.state.Insert(c.Context(), &models.Post{
-		Title:     createPostDTO.Title,
-		Content:   createPostDTO.Content,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	})
+	post := &models.Post{
+		Title:            createPostDTO.Title,
+		Content:          createPostDTO.Content,
+		Tags:             models.ParseTags(createPostDTO.Tags),
+		CategoryID:       categoryID,
+		Author:           strings.TrimSpace(createPostDTO.Author),
+		HeroImage:        heroImage,
+		AuthorEmail:      strings.TrimSpace(createPostDTO.AuthorEmail),
+		CommentsDisabled: createPostDTO.CommentsDisabled,
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
+	}
+	err = p.state.Insert(c.Context(), post)
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
-	p.cache.Invalidate(cache.PostsUpdated)
 
 	return c.SendStatus(fiber.StatusCreated)
 }
@@ -61,10 +81,12 @@ func (p *Post) Delete(c *fiber.Ctx) error {
 	}
 
 	err := p.state.Delete(c.Context(), id)
+	if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+		return fiber.NewError(fiber.StatusNotFound, "post not found")
+	}
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
-	p.cache.Invalidate(cache.PostsUpdated)
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
@@ -85,21 +107,66 @@ func (p *Post) Update(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
 	}
+	categoryID, err := p.findCategory(c, createPostDTO.Category)
+	if err != nil {
+		return err
+	}
+	heroImage, err := parseHeroImage(createPostDTO.HeroImage)
+	if err != nil {
+		return err
+	}
 
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
-	err = p.state.Update(c.Context(), &models.Post{
-		ID:        objectID,
-		Title:     createPostDTO.Title,
-		Content:   createPostDTO.Content,
-		UpdatedAt: time.Now(),
-	})
+	post := &models.Post{
+		ID:               objectID,
+		Title:            createPostDTO.Title,
+		Content:          createPostDTO.Content,
+		Tags:             models.ParseTags(createPostDTO.Tags),
+		CategoryID:       categoryID,
+		Author:           strings.TrimSpace(createPostDTO.Author),
+		HeroImage:        heroImage,
+		AuthorEmail:      strings.TrimSpace(createPostDTO.AuthorEmail),
+		CommentsDisabled: createPostDTO.CommentsDisabled,
+		UpdatedAt:        time.Now(),
+	}
+	err = p.state.Update(c.Context(), post)
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
-	p.cache.Invalidate(cache.PostsUpdated)
 
 	return c.SendStatus(fiber.StatusOK)
 }
+
+// findCategory returns the ID of the category picked for a post, zero when none was picked.
+func (p *Post) findCategory(c *fiber.Ctx, id string) (primitive.ObjectID, error) {
+	if id == "" {
+		return primitive.NilObjectID, nil
+	}
+
+	category, err := p.categories.FindByID(c.Context(), id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return primitive.NilObjectID, fiber.NewError(fiber.StatusUnprocessableEntity, "category does not exist")
+	}
+	if err != nil {
+		return primitive.NilObjectID, fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return category.ID, nil
+}
+
+// parseHeroImage returns the attachment key of the hero image, given as the key or the
+// path of an uploaded image.
+func parseHeroImage(input string) (string, error) {
+	key := strings.TrimPrefix(strings.TrimSpace(input), "/attachments/")
+	if key == "" {
+		return "", nil
+	}
+	if strings.ContainsAny(key, "/?#") || !images.Resizable(key) {
+		return "", fiber.NewError(fiber.StatusUnprocessableEntity, "hero image must be an uploaded JPEG, PNG or WebP image")
+	}
+
+	return key, nil
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+	"newsteller/internal/config"
+	"newsteller/internal/ratelimit"
+	"newsteller/internal/templates"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rateLimitGroup limits the requests it matches per client, requests without a client
+// key are not limited by the group.
+type rateLimitGroup struct {
+	rule   ratelimit.Rule
+	match  func(c *fiber.Ctx) bool
+	client func(c *fiber.Ctx) string
+}
+
+// RateLimit limits the requests of clients per route group.
+type RateLimit struct {
+	limiter *ratelimit.Limiter
+	groups  []rateLimitGroup
+}
+
+func NewRateLimit(cfg *config.Config, store ratelimit.Store) *RateLimit {
+	limits := cfg.RateLimit
+	return &RateLimit{
+		limiter: ratelimit.NewLimiter(store),
+		groups: []rateLimitGroup{
+			{
+				rule:   ratelimit.Rule{Name: "all", Requests: limits.Requests, Window: limits.Window},
+				match:  func(*fiber.Ctx) bool { return true },
+				client: clientIP,
+			},
+			{
+				rule:   ratelimit.Rule{Name: "write", Requests: limits.WriteRequests, Window: limits.WriteWindow},
+				match:  isWrite,
+				client: clientIP,
+			},
+			{
+				rule:   ratelimit.Rule{Name: "search", Requests: limits.SearchRequests, Window: limits.SearchWindow},
+				match:  isSearch,
+				client: clientIP,
+			},
+			{
+				// limits of the addresses still apply, clients can not escape them with made-up tokens
+				rule:   ratelimit.Rule{Name: "token", Requests: limits.TokenRequests, Window: limits.TokenWindow},
+				match:  func(*fiber.Ctx) bool { return true },
+				client: clientToken,
+			},
+		},
+	}
+}
+
+// Limit counts the request against the groups matching it and rejects it once a client
+// exceeds a limit. The RateLimit headers describe the group closest to its limit.
+func (r *RateLimit) Limit(c *fiber.Ctx) error {
+	var closest *ratelimit.Result
+	var closestRule ratelimit.Rule
+	for _, group := range r.groups {
+		if !group.match(c) {
+			continue
+		}
+		client := group.client(c)
+		if client == "" {
+			continue
+		}
+
+		result, err := r.limiter.Take(c.UserContext(), group.rule, client)
+		if err != nil {
+			// the site stays up when the store is down
+			zap.L().Error("failed to count request", zap.String("group", group.rule.Name), zap.Error(err))
+			continue
+		}
+		if result.Limit == 0 {
+			continue
+		}
+		if closest == nil || !result.Allowed || result.Remaining < closest.Remaining {
+			closest, closestRule = &result, group.rule
+		}
+		if !result.Allowed {
+			break
+		}
+	}
+	if closest == nil {
+		return c.Next()
+	}
+
+	reset := seconds(closest.Reset)
+	c.Set("RateLimit-Limit", strconv.Itoa(closest.Limit))
+	c.Set("RateLimit-Remaining", strconv.Itoa(closest.Remaining))
+	c.Set("RateLimit-Reset", reset)
+	c.Set("RateLimit-Policy", strconv.Itoa(closestRule.Requests)+";w="+seconds(closestRule.Window))
+	if closest.Allowed {
+		return c.Next()
+	}
+
+	c.Set(fiber.HeaderRetryAfter, reset)
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	page := templates.NewTooManyRequests(closest.Reset)
+	if c.Get("HX-Request") == "true" {
+		page.AsFragment()
+	}
+	html, err := page.GeneratePage()
+	if err != nil {
+		return fiber.NewError(fiber.StatusTooManyRequests, err.Error())
+	}
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+
+	return c.Status(fiber.StatusTooManyRequests).SendString(html)
+}
+
+func clientIP(c *fiber.Ctx) string {
+	return "ip:" + c.IP()
+}
+
+// clientToken returns the hash of the bearer token or token query of the request, tokens
+// are not kept in the store.
+func clientToken(c *fiber.Ctx) string {
+	token := c.Query("token")
+	if bearer, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer "); ok {
+		token = bearer
+	}
+	if token == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(token))
+
+	return "token:" + hex.EncodeToString(sum[:16])
+}
+
+func isWrite(c *fiber.Ctx) bool {
+	switch c.Method() {
+	case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// isSearch matches the search routes and the post list filtered by the search box, its
+// keyword runs the most expensive query of the site.
+func isSearch(c *fiber.Ctx) bool {
+	path := c.Path()
+	if path == "/posts" && c.Method() == fiber.MethodGet {
+		return strings.TrimSpace(c.Query("keyword")) != ""
+	}
+
+	return path == "/posts/search" || path == "/search" || strings.HasPrefix(path, "/search/")
+}
+
+// seconds rounds the duration up to whole seconds, as the headers expect.
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64((d+time.Second-1)/time.Second), 10)
+}
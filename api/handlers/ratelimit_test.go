@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSearch(t *testing.T) {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		return c.SendString(strconv.FormatBool(isSearch(c)))
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		search bool
+	}{
+		{"Positive: Search suggestions", fiber.MethodGet, "/search/suggestions?keyword=go", true},
+		{"Positive: Post search", fiber.MethodGet, "/posts/search", true},
+		{"Positive: Posts filtered by the search box", fiber.MethodGet, "/posts?keyword=go", true},
+		{"Negative: Posts without a keyword", fiber.MethodGet, "/posts?keyword=+", false},
+		{"Negative: Post list", fiber.MethodGet, "/posts", false},
+		{"Negative: Created post", fiber.MethodPost, "/posts?keyword=go", false},
+		{"Negative: Post page", fiber.MethodGet, "/posts/search-engines?keyword=go", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, body := readBody(t, app, tt.method, tt.target, nil)
+			assert.Equal(t, strconv.FormatBool(tt.search), body)
+		})
+	}
+}
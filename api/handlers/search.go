@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"newsteller/internal/config"
+	"newsteller/internal/repositories"
+	"newsteller/internal/search"
+	"newsteller/internal/templates"
+	"strings"
+)
+
+type Search struct {
+	cfg     *config.Config
+	queries *repositories.SearchQuery
+	posts   *repositories.Post
+	index   *search.TitleIndex
+}
+
+func NewSearch(cfg *config.Config, queries *mongo.Collection, posts *mongo.Collection, index *search.TitleIndex) *Search {
+	return &Search{
+		cfg:     cfg,
+		queries: repositories.NewSearchQueryRepository(queries),
+		posts:   repositories.NewPostRepository(posts),
+		index:   index,
+	}
+}
+
+// GET /search/suggestions
+func (s *Search) Suggestions(c *fiber.Ctx) error {
+	keyword := strings.TrimSpace(c.Query("keyword"))
+
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	if keyword == "" {
+		return c.SendString("")
+	}
+
+	posts := s.index.Suggest(keyword, s.cfg.SuggestionsLimit)
+	queries, err := s.queries.FindPopular(c.Context(), keyword, s.cfg.SuggestionsLimit)
+	if err != nil {
+		// popular queries are optional, title completions are still useful
+		zap.L().Error("could not get popular queries", zap.Error(err))
+	}
+
+	html, err := templates.NewSuggestions(posts, queries).GeneratePage()
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.SendString(html)
+}
+
+// POST /search/queries
+// Adds the keyword to the query log used for popular search suggestions. Pages send it once
+// the reader completes a search, the results themselves are cached and typing fetches them for
+// every prefix. Only searches with results are logged, so typos do not end up being suggested.
+func (s *Search) RecordQuery(c *fiber.Ctx) error {
+	keyword := strings.TrimSpace(c.FormValue("keyword"))
+	if keyword == "" {
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+
+	_, total, err := s.posts.FindPaginated(c.Context(), &repositories.PaginatedSearchQuery{
+		Page:    1,
+		Limit:   1,
+		Keyword: keyword,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	if total > 0 {
+		err = s.queries.Record(c.Context(), keyword)
+		if err != nil {
+			zap.L().Warn("could not record search query", zap.String("query", keyword), zap.Error(err))
+		}
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"bytes"
+	"github.com/gofiber/fiber/v2"
+	"newsteller/internal/config"
+	"newsteller/internal/security"
+	"newsteller/internal/templates"
+	"newsteller/internal/tokens"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	csrfCookie = "csrf_token"
+	csrfHeader = "X-CSRF-Token"
+)
+
+// csrfExempt - routes posted to by other services, they authenticate with tokens of their own:
+// one-click unsubscribes of mail clients (RFC 8058) and bounce notifications
+var csrfExempt = []string{"/subscribers/unsubscribe", "/bounces"}
+
+// Security sends the security headers and protects requests changing data against CSRF.
+type Security struct {
+	cfg   *config.Config
+	csrf  *security.CSRF
+	https bool
+}
+
+func NewSecurity(cfg *config.Config, signer *tokens.Signer) *Security {
+	return &Security{
+		cfg:   cfg,
+		csrf:  security.NewCSRF(signer),
+		https: strings.HasPrefix(cfg.BaseURL, "https://"),
+	}
+}
+
+// Headers sets the security headers of the response and the nonce of its inline scripts.
+// Pages are rendered, and cached, with a placeholder replaced by the nonce of every response.
+func (s *Security) Headers(c *fiber.Ctx) error {
+	nonce := security.Nonce()
+	cfg := s.cfg.Security
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	if cfg.CSP != "" {
+		c.Set(fiber.HeaderContentSecurityPolicy, security.Policy(cfg.CSP, nonce))
+	}
+	if cfg.FrameOptions != "" {
+		c.Set(fiber.HeaderXFrameOptions, cfg.FrameOptions)
+	}
+	if cfg.ReferrerPolicy != "" {
+		c.Set(fiber.HeaderReferrerPolicy, cfg.ReferrerPolicy)
+	}
+	if s.https && cfg.HSTSMaxAge > 0 {
+		c.Set(fiber.HeaderStrictTransportSecurity, "max-age="+strconv.Itoa(int(cfg.HSTSMaxAge.Seconds())))
+	}
+
+	err := c.Next()
+
+	if strings.HasPrefix(string(c.Response().Header.ContentType()), fiber.MIMETextHTML) {
+		body := c.Response().Body()
+		if bytes.Contains(body, []byte(templates.NoncePlaceholder)) {
+			c.Response().SetBodyRaw(bytes.ReplaceAll(body, []byte(templates.NoncePlaceholder), []byte(nonce)))
+		}
+	}
+
+	return err
+}
+
+// CSRF issues the csrf cookie and rejects requests changing data without its token.
+func (s *Security) CSRF(c *fiber.Ctx) error {
+	cookie := c.Cookies(csrfCookie)
+	if !s.csrf.Valid(cookie) {
+		cookie = s.csrf.Token()
+		// read by the pages, so it is not HTTP only
+		c.Cookie(&fiber.Cookie{
+			Name:     csrfCookie,
+			Value:    cookie,
+			Path:     "/",
+			Secure:   s.https,
+			SameSite: fiber.CookieSameSiteLaxMode,
+		})
+	}
+
+	switch c.Method() {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		return c.Next()
+	}
+	if slices.Contains(csrfExempt, strings.TrimSuffix(c.Path(), "/")) {
+		return c.Next()
+	}
+	if s.csrf.Matches(cookie, c.Get(csrfHeader)) {
+		return c.Next()
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	page := templates.NewInvalidCSRF()
+	if c.Get("HX-Request") == "true" {
+		page.AsFragment()
+	}
+	html, err := page.GeneratePage()
+	if err != nil {
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	}
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+
+	return c.Status(fiber.StatusForbidden).SendString(html)
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"newsteller/internal/cache"
+	"newsteller/internal/config"
+	"newsteller/internal/repositories"
+	"newsteller/internal/sitemap"
+	"os"
+	"strings"
+)
+
+// sitemapGenerated - cache key marking the sitemaps as generated, so requests of missing
+// parts do not regenerate them
+const sitemapGenerated = cache.SitemapPrefix
+
+type Sitemap struct {
+	cfg    *config.Config
+	posts  *repositories.Post
+	cache  *cache.PagesCache
+	robots string
+}
+
+func NewSitemap(cfg *config.Config, posts *mongo.Collection, cache *cache.PagesCache) *Sitemap {
+	robots := sitemap.Robots(cfg.BaseURL, strings.Split(cfg.Robots.Disallow, ","))
+	if cfg.Robots.File != "" {
+		content, err := os.ReadFile(cfg.Robots.File)
+		if err != nil {
+			zap.L().Error("could not read robots.txt, serving the generated one", zap.String("file", cfg.Robots.File), zap.Error(err))
+		} else {
+			robots = string(content)
+		}
+	}
+
+	return &Sitemap{
+		cfg:    cfg,
+		posts:  repositories.NewPostRepository(posts),
+		cache:  cache,
+		robots: robots,
+	}
+}
+
+// GET /sitemap.xml, /sitemap-:part.xml
+// Sitemaps are generated on the first request after posts change and cached until the next change.
+func (s *Sitemap) GetSitemap(c *fiber.Ctx) error {
+	key := cache.SitemapPrefix + c.Path()
+	page, ok := s.cache.Get(key)
+	if !ok {
+		if _, generated := s.cache.Get(sitemapGenerated); generated {
+			return fiber.ErrNotFound
+		}
+		err := s.generate(c.Context())
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+		if page, ok = s.cache.Get(key); !ok {
+			return fiber.ErrNotFound
+		}
+	}
+
+	return sendConditional(c, page)
+}
+
+// GET /robots.txt
+func (s *Sitemap) GetRobots(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+	return c.SendString(s.robots)
+}
+
+// generate renders every sitemap at once, so the parts of a split sitemap always match the index.
+func (s *Sitemap) generate(ctx context.Context) error {
+	posts, err := s.posts.AllDates(ctx)
+	if err != nil {
+		return err
+	}
+
+	documents, err := sitemap.Build(s.cfg.BaseURL, sitemap.SiteURLs(s.cfg.BaseURL, posts, s.cfg.PostsPerPage))
+	if err != nil {
+		return err
+	}
+
+	for path, document := range documents {
+		s.cache.Set(cache.SitemapPrefix+path, cache.Page{
+			Body:        string(document.Body),
+			ContentType: fiber.MIMEApplicationXMLCharsetUTF8,
+			ModifiedAt:  document.LastMod,
+		})
+	}
+	s.cache.Set(sitemapGenerated, cache.Page{})
+
+	return nil
+}
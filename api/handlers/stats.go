@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/internal/config"
+	"newsteller/internal/models"
+	"newsteller/internal/repositories"
+	"newsteller/internal/templates"
+	"slices"
+	"time"
+)
+
+const (
+	// statsTopLimit - number of top links and posts shown
+	statsTopLimit = 10
+	// statsMaxDays - days after sending the issue stats page shows daily events for
+	statsMaxDays = 90
+)
+
+type Stats struct {
+	cfg    *config.Config
+	events *repositories.TrackingEvent
+	issues *repositories.Issue
+	posts  *repositories.Post
+}
+
+func NewStats(
+	cfg *config.Config,
+	events *mongo.Collection,
+	issues *mongo.Collection,
+	posts *mongo.Collection,
+) *Stats {
+	return &Stats{
+		cfg:    cfg,
+		events: repositories.NewTrackingEventRepository(events),
+		issues: repositories.NewIssueRepository(issues),
+		posts:  repositories.NewPostRepository(posts),
+	}
+}
+
+// GET /stats
+func (s *Stats) GetStatsPage(c *fiber.Ctx) error {
+	days := c.QueryInt("days", 30)
+	if !slices.Contains(templates.StatsPeriods, days) {
+		return fiber.NewError(fiber.StatusBadRequest, "unsupported stats period")
+	}
+	since := time.Now().AddDate(0, 0, -days+1)
+
+	issues, err := s.issues.FindSent(c.Context())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	ids := make([]primitive.ObjectID, len(issues))
+	for i := range issues {
+		ids[i] = issues[i].ID
+	}
+	totals, err := s.events.IssueTotals(c.Context(), ids)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	issueStats := make([]templates.IssueStats, len(issues))
+	for i := range issues {
+		issueStats[i] = templates.IssueStats{Issue: issues[i], Totals: totals[issues[i].ID]}
+	}
+
+	topPosts, err := s.events.TopPosts(c.Context(), since, statsTopLimit)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	posts, err := s.titled(c.Context(), topPosts)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	daily, err := s.events.Daily(c.Context(), primitive.NilObjectID, since)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return sendHTML(c, templates.NewStats(days, issueStats, posts, daily))
+}
+
+// GET /stats/issues/:id
+func (s *Stats) GetIssueStatsPage(c *fiber.Ctx) error {
+	issue, err := s.issues.FindByID(c.Context(), c.Params("id"))
+	if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) ||
+		err == nil && issue.Status != models.IssueSent {
+		return fiber.NewError(fiber.StatusNotFound, "issue not found")
+	}
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	totals, err := s.events.IssueTotals(c.Context(), []primitive.ObjectID{issue.ID})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	topLinks, err := s.events.TopLinks(c.Context(), issue.ID, statsTopLimit)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	links, err := s.titled(c.Context(), topLinks)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	since := issue.SentAt
+	if limit := time.Now().AddDate(0, 0, -statsMaxDays+1); since.Before(limit) {
+		since = limit
+	}
+	daily, err := s.events.Daily(c.Context(), issue.ID, since)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return sendHTML(c, templates.NewIssueStatsPage(issue, totals[issue.ID], links, daily))
+}
+
+// titled names the links after the posts they lead to, deleted posts are named by the URL.
+func (s *Stats) titled(ctx context.Context, links []models.LinkStats) ([]templates.LinkStats, error) {
+	titled := make([]templates.LinkStats, len(links))
+	for i, link := range links {
+		titled[i] = templates.LinkStats{LinkStats: link, Title: link.URL}
+		if link.PostID.IsZero() {
+			continue
+		}
+
+		post, err := s.posts.FindByID(ctx, link.PostID.Hex())
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		titled[i].Title = post.Title
+		titled[i].URL = s.cfg.BaseURL + post.Path()
+	}
+
+	return titled, nil
+}
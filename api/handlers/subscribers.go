@@ -0,0 +1,452 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"newsteller/api/dto"
+	"newsteller/internal/antispam"
+	"newsteller/internal/config"
+	"newsteller/internal/mailer"
+	"newsteller/internal/models"
+	"newsteller/internal/newsletter"
+	"newsteller/internal/repositories"
+	"newsteller/internal/taxonomy"
+	"newsteller/internal/templates"
+	"newsteller/internal/tokens"
+	"slices"
+	"time"
+)
+
+// suppressionListLimit - max suppressed subscribers listed on the moderation page
+const suppressionListLimit = 50
+
+// suppressedMessage - answer to readers of a suppressed address, mail to it bounced or was reported as spam
+const suppressedMessage = "We cannot deliver email to this address. Please use another one."
+
+type Subscriber struct {
+	cfg        *config.Config
+	repo       *repositories.Subscriber
+	posts      *repositories.Post
+	categories *repositories.Category
+	signer     *tokens.Signer
+	links      *newsletter.Links
+	mailer     *mailer.Mailer
+	guard      *antispam.Guard
+}
+
+func NewSubscriber(
+	cfg *config.Config,
+	c *mongo.Collection,
+	posts *mongo.Collection,
+	categories *mongo.Collection,
+	signer *tokens.Signer,
+	mailer *mailer.Mailer,
+	guard *antispam.Guard,
+) *Subscriber {
+	return &Subscriber{
+		cfg:        cfg,
+		repo:       repositories.NewSubscriberRepository(c),
+		posts:      repositories.NewPostRepository(posts),
+		categories: repositories.NewCategoryRepository(categories),
+		signer:     signer,
+		links:      newsletter.NewLinks(cfg, signer),
+		mailer:     mailer,
+		guard:      guard,
+	}
+}
+
+// POST /subscribers
+func (s *Subscriber) Subscribe(c *fiber.Ctx) error {
+	var subscribeDTO dto.SubscribeDTO
+	err := c.BodyParser(&subscribeDTO)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	err = validate.Struct(subscribeDTO)
+	if err != nil {
+		return s.sendResponse(c, templates.MessageError, "Please enter a valid email address.")
+	}
+
+	message, challenge := checkForm(c, s.guard, antispam.FormSubscribe)
+	respond := func(kind templates.MessageKind, text string) error {
+		return s.sendSubscribeResponse(c, templates.NewSubscribeResponse(kind, text).WithChallenge(challenge))
+	}
+	if message != "" {
+		return respond(templates.MessageError, message)
+	}
+
+	subscriber, err := s.repo.FindByEmail(c.Context(), subscribeDTO.Email)
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		subscriber = &models.Subscriber{
+			Email:     repositories.NormalizeEmail(subscribeDTO.Email),
+			Status:    models.SubscriberPending,
+			Source:    subscribeDTO.Source,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		s.setDigestSchedule(subscriber, &subscribeDTO)
+		id, err := s.repo.Create(c.Context(), subscriber)
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+		subscriber.ID = *id
+	case err != nil:
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	case subscriber.Status == models.SubscriberActive:
+		return respond(templates.MessageSuccess, "You are already subscribed.")
+	case subscriber.Suppressed():
+		// mail to the address bounced or was reported as spam, only an editor may reactivate it
+		return respond(templates.MessageError, suppressedMessage)
+	default:
+		// pending subscribers may resubmit the form with another schedule
+		s.setDigestSchedule(subscriber, &subscribeDTO)
+		err = s.repo.UpdateDigestSchedule(c.Context(), subscriber)
+		if err == nil && subscriber.Status == models.SubscriberUnsubscribed {
+			err = s.repo.UpdateStatus(c.Context(), subscriber.ID, models.SubscriberPending, time.Now())
+		}
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+	}
+
+	err = s.sendConfirmation(c.Context(), subscriber)
+	if err != nil {
+		zap.L().Error("could not send confirmation", zap.String("id", subscriber.ID.Hex()), zap.Error(err))
+		return respond(templates.MessageError, "We could not send the confirmation email. Please try again later.")
+	}
+
+	return respond(templates.MessageSuccess, "Almost there! Check your inbox to confirm the subscription.")
+}
+
+// GET /subscribers/confirm
+func (s *Subscriber) Confirm(c *fiber.Ctx) error {
+	subscriber, err := s.subscriberFromToken(c, tokens.PurposeConfirmSubscription)
+	if err != nil {
+		return s.sendPage(
+			c,
+			fiber.StatusBadRequest,
+			templates.MessageError,
+			"Invalid link",
+			"This confirmation link is invalid or has expired. Please subscribe again.",
+		)
+	}
+
+	// only editors reactivate suppressed addresses, following an old link does not
+	if subscriber.Suppressed() {
+		return s.sendPage(
+			c,
+			fiber.StatusConflict,
+			templates.MessageError,
+			"Subscription not confirmed",
+			suppressedMessage,
+		)
+	}
+
+	if subscriber.Status != models.SubscriberActive {
+		err = s.repo.UpdateStatus(c.Context(), subscriber.ID, models.SubscriberActive, time.Now())
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return s.sendPage(
+		c,
+		fiber.StatusOK,
+		templates.MessageSuccess,
+		"Subscription confirmed",
+		"Thanks for subscribing! You will receive our newsletter at "+subscriber.Email+".",
+	)
+}
+
+// GET /subscribers/unsubscribe
+// Opening the link only asks to confirm, link scanners and prefetching mail clients follow
+// the links of messages without the reader clicking them.
+func (s *Subscriber) GetUnsubscribePage(c *fiber.Ctx) error {
+	subscriber, err := s.subscriberFromToken(c, tokens.PurposeUnsubscribe)
+	if err != nil {
+		return s.sendPage(
+			c,
+			fiber.StatusBadRequest,
+			templates.MessageError,
+			"Invalid link",
+			"This unsubscribe link is invalid.",
+		)
+	}
+
+	return s.sendSubscriptionPage(
+		c,
+		fiber.StatusOK,
+		templates.NewSubscriptionPage(
+			templates.MessageSuccess,
+			"Unsubscribe",
+			"Do you want "+subscriber.Email+" to stop receiving our newsletter?",
+		).
+			WithForm(s.links.UnsubscribeURL(subscriber), "Unsubscribe").
+			WithLink(s.links.PreferencesURL(subscriber), "Manage your preferences instead"),
+	)
+}
+
+// POST /subscribers/unsubscribe
+// Posted by the confirmation page and by mail clients supporting one-click unsubscribe (RFC 8058).
+func (s *Subscriber) Unsubscribe(c *fiber.Ctx) error {
+	subscriber, err := s.subscriberFromToken(c, tokens.PurposeUnsubscribe)
+	if err != nil {
+		return s.sendPage(
+			c,
+			fiber.StatusBadRequest,
+			templates.MessageError,
+			"Invalid link",
+			"This unsubscribe link is invalid.",
+		)
+	}
+
+	// suppressed addresses keep their status, subscribing again must not revive them
+	if subscriber.Status != models.SubscriberUnsubscribed && !subscriber.Suppressed() {
+		err = s.repo.UpdateStatus(c.Context(), subscriber.ID, models.SubscriberUnsubscribed, time.Now())
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return s.sendSubscriptionPage(
+		c,
+		fiber.StatusOK,
+		templates.NewSubscriptionPage(
+			templates.MessageSuccess,
+			"Unsubscribed",
+			subscriber.Email+" will no longer receive our newsletter.",
+		).WithLink(s.links.PreferencesURL(subscriber), "Manage your preferences"),
+	)
+}
+
+// GET /subscribers/preferences
+// The page is reachable from the unsubscribe link and uses the same token.
+func (s *Subscriber) GetPreferencesPage(c *fiber.Ctx) error {
+	subscriber, err := s.subscriberFromToken(c, tokens.PurposeUnsubscribe)
+	if err != nil {
+		return s.sendPage(
+			c,
+			fiber.StatusBadRequest,
+			templates.MessageError,
+			"Invalid link",
+			"This preferences link is invalid.",
+		)
+	}
+
+	topics, err := s.posts.Tags(c.Context())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	categories, err := s.categories.All(c.Context())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	html, err := templates.
+		NewPreferences(
+			subscriber,
+			topics,
+			taxonomy.Tree(categories),
+			c.Query("token"),
+			s.links.UnsubscribeURL(subscriber),
+		).
+		GeneratePage()
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.SendString(html)
+}
+
+// POST /subscribers/preferences
+func (s *Subscriber) UpdatePreferences(c *fiber.Ctx) error {
+	subscriber, err := s.subscriberFromToken(c, tokens.PurposeUnsubscribe)
+	if err != nil {
+		return s.sendResponse(c, templates.MessageError, "This preferences link is invalid.")
+	}
+	if subscriber.Suppressed() {
+		return s.sendResponse(c, templates.MessageError, suppressedMessage)
+	}
+
+	var preferencesDTO dto.PreferencesDTO
+	err = c.BodyParser(&preferencesDTO)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	err = validate.Struct(preferencesDTO)
+	if err != nil {
+		return s.sendResponse(c, templates.MessageError, "Please check the digest and topic fields.")
+	}
+
+	subscriber.DigestFrequency = models.DigestFrequency(preferencesDTO.Frequency)
+	subscriber.DigestHour = preferencesDTO.Hour
+	subscriber.Topics = models.NormalizeTags(preferencesDTO.Topics)
+	subscriber.Categories, err = s.existingCategories(c.Context(), preferencesDTO.Categories)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	subscriber.TrackingOptOut = preferencesDTO.TrackingOptOut
+	if _, err := time.LoadLocation(preferencesDTO.Timezone); err == nil && preferencesDTO.Timezone != "Local" {
+		subscriber.Timezone = preferencesDTO.Timezone
+	}
+
+	err = s.repo.UpdatePreferences(c.Context(), subscriber)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return s.sendResponse(c, templates.MessageSuccess, "Your preferences were saved.")
+}
+
+// existingCategories returns the IDs of the categories that exist, deleted ones are dropped.
+func (s *Subscriber) existingCategories(ctx context.Context, ids []string) ([]primitive.ObjectID, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	categories, err := s.categories.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make([]primitive.ObjectID, 0, len(ids))
+	for _, category := range categories {
+		if slices.Contains(ids, category.ID.Hex()) {
+			existing = append(existing, category.ID)
+		}
+	}
+
+	return existing, nil
+}
+
+// GET /subscribers/moderation
+func (s *Subscriber) GetModerationPage(c *fiber.Ctx) error {
+	query, err := validatePaginationQuery(c, s.cfg.PostsPerPage)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	subscribers, total, err := s.repo.FindPaginated(c.Context(), query)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	suppressed, totalSuppressed, err := s.repo.FindSuppressed(c.Context(), suppressionListLimit)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	html, err := templates.
+		NewSubscribers(
+			subscribers,
+			query.Page,
+			query.Limit,
+			int(total),
+		).
+		WithSuppressed(suppressed, int(totalSuppressed)).
+		GeneratePage()
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.SendString(html)
+}
+
+// POST /subscribers/:id/reactivate
+// Removes the subscriber from the suppression list, bounces received before do not count anymore.
+func (s *Subscriber) Reactivate(c *fiber.Ctx) error {
+	subscriber, err := s.repo.FindByID(c.Context(), c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "subscriber not found")
+	}
+	if !subscriber.Suppressed() {
+		return fiber.NewError(fiber.StatusConflict, "subscriber is not suppressed")
+	}
+
+	err = s.repo.UpdateStatus(c.Context(), subscriber.ID, models.SubscriberActive, time.Now())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Set("HX-Redirect", "/subscribers/moderation")
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// setDigestSchedule applies the digest preferences from the form, digests are sent
+// at the configured time of the subscriber time zone.
+func (s *Subscriber) setDigestSchedule(subscriber *models.Subscriber, subscribeDTO *dto.SubscribeDTO) {
+	subscriber.DigestFrequency = models.DigestWeekly
+	if subscribeDTO.Frequency != "" {
+		subscriber.DigestFrequency = models.DigestFrequency(subscribeDTO.Frequency)
+	}
+	subscriber.DigestHour = s.cfg.Newsletter.DigestHour
+	subscriber.DigestWeekday = time.Weekday(s.cfg.Newsletter.DigestWeekday)
+
+	subscriber.Timezone = ""
+	if _, err := time.LoadLocation(subscribeDTO.Timezone); err == nil && subscribeDTO.Timezone != "Local" {
+		subscriber.Timezone = subscribeDTO.Timezone
+	}
+}
+
+// sendConfirmation enqueues the double opt-in email for the subscriber.
+func (s *Subscriber) sendConfirmation(ctx context.Context, subscriber *models.Subscriber) error {
+	email, err := templates.NewConfirmationEmail(s.links.ConfirmURL(subscriber)).GenerateEmail()
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Enqueue(ctx, &mailer.Message{
+		To:      subscriber.Email,
+		Subject: email.Subject,
+		HTML:    email.HTML,
+		Text:    email.Text,
+	})
+}
+
+func (s *Subscriber) subscriberFromToken(c *fiber.Ctx, purpose tokens.Purpose) (*models.Subscriber, error) {
+	id, err := s.signer.Verify(purpose, c.Query("token"))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindByID(c.Context(), id)
+}
+
+func (s *Subscriber) sendResponse(c *fiber.Ctx, kind templates.MessageKind, text string) error {
+	return s.sendSubscribeResponse(c, templates.NewSubscribeResponse(kind, text))
+}
+
+func (s *Subscriber) sendSubscribeResponse(c *fiber.Ctx, response *templates.SubscribeResponse) error {
+	html, err := response.GeneratePage()
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.SendString(html)
+}
+
+func (s *Subscriber) sendPage(c *fiber.Ctx, status int, kind templates.MessageKind, title, text string) error {
+	return s.sendSubscriptionPage(c, status, templates.NewSubscriptionPage(kind, title, text))
+}
+
+func (s *Subscriber) sendSubscriptionPage(c *fiber.Ctx, status int, page *templates.SubscriptionPage) error {
+	html, err := page.GeneratePage()
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.Status(status).SendString(html)
+}
@@ -0,0 +1,301 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"newsteller/internal/antispam"
+	"newsteller/internal/config"
+	"newsteller/internal/models"
+	"newsteller/internal/ratelimit"
+	"newsteller/internal/tokens"
+)
+
+var dbClient *mongo.Client
+
+func TestMain(m *testing.M) {
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		log.Fatalf("Could not construct pool: %s", err)
+	}
+
+	err = pool.Client.Ping()
+	if err != nil {
+		log.Fatalf("Could not connect to Docker: %s", err)
+	}
+
+	resource, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: "mongo",
+		Tag:        "latest",
+		Env: []string{
+			"MONGO_INITDB_ROOT_USERNAME=root",
+			"MONGO_INITDB_ROOT_PASSWORD=password",
+		},
+	}, func(config *docker.HostConfig) {
+		config.AutoRemove = true
+		config.RestartPolicy = docker.RestartPolicy{Name: "no"}
+	})
+	if err != nil {
+		log.Fatalf("Could not start resource: %s", err)
+	}
+
+	mongoURI := fmt.Sprintf("mongodb://root:password@%s", resource.GetHostPort("27017/tcp"))
+
+	if err := pool.Retry(func() error {
+		var err error
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		dbClient, err = mongo.Connect(ctx, options.Client().ApplyURI(mongoURI))
+		if err != nil {
+			return err
+		}
+		return dbClient.Ping(ctx, nil)
+	}); err != nil {
+		log.Fatalf("Could not connect to docker: %s", err)
+	}
+
+	code := m.Run()
+
+	if err := dbClient.Disconnect(context.Background()); err != nil {
+		log.Printf("Could not disconnect from mongo: %s", err)
+	}
+
+	if err := pool.Purge(resource); err != nil {
+		log.Fatalf("Could not purge resource: %s", err)
+	}
+
+	os.Exit(code)
+}
+
+// newSubscriberApp serves the subscriber routes of the token links and returns the handler
+// and its signer.
+func newSubscriberApp(t *testing.T) (*fiber.App, *Subscriber, *tokens.Signer) {
+	t.Helper()
+	database := dbClient.Database("newsteller_test")
+	subscribers := database.Collection("subscribers_test")
+	categories := database.Collection("categories_test")
+	t.Cleanup(func() {
+		subscribers.DeleteMany(context.Background(), bson.M{})
+		categories.DeleteMany(context.Background(), bson.M{})
+	})
+
+	cfg := &config.Config{BaseURL: "http://localhost:3000"}
+	cfg.Newsletter.ConfirmationTTL = time.Hour
+	signer := tokens.NewSigner("secret")
+	handler := NewSubscriber(
+		cfg,
+		subscribers,
+		database.Collection("posts_test"),
+		categories,
+		signer,
+		nil,
+		antispam.NewGuard(cfg, signer, ratelimit.NewMemoryStore()),
+	)
+
+	app := fiber.New()
+	app.Post("/subscribers", handler.Subscribe)
+	app.Get("/subscribers/confirm", handler.Confirm)
+	app.Get("/subscribers/unsubscribe", handler.GetUnsubscribePage)
+	app.Post("/subscribers/unsubscribe", handler.Unsubscribe)
+	app.Post("/subscribers/preferences", handler.UpdatePreferences)
+
+	return app, handler, signer
+}
+
+func createSubscriber(t *testing.T, handler *Subscriber, status models.SubscriberStatus) *models.Subscriber {
+	t.Helper()
+	subscriber := &models.Subscriber{Email: "reader@example.com", Status: status, DigestFrequency: models.DigestWeekly}
+	id, err := handler.repo.Create(context.Background(), subscriber)
+	require.NoError(t, err)
+	subscriber.ID = *id
+
+	return subscriber
+}
+
+func readBody(t *testing.T, app *fiber.App, method, target string, body io.Reader) (int, string) {
+	t.Helper()
+	req := httptest.NewRequest(method, target, body)
+	if body != nil {
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationForm)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return resp.StatusCode, string(data)
+}
+
+func TestSubscriber_Confirm(t *testing.T) {
+	app, handler, signer := newSubscriberApp(t)
+
+	t.Run("Positive: Pending subscriber is activated", func(t *testing.T) {
+		subscriber := createSubscriber(t, handler, models.SubscriberPending)
+		token := signer.Sign(tokens.PurposeConfirmSubscription, subscriber.ID.Hex(), time.Now().Add(time.Hour))
+
+		status, body := readBody(t, app, fiber.MethodGet, "/subscribers/confirm?token="+url.QueryEscape(token), nil)
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Contains(t, body, "Subscription confirmed")
+
+		found, err := handler.repo.FindByID(context.Background(), subscriber.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, models.SubscriberActive, found.Status)
+	})
+
+	for _, suppressed := range []models.SubscriberStatus{models.SubscriberBounced, models.SubscriberComplained} {
+		t.Run("Negative: "+string(suppressed)+" subscriber stays suppressed", func(t *testing.T) {
+			subscriber := createSubscriber(t, handler, suppressed)
+			token := signer.Sign(tokens.PurposeConfirmSubscription, subscriber.ID.Hex(), time.Now().Add(time.Hour))
+
+			status, body := readBody(t, app, fiber.MethodGet, "/subscribers/confirm?token="+url.QueryEscape(token), nil)
+			assert.Equal(t, fiber.StatusConflict, status)
+			assert.Contains(t, body, "We cannot deliver email to this address.")
+
+			found, err := handler.repo.FindByID(context.Background(), subscriber.ID.Hex())
+			require.NoError(t, err)
+			assert.Equal(t, suppressed, found.Status)
+		})
+	}
+}
+
+func TestSubscriber_Subscribe(t *testing.T) {
+	app, handler, _ := newSubscriberApp(t)
+	form := url.Values{"email": {"reader@example.com"}}.Encode()
+
+	for _, suppressed := range []models.SubscriberStatus{models.SubscriberBounced, models.SubscriberComplained} {
+		t.Run("Negative: "+string(suppressed)+" subscriber is not subscribed again", func(t *testing.T) {
+			subscriber := createSubscriber(t, handler, suppressed)
+
+			_, body := readBody(t, app, fiber.MethodPost, "/subscribers", strings.NewReader(form))
+			assert.Contains(t, body, "We cannot deliver email to this address.")
+
+			found, err := handler.repo.FindByID(context.Background(), subscriber.ID.Hex())
+			require.NoError(t, err)
+			assert.Equal(t, suppressed, found.Status)
+		})
+	}
+}
+
+func TestSubscriber_Unsubscribe(t *testing.T) {
+	app, handler, signer := newSubscriberApp(t)
+
+	t.Run("Positive: Opening the link only asks to confirm", func(t *testing.T) {
+		subscriber := createSubscriber(t, handler, models.SubscriberActive)
+		token := signer.Sign(tokens.PurposeUnsubscribe, subscriber.ID.Hex(), time.Time{})
+
+		status, body := readBody(t, app, fiber.MethodGet, "/subscribers/unsubscribe?token="+url.QueryEscape(token), nil)
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Contains(t, body, `<form method="post" action="http://localhost:3000/subscribers/unsubscribe?token=`)
+
+		found, err := handler.repo.FindByID(context.Background(), subscriber.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, models.SubscriberActive, found.Status)
+	})
+
+	t.Run("Positive: Active subscriber is unsubscribed", func(t *testing.T) {
+		subscriber := createSubscriber(t, handler, models.SubscriberActive)
+		token := signer.Sign(tokens.PurposeUnsubscribe, subscriber.ID.Hex(), time.Time{})
+
+		status, body := readBody(t, app, fiber.MethodPost, "/subscribers/unsubscribe?token="+url.QueryEscape(token), nil)
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Contains(t, body, "Unsubscribed")
+
+		found, err := handler.repo.FindByID(context.Background(), subscriber.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, models.SubscriberUnsubscribed, found.Status)
+	})
+
+	for _, suppressed := range []models.SubscriberStatus{models.SubscriberBounced, models.SubscriberComplained} {
+		t.Run("Positive: "+string(suppressed)+" subscriber stays suppressed", func(t *testing.T) {
+			subscriber := createSubscriber(t, handler, suppressed)
+			token := signer.Sign(tokens.PurposeUnsubscribe, subscriber.ID.Hex(), time.Time{})
+
+			status, _ := readBody(t, app, fiber.MethodPost, "/subscribers/unsubscribe?token="+url.QueryEscape(token), nil)
+			assert.Equal(t, fiber.StatusOK, status)
+
+			found, err := handler.repo.FindByID(context.Background(), subscriber.ID.Hex())
+			require.NoError(t, err)
+			assert.Equal(t, suppressed, found.Status)
+		})
+	}
+}
+
+func TestSubscriber_UpdatePreferences(t *testing.T) {
+	app, handler, signer := newSubscriberApp(t)
+	form := url.Values{"frequency": {"daily"}, "hour": {"7"}}.Encode()
+
+	t.Run("Positive: Preferences are saved", func(t *testing.T) {
+		subscriber := createSubscriber(t, handler, models.SubscriberActive)
+		token := signer.Sign(tokens.PurposeUnsubscribe, subscriber.ID.Hex(), time.Time{})
+
+		_, body := readBody(t, app, fiber.MethodPost, "/subscribers/preferences?token="+url.QueryEscape(token), strings.NewReader(form))
+		assert.Contains(t, body, "Your preferences were saved.")
+
+		found, err := handler.repo.FindByID(context.Background(), subscriber.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, models.DigestDaily, found.DigestFrequency)
+	})
+
+	t.Run("Positive: Only existing categories are followed", func(t *testing.T) {
+		categoryID, err := handler.categories.Create(context.Background(), &models.Category{Name: "Tech", Slug: "tech"})
+		require.NoError(t, err)
+		subscriber := createSubscriber(t, handler, models.SubscriberActive)
+		token := signer.Sign(tokens.PurposeUnsubscribe, subscriber.ID.Hex(), time.Time{})
+
+		body := url.Values{
+			"frequency":  {"weekly"},
+			"hour":       {"7"},
+			"categories": {categoryID.Hex(), primitive.NewObjectID().Hex()},
+		}.Encode()
+		_, response := readBody(t, app, fiber.MethodPost, "/subscribers/preferences?token="+url.QueryEscape(token), strings.NewReader(body))
+		assert.Contains(t, response, "Your preferences were saved.")
+
+		found, err := handler.repo.FindByID(context.Background(), subscriber.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, []primitive.ObjectID{*categoryID}, found.Categories)
+	})
+
+	t.Run("Positive: Unsubscribed subscriber stays unsubscribed", func(t *testing.T) {
+		subscriber := createSubscriber(t, handler, models.SubscriberUnsubscribed)
+		token := signer.Sign(tokens.PurposeUnsubscribe, subscriber.ID.Hex(), time.Time{})
+
+		_, body := readBody(t, app, fiber.MethodPost, "/subscribers/preferences?token="+url.QueryEscape(token), strings.NewReader(form))
+		assert.Contains(t, body, "Your preferences were saved.")
+
+		found, err := handler.repo.FindByID(context.Background(), subscriber.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, models.SubscriberUnsubscribed, found.Status)
+		assert.Equal(t, models.DigestDaily, found.DigestFrequency)
+	})
+
+	t.Run("Negative: Suppressed subscriber is not saved", func(t *testing.T) {
+		subscriber := createSubscriber(t, handler, models.SubscriberBounced)
+		token := signer.Sign(tokens.PurposeUnsubscribe, subscriber.ID.Hex(), time.Time{})
+
+		_, body := readBody(t, app, fiber.MethodPost, "/subscribers/preferences?token="+url.QueryEscape(token), strings.NewReader(form))
+		assert.Contains(t, body, "We cannot deliver email to this address.")
+
+		found, err := handler.repo.FindByID(context.Background(), subscriber.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, models.SubscriberBounced, found.Status)
+		assert.Equal(t, models.DigestWeekly, found.DigestFrequency)
+	})
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"net/url"
+	"newsteller/api/dto"
+	"newsteller/internal/cache"
+	"newsteller/internal/config"
+	"newsteller/internal/models"
+	"newsteller/internal/repositories"
+	"newsteller/internal/taxonomy"
+	"newsteller/internal/templates"
+	"strings"
+	"time"
+)
+
+const invalidCategoryMessage = "Please enter a name of at most 100 characters and pick an existing parent."
+
+// Taxonomy serves the pages of tags and categories and manages the categories.
+type Taxonomy struct {
+	cfg        *config.Config
+	posts      *repositories.Post
+	categories *repositories.Category
+	cache      *cache.PagesCache
+}
+
+func NewTaxonomy(
+	cfg *config.Config,
+	posts *mongo.Collection,
+	categories *mongo.Collection,
+	cache *cache.PagesCache,
+) *Taxonomy {
+	return &Taxonomy{
+		cfg:        cfg,
+		posts:      repositories.NewPostRepository(posts),
+		categories: repositories.NewCategoryRepository(categories),
+		cache:      cache,
+	}
+}
+
+// GET /tags/:tag
+func (t *Taxonomy) GetTagPage(c *fiber.Ctx) error {
+	tag, err := url.PathUnescape(c.Params("tag"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "tag not found")
+	}
+	tags := models.ParseTags(tag)
+	if len(tags) != 1 {
+		return fiber.NewError(fiber.StatusNotFound, "tag not found")
+	}
+	if tags[0] != tag {
+		// tags are stored normalized, e.g. /tags/Go is the page of go
+		return c.Redirect("/tags/"+url.PathEscape(tags[0]), fiber.StatusMovedPermanently)
+	}
+
+	query, err := validatePaginationQuery(c, t.cfg.PostsPerPage)
+	if err != nil {
+		return err
+	}
+	query.Limit = t.cfg.PostsPerPage
+	query.Tag = tag
+
+	posts, total, err := t.posts.FindPaginated(c.Context(), query)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	if total == 0 {
+		return fiber.NewError(fiber.StatusNotFound, "tag not found")
+	}
+
+	return sendHTML(c, templates.NewTagPage(tag, posts, query.Page, int(total), query.Limit))
+}
+
+// GET /categories/:slug, lists the posts of the category and of its subcategories
+func (t *Taxonomy) GetCategoryPage(c *fiber.Ctx) error {
+	category, err := t.categories.FindBySlug(c.Context(), c.Params("slug"))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return fiber.NewError(fiber.StatusNotFound, "category not found")
+	}
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	all, err := t.categories.All(c.Context())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	query, err := validatePaginationQuery(c, t.cfg.PostsPerPage)
+	if err != nil {
+		return err
+	}
+	query.Limit = t.cfg.PostsPerPage
+	query.Categories = taxonomy.Subtree(all, category.ID)
+
+	posts, total, err := t.posts.FindPaginated(c.Context(), query)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return sendHTML(c, templates.NewCategoryPage(
+		category,
+		taxonomy.Ancestors(all, *category),
+		taxonomy.Children(all, category.ID),
+		posts,
+		query.Page,
+		int(total),
+		query.Limit,
+	))
+}
+
+// GET /categories
+func (t *Taxonomy) GetCategoriesPage(c *fiber.Ctx) error {
+	all, err := t.categories.All(c.Context())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return sendHTML(c, templates.NewCategories(taxonomy.Tree(all)))
+}
+
+// POST /categories
+func (t *Taxonomy) CreateCategory(c *fiber.Ctx) error {
+	var categoryDTO dto.CategoryDTO
+	err := c.BodyParser(&categoryDTO)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	categoryDTO.Name = strings.TrimSpace(categoryDTO.Name)
+	categoryDTO.Description = strings.TrimSpace(categoryDTO.Description)
+	err = validator.New(validator.WithRequiredStructEnabled()).Struct(categoryDTO)
+	if err != nil {
+		return t.sendMessage(c, templates.MessageError, invalidCategoryMessage)
+	}
+
+	var parentID primitive.ObjectID
+	if categoryDTO.Parent != "" {
+		parent, err := t.categories.FindByID(c.Context(), categoryDTO.Parent)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return t.sendMessage(c, templates.MessageError, invalidCategoryMessage)
+		}
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+		parentID = parent.ID
+	}
+
+	slug, err := t.categories.UniqueSlug(c.Context(), categoryDTO.Name)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	_, err = t.categories.Create(c.Context(), &models.Category{
+		Name:        categoryDTO.Name,
+		Slug:        slug,
+		Description: categoryDTO.Description,
+		ParentID:    parentID,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	// the post forms offer the new category
+	t.cache.InvalidatePosts()
+
+	c.Set("HX-Redirect", "/categories")
+	return c.SendStatus(fiber.StatusCreated)
+}
+
+// DELETE /categories/:id, only empty categories without subcategories can be deleted
+func (t *Taxonomy) DeleteCategory(c *fiber.Ctx) error {
+	category, err := t.categories.FindByID(c.Context(), c.Params("id"))
+	if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+		return fiber.NewError(fiber.StatusNotFound, "category not found")
+	}
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	all, err := t.categories.All(c.Context())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	if len(taxonomy.Children(all, category.ID)) > 0 {
+		return t.sendMessage(c, templates.MessageError, "Move or delete the subcategories of "+category.Name+" first.")
+	}
+	count, err := t.posts.CountInCategory(c.Context(), category.ID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	if count > 0 {
+		return t.sendMessage(c, templates.MessageError, "Move the posts of "+category.Name+" to another category first.")
+	}
+
+	err = t.categories.Delete(c.Context(), category.ID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	t.cache.InvalidatePosts()
+
+	c.Set("HX-Redirect", "/categories")
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (t *Taxonomy) sendMessage(c *fiber.Ctx, kind templates.MessageKind, text string) error {
+	return sendHTML(c, templates.NewCategoryMessage(kind, text))
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"net/url"
+	"newsteller/internal/config"
+	"newsteller/internal/models"
+	"newsteller/internal/newsletter"
+	"newsteller/internal/repositories"
+	"newsteller/internal/templates"
+	"newsteller/internal/tokens"
+	"time"
+)
+
+// transparentGIF - 1x1 transparent GIF served as the open pixel
+var transparentGIF = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+type Tracking struct {
+	cfg         *config.Config
+	events      *repositories.TrackingEvent
+	subscribers *repositories.Subscriber
+	links       *newsletter.Links
+}
+
+func NewTracking(
+	cfg *config.Config,
+	events *mongo.Collection,
+	subscribers *mongo.Collection,
+	signer *tokens.Signer,
+) *Tracking {
+	return &Tracking{
+		cfg:         cfg,
+		events:      repositories.NewTrackingEventRepository(events),
+		subscribers: repositories.NewSubscriberRepository(subscribers),
+		links:       newsletter.NewLinks(cfg, signer),
+	}
+}
+
+// GET /track/open
+// The pixel is served for invalid tokens too, so broken links never show up in emails.
+func (t *Tracking) Open(c *fiber.Ctx) error {
+	ref, err := t.links.ParseOpenToken(c.Query("token"))
+	if err == nil {
+		t.record(c.Context(), ref, models.TrackingOpen, "")
+	}
+
+	c.Set(fiber.HeaderContentType, "image/gif")
+	c.Set(fiber.HeaderCacheControl, "no-store, no-cache, must-revalidate, private")
+	return c.Send(transparentGIF)
+}
+
+// GET /track/click
+// Only signed targets are redirected to, so the endpoint is not an open redirect.
+func (t *Tracking) Click(c *fiber.Ctx) error {
+	ref, target, err := t.links.ParseClickToken(c.Query("token"))
+	if err == nil {
+		var parsed *url.URL
+		parsed, err = url.Parse(target)
+		if err == nil && parsed.Scheme != "http" && parsed.Scheme != "https" {
+			err = newsletter.ErrInvalidTrackingToken
+		}
+	}
+	if err != nil {
+		html, err := templates.
+			NewSubscriptionPage(templates.MessageError, "Invalid link", "This link is invalid.").
+			GeneratePage()
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+
+		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+		return c.Status(fiber.StatusBadRequest).SendString(html)
+	}
+
+	t.record(c.Context(), ref, models.TrackingClick, target)
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Redirect(target, fiber.StatusFound)
+}
+
+// record stores the event unless the subscriber opted out after the email was sent.
+// Failures are only logged, readers must get the pixel or the redirect anyway.
+func (t *Tracking) record(ctx context.Context, ref newsletter.TrackingRef, kind models.TrackingEventKind, target string) {
+	subscriber, err := t.subscribers.FindByID(ctx, ref.SubscriberID.Hex())
+	if errors.Is(err, mongo.ErrNoDocuments) || err == nil && subscriber.TrackingOptOut {
+		return
+	}
+	if err != nil {
+		zap.L().Error("could not find tracked subscriber", zap.String("id", ref.SubscriberID.Hex()), zap.Error(err))
+		return
+	}
+
+	err = t.events.Record(ctx, &models.TrackingEvent{
+		Kind:         kind,
+		Source:       ref.Source,
+		IssueID:      ref.IssueID,
+		SubscriberID: ref.SubscriberID,
+		PostID:       ref.PostID,
+		URL:          target,
+		CreatedAt:    time.Now(),
+	})
+	if err != nil {
+		zap.L().Error("could not record tracking event", zap.Error(err))
+	}
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/api/dto"
+	"newsteller/internal/config"
+	"newsteller/internal/models"
+	"newsteller/internal/repositories"
+	"newsteller/internal/templates"
+	"newsteller/internal/webhooks"
+	"strings"
+	"time"
+)
+
+const (
+	// deliveryLogLimit - deliveries shown on the page of a webhook
+	deliveryLogLimit      = 50
+	invalidWebhookMessage = "Please enter a valid http or https URL and pick known events."
+)
+
+type Webhook struct {
+	cfg        *config.Config
+	repo       *repositories.Webhook
+	deliveries *repositories.WebhookDelivery
+	dispatcher *webhooks.Dispatcher
+}
+
+func NewWebhook(
+	cfg *config.Config,
+	webhooksCollection *mongo.Collection,
+	deliveries *mongo.Collection,
+	dispatcher *webhooks.Dispatcher,
+) *Webhook {
+	return &Webhook{
+		cfg:        cfg,
+		repo:       repositories.NewWebhookRepository(webhooksCollection),
+		deliveries: repositories.NewWebhookDeliveryRepository(deliveries),
+		dispatcher: dispatcher,
+	}
+}
+
+// GET /webhooks
+func (w *Webhook) GetWebhooksPage(c *fiber.Ctx) error {
+	all, err := w.repo.All(c.Context())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return sendHTML(c, templates.NewWebhooks(all))
+}
+
+// POST /webhooks
+func (w *Webhook) Create(c *fiber.Ctx) error {
+	var webhookDTO dto.WebhookDTO
+	err := c.BodyParser(&webhookDTO)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	err = validateWebhook(&webhookDTO)
+	if err != nil {
+		return w.sendMessage(c, templates.MessageError, invalidWebhookMessage)
+	}
+
+	secret, err := webhooks.NewSecret()
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	id, err := w.repo.Create(c.Context(), &models.Webhook{
+		URL:         webhookDTO.URL,
+		Description: webhookDTO.Description,
+		Secret:      secret,
+		Events:      webhookEvents(webhookDTO.Events),
+		Active:      true,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Set("HX-Redirect", "/webhooks/"+id.Hex())
+	return c.SendStatus(fiber.StatusCreated)
+}
+
+// GET /webhooks/:id
+func (w *Webhook) GetWebhookPage(c *fiber.Ctx) error {
+	webhook, err := w.findWebhook(c)
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := w.deliveries.FindByWebhook(c.Context(), webhook.ID, deliveryLogLimit)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return sendHTML(c, templates.NewWebhookPage(webhook, deliveries))
+}
+
+// PUT /webhooks/:id
+func (w *Webhook) Update(c *fiber.Ctx) error {
+	webhook, err := w.findWebhook(c)
+	if err != nil {
+		return err
+	}
+
+	var webhookDTO dto.WebhookDTO
+	err = c.BodyParser(&webhookDTO)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	err = validateWebhook(&webhookDTO)
+	if err != nil {
+		return w.sendMessage(c, templates.MessageError, invalidWebhookMessage)
+	}
+
+	webhook.URL = webhookDTO.URL
+	webhook.Description = webhookDTO.Description
+	webhook.Events = webhookEvents(webhookDTO.Events)
+	webhook.Active = webhookDTO.Active
+	err = w.repo.Update(c.Context(), webhook)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return w.sendMessage(c, templates.MessageSuccess, "Saved.")
+}
+
+// DELETE /webhooks/:id
+func (w *Webhook) Delete(c *fiber.Ctx) error {
+	webhook, err := w.findWebhook(c)
+	if err != nil {
+		return err
+	}
+
+	err = w.repo.Delete(c.Context(), webhook.ID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	err = w.deliveries.DeleteByWebhook(c.Context(), webhook.ID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Set("HX-Redirect", "/webhooks")
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// POST /webhooks/deliveries/:id/redeliver
+func (w *Webhook) Redeliver(c *fiber.Ctx) error {
+	delivery, err := w.deliveries.FindByID(c.Context(), c.Params("id"))
+	if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+		return fiber.NewError(fiber.StatusNotFound, "delivery not found")
+	}
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	_, err = w.dispatcher.Redeliver(c.Context(), delivery)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Set("HX-Redirect", "/webhooks/"+delivery.WebhookID.Hex())
+	return c.SendStatus(fiber.StatusCreated)
+}
+
+// validateWebhook normalizes and validates the submitted settings.
+func validateWebhook(webhookDTO *dto.WebhookDTO) error {
+	webhookDTO.URL = strings.TrimSpace(webhookDTO.URL)
+	webhookDTO.Description = strings.TrimSpace(webhookDTO.Description)
+
+	return validator.New(validator.WithRequiredStructEnabled()).Struct(webhookDTO)
+}
+
+func (w *Webhook) findWebhook(c *fiber.Ctx) (*models.Webhook, error) {
+	webhook, err := w.repo.FindByID(c.Context(), c.Params("id"))
+	if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+		return nil, fiber.NewError(fiber.StatusNotFound, "webhook not found")
+	}
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return webhook, nil
+}
+
+func (w *Webhook) sendMessage(c *fiber.Ctx, kind templates.MessageKind, text string) error {
+	return sendHTML(c, templates.NewWebhookMessage(kind, text))
+}
+
+func webhookEvents(names []string) []models.WebhookEvent {
+	events := make([]models.WebhookEvent, 0, len(names))
+	for _, name := range names {
+		events = append(events, models.WebhookEvent(name))
+	}
+
+	return events
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"newsteller/api/handlers"
+	"newsteller/internal/antispam"
+)
+
+type Antispam struct {
+	handler *handlers.Antispam
+}
+
+func NewAntispam(guard *antispam.Guard) *Antispam {
+	return &Antispam{
+		handler: handlers.NewAntispam(guard),
+	}
+}
+
+// SetRoutes registers the challenges of protected forms, every request gets a fresh
+// one, so they must be registered before the pages cache.
+func (a *Antispam) SetRoutes(app *fiber.App) {
+	app.Get("/antispam/challenge/:form", a.handler.GetChallenge)
+}
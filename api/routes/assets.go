@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"newsteller/api/handlers"
+	"newsteller/internal/assets"
+)
+
+type Assets struct {
+	handler *handlers.Asset
+}
+
+func NewAssets() *Assets {
+	return &Assets{
+		handler: handlers.NewAsset(),
+	}
+}
+
+// SetRoutes registers the embedded assets, they carry cache headers of their own, so they
+// must be registered before the pages cache.
+func (a *Assets) SetRoutes(app *fiber.App) {
+	app.Get(assets.Prefix+":file", a.handler.Get)
+}
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/api/handlers"
+	"newsteller/internal/attachments"
+	"newsteller/internal/config"
+)
+
+type Attachments struct {
+	handler *handlers.Attachment
+}
+
+func NewAttachments(cfg *config.Config, c *mongo.Collection, storage attachments.Storage) *Attachments {
+	return &Attachments{
+		handler: handlers.NewAttachment(cfg, c, storage),
+	}
+}
+
+// SetRoutes registers the uploads and the files of posts, which are cached by clients
+// instead of the pages cache, so they must be registered before it.
+func (a *Attachments) SetRoutes(app *fiber.App) {
+	attachmentsGroup := app.Group("/attachments")
+	attachmentsGroup.Get("/", a.handler.List)
+	attachmentsGroup.Post("/", a.handler.Upload)
+	attachmentsGroup.Get("/:key", a.handler.Get)
+	attachmentsGroup.Delete("/:key", a.handler.Delete)
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/api/handlers"
+	"newsteller/internal/config"
+)
+
+type Bounces struct {
+	handler *handlers.Bounces
+}
+
+func NewBounces(cfg *config.Config, bounces *mongo.Collection, subscribers *mongo.Collection) *Bounces {
+	return &Bounces{
+		handler: handlers.NewBounces(cfg, bounces, subscribers),
+	}
+}
+
+func (b *Bounces) SetRoutes(app *fiber.App) {
+	app.Post("/bounces", b.handler.Receive)
+}
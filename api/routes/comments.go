@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/api/handlers"
+	"newsteller/internal/antispam"
+	"newsteller/internal/comments"
+	"newsteller/internal/config"
+)
+
+type Comments struct {
+	handler *handlers.Comment
+}
+
+func NewComments(
+	cfg *config.Config,
+	posts *mongo.Collection,
+	comments *comments.Comments,
+	guard *antispam.Guard,
+) *Comments {
+	return &Comments{
+		handler: handlers.NewComment(cfg, posts, comments, guard),
+	}
+}
+
+// SetRoutes registers the comment fragments loaded into the cached post and moderation
+// pages, they change with every comment, so they must be registered before the pages cache.
+func (cm *Comments) SetRoutes(app *fiber.App) {
+	app.Get("/posts/:id/comments", cm.handler.GetSection)
+	app.Post("/posts/:id/comments", cm.handler.Create)
+
+	commentsGroup := app.Group("/comments")
+	commentsGroup.Get("/moderation", cm.handler.GetQueue)
+	commentsGroup.Post("/:id/approve", cm.handler.Approve)
+	commentsGroup.Post("/:id/spam", cm.handler.MarkSpam)
+	commentsGroup.Delete("/:id", cm.handler.Delete)
+}
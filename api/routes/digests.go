@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/api/handlers"
+	"newsteller/internal/config"
+	"newsteller/internal/tokens"
+)
+
+type Digests struct {
+	handler *handlers.Digest
+}
+
+func NewDigests(cfg *config.Config, posts *mongo.Collection, signer *tokens.Signer) *Digests {
+	return &Digests{
+		handler: handlers.NewDigest(cfg, posts, signer),
+	}
+}
+
+func (d *Digests) SetRoutes(app *fiber.App) {
+	digestsGroup := app.Group("/digests")
+	digestsGroup.Get("/preview", d.handler.GetPreviewPage)
+}
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/api/handlers"
+	"newsteller/internal/cache"
+	"newsteller/internal/config"
+)
+
+type Feeds struct {
+	handler *handlers.Feed
+}
+
+func NewFeeds(cfg *config.Config, posts *mongo.Collection, cache *cache.PagesCache) *Feeds {
+	return &Feeds{
+		handler: handlers.NewFeed(cfg, posts, cache),
+	}
+}
+
+// SetRoutes registers the site feeds and the feeds of every tag and author.
+// Feeds are cached by the handler, so they must be registered before the pages cache.
+func (f *Feeds) SetRoutes(app *fiber.App) {
+	for _, prefix := range []string{"", "/tags/:tag", "/authors/:author"} {
+		app.Get(prefix+"/feed.xml", f.handler.GetRSS)
+		app.Get(prefix+"/atom.xml", f.handler.GetAtom)
+		app.Get(prefix+"/feed.json", f.handler.GetJSON)
+	}
+}
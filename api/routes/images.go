@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/api/handlers"
+	"newsteller/internal/attachments"
+	"newsteller/internal/config"
+)
+
+type Images struct {
+	handler *handlers.Image
+}
+
+func NewImages(cfg *config.Config, c *mongo.Collection, storage attachments.Storage) *Images {
+	return &Images{
+		handler: handlers.NewImage(cfg, c, storage),
+	}
+}
+
+// SetRoutes registers the resized images, cached on disk and by clients, so they must be
+// registered before the pages cache.
+func (i *Images) SetRoutes(app *fiber.App) {
+	app.Get("/images/:key/:width", i.handler.GetVariant)
+}
@@ -0,0 +1,47 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/api/handlers"
+	"newsteller/internal/config"
+	"newsteller/internal/mailer"
+	"newsteller/internal/tokens"
+)
+
+type Issues struct {
+	handler *handlers.Issue
+}
+
+func NewIssues(
+	cfg *config.Config,
+	issues *mongo.Collection,
+	subscribers *mongo.Collection,
+	posts *mongo.Collection,
+	signer *tokens.Signer,
+	mailer *mailer.Mailer,
+) *Issues {
+	return &Issues{
+		handler: handlers.NewIssue(cfg, issues, subscribers, posts, signer, mailer),
+	}
+}
+
+func (i *Issues) SetRoutes(app *fiber.App) {
+	issuesGroup := app.Group("/issues")
+	issuesGroup.Get("/", i.handler.GetIssuesPage)
+	issuesGroup.Post("/", i.handler.Create)
+	issuesGroup.Get("/:id/edit", i.handler.GetEditorPage)
+	issuesGroup.Put("/:id", i.handler.Update)
+	issuesGroup.Delete("/:id", i.handler.Delete)
+	issuesGroup.Get("/:id/picker", i.handler.GetPostPicker)
+	issuesGroup.Post("/:id/posts", i.handler.AddPost)
+	issuesGroup.Delete("/:id/posts/:postID", i.handler.RemovePost)
+	issuesGroup.Post("/:id/posts/:postID/move", i.handler.MovePost)
+	issuesGroup.Post("/:id/test", i.handler.TestSend)
+	issuesGroup.Post("/:id/schedule", i.handler.Schedule)
+	issuesGroup.Post("/:id/unschedule", i.handler.Unschedule)
+
+	archiveGroup := app.Group("/newsletter")
+	archiveGroup.Get("/", i.handler.GetArchivePage)
+	archiveGroup.Get("/:id", i.handler.GetArchivedIssue)
+}
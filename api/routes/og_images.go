@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/api/handlers"
+	"newsteller/internal/config"
+	"newsteller/internal/ogimage"
+)
+
+type OGImages struct {
+	handler *handlers.OGImage
+}
+
+func NewOGImages(cfg *config.Config, posts *mongo.Collection, store ogimage.Store) *OGImages {
+	return &OGImages{
+		handler: handlers.NewOGImage(cfg, posts, store),
+	}
+}
+
+// SetRoutes registers the post preview cards, cached by their store, so they must be
+// registered before the pages cache.
+func (o *OGImages) SetRoutes(app *fiber.App) {
+	app.Get("/posts/:id/og.png", o.handler.GetImage)
+}
@@ -12,27 +12,42 @@ import (
 type Pages struct {
 	handler *handlers.Page
 	cache   *cache.PagesCache
+	// reload - templates change while running, pages are not cached
+	reload bool
 }
 
-func NewPages(cfg *config.Config, c *mongo.Collection, cache *cache.PagesCache) *Pages {
+func NewPages(
+	cfg *config.Config,
+	c *mongo.Collection,
+	tags *mongo.Collection,
+	categories *mongo.Collection,
+	cache *cache.PagesCache,
+) *Pages {
 	return &Pages{
-		handler: handlers.NewPage(cfg, c, cache),
+		handler: handlers.NewPage(cfg, c, tags, categories, cache),
 		cache:   cache,
+		reload:  cfg.Templates.Reload,
 	}
 }
 
 func (p *Pages) SetRoutes(app *fiber.App) {
 	app.Use(func(c *fiber.Ctx) error {
-		if html, ok := p.cache.Get(c.Request().URI().String()); ok {
-			c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
-			return c.SendString(html)
+		if p.reload {
+			return c.Next()
+		}
+		if page, ok := p.cache.Get(c.Request().URI().String()); ok {
+			c.Set(fiber.HeaderContentType, page.ContentType)
+			return c.SendString(page.Body)
 		}
 		err := c.Next()
 		if err != nil {
 			return err
 		}
 		if c.Response().StatusCode() >= 200 && c.Response().StatusCode() < 300 {
-			p.cache.Set(c.Request().URI().String(), string(c.Response().Body()))
+			p.cache.Set(c.Request().URI().String(), cache.Page{
+				Body:        string(c.Response().Body()),
+				ContentType: string(c.Response().Header.ContentType()),
+			})
 		}
 
 		return nil
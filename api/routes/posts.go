@@ -4,17 +4,17 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/mongo"
 	"newsteller/api/handlers"
-	"newsteller/internal/cache"
 	"newsteller/internal/config"
+	"newsteller/internal/events"
 )
 
 type Posts struct {
 	handler *handlers.Post
 }
 
-func NewPosts(cfg *config.Config, c *mongo.Collection, cache *cache.PagesCache) *Posts {
+func NewPosts(cfg *config.Config, c *mongo.Collection, categories *mongo.Collection, outbox *events.Outbox) *Posts {
 	return &Posts{
-		handler: handlers.NewPost(cfg, c, cache),
+		handler: handlers.NewPost(cfg, c, categories, outbox),
 	}
 }
 
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"newsteller/api/handlers"
+	"newsteller/internal/config"
+	"newsteller/internal/ratelimit"
+)
+
+type RateLimit struct {
+	cfg     *config.Config
+	handler *handlers.RateLimit
+}
+
+func NewRateLimit(cfg *config.Config, store ratelimit.Store) *RateLimit {
+	return &RateLimit{
+		cfg:     cfg,
+		handler: handlers.NewRateLimit(cfg, store),
+	}
+}
+
+// SetRoutes limits every route, including the cached pages, so it must be registered first.
+func (r *RateLimit) SetRoutes(app *fiber.App) {
+	if !r.cfg.RateLimit.Enabled {
+		return
+	}
+
+	app.Use(r.handler.Limit)
+}
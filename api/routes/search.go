@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/api/handlers"
+	"newsteller/internal/config"
+	"newsteller/internal/search"
+)
+
+type Search struct {
+	handler *handlers.Search
+}
+
+func NewSearch(cfg *config.Config, queries *mongo.Collection, posts *mongo.Collection, index *search.TitleIndex) *Search {
+	return &Search{
+		handler: handlers.NewSearch(cfg, queries, posts, index),
+	}
+}
+
+func (s *Search) SetRoutes(app *fiber.App) {
+	searchGroup := app.Group("/search")
+	searchGroup.Get("/suggestions", s.handler.Suggestions)
+	searchGroup.Post("/queries", s.handler.RecordQuery)
+}
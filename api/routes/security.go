@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"newsteller/api/handlers"
+	"newsteller/internal/config"
+	"newsteller/internal/tokens"
+)
+
+type Security struct {
+	cfg     *config.Config
+	handler *handlers.Security
+}
+
+func NewSecurity(cfg *config.Config, signer *tokens.Signer) *Security {
+	return &Security{
+		cfg:     cfg,
+		handler: handlers.NewSecurity(cfg, signer),
+	}
+}
+
+// SetRoutes protects every route, including the cached pages, so it must be registered first.
+func (s *Security) SetRoutes(app *fiber.App) {
+	app.Use(s.handler.Headers)
+	if s.cfg.Security.CSRF {
+		app.Use(s.handler.CSRF)
+	}
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/api/handlers"
+	"newsteller/internal/cache"
+	"newsteller/internal/config"
+)
+
+type Sitemap struct {
+	handler *handlers.Sitemap
+}
+
+func NewSitemap(cfg *config.Config, posts *mongo.Collection, cache *cache.PagesCache) *Sitemap {
+	return &Sitemap{
+		handler: handlers.NewSitemap(cfg, posts, cache),
+	}
+}
+
+func (s *Sitemap) SetRoutes(app *fiber.App) {
+	app.Get("/sitemap.xml", s.handler.GetSitemap)
+	app.Get("/sitemap-:part.xml", s.handler.GetSitemap)
+	app.Get("/robots.txt", s.handler.GetRobots)
+}
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/api/handlers"
+	"newsteller/internal/config"
+)
+
+type Stats struct {
+	handler *handlers.Stats
+}
+
+func NewStats(
+	cfg *config.Config,
+	events *mongo.Collection,
+	issues *mongo.Collection,
+	posts *mongo.Collection,
+) *Stats {
+	return &Stats{
+		handler: handlers.NewStats(cfg, events, issues, posts),
+	}
+}
+
+func (s *Stats) SetRoutes(app *fiber.App) {
+	statsGroup := app.Group("/stats")
+	statsGroup.Get("/", s.handler.GetStatsPage)
+	statsGroup.Get("/issues/:id", s.handler.GetIssueStatsPage)
+}
@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/api/handlers"
+	"newsteller/internal/antispam"
+	"newsteller/internal/config"
+	"newsteller/internal/mailer"
+	"newsteller/internal/tokens"
+)
+
+type Subscribers struct {
+	handler *handlers.Subscriber
+}
+
+func NewSubscribers(
+	cfg *config.Config,
+	c *mongo.Collection,
+	posts *mongo.Collection,
+	categories *mongo.Collection,
+	signer *tokens.Signer,
+	mailer *mailer.Mailer,
+	guard *antispam.Guard,
+) *Subscribers {
+	return &Subscribers{
+		handler: handlers.NewSubscriber(cfg, c, posts, categories, signer, mailer, guard),
+	}
+}
+
+func (s *Subscribers) SetRoutes(app *fiber.App) {
+	subscribersGroup := app.Group("/subscribers")
+	subscribersGroup.Post("/", s.handler.Subscribe)
+	subscribersGroup.Get("/confirm", s.handler.Confirm)
+	subscribersGroup.Get("/unsubscribe", s.handler.GetUnsubscribePage)
+	subscribersGroup.Post("/unsubscribe", s.handler.Unsubscribe)
+	subscribersGroup.Get("/preferences", s.handler.GetPreferencesPage)
+	subscribersGroup.Post("/preferences", s.handler.UpdatePreferences)
+	subscribersGroup.Get("/moderation", s.handler.GetModerationPage)
+	subscribersGroup.Post("/:id/reactivate", s.handler.Reactivate)
+}
@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/api/handlers"
+	"newsteller/internal/cache"
+	"newsteller/internal/config"
+)
+
+type Taxonomy struct {
+	handler *handlers.Taxonomy
+}
+
+func NewTaxonomy(
+	cfg *config.Config,
+	posts *mongo.Collection,
+	categories *mongo.Collection,
+	cache *cache.PagesCache,
+) *Taxonomy {
+	return &Taxonomy{
+		handler: handlers.NewTaxonomy(cfg, posts, categories, cache),
+	}
+}
+
+// SetRoutes registers the pages of tags and categories, they are not cached as they
+// depend on categories besides posts, so they must be registered before the pages cache.
+func (t *Taxonomy) SetRoutes(app *fiber.App) {
+	app.Get("/tags/:tag", t.handler.GetTagPage)
+
+	categoriesGroup := app.Group("/categories")
+	categoriesGroup.Get("/", t.handler.GetCategoriesPage)
+	categoriesGroup.Post("/", t.handler.CreateCategory)
+	categoriesGroup.Get("/:slug", t.handler.GetCategoryPage)
+	categoriesGroup.Delete("/:id", t.handler.DeleteCategory)
+}
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/api/handlers"
+	"newsteller/internal/config"
+	"newsteller/internal/tokens"
+)
+
+type Tracking struct {
+	handler *handlers.Tracking
+}
+
+func NewTracking(
+	cfg *config.Config,
+	events *mongo.Collection,
+	subscribers *mongo.Collection,
+	signer *tokens.Signer,
+) *Tracking {
+	return &Tracking{
+		handler: handlers.NewTracking(cfg, events, subscribers, signer),
+	}
+}
+
+func (t *Tracking) SetRoutes(app *fiber.App) {
+	trackingGroup := app.Group("/track")
+	trackingGroup.Get("/open", t.handler.Open)
+	trackingGroup.Get("/click", t.handler.Click)
+}
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/api/handlers"
+	"newsteller/internal/config"
+	"newsteller/internal/webhooks"
+)
+
+type Webhooks struct {
+	handler *handlers.Webhook
+}
+
+func NewWebhooks(
+	cfg *config.Config,
+	webhooksCollection *mongo.Collection,
+	deliveries *mongo.Collection,
+	dispatcher *webhooks.Dispatcher,
+) *Webhooks {
+	return &Webhooks{
+		handler: handlers.NewWebhook(cfg, webhooksCollection, deliveries, dispatcher),
+	}
+}
+
+func (w *Webhooks) SetRoutes(app *fiber.App) {
+	webhooksGroup := app.Group("/webhooks")
+	webhooksGroup.Get("/", w.handler.GetWebhooksPage)
+	webhooksGroup.Post("/", w.handler.Create)
+	webhooksGroup.Get("/:id", w.handler.GetWebhookPage)
+	webhooksGroup.Put("/:id", w.handler.Update)
+	webhooksGroup.Delete("/:id", w.handler.Delete)
+	webhooksGroup.Post("/deliveries/:id/redeliver", w.handler.Redeliver)
+}
@@ -7,12 +7,30 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.uber.org/zap"
 	"newsteller/api/routes"
+	"newsteller/internal/antispam"
+	"newsteller/internal/attachments"
+	"newsteller/internal/bounces"
 	"newsteller/internal/cache"
+	"newsteller/internal/comments"
 	"newsteller/internal/config"
 	"newsteller/internal/db"
+	"newsteller/internal/events"
+	"newsteller/internal/mailer"
 	"newsteller/internal/models"
+	"newsteller/internal/newsletter"
+	"newsteller/internal/ogimage"
+	"newsteller/internal/ratelimit"
+	"newsteller/internal/repositories"
+	"newsteller/internal/search"
+	"newsteller/internal/taxonomy"
+	"newsteller/internal/templates"
+	"newsteller/internal/theme"
+	"newsteller/internal/tokens"
+	"newsteller/internal/webhooks"
 	"os/signal"
+	"strings"
 	"syscall"
+	_ "time/tzdata"
 )
 
 var (
@@ -31,6 +49,16 @@ func main() {
 		panic(fmt.Sprintf("failed to read config: %v", err))
 	}
 
+	// broken overrides stop the start instead of failing pages
+	if err := templates.Load(cfg.Templates.Dir, cfg.Templates.Reload); err != nil {
+		panic(fmt.Sprintf("failed to load templates: %v", err))
+	}
+	siteTheme, err := theme.Load(cfg)
+	if err != nil {
+		panic(fmt.Sprintf("failed to load theme: %v", err))
+	}
+	templates.SetTheme(siteTheme)
+
 	client, err := db.Connect(ctx, cfg)
 	if err != nil {
 		zap.L().Fatal("failed to connect to database", zap.Error(err))
@@ -42,7 +70,21 @@ func main() {
 		}
 	}()
 
-	go createWebServer(cfg, client)
+	// signer is shared, links signed in background must be valid in handlers
+	signer := tokens.NewSigner(cfg.Secret)
+
+	// subscribers are registered before the relay starts publishing
+	bus := events.NewBus()
+	createWebServer(cfg, client, signer, bus)
+	go runWebServer(cfg)
+	go runEventRelay(ctx, cfg, client, bus)
+	go runMailWorker(ctx, cfg, client)
+	go runWebhookWorker(ctx, cfg, client)
+	go runDigestScheduler(ctx, cfg, client, signer)
+	go runIssueSender(ctx, cfg, client, signer)
+	if cfg.Mail.Bounces.Maildir != "" {
+		go runBounceMaildir(ctx, cfg, client)
+	}
 
 	for range ctx.Done() {
 		_ = webApp.ShutdownWithContext(ctx)
@@ -50,24 +92,233 @@ func main() {
 	}
 }
 
-func createWebServer(cfg *config.Config, client *mongo.Client) {
-	webApp = fiber.New()
-	setupWebServer(cfg, webApp, client)
+func createWebServer(cfg *config.Config, client *mongo.Client, signer *tokens.Signer, bus *events.Bus) {
+	var trustedProxies []string
+	for _, proxy := range strings.Split(cfg.TrustedProxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+	if cfg.ProxyHeader != "" && len(trustedProxies) == 0 {
+		zap.L().Fatal("TRUSTED_PROXIES must list the proxies passing PROXY_HEADER, anyone could send it otherwise")
+	}
+
+	webApp = fiber.New(fiber.Config{
+		// uploads carry the form fields besides the file
+		BodyLimit: max(fiber.DefaultBodyLimit, int(cfg.Attachments.MaxSize)+1<<20),
+		// rate limits tell clients apart by their address, the header is only read from
+		// connections of the trusted proxies and must hold a valid address
+		ProxyHeader:             cfg.ProxyHeader,
+		EnableTrustedProxyCheck: cfg.ProxyHeader != "",
+		TrustedProxies:          trustedProxies,
+		EnableIPValidation:      true,
+	})
+	setupWebServer(cfg, webApp, client, signer, bus)
+}
 
+func runWebServer(cfg *config.Config) {
 	if err := webApp.Listen(":" + cfg.Port); err != nil {
 		zap.L().Fatal("failed to start server", zap.Error(err))
 	}
 }
 
-func setupWebServer(cfg *config.Config, app *fiber.App, client *mongo.Client) {
+func runEventRelay(ctx context.Context, cfg *config.Config, client *mongo.Client, bus *events.Bus) {
+	database := client.Database(cfg.Database.Name)
+
+	events.NewRelay(events.NewOutbox(
+		cfg,
+		database.Collection(models.OutboxEvent{}.CollectionName()),
+		database.Collection(models.Post{}.CollectionName()),
+		bus,
+	)).Run(ctx)
+}
+
+func runMailWorker(ctx context.Context, cfg *config.Config, client *mongo.Client) {
+	transport, err := mailer.NewTransport(cfg)
+	if err != nil {
+		zap.L().Fatal("failed to create mail transport", zap.Error(err))
+	}
+	database := client.Database(cfg.Database.Name)
+
+	mailer.NewWorker(
+		cfg,
+		database.Collection(models.OutboxMessage{}.CollectionName()),
+		database.Collection(models.Subscriber{}.CollectionName()),
+		transport,
+	).Run(ctx)
+}
+
+func runWebhookWorker(ctx context.Context, cfg *config.Config, client *mongo.Client) {
+	database := client.Database(cfg.Database.Name)
+
+	webhooks.NewWorker(
+		cfg,
+		database.Collection(models.Webhook{}.CollectionName()),
+		database.Collection(models.WebhookDelivery{}.CollectionName()),
+	).Run(ctx)
+}
+
+func runDigestScheduler(ctx context.Context, cfg *config.Config, client *mongo.Client, signer *tokens.Signer) {
+	database := client.Database(cfg.Database.Name)
+
+	newsletter.NewScheduler(
+		cfg,
+		database.Collection(models.Subscriber{}.CollectionName()),
+		database.Collection(models.DigestSend{}.CollectionName()),
+		database.Collection(models.Post{}.CollectionName()),
+		database.Collection(models.Category{}.CollectionName()),
+		signer,
+		mailer.New(database.Collection(models.OutboxMessage{}.CollectionName())),
+	).Run(ctx)
+}
+
+func runIssueSender(ctx context.Context, cfg *config.Config, client *mongo.Client, signer *tokens.Signer) {
+	database := client.Database(cfg.Database.Name)
+
+	newsletter.NewIssueSender(
+		cfg,
+		database.Collection(models.Issue{}.CollectionName()),
+		database.Collection(models.Subscriber{}.CollectionName()),
+		database.Collection(models.Post{}.CollectionName()),
+		database.Collection(models.Category{}.CollectionName()),
+		signer,
+		mailer.New(database.Collection(models.OutboxMessage{}.CollectionName())),
+	).Run(ctx)
+}
+
+func runBounceMaildir(ctx context.Context, cfg *config.Config, client *mongo.Client) {
+	database := client.Database(cfg.Database.Name)
+	processor := bounces.NewProcessor(
+		cfg,
+		database.Collection(models.Bounce{}.CollectionName()),
+		database.Collection(models.Subscriber{}.CollectionName()),
+	)
+
+	bounces.NewMaildir(cfg, processor).Run(ctx)
+}
+
+func setupWebServer(cfg *config.Config, app *fiber.App, client *mongo.Client, signer *tokens.Signer, bus *events.Bus) {
 	pagesCache := cache.NewPagesCache()
 	postsCollection := client.
 		Database(cfg.Database.Name).
 		Collection(models.Post{}.CollectionName())
+	searchQueriesCollection := client.
+		Database(cfg.Database.Name).
+		Collection(models.SearchQuery{}.CollectionName())
+	subscribersCollection := client.
+		Database(cfg.Database.Name).
+		Collection(models.Subscriber{}.CollectionName())
+	outboxCollection := client.
+		Database(cfg.Database.Name).
+		Collection(models.OutboxMessage{}.CollectionName())
+	issuesCollection := client.
+		Database(cfg.Database.Name).
+		Collection(models.Issue{}.CollectionName())
+	trackingEventsCollection := client.
+		Database(cfg.Database.Name).
+		Collection(models.TrackingEvent{}.CollectionName())
+	bouncesCollection := client.
+		Database(cfg.Database.Name).
+		Collection(models.Bounce{}.CollectionName())
+	webhooksCollection := client.
+		Database(cfg.Database.Name).
+		Collection(models.Webhook{}.CollectionName())
+	webhookDeliveriesCollection := client.
+		Database(cfg.Database.Name).
+		Collection(models.WebhookDelivery{}.CollectionName())
+	eventOutboxCollection := client.
+		Database(cfg.Database.Name).
+		Collection(models.OutboxEvent{}.CollectionName())
+	tagsCollection := client.
+		Database(cfg.Database.Name).
+		Collection(models.Tag{}.CollectionName())
+	categoriesCollection := client.
+		Database(cfg.Database.Name).
+		Collection(models.Category{}.CollectionName())
+	attachmentsCollection := client.
+		Database(cfg.Database.Name).
+		Collection(models.Attachment{}.CollectionName())
+	commentsCollection := client.
+		Database(cfg.Database.Name).
+		Collection(models.Comment{}.CollectionName())
+	spamTokensCollection := client.
+		Database(cfg.Database.Name).
+		Collection(models.SpamToken{}.CollectionName())
+	mail := mailer.New(outboxCollection)
+	postComments := comments.New(cfg, commentsCollection, postsCollection, mail, antispam.NewScorer(spamTokensCollection))
+	webhookDispatcher := webhooks.NewDispatcher(cfg, webhooksCollection, webhookDeliveriesCollection)
+	eventOutbox := events.NewOutbox(cfg, eventOutboxCollection, postsCollection, bus)
+	ogImageStore, err := ogimage.NewStore(cfg, client.Database(cfg.Database.Name))
+	if err != nil {
+		zap.L().Fatal("failed to create social image store", zap.Error(err))
+	}
+	attachmentStorage, err := attachments.NewStorage(context.Background(), cfg, client.Database(cfg.Database.Name))
+	if err != nil {
+		zap.L().Fatal("failed to create attachment storage", zap.Error(err))
+	}
+	rateLimitStore, err := ratelimit.NewStore(context.Background(), cfg)
+	if err != nil {
+		zap.L().Fatal("failed to create rate limit store", zap.Error(err))
+	}
+	guard := antispam.NewGuard(cfg, signer, rateLimitStore)
+
+	postRepository := repositories.NewPostRepository(postsCollection)
+	// posts created before slugs existed get one before links to them are rendered
+	backfilled, err := postRepository.BackfillSlugs(context.Background())
+	if err != nil {
+		zap.L().Error("failed to backfill post slugs", zap.Error(err))
+	} else if backfilled > 0 {
+		zap.L().Info("backfilled post slugs", zap.Int("posts", backfilled))
+	}
+
+	titleIndex := search.NewTitleIndex()
+	posts, err := postRepository.All(context.Background())
+	if err != nil {
+		zap.L().Error("failed to build search index", zap.Error(err))
+	}
+	titleIndex.Rebuild(posts)
+
+	tags := taxonomy.NewTags(tagsCollection)
+	// tags of posts saved before tags were registered are offered by the tag picker too
+	usedTags, err := postRepository.Tags(context.Background())
+	if err == nil {
+		err = tags.Register(context.Background(), usedTags)
+	}
+	if err != nil {
+		zap.L().Error("failed to register post tags", zap.Error(err))
+	}
+
+	// tags are registered before the cache drops the post forms offering them
+	tags.Subscribe(bus)
+	pagesCache.Subscribe(bus)
+	titleIndex.Subscribe(bus)
+	webhookDispatcher.Subscribe(bus)
+	newsletter.NewPostNotifier(cfg, mail).Subscribe(bus)
+	postComments.Subscribe(bus)
+	ogimage.NewGenerator(cfg, ogImageStore).Subscribe(bus)
 
 	routes.New().InitializeRoutes(
 		app,
-		routes.NewPosts(cfg, postsCollection, pagesCache),
-		routes.NewPages(cfg, postsCollection, pagesCache),
+		routes.NewSecurity(cfg, signer),
+		routes.NewRateLimit(cfg, rateLimitStore),
+		routes.NewPosts(cfg, postsCollection, categoriesCollection, eventOutbox),
+		routes.NewSearch(cfg, searchQueriesCollection, postsCollection, titleIndex),
+		routes.NewSubscribers(cfg, subscribersCollection, postsCollection, categoriesCollection, signer, mail, guard),
+		routes.NewDigests(cfg, postsCollection, signer),
+		routes.NewIssues(cfg, issuesCollection, subscribersCollection, postsCollection, signer, mail),
+		routes.NewTracking(cfg, trackingEventsCollection, subscribersCollection, signer),
+		routes.NewStats(cfg, trackingEventsCollection, issuesCollection, postsCollection),
+		routes.NewBounces(cfg, bouncesCollection, subscribersCollection),
+		routes.NewFeeds(cfg, postsCollection, pagesCache),
+		routes.NewSitemap(cfg, postsCollection, pagesCache),
+		routes.NewOGImages(cfg, postsCollection, ogImageStore),
+		routes.NewWebhooks(cfg, webhooksCollection, webhookDeliveriesCollection, webhookDispatcher),
+		routes.NewTaxonomy(cfg, postsCollection, categoriesCollection, pagesCache),
+		routes.NewAttachments(cfg, attachmentsCollection, attachmentStorage),
+		routes.NewImages(cfg, attachmentsCollection, attachmentStorage),
+		routes.NewComments(cfg, postsCollection, postComments, guard),
+		routes.NewAntispam(guard),
+		routes.NewAssets(),
+		routes.NewPages(cfg, postsCollection, tagsCollection, categoriesCollection, pagesCache),
 	)
 }
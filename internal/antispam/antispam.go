@@ -0,0 +1,164 @@
+package antispam
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"go.uber.org/zap"
+	"newsteller/internal/config"
+	"newsteller/internal/ratelimit"
+	"newsteller/internal/tokens"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/**
+Spam protection of the public forms, without third-party CAPTCHAs. Forms carry a signed
+challenge issued when they are shown and a honeypot field hidden from people. Submissions
+are rejected when the honeypot is filled, when they come sooner than MinSubmitTime after
+the form was shown, when the browser did not solve the proof of work of the challenge, when
+the challenge was already used and when the IP address submitted the form too often. Used
+challenges and submissions are counted by the rate limit store, shared by the replicas when
+it is redis.
+Comments passing the checks are scored by a Bayesian filter trained from moderator decisions.
+*/
+
+// Forms protected by the guard, the name is part of their challenges.
+const (
+	FormComment   = "comment"
+	FormSubscribe = "subscribe"
+)
+
+// Forms - names of the protected forms
+var Forms = []string{FormComment, FormSubscribe}
+
+// Fields of the protected forms.
+const (
+	// HoneypotField - hidden field left empty by people, bots fill every field
+	HoneypotField  = "website"
+	ChallengeField = "challenge"
+	ProofField     = "proof"
+)
+
+var (
+	ErrHoneypot         = errors.New("honeypot field filled")
+	ErrInvalidChallenge = errors.New("invalid form challenge")
+	ErrTooFast          = errors.New("form submitted too fast")
+	ErrExpired          = errors.New("form expired")
+	ErrInvalidProof     = errors.New("invalid proof of work")
+	ErrReplayed         = errors.New("form challenge already used")
+	ErrRateLimited      = errors.New("too many submissions")
+)
+
+// Challenge is issued to a form being shown, browsers solve its proof of work.
+type Challenge struct {
+	Form       string
+	Token      string
+	Difficulty int
+}
+
+// Submission holds the anti-spam fields of a submitted form.
+type Submission struct {
+	IP        string
+	Honeypot  string
+	Challenge string
+	Proof     string
+}
+
+// Guard issues and checks the challenges of the protected forms.
+type Guard struct {
+	cfg     *config.Config
+	signer  *tokens.Signer
+	limiter *ratelimit.Limiter
+	now     func() time.Time
+}
+
+func NewGuard(cfg *config.Config, signer *tokens.Signer, store ratelimit.Store) *Guard {
+	return &Guard{
+		cfg:     cfg,
+		signer:  signer,
+		limiter: ratelimit.NewLimiter(store),
+		now:     time.Now,
+	}
+}
+
+// Challenge issues the challenge of the form, it is empty when the guard is disabled.
+func (g *Guard) Challenge(form string) Challenge {
+	if !g.cfg.Antispam.Enabled {
+		return Challenge{Form: form}
+	}
+
+	// challenges of forms shown at once differ
+	nonce := make([]byte, 8)
+	_, _ = rand.Read(nonce)
+	now := g.now()
+	subject := form + "|" + strconv.FormatInt(now.UnixMilli(), 10) + "|" + hex.EncodeToString(nonce)
+
+	return Challenge{
+		Form:       form,
+		Token:      g.signer.Sign(tokens.PurposeFormChallenge, subject, now.Add(g.cfg.Antispam.MaxFormAge)),
+		Difficulty: g.cfg.Antispam.Difficulty,
+	}
+}
+
+// Check returns an error when the submission of the form looks automated. The challenge
+// of an accepted submission can not be used again.
+func (g *Guard) Check(ctx context.Context, form string, submission Submission) error {
+	if !g.cfg.Antispam.Enabled {
+		return nil
+	}
+
+	// rejected submissions count too, bots retrying must not get more attempts
+	submissions := ratelimit.Rule{Name: "antispam:" + form, Requests: g.cfg.Antispam.Submissions, Window: g.cfg.Antispam.Window}
+	if !g.take(ctx, submissions, submission.IP) {
+		return ErrRateLimited
+	}
+	if submission.Honeypot != "" {
+		return ErrHoneypot
+	}
+
+	subject, err := g.signer.Verify(tokens.PurposeFormChallenge, submission.Challenge)
+	if errors.Is(err, tokens.ErrExpiredToken) {
+		return ErrExpired
+	}
+	if err != nil {
+		return ErrInvalidChallenge
+	}
+	parts := strings.Split(subject, "|")
+	if len(parts) != 3 || parts[0] != form {
+		return ErrInvalidChallenge
+	}
+	shownAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return ErrInvalidChallenge
+	}
+	now := g.now()
+	if now.Sub(time.UnixMilli(shownAt)) < g.cfg.Antispam.MinSubmitTime {
+		return ErrTooFast
+	}
+	if !Solved(submission.Challenge, submission.Proof, g.cfg.Antispam.Difficulty) {
+		return ErrInvalidProof
+	}
+
+	// a used challenge stays counted for at least a window of the form age, it expires sooner
+	used := ratelimit.Rule{Name: "antispam:challenge", Requests: 1, Window: g.cfg.Antispam.MaxFormAge}
+	sum := sha256.Sum256([]byte(submission.Challenge))
+	if !g.take(ctx, used, hex.EncodeToString(sum[:])) {
+		return ErrReplayed
+	}
+
+	return nil
+}
+
+// take counts the submission against the rule, it is allowed when the store is down.
+func (g *Guard) take(ctx context.Context, rule ratelimit.Rule, client string) bool {
+	result, err := g.limiter.Take(ctx, rule, client)
+	if err != nil {
+		zap.L().Error("failed to count form submission", zap.String("rule", rule.Name), zap.Error(err))
+	}
+
+	return result.Allowed
+}
@@ -0,0 +1,214 @@
+package antispam
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"newsteller/internal/config"
+	"newsteller/internal/models"
+	"newsteller/internal/ratelimit"
+	"newsteller/internal/tokens"
+)
+
+func newTestGuard(submissions int) (*Guard, *time.Time) {
+	return newSharedTestGuard(ratelimit.NewMemoryStore(), submissions)
+}
+
+// newSharedTestGuard returns a guard counting in the store, like replicas sharing redis
+func newSharedTestGuard(store ratelimit.Store, submissions int) (*Guard, *time.Time) {
+	cfg := &config.Config{}
+	cfg.Antispam.Enabled = true
+	cfg.Antispam.MinSubmitTime = 3 * time.Second
+	cfg.Antispam.MaxFormAge = time.Hour
+	cfg.Antispam.Difficulty = 8
+	cfg.Antispam.Submissions = submissions
+	cfg.Antispam.Window = time.Minute
+
+	now := time.Now()
+	guard := NewGuard(cfg, tokens.NewSigner("secret"), store)
+	guard.now = func() time.Time { return now }
+
+	return guard, &now
+}
+
+// solve finds the proof of the challenge like browsers do
+func solve(t *testing.T, challenge Challenge) string {
+	for nonce := 0; nonce < 1<<20; nonce++ {
+		if proof := strconv.Itoa(nonce); Solved(challenge.Token, proof, challenge.Difficulty) {
+			return proof
+		}
+	}
+	t.Fatal("no proof found")
+	return ""
+}
+
+func TestGuard_Check(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Positive: Solved challenge submitted in time", func(t *testing.T) {
+		guard, now := newTestGuard(5)
+		challenge := guard.Challenge(FormComment)
+		assert.Equal(t, FormComment, challenge.Form)
+		assert.Equal(t, 8, challenge.Difficulty)
+
+		*now = now.Add(5 * time.Second)
+		err := guard.Check(ctx, FormComment, Submission{IP: "1.2.3.4", Challenge: challenge.Token, Proof: solve(t, challenge)})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Negative: Challenge used again", func(t *testing.T) {
+		guard, now := newTestGuard(5)
+		challenge := guard.Challenge(FormComment)
+		*now = now.Add(5 * time.Second)
+		submission := Submission{IP: "1.2.3.4", Challenge: challenge.Token, Proof: solve(t, challenge)}
+
+		require.NoError(t, guard.Check(ctx, FormComment, submission))
+		assert.ErrorIs(t, guard.Check(ctx, FormComment, submission), ErrReplayed)
+	})
+
+	t.Run("Negative: Challenge used again on another replica", func(t *testing.T) {
+		store := ratelimit.NewMemoryStore()
+		guard, now := newSharedTestGuard(store, 5)
+		replica, _ := newSharedTestGuard(store, 5)
+		replica.now = guard.now
+		challenge := guard.Challenge(FormComment)
+		*now = now.Add(5 * time.Second)
+
+		submission := Submission{IP: "1.2.3.4", Challenge: challenge.Token, Proof: solve(t, challenge)}
+		require.NoError(t, guard.Check(ctx, FormComment, submission))
+		assert.ErrorIs(t, replica.Check(ctx, FormComment, submission), ErrReplayed)
+	})
+
+	t.Run("Negative: Challenge used again after traffic on another rule", func(t *testing.T) {
+		store := ratelimit.NewMemoryStore()
+		guard, now := newSharedTestGuard(store, 5)
+		challenge := guard.Challenge(FormComment)
+		*now = now.Add(5 * time.Second)
+
+		submission := Submission{IP: "1.2.3.4", Challenge: challenge.Token, Proof: solve(t, challenge)}
+		require.NoError(t, guard.Check(ctx, FormComment, submission))
+
+		// a request on a one-minute rule past the window of used challenges prunes the store
+		next := time.Now().Truncate(guard.cfg.Antispam.MaxFormAge).Add(guard.cfg.Antispam.MaxFormAge + time.Minute)
+		_, err := store.Take(ctx, "ratelimit:requests:5.6.7.8", 10, time.Minute, next, 1)
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, guard.Check(ctx, FormComment, submission), ErrReplayed)
+	})
+
+	t.Run("Negative: Submitted too fast", func(t *testing.T) {
+		guard, now := newTestGuard(5)
+		challenge := guard.Challenge(FormComment)
+		*now = now.Add(time.Second)
+
+		err := guard.Check(ctx, FormComment, Submission{IP: "1.2.3.4", Challenge: challenge.Token, Proof: solve(t, challenge)})
+		assert.ErrorIs(t, err, ErrTooFast)
+	})
+
+	t.Run("Negative: Honeypot filled", func(t *testing.T) {
+		guard, now := newTestGuard(5)
+		challenge := guard.Challenge(FormComment)
+		*now = now.Add(5 * time.Second)
+
+		err := guard.Check(ctx, FormComment, Submission{
+			IP:        "1.2.3.4",
+			Honeypot:  "https://spam.example",
+			Challenge: challenge.Token,
+			Proof:     solve(t, challenge),
+		})
+		assert.ErrorIs(t, err, ErrHoneypot)
+	})
+
+	t.Run("Negative: Challenge of another form", func(t *testing.T) {
+		guard, now := newTestGuard(5)
+		challenge := guard.Challenge(FormSubscribe)
+		*now = now.Add(5 * time.Second)
+
+		err := guard.Check(ctx, FormComment, Submission{IP: "1.2.3.4", Challenge: challenge.Token, Proof: solve(t, challenge)})
+		assert.ErrorIs(t, err, ErrInvalidChallenge)
+	})
+
+	t.Run("Negative: Forged challenge", func(t *testing.T) {
+		guard, _ := newTestGuard(5)
+
+		err := guard.Check(ctx, FormComment, Submission{IP: "1.2.3.4", Challenge: "forged", Proof: "1"})
+		assert.ErrorIs(t, err, ErrInvalidChallenge)
+	})
+
+	t.Run("Negative: Proof not solved", func(t *testing.T) {
+		guard, now := newTestGuard(5)
+		challenge := guard.Challenge(FormComment)
+		*now = now.Add(5 * time.Second)
+
+		err := guard.Check(ctx, FormComment, Submission{IP: "1.2.3.4", Challenge: challenge.Token})
+		assert.ErrorIs(t, err, ErrInvalidProof)
+	})
+
+	t.Run("Negative: Too many submissions from the address", func(t *testing.T) {
+		guard, _ := newTestGuard(2)
+
+		assert.ErrorIs(t, guard.Check(ctx, FormComment, Submission{IP: "1.2.3.4"}), ErrInvalidChallenge)
+		assert.ErrorIs(t, guard.Check(ctx, FormComment, Submission{IP: "1.2.3.4"}), ErrInvalidChallenge)
+		assert.ErrorIs(t, guard.Check(ctx, FormComment, Submission{IP: "1.2.3.4"}), ErrRateLimited)
+		assert.ErrorIs(t, guard.Check(ctx, FormComment, Submission{IP: "5.6.7.8"}), ErrInvalidChallenge, "other addresses are not limited")
+		assert.ErrorIs(t, guard.Check(ctx, FormSubscribe, Submission{IP: "1.2.3.4"}), ErrInvalidChallenge, "forms are limited apart")
+	})
+
+	t.Run("Positive: Disabled guard accepts everything", func(t *testing.T) {
+		guard, _ := newTestGuard(5)
+		guard.cfg.Antispam.Enabled = false
+
+		assert.Empty(t, guard.Challenge(FormComment).Token)
+		assert.NoError(t, guard.Check(ctx, FormComment, Submission{Honeypot: "filled"}))
+	})
+}
+
+func TestSolved(t *testing.T) {
+	challenge := Challenge{Token: "challenge", Difficulty: 12}
+	proof := solve(t, challenge)
+
+	assert.True(t, Solved("challenge", proof, 12))
+	assert.True(t, Solved("challenge", proof, 0))
+	assert.False(t, Solved("another", proof, 12))
+	assert.False(t, Solved("challenge", "", 0), "proofs can not be empty")
+	assert.False(t, Solved("challenge", "123456789012345678901", 0), "proofs can not be long")
+}
+
+func TestTokens(t *testing.T) {
+	tokens := Tokens("Cheap PILLS, cheap pills! Visit https://Spam.example/buy http://a.example http://b.example now")
+
+	assert.Equal(t, []string{
+		"cheap",
+		"host:a.example",
+		"host:b.example",
+		"host:spam.example",
+		"links:many",
+		"now",
+		"pills",
+		"visit",
+	}, tokens)
+	assert.Empty(t, Tokens("a b"))
+}
+
+func TestScore(t *testing.T) {
+	counts := map[string]models.SpamToken{
+		models.SpamMessagesToken: {Spam: 10, Ham: 10},
+		"pills":                  {Spam: 9, Ham: 0},
+		"cheap":                  {Spam: 8, Ham: 1},
+		"newsletter":             {Spam: 0, Ham: 9},
+		"thanks":                 {Spam: 1, Ham: 8},
+	}
+
+	assert.Greater(t, score([]string{"cheap", "pills"}, counts), 0.9)
+	assert.Less(t, score([]string{"newsletter", "thanks"}, counts), 0.1)
+	assert.Equal(t, 0.5, score([]string{"unknown"}, counts))
+	assert.Equal(t, 0.5, score([]string{"pills"}, map[string]models.SpamToken{
+		models.SpamMessagesToken: {Spam: 10},
+		"pills":                  {Spam: 9},
+	}), "messages should be neutral until ham was learned")
+}
@@ -0,0 +1,29 @@
+package antispam
+
+import (
+	"crypto/sha256"
+	"math/bits"
+)
+
+// maxProofLength - longest proof, a decimal counter
+const maxProofLength = 20
+
+// Solved reports whether the SHA-256 hash of "<challenge>:<proof>" starts with
+// difficulty zero bits. Finding a proof takes about 2^difficulty hashes, checking it one.
+func Solved(challenge, proof string, difficulty int) bool {
+	if proof == "" || len(proof) > maxProofLength {
+		return false
+	}
+
+	sum := sha256.Sum256([]byte(challenge + ":" + proof))
+	zeros := 0
+	for _, b := range sum {
+		if b != 0 {
+			zeros += bits.LeadingZeros8(b)
+			break
+		}
+		zeros += 8
+	}
+
+	return zeros >= difficulty
+}
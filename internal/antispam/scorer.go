@@ -0,0 +1,133 @@
+package antispam
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/mongo"
+	"math"
+	"net/url"
+	"newsteller/internal/models"
+	"newsteller/internal/repositories"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// interestingTokens - tokens deciding the score, those furthest from neutral
+	interestingTokens = 15
+	// strength and neutral of the prior of rarely seen tokens, per Gary Robinson
+	priorStrength = 1.0
+	neutral       = 0.5
+	// manyLinks - links making a message look like link spam
+	manyLinks = 3
+)
+
+var linkPattern = regexp.MustCompile(`https?://[^\s<>"']+`)
+
+// Scorer rates the probability of messages being spam with a naive Bayes filter over
+// their words, trained from the spam and ham decisions of moderators.
+type Scorer struct {
+	repo *repositories.SpamToken
+}
+
+func NewScorer(c *mongo.Collection) *Scorer {
+	return &Scorer{repo: repositories.NewSpamTokenRepository(c)}
+}
+
+// Score returns the probability of the message being spam, 0.5 until both spam and
+// ham were learned.
+func (s *Scorer) Score(ctx context.Context, text string) (float64, error) {
+	tokens := Tokens(text)
+	counts, err := s.repo.Find(ctx, append(tokens, models.SpamMessagesToken))
+	if err != nil {
+		return neutral, err
+	}
+
+	return score(tokens, counts), nil
+}
+
+// Learn trains the filter with a message decided to be spam or ham.
+func (s *Scorer) Learn(ctx context.Context, text string, spam bool) error {
+	return s.train(ctx, text, spam, 1)
+}
+
+// Forget undoes the training with a message, e.g. when a decision is reverted.
+func (s *Scorer) Forget(ctx context.Context, text string, spam bool) error {
+	return s.train(ctx, text, spam, -1)
+}
+
+func (s *Scorer) train(ctx context.Context, text string, spam bool, delta int64) error {
+	tokens := append(Tokens(text), models.SpamMessagesToken)
+	if spam {
+		return s.repo.Increment(ctx, tokens, delta, 0)
+	}
+
+	return s.repo.Increment(ctx, tokens, 0, delta)
+}
+
+// Tokens returns the distinct lowercase words of the text, the hosts of its links and
+// a token of messages carrying many links.
+func Tokens(text string) []string {
+	var tokens []string
+	links := linkPattern.FindAllString(text, -1)
+	for _, link := range links {
+		if u, err := url.Parse(link); err == nil && u.Host != "" {
+			tokens = append(tokens, "host:"+strings.ToLower(u.Hostname()))
+		}
+	}
+	if len(links) >= manyLinks {
+		tokens = append(tokens, "links:many")
+	}
+
+	words := strings.FieldsFunc(linkPattern.ReplaceAllString(text, " "), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '$'
+	})
+	for _, word := range words {
+		word = strings.ToLower(strings.Trim(word, "'"))
+		// very short words are in every message, very long ones are noise
+		if n := len([]rune(word)); n >= 3 && n <= 24 {
+			tokens = append(tokens, word)
+		}
+	}
+
+	slices.Sort(tokens)
+	return slices.Compact(tokens)
+}
+
+// score combines the spam probabilities of the most interesting tokens.
+func score(tokens []string, counts map[string]models.SpamToken) float64 {
+	messages := counts[models.SpamMessagesToken]
+	if messages.Spam <= 0 || messages.Ham <= 0 {
+		return neutral
+	}
+
+	var probabilities []float64
+	for _, token := range tokens {
+		count, ok := counts[token]
+		if !ok || count.Spam+count.Ham <= 0 {
+			continue
+		}
+		spamFrequency := float64(max(count.Spam, 0)) / float64(messages.Spam)
+		hamFrequency := float64(max(count.Ham, 0)) / float64(messages.Ham)
+		p := spamFrequency / (spamFrequency + hamFrequency)
+		// tokens seen a few times are pulled towards neutral
+		n := float64(count.Spam + count.Ham)
+		probabilities = append(probabilities, (priorStrength*neutral+n*p)/(priorStrength+n))
+	}
+	sort.Slice(probabilities, func(i, j int) bool {
+		return math.Abs(probabilities[i]-neutral) > math.Abs(probabilities[j]-neutral)
+	})
+	probabilities = probabilities[:min(len(probabilities), interestingTokens)]
+
+	// naive Bayes in log space, products of small probabilities underflow
+	var logSpam, logHam float64
+	for _, p := range probabilities {
+		p = min(max(p, 0.01), 0.99)
+		logSpam += math.Log(p)
+		logHam += math.Log(1 - p)
+	}
+
+	return 1 / (1 + math.Exp(logHam-logSpam))
+}
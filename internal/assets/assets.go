@@ -0,0 +1,124 @@
+package assets
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"embed"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"mime"
+	"path"
+	"strings"
+)
+
+/**
+Static assets of the pages, htmx and the shared CSS and JS, embedded into the binary, so pages
+load nothing from other hosts. Assets are served under Prefix with the hash of their content in
+the file name, e.g. /static/htmx.min.1a2b3c4d5e6f.js, so they are cached for good and a new
+version gets a new URL. Pages reference them by name through Path and check them with Integrity.
+*/
+
+// Prefix - path the assets are served under
+const Prefix = "/static/"
+
+//go:embed static
+var static embed.FS
+
+// Asset is an embedded file.
+type Asset struct {
+	// Name - file name, e.g. htmx.min.js
+	Name string
+	// File - file name with the hash of the content, e.g. htmx.min.1a2b3c4d5e6f.js
+	File string
+	// Integrity - subresource integrity of the content, e.g. sha384-...
+	Integrity   string
+	ContentType string
+	Data        []byte
+}
+
+var (
+	byName = map[string]*Asset{}
+	byFile = map[string]*Asset{}
+)
+
+func init() {
+	err := fs.WalkDir(static, "static", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := static.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		asset := newAsset(path.Base(p), data)
+		byName[asset.Name] = asset
+		byFile[asset.File] = asset
+
+		return nil
+	})
+	if err != nil {
+		panic(fmt.Sprintf("failed to load embedded assets: %v", err))
+	}
+}
+
+func newAsset(name string, data []byte) *Asset {
+	sum := sha256.Sum256(data)
+	integrity := sha512.Sum384(data)
+	ext := path.Ext(name)
+	contentType := mime.TypeByExtension(ext)
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return &Asset{
+		Name:        name,
+		File:        strings.TrimSuffix(name, ext) + "." + hex.EncodeToString(sum[:6]) + ext,
+		Integrity:   "sha384-" + base64.StdEncoding.EncodeToString(integrity[:]),
+		ContentType: contentType,
+		Data:        data,
+	}
+}
+
+// Add serves the data as the asset of the name, e.g. the files of a theme. Assets must be
+// added at startup, before pages are served, an asset of the same name is replaced.
+func Add(name string, data []byte) {
+	if previous, ok := byName[name]; ok {
+		delete(byFile, previous.File)
+	}
+	asset := newAsset(name, data)
+	byName[asset.Name] = asset
+	byFile[asset.File] = asset
+}
+
+// Path returns the URL path of the asset, it changes with the content.
+func Path(name string) (string, error) {
+	asset, ok := byName[name]
+	if !ok {
+		return "", fmt.Errorf("unknown asset: %s", name)
+	}
+
+	return Prefix + asset.File, nil
+}
+
+// Integrity returns the subresource integrity of the asset.
+func Integrity(name string) (string, error) {
+	asset, ok := byName[name]
+	if !ok {
+		return "", fmt.Errorf("unknown asset: %s", name)
+	}
+
+	return asset.Integrity, nil
+}
+
+// Lookup finds the asset of a file served under Prefix, by its hashed file name or by its
+// name. Hashed reports whether the file name carries the hash.
+func Lookup(file string) (asset *Asset, hashed bool, ok bool) {
+	if asset, ok := byFile[file]; ok {
+		return asset, true, true
+	}
+	asset, ok = byName[file]
+
+	return asset, false, ok
+}
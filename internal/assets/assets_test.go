@@ -0,0 +1,69 @@
+package assets
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPath(t *testing.T) {
+	path, err := Path("htmx.min.js")
+	require.NoError(t, err)
+	assert.Regexp(t, `^/static/htmx\.min\.[0-9a-f]{12}\.js$`, path)
+
+	_, err = Path("missing.js")
+	assert.ErrorContains(t, err, "unknown asset")
+}
+
+func TestIntegrity(t *testing.T) {
+	// published by htmx for the 1.9.10 release
+	integrity, err := Integrity("htmx.min.js")
+	require.NoError(t, err)
+	assert.Equal(t, "sha384-D1Kt99CQMDuVetoL1lrYwg5t+9QdHe7NLX/SoJYkXDFfX37iInKRy5xLSi8nO7UC", integrity)
+}
+
+func TestLookup(t *testing.T) {
+	path, err := Path("app.js")
+	require.NoError(t, err)
+
+	asset, hashed, ok := Lookup(strings.TrimPrefix(path, Prefix))
+	require.True(t, ok)
+	assert.True(t, hashed)
+	assert.Equal(t, "app.js", asset.Name)
+	assert.Equal(t, "text/javascript; charset=utf-8", asset.ContentType)
+	assert.Contains(t, string(asset.Data), "X-CSRF-Token")
+
+	asset, hashed, ok = Lookup("editor.css")
+	require.True(t, ok)
+	assert.False(t, hashed, "assets are found by their plain name too")
+	assert.Equal(t, "text/css; charset=utf-8", asset.ContentType)
+
+	_, _, ok = Lookup("app.000000000000.js")
+	assert.False(t, ok, "other versions are not served")
+}
+
+func TestNewAsset(t *testing.T) {
+	asset := newAsset("site.min.css", []byte("body{}"))
+	assert.Equal(t, "site.min.css", asset.Name)
+	assert.Regexp(t, `^site\.min\.[0-9a-f]{12}\.css$`, asset.File)
+	assert.NotEqual(t, asset.File, newAsset("site.min.css", []byte("body{color:red}")).File, "new content gets a new file name")
+}
+
+func TestAdd(t *testing.T) {
+	Add("brand.css", []byte(":root{}"))
+	first, err := Path("brand.css")
+	require.NoError(t, err)
+
+	Add("brand.css", []byte(":root{--primary:red}"))
+	second, err := Path("brand.css")
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+
+	_, _, ok := Lookup(strings.TrimPrefix(first, Prefix))
+	assert.False(t, ok, "the replaced content is not served")
+	asset, _, ok := Lookup(strings.TrimPrefix(second, Prefix))
+	require.True(t, ok)
+	assert.Equal(t, ":root{--primary:red}", string(asset.Data))
+}
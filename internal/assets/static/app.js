@@ -0,0 +1,85 @@
+// Shared scripts of the pages, loaded after htmx.
+(function() {
+    // requests changing data carry the token of the csrf cookie
+    window.csrfToken = function() {
+        const match = document.cookie.match(/(?:^|;\s*)csrf_token=([^;]*)/);
+        return match ? decodeURIComponent(match[1]) : '';
+    };
+
+    document.addEventListener('htmx:configRequest', function(e) {
+        e.detail.headers['X-CSRF-Token'] = window.csrfToken();
+    });
+
+    // solveAntispam solves the proof of work of the anti-spam fields of a form, submit
+    // buttons are enabled once it is solved. SHA-256 is implemented here, crypto.subtle is
+    // only available over HTTPS.
+    window.solveAntispam = (function() {
+        const K = [
+            0x428a2f98, 0x71374491, 0xb5c0fbcf, 0xe9b5dba5, 0x3956c25b, 0x59f111f1, 0x923f82a4, 0xab1c5ed5,
+            0xd807aa98, 0x12835b01, 0x243185be, 0x550c7dc3, 0x72be5d74, 0x80deb1fe, 0x9bdc06a7, 0xc19bf174,
+            0xe49b69c1, 0xefbe4786, 0x0fc19dc6, 0x240ca1cc, 0x2de92c6f, 0x4a7484aa, 0x5cb0a9dc, 0x76f988da,
+            0x983e5152, 0xa831c66d, 0xb00327c8, 0xbf597fc7, 0xc6e00bf3, 0xd5a79147, 0x06ca6351, 0x14292967,
+            0x27b70a85, 0x2e1b2138, 0x4d2c6dfc, 0x53380d13, 0x650a7354, 0x766a0abb, 0x81c2c92e, 0x92722c85,
+            0xa2bfe8a1, 0xa81a664b, 0xc24b8b70, 0xc76c51a3, 0xd192e819, 0xd6990624, 0xf40e3585, 0x106aa070,
+            0x19a4c116, 0x1e376c08, 0x2748774c, 0x34b0bcb5, 0x391c0cb3, 0x4ed8aa4a, 0x5b9cca4f, 0x682e6ff3,
+            0x748f82ee, 0x78a5636f, 0x84c87814, 0x8cc70208, 0x90befffa, 0xa4506ceb, 0xbef9a3f7, 0xc67178f2
+        ];
+
+        // first word of the SHA-256 hash of an ASCII string, enough to count leading zeros
+        function sha256FirstWord(text) {
+            const length = text.length;
+            const words = new Array(((length + 8) >> 6) + 1 << 4).fill(0);
+            for (let i = 0; i < length; i++) {
+                words[i >> 2] |= text.charCodeAt(i) << (24 - (i % 4) * 8);
+            }
+            words[length >> 2] |= 0x80 << (24 - (length % 4) * 8);
+            words[words.length - 1] = length * 8;
+
+            let h = [0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a, 0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19];
+            const w = new Array(64);
+            const rotr = (x, n) => (x >>> n) | (x << (32 - n));
+            for (let block = 0; block < words.length; block += 16) {
+                for (let i = 0; i < 64; i++) {
+                    if (i < 16) {
+                        w[i] = words[block + i];
+                    } else {
+                        const s0 = rotr(w[i - 15], 7) ^ rotr(w[i - 15], 18) ^ (w[i - 15] >>> 3);
+                        const s1 = rotr(w[i - 2], 17) ^ rotr(w[i - 2], 19) ^ (w[i - 2] >>> 10);
+                        w[i] = (w[i - 16] + s0 + w[i - 7] + s1) | 0;
+                    }
+                }
+                let [a, b, c, d, e, f, g, hh] = h;
+                for (let i = 0; i < 64; i++) {
+                    const t1 = (hh + (rotr(e, 6) ^ rotr(e, 11) ^ rotr(e, 25)) + ((e & f) ^ (~e & g)) + K[i] + w[i]) | 0;
+                    const t2 = ((rotr(a, 2) ^ rotr(a, 13) ^ rotr(a, 22)) + ((a & b) ^ (a & c) ^ (b & c))) | 0;
+                    hh = g; g = f; f = e; e = (d + t1) | 0;
+                    d = c; c = b; b = a; a = (t1 + t2) | 0;
+                }
+                h = [h[0] + a, h[1] + b, h[2] + c, h[3] + d, h[4] + e, h[5] + f, h[6] + g, h[7] + hh].map(x => x | 0);
+            }
+            return h[0] >>> 0;
+        }
+
+        return function(container) {
+            const challenge = container.dataset.challenge;
+            const difficulty = Math.min(parseInt(container.dataset.difficulty, 10) || 0, 32);
+            const form = container.closest('form');
+            const buttons = form ? form.querySelectorAll('button[type="submit"], button:not([type])') : [];
+            buttons.forEach(button => button.disabled = true);
+
+            let nonce = 0;
+            (function work() {
+                // hash in slices, so the page stays responsive
+                for (let i = 0; i < 5000; i++, nonce++) {
+                    const first = sha256FirstWord(challenge + ':' + nonce);
+                    if (difficulty === 0 || Math.clz32(first) >= difficulty) {
+                        container.querySelector('input[name="proof"]').value = String(nonce);
+                        buttons.forEach(button => button.disabled = false);
+                        return;
+                    }
+                }
+                setTimeout(work, 0);
+            })();
+        };
+    })();
+})();
@@ -0,0 +1,76 @@
+// Shared scripts of the post forms, loaded deferred: the attachments panel and the tag picker.
+(function() {
+    // the references are inserted at the cursor of the post body
+    document.addEventListener('click', function(e) {
+        if (!e.target.matches('.attachment-insert')) {
+            return;
+        }
+        const content = document.getElementById('content');
+        const start = content.selectionStart, end = content.selectionEnd;
+        const reference = e.target.dataset.reference;
+        content.value = content.value.slice(0, start) + reference + content.value.slice(end);
+        content.focus();
+        content.selectionStart = content.selectionEnd = start + reference.length;
+        content.dispatchEvent(new Event('input'));
+    });
+
+    document.addEventListener('click', function(e) {
+        if (e.target.matches('.attachment-hero')) {
+            document.getElementById('hero_image').value = e.target.dataset.key;
+        }
+    });
+
+    // rejected uploads explain why instead of adding to the list
+    document.body.addEventListener('htmx:beforeSwap', function(e) {
+        if (e.detail.target.id === 'attachment-list' && e.detail.xhr.status >= 400) {
+            e.detail.shouldSwap = true;
+            e.detail.isError = false;
+            e.detail.target = document.getElementById('attachment-message');
+            e.detail.swapOverride = 'innerHTML';
+        }
+    });
+
+    // uploading a file again returns the existing attachment, listed once
+    document.body.addEventListener('htmx:afterSwap', function(e) {
+        if (e.detail.target.id !== 'attachment-list') {
+            return;
+        }
+        document.getElementById('attachment-message').innerHTML = '';
+        const items = document.querySelectorAll('#attachment-list .attachment-item');
+        const seen = new Set();
+        items.forEach(function(item) {
+            seen.has(item.id) ? item.remove() : seen.add(item.id);
+        });
+        if (items.length > 0) {
+            document.querySelectorAll('#attachment-list .attachment-empty').forEach(function(empty) {
+                empty.remove();
+            });
+        }
+    });
+})();
+
+(function() {
+    const input = document.getElementById('tags');
+    if (!input) {
+        return;
+    }
+    const current = () => input.value.split(',').map(t => t.trim().toLowerCase()).filter(t => t);
+    const refresh = () => {
+        const tags = current();
+        document.querySelectorAll('.tag-option').forEach(option => {
+            option.classList.toggle('selected', tags.includes(option.dataset.tag));
+        });
+    };
+    document.querySelectorAll('.tag-option').forEach(option => {
+        option.addEventListener('click', function() {
+            let tags = current();
+            tags = tags.includes(this.dataset.tag)
+                ? tags.filter(t => t !== this.dataset.tag)
+                : tags.concat(this.dataset.tag);
+            input.value = tags.join(', ');
+            refresh();
+        });
+    });
+    input.addEventListener('input', refresh);
+    refresh();
+})();
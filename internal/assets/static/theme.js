@@ -0,0 +1,37 @@
+// Palette preference of the reader, kept in the theme cookie. Loaded in the head of every
+// page, before it is shown, so the page is not shown in the wrong palette first.
+(function() {
+    const root = document.documentElement;
+    const year = 60 * 60 * 24 * 365;
+
+    function preference() {
+        const match = document.cookie.match(/(?:^|;\s*)theme=(light|dark)(?:;|$)/);
+        if (match) {
+            return match[1];
+        }
+        // the site default, the system decides when it is auto
+        const meta = document.querySelector('meta[name="theme-mode"]');
+        return meta && (meta.content === 'light' || meta.content === 'dark') ? meta.content : '';
+    }
+
+    function current() {
+        if (root.dataset.theme) {
+            return root.dataset.theme;
+        }
+        return window.matchMedia('(prefers-color-scheme: dark)').matches ? 'dark' : 'light';
+    }
+
+    const mode = preference();
+    if (mode) {
+        root.dataset.theme = mode;
+    }
+
+    document.addEventListener('click', function(e) {
+        const toggle = e.target.closest('[data-theme-toggle]');
+        if (!toggle) return;
+
+        const next = current() === 'dark' ? 'light' : 'dark';
+        root.dataset.theme = next;
+        document.cookie = 'theme=' + next + '; path=/; max-age=' + year + '; SameSite=Lax';
+    });
+})();
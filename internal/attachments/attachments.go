@@ -0,0 +1,206 @@
+package attachments
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"io"
+	"mime"
+	"net/http"
+	"newsteller/internal/config"
+	"newsteller/internal/images"
+	"newsteller/internal/models"
+	"newsteller/internal/repositories"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+/**
+Files uploaded for posts. The type of a file is sniffed from its content, the name and
+the type claimed by the browser are not trusted, and the metadata of images is removed.
+Files are stored under the SHA-256 of their content, so uploading a file again returns
+the existing attachment.
+*/
+
+var (
+	ErrEmpty           = errors.New("the file is empty")
+	ErrTooLarge        = errors.New("the file is too large")
+	ErrUnsupportedType = errors.New("the file type is not accepted")
+)
+
+// extensions - extensions of the keys of common types, mime.ExtensionsByType lists
+// several for some types in an order depending on the system
+var extensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+// maxNameLength - max characters of the kept upload names
+const maxNameLength = 100
+
+type Attachments struct {
+	repo    *repositories.Attachment
+	storage Storage
+	maxSize int64
+	types   []string
+}
+
+func New(cfg *config.Config, c *mongo.Collection, storage Storage) *Attachments {
+	return &Attachments{
+		repo:    repositories.NewAttachmentRepository(c),
+		storage: storage,
+		maxSize: cfg.Attachments.MaxSize,
+		types:   ParseTypes(cfg.Attachments.Types),
+	}
+}
+
+// MaxSize returns the size of the largest accepted file in bytes.
+func (a *Attachments) MaxSize() int64 {
+	return a.maxSize
+}
+
+// Upload stores the file unless a file with the same content exists.
+func (a *Attachments) Upload(ctx context.Context, name string, r io.Reader) (*models.Attachment, error) {
+	data, err := io.ReadAll(io.LimitReader(r, a.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, ErrEmpty
+	}
+	if int64(len(data)) > a.maxSize {
+		return nil, ErrTooLarge
+	}
+	contentType, err := Sniff(data, a.types)
+	if err != nil {
+		return nil, err
+	}
+	// photos may reveal where they were taken
+	data, err = images.StripMetadata(contentType, data)
+	if err != nil {
+		return nil, ErrUnsupportedType
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	existing, err := a.repo.FindByHash(ctx, hash)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+
+	attachment := &models.Attachment{
+		ID:          primitive.NewObjectID(),
+		Hash:        hash,
+		Key:         hash + extension(contentType),
+		Name:        CleanName(name),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		CreatedAt:   time.Now(),
+	}
+	// stored before being recorded, so recorded attachments always have a file
+	err = a.storage.Put(ctx, attachment.Key, contentType, data)
+	if err != nil {
+		return nil, err
+	}
+	err = a.repo.Create(ctx, attachment)
+	if mongo.IsDuplicateKeyError(err) {
+		// the same file was uploaded concurrently
+		return a.repo.FindByHash(ctx, hash)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// Find returns the attachment stored under the key.
+func (a *Attachments) Find(ctx context.Context, key string) (*models.Attachment, error) {
+	return a.repo.FindByKey(ctx, key)
+}
+
+// Open returns the content of the attachment.
+func (a *Attachments) Open(ctx context.Context, attachment *models.Attachment) (io.ReadCloser, error) {
+	return a.storage.Open(ctx, attachment.Key)
+}
+
+func (a *Attachments) Recent(ctx context.Context, limit int) ([]models.Attachment, error) {
+	return a.repo.Recent(ctx, limit)
+}
+
+// Delete removes the attachment, posts referencing it show a broken image or link.
+func (a *Attachments) Delete(ctx context.Context, attachment *models.Attachment) error {
+	err := a.repo.Delete(ctx, attachment.ID)
+	if err != nil {
+		return err
+	}
+
+	return a.storage.Delete(ctx, attachment.Key)
+}
+
+// Sniff detects the type of the content, which must be one of the accepted types.
+func Sniff(data []byte, types []string) (string, error) {
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(data))
+	if err != nil || !slices.Contains(types, contentType) {
+		return "", ErrUnsupportedType
+	}
+
+	return contentType, nil
+}
+
+// ParseTypes splits comma separated MIME types.
+func ParseTypes(input string) []string {
+	var types []string
+	for _, contentType := range strings.Split(input, ",") {
+		contentType = strings.ToLower(strings.TrimSpace(contentType))
+		if contentType != "" {
+			types = append(types, contentType)
+		}
+	}
+
+	return types
+}
+
+// CleanName keeps the base name of an uploaded file without control characters,
+// shortened to maxNameLength characters.
+func CleanName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "." || name == "/" || name == "" {
+		return "file"
+	}
+	if runes := []rune(name); len(runes) > maxNameLength {
+		name = string(runes[:maxNameLength])
+	}
+
+	return name
+}
+
+func extension(contentType string) string {
+	if ext, ok := extensions[contentType]; ok {
+		return ext
+	}
+	if exts, _ := mime.ExtensionsByType(contentType); len(exts) > 0 {
+		return exts[0]
+	}
+
+	return ""
+}
@@ -0,0 +1,102 @@
+package attachments
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestSniff(t *testing.T) {
+	types := ParseTypes("image/png, application/pdf")
+
+	t.Run("Positive: Detected from the content", func(t *testing.T) {
+		contentType, err := Sniff(pngHeader, types)
+		require.NoError(t, err)
+		assert.Equal(t, "image/png", contentType)
+
+		contentType, err = Sniff([]byte("%PDF-1.7\n"), types)
+		require.NoError(t, err)
+		assert.Equal(t, "application/pdf", contentType)
+	})
+
+	t.Run("Negative: Not accepted", func(t *testing.T) {
+		_, err := Sniff([]byte("<html><script>alert(1)</script></html>"), types)
+		assert.ErrorIs(t, err, ErrUnsupportedType)
+
+		_, err = Sniff([]byte("GIF89a"), types)
+		assert.ErrorIs(t, err, ErrUnsupportedType)
+	})
+}
+
+func TestParseTypes(t *testing.T) {
+	assert.Equal(t, []string{"image/png", "image/gif"}, ParseTypes(" Image/PNG,,image/gif ,"))
+	assert.Empty(t, ParseTypes(""))
+}
+
+func TestCleanName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Plain", "photo.jpg", "photo.jpg"},
+		{"Unix path", "/home/user/photo.jpg", "photo.jpg"},
+		{"Windows path", `C:\Users\user\photo.jpg`, "photo.jpg"},
+		{"Control characters", "pho\x00to\n.jpg", "photo.jpg"},
+		{"Empty", "", "file"},
+		{"Directory", "/", "file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CleanName(tt.input))
+		})
+	}
+
+	t.Run("Long", func(t *testing.T) {
+		assert.Len(t, []rune(CleanName(string(bytes.Repeat([]byte("é"), 150)))), maxNameLength)
+	})
+}
+
+func TestLocalStorage(t *testing.T) {
+	ctx := context.Background()
+	directory := t.TempDir()
+	storage := NewLocalStorage(directory)
+
+	t.Run("Negative: Not found", func(t *testing.T) {
+		_, err := storage.Open(ctx, "missing.png")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Positive: Put, open and delete", func(t *testing.T) {
+		require.NoError(t, storage.Put(ctx, "abc.png", "image/png", pngHeader))
+
+		reader, err := storage.Open(ctx, "abc.png")
+		require.NoError(t, err)
+		data, err := io.ReadAll(reader)
+		reader.Close()
+		require.NoError(t, err)
+		assert.Equal(t, pngHeader, data)
+
+		entries, err := os.ReadDir(directory)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1, "no temporary files are left")
+
+		require.NoError(t, storage.Delete(ctx, "abc.png"))
+		_, err = storage.Open(ctx, "abc.png")
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.NoError(t, storage.Delete(ctx, "abc.png"), "deleting twice is not an error")
+	})
+
+	t.Run("Positive: Keys stay within the directory", func(t *testing.T) {
+		require.NoError(t, storage.Put(ctx, "../escape.png", "image/png", pngHeader))
+		_, err := os.Stat(directory + "/escape.png")
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,90 @@
+package attachments
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"io"
+)
+
+// GridFSStorage keeps the files in the attachment_files bucket, named by key.
+type GridFSStorage struct {
+	bucket *gridfs.Bucket
+}
+
+func NewGridFSStorage(db *mongo.Database) (*GridFSStorage, error) {
+	bucket, err := gridfs.NewBucket(db, options.GridFSBucket().SetName("attachment_files"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &GridFSStorage{bucket: bucket}, nil
+}
+
+func (g *GridFSStorage) Put(ctx context.Context, key, contentType string, data []byte) error {
+	files, err := g.find(ctx, key)
+	if err != nil {
+		return err
+	}
+	if len(files) > 0 {
+		// the key is derived from the content, the stored file is the same
+		return nil
+	}
+
+	uploadOptions := options.GridFSUpload().SetMetadata(bson.M{"content_type": contentType})
+	_, err = g.bucket.UploadFromStream(key, bytes.NewReader(data), uploadOptions)
+
+	return err
+}
+
+func (g *GridFSStorage) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	stream, err := g.bucket.OpenDownloadStreamByName(key)
+	if errors.Is(err, gridfs.ErrFileNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return stream, nil
+}
+
+func (g *GridFSStorage) Delete(ctx context.Context, key string) error {
+	files, err := g.find(ctx, key)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		err = g.bucket.DeleteContext(ctx, file.ID)
+		if err != nil && !errors.Is(err, gridfs.ErrFileNotFound) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type gridFSFile struct {
+	ID primitive.ObjectID `bson:"_id"`
+}
+
+func (g *GridFSStorage) find(ctx context.Context, key string) ([]gridFSFile, error) {
+	cursor, err := g.bucket.FindContext(ctx, bson.M{"filename": key})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var files []gridFSFile
+	err = cursor.All(ctx, &files)
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
@@ -0,0 +1,78 @@
+package attachments
+
+import (
+	"bytes"
+	"context"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"io"
+	"newsteller/internal/config"
+)
+
+// S3Storage keeps the files as objects of a bucket of an S3-compatible service.
+type S3Storage struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3Storage connects to the service and creates the bucket when it does not exist.
+func NewS3Storage(ctx context.Context, cfg *config.Config) (*S3Storage, error) {
+	s3 := cfg.Attachments.S3
+	client, err := minio.New(s3.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(s3.AccessKey, s3.SecretKey, ""),
+		Secure: s3.UseSSL,
+		Region: s3.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := client.BucketExists(ctx, s3.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		err = client.MakeBucket(ctx, s3.Bucket, minio.MakeBucketOptions{Region: s3.Region})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &S3Storage{client: client, bucket: s3.Bucket}, nil
+}
+
+func (s *S3Storage) Put(ctx context.Context, key, contentType string, data []byte) error {
+	_, err := s.client.PutObject(
+		ctx,
+		s.bucket,
+		key,
+		bytes.NewReader(data),
+		int64(len(data)),
+		minio.PutObjectOptions{ContentType: contentType},
+	)
+
+	return err
+}
+
+func (s *S3Storage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	object, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	// objects are fetched lazily, missing ones fail on the first request
+	_, err = object.Stat()
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		object.Close()
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		object.Close()
+		return nil, err
+	}
+
+	return object, nil
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
@@ -0,0 +1,80 @@
+package attachments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/mongo"
+	"io"
+	"newsteller/internal/config"
+	"newsteller/internal/files"
+	"os"
+	"path/filepath"
+)
+
+const (
+	StoreLocal  = "local"
+	StoreGridFS = "gridfs"
+	StoreS3     = "s3"
+)
+
+// ErrNotFound is returned by storages not having the file.
+var ErrNotFound = errors.New("attachment not found")
+
+// Storage keeps the files of attachments by key. Keys are derived from the content,
+// so a file is never replaced by a different one.
+type Storage interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewStorage creates the storage selected in the configuration.
+func NewStorage(ctx context.Context, cfg *config.Config, db *mongo.Database) (Storage, error) {
+	switch cfg.Attachments.Store {
+	case StoreLocal:
+		return NewLocalStorage(cfg.Attachments.Directory), nil
+	case StoreGridFS:
+		return NewGridFSStorage(db)
+	case StoreS3:
+		return NewS3Storage(ctx, cfg)
+	default:
+		return nil, fmt.Errorf("unknown attachment store: %q", cfg.Attachments.Store)
+	}
+}
+
+// LocalStorage keeps the files in a directory of the local filesystem.
+type LocalStorage struct {
+	directory string
+}
+
+func NewLocalStorage(directory string) *LocalStorage {
+	return &LocalStorage{directory: directory}
+}
+
+func (l *LocalStorage) Put(_ context.Context, key, _ string, data []byte) error {
+	return files.WriteAtomic(l.path(key), data)
+}
+
+func (l *LocalStorage) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	file, err := os.Open(l.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+
+	return file, err
+}
+
+func (l *LocalStorage) Delete(_ context.Context, key string) error {
+	err := os.Remove(l.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+// path keeps keys within the directory, they are generated but served from URLs
+func (l *LocalStorage) path(key string) string {
+	return filepath.Join(l.directory, filepath.Base(key))
+}
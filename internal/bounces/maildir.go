@@ -0,0 +1,88 @@
+package bounces
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"newsteller/internal/config"
+	"newsteller/internal/models"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Maildir processes bounce notifications a local mail server delivers into a Maildir.
+// Processed messages are moved from new/ to cur/ and flagged as seen, messages failing
+// for any other reason than not being a report stay in new/ and are retried.
+type Maildir struct {
+	dir       string
+	interval  time.Duration
+	processor *Processor
+}
+
+func NewMaildir(cfg *config.Config, processor *Processor) *Maildir {
+	return &Maildir{
+		dir:       cfg.Mail.Bounces.Maildir,
+		interval:  cfg.Mail.Bounces.PollInterval,
+		processor: processor,
+	}
+}
+
+// Run processes new messages every poll interval until the context is cancelled.
+func (m *Maildir) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		m.processNew(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Maildir) processNew(ctx context.Context) {
+	entries, err := os.ReadDir(filepath.Join(m.dir, "new"))
+	if err != nil {
+		zap.L().Error("could not read bounce maildir", zap.String("dir", m.dir), zap.Error(err))
+		return
+	}
+
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return
+		}
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		err := m.process(ctx, entry.Name())
+		if err != nil {
+			zap.L().Error("could not process bounce message", zap.String("file", entry.Name()), zap.Error(err))
+		}
+	}
+}
+
+func (m *Maildir) process(ctx context.Context, name string) error {
+	path := filepath.Join(m.dir, "new", name)
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	count, err := m.processor.Process(ctx, models.BounceSourceMailbox, file)
+	_ = file.Close()
+	switch {
+	case errors.Is(err, ErrNotReport):
+		zap.L().Info("skipping message that is not a bounce report", zap.String("file", name))
+	case err != nil:
+		return err
+	default:
+		zap.L().Info("processed bounce message", zap.String("file", name), zap.Int("reports", count))
+	}
+
+	return os.Rename(path, filepath.Join(m.dir, "cur", name+":2,S"))
+}
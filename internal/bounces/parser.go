@@ -0,0 +1,220 @@
+package bounces
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"newsteller/internal/models"
+	"strings"
+)
+
+/**
+Bounces arrive as multipart/report messages: delivery status notifications (RFC 3464)
+with a message/delivery-status part listing the failed recipients, and complaints in the
+Abuse Reporting Format (RFC 5965) with a message/feedback-report part followed by the
+original message. Reports of successful deliveries and non-complaint feedback are ignored.
+*/
+
+// ErrNotReport is returned for messages that are not delivery status notifications or complaints.
+var ErrNotReport = errors.New("not a bounce or complaint report")
+
+// Report is a bounce or a complaint for a single recipient.
+type Report struct {
+	Email      string
+	Kind       models.BounceKind
+	Status     string
+	Diagnostic string
+}
+
+// Parse reads the reports of a raw DSN or ARF message.
+func Parse(r io.Reader) ([]Report, error) {
+	message, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotReport, err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/report" || params["boundary"] == "" {
+		return nil, ErrNotReport
+	}
+
+	var (
+		reports  []Report
+		feedback textproto.MIMEHeader
+		original string
+	)
+	parts := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := parts.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrNotReport, err)
+		}
+
+		body := partBody(part)
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		switch contentType {
+		case "message/delivery-status":
+			reports, err = parseDeliveryStatus(body)
+		case "message/feedback-report":
+			feedback, err = readFields(body)
+		case "message/rfc822", "text/rfc822-headers":
+			original, err = originalRecipient(body)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrNotReport, err)
+		}
+	}
+
+	switch strings.ToLower(params["report-type"]) {
+	case "delivery-status":
+		return reports, nil
+	case "feedback-report":
+		return parseFeedback(feedback, original), nil
+	default:
+		return nil, ErrNotReport
+	}
+}
+
+// parseDeliveryStatus reads the per-recipient fields following the per-message fields.
+func parseDeliveryStatus(r io.Reader) ([]Report, error) {
+	reader := textproto.NewReader(bufio.NewReader(r))
+	// the per-message fields carry nothing about the recipients
+	if _, err := reader.ReadMIMEHeader(); err != nil {
+		return nil, ignoreEOF(err)
+	}
+
+	var reports []Report
+	for {
+		fields, err := reader.ReadMIMEHeader()
+		if report, ok := recipientReport(fields); ok {
+			reports = append(reports, report)
+		}
+		if err != nil {
+			return reports, ignoreEOF(err)
+		}
+	}
+}
+
+func recipientReport(fields textproto.MIMEHeader) (Report, bool) {
+	email := typedValue(fields.Get("Final-Recipient"))
+	if email == "" {
+		email = typedValue(fields.Get("Original-Recipient"))
+	}
+	if email == "" {
+		return Report{}, false
+	}
+
+	status, _, _ := strings.Cut(strings.TrimSpace(fields.Get("Status")), " ")
+	report := Report{
+		Email:      normalizeAddress(email),
+		Status:     status,
+		Diagnostic: typedValue(fields.Get("Diagnostic-Code")),
+	}
+	switch strings.ToLower(strings.TrimSpace(fields.Get("Action"))) {
+	case "failed":
+		report.Kind = failureKind(status)
+	case "delayed":
+		report.Kind = models.BounceSoft
+	default:
+		// delivered, relayed and expanded messages did not bounce
+		return Report{}, false
+	}
+
+	return report, true
+}
+
+// failureKind tells permanent failures of the address from failures that may pass, like a
+// full mailbox, a too large message or a policy rejection of the content.
+func failureKind(status string) models.BounceKind {
+	if !strings.HasPrefix(status, "5.") {
+		return models.BounceSoft
+	}
+	if status == "5.2.2" || status == "5.3.4" || strings.HasPrefix(status, "5.7.") {
+		return models.BounceSoft
+	}
+
+	return models.BounceHard
+}
+
+func parseFeedback(fields textproto.MIMEHeader, original string) []Report {
+	feedbackType := strings.ToLower(strings.TrimSpace(fields.Get("Feedback-Type")))
+	switch feedbackType {
+	case "abuse", "fraud", "virus", "other":
+	default:
+		// not-spam and auth-failure reports are no complaints about the newsletter
+		return nil
+	}
+
+	email := fields.Get("Original-Rcpt-To")
+	if email == "" {
+		email = original
+	}
+	if email == "" {
+		return nil
+	}
+
+	return []Report{{
+		Email:      normalizeAddress(email),
+		Kind:       models.BounceComplaint,
+		Diagnostic: feedbackType,
+	}}
+}
+
+// originalRecipient returns the To address of the original message.
+func originalRecipient(r io.Reader) (string, error) {
+	fields, err := readFields(r)
+	if err != nil {
+		return "", err
+	}
+
+	return fields.Get("To"), nil
+}
+
+func readFields(r io.Reader) (textproto.MIMEHeader, error) {
+	fields, err := textproto.NewReader(bufio.NewReader(r)).ReadMIMEHeader()
+	return fields, ignoreEOF(err)
+}
+
+// partBody decodes base64 parts, quoted-printable is decoded by the multipart reader.
+func partBody(part *multipart.Part) io.Reader {
+	if strings.EqualFold(part.Header.Get("Content-Transfer-Encoding"), "base64") {
+		return base64.NewDecoder(base64.StdEncoding, part)
+	}
+
+	return part
+}
+
+// typedValue strips the type of fields like "rfc822; reader@example.com".
+func typedValue(value string) string {
+	if _, typed, ok := strings.Cut(value, ";"); ok {
+		value = typed
+	}
+
+	return strings.TrimSpace(value)
+}
+
+func normalizeAddress(value string) string {
+	if address, err := mail.ParseAddress(value); err == nil {
+		value = address.Address
+	}
+
+	return strings.ToLower(strings.Trim(strings.TrimSpace(value), "<>"))
+}
+
+// ignoreEOF accepts field blocks ending with the message instead of an empty line.
+func ignoreEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,149 @@
+package bounces
+
+import (
+	"newsteller/internal/models"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dsnMessage = `From: Mail Delivery System <MAILER-DAEMON@mx.example.com>
+To: no-reply@news.example.com
+Subject: Undelivered Mail Returned to Sender
+MIME-Version: 1.0
+Content-Type: multipart/report; report-type=delivery-status; boundary="dsn-boundary"
+
+--dsn-boundary
+Content-Type: text/plain
+
+This is the mail system at host mx.example.com.
+
+--dsn-boundary
+Content-Type: message/delivery-status
+
+Reporting-MTA: dns; mx.example.com
+Arrival-Date: Mon, 19 Oct 2026 08:00:00 +0000
+
+Final-Recipient: rfc822; Gone@Example.com
+Original-Recipient: rfc822;gone@example.com
+Action: failed
+Status: 5.1.1
+Diagnostic-Code: smtp; 550 5.1.1 <gone@example.com>: Recipient address
+    rejected: User unknown
+
+Final-Recipient: rfc822; full@example.com
+Action: failed
+Status: 5.2.2
+Diagnostic-Code: smtp; 552 5.2.2 Mailbox full
+
+Final-Recipient: rfc822; slow@example.com
+Action: delayed
+Status: 4.4.1
+
+Final-Recipient: rfc822; reader@example.com
+Action: delivered
+Status: 2.0.0
+
+--dsn-boundary
+Content-Type: text/rfc822-headers
+
+From: no-reply@news.example.com
+To: gone@example.com
+Subject: Weekly digest
+
+--dsn-boundary--
+`
+
+const arfMessage = `From: abuse@mailbox.example.org
+To: bounces@news.example.com
+Subject: Complaint
+MIME-Version: 1.0
+Content-Type: multipart/report; report-type=feedback-report; boundary="arf-boundary"
+
+--arf-boundary
+Content-Type: text/plain
+
+This is an email abuse report.
+
+--arf-boundary
+Content-Type: message/feedback-report
+
+Feedback-Type: abuse
+User-Agent: SomeGenerator/1.0
+Version: 1
+
+--arf-boundary
+Content-Type: message/rfc822
+Content-Transfer-Encoding: base64
+
+RnJvbTogbm8tcmVwbHlAbmV3cy5leGFtcGxlLmNvbQpUbzogIlJlYWRlciIgPEFuZ3J5QEV4YW1w
+bGUub3JnPgpTdWJqZWN0OiBXZWVrbHkgZGlnZXN0CgpIZWxsbw==
+
+--arf-boundary--
+`
+
+func crlf(message string) string {
+	return strings.ReplaceAll(message, "\n", "\r\n")
+}
+
+func TestParse(t *testing.T) {
+	t.Run("Positive: Delivery status notification", func(t *testing.T) {
+		reports, err := Parse(strings.NewReader(crlf(dsnMessage)))
+		require.NoError(t, err)
+		require.Len(t, reports, 3)
+
+		assert.Equal(t, Report{
+			Email:      "gone@example.com",
+			Kind:       models.BounceHard,
+			Status:     "5.1.1",
+			Diagnostic: "550 5.1.1 <gone@example.com>: Recipient address rejected: User unknown",
+		}, reports[0])
+		assert.Equal(t, "full@example.com", reports[1].Email)
+		assert.Equal(t, models.BounceSoft, reports[1].Kind, "a full mailbox is no permanent failure")
+		assert.Equal(t, "slow@example.com", reports[2].Email)
+		assert.Equal(t, models.BounceSoft, reports[2].Kind)
+	})
+
+	t.Run("Positive: LF line endings", func(t *testing.T) {
+		reports, err := Parse(strings.NewReader(dsnMessage))
+		require.NoError(t, err)
+		assert.Len(t, reports, 3)
+	})
+
+	t.Run("Positive: Complaint addressed by the original message", func(t *testing.T) {
+		reports, err := Parse(strings.NewReader(crlf(arfMessage)))
+		require.NoError(t, err)
+		assert.Equal(t, []Report{{
+			Email:      "angry@example.org",
+			Kind:       models.BounceComplaint,
+			Diagnostic: "abuse",
+		}}, reports)
+	})
+
+	t.Run("Positive: Complaint with the original recipient field", func(t *testing.T) {
+		message := strings.Replace(arfMessage, "Version: 1\n", "Version: 1\nOriginal-Rcpt-To: <other@example.org>\n", 1)
+		reports, err := Parse(strings.NewReader(message))
+		require.NoError(t, err)
+		require.Len(t, reports, 1)
+		assert.Equal(t, "other@example.org", reports[0].Email)
+	})
+
+	t.Run("Negative: Not spam feedback", func(t *testing.T) {
+		message := strings.Replace(arfMessage, "Feedback-Type: abuse", "Feedback-Type: not-spam", 1)
+		reports, err := Parse(strings.NewReader(message))
+		require.NoError(t, err)
+		assert.Empty(t, reports)
+	})
+
+	t.Run("Negative: Regular message", func(t *testing.T) {
+		_, err := Parse(strings.NewReader("From: reader@example.com\r\nSubject: Hello\r\n\r\nHi there"))
+		assert.ErrorIs(t, err, ErrNotReport)
+	})
+
+	t.Run("Negative: Garbage", func(t *testing.T) {
+		_, err := Parse(strings.NewReader("not a message"))
+		assert.ErrorIs(t, err, ErrNotReport)
+	})
+}
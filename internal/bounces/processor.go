@@ -0,0 +1,125 @@
+package bounces
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"io"
+	"newsteller/internal/config"
+	"newsteller/internal/models"
+	"newsteller/internal/repositories"
+	"time"
+)
+
+// Processor records reports and suppresses subscribers reaching the configured limits.
+type Processor struct {
+	cfg         *config.Config
+	bounces     *repositories.Bounce
+	subscribers *repositories.Subscriber
+}
+
+func NewProcessor(cfg *config.Config, bounces *mongo.Collection, subscribers *mongo.Collection) *Processor {
+	return &Processor{
+		cfg:         cfg,
+		bounces:     repositories.NewBounceRepository(bounces),
+		subscribers: repositories.NewSubscriberRepository(subscribers),
+	}
+}
+
+// Process parses a raw DSN or ARF message and records its reports, returning how many were
+// recorded. Messages that are no reports fail with ErrNotReport.
+func (p *Processor) Process(ctx context.Context, source models.BounceSource, r io.Reader) (int, error) {
+	reports, err := Parse(r)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, report := range reports {
+		if err := p.record(ctx, source, report); err != nil {
+			return i, err
+		}
+	}
+
+	return len(reports), nil
+}
+
+func (p *Processor) record(ctx context.Context, source models.BounceSource, report Report) error {
+	now := time.Now()
+	bounce := &models.Bounce{
+		Email:      report.Email,
+		Kind:       report.Kind,
+		Status:     report.Status,
+		Diagnostic: report.Diagnostic,
+		Source:     source,
+		CreatedAt:  now,
+	}
+
+	subscriber, err := p.subscribers.FindByEmail(ctx, report.Email)
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		// still recorded, the address may subscribe later
+		subscriber = nil
+	case err != nil:
+		return err
+	default:
+		bounce.SubscriberID = subscriber.ID
+	}
+
+	err = p.bounces.Create(ctx, bounce)
+	if err != nil || subscriber == nil || subscriber.Suppressed() {
+		return err
+	}
+
+	limit := p.limit(report.Kind)
+	if limit <= 0 {
+		return nil
+	}
+
+	// reports received before the subscriber was reactivated do not count
+	since := now.Add(-p.cfg.Mail.Bounces.Window)
+	if subscriber.ConfirmedAt.After(since) {
+		since = subscriber.ConfirmedAt
+	}
+	count, err := p.bounces.Count(ctx, report.Email, report.Kind, since)
+	if err != nil || count < int64(limit) {
+		return err
+	}
+
+	status := models.SubscriberBounced
+	if report.Kind == models.BounceComplaint {
+		status = models.SubscriberComplained
+	}
+	zap.L().Info(
+		"suppressing subscriber",
+		zap.String("id", subscriber.ID.Hex()),
+		zap.String("status", string(status)),
+		zap.Int64("reports", count),
+	)
+
+	return p.subscribers.Suppress(ctx, subscriber.ID, status, reason(report), now)
+}
+
+func (p *Processor) limit(kind models.BounceKind) int {
+	switch kind {
+	case models.BounceHard:
+		return p.cfg.Mail.Bounces.HardLimit
+	case models.BounceSoft:
+		return p.cfg.Mail.Bounces.SoftLimit
+	default:
+		return p.cfg.Mail.Bounces.ComplaintLimit
+	}
+}
+
+func reason(report Report) string {
+	switch {
+	case report.Kind == models.BounceComplaint:
+		return "Marked as spam (" + report.Diagnostic + ")"
+	case report.Diagnostic != "":
+		return report.Diagnostic
+	case report.Status != "":
+		return "Status " + report.Status
+	default:
+		return string(report.Kind) + " bounce"
+	}
+}
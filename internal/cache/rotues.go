@@ -1,51 +1,72 @@
 package cache
 
 import (
+	"context"
 	"github.com/puzpuzpuz/xsync/v4"
 	"go.uber.org/zap"
+	"newsteller/internal/events"
 	"strings"
+	"time"
 )
 
 /**
 A cache for basic pages, which implements reverse caching logic.
 */
 
-type Event string
-
+// FeedPrefix and SitemapPrefix - prefixes of the keys of cached feeds and sitemaps,
+// invalidated together with the pages of posts
 const (
-	PostsUpdated Event = "post"
+	FeedPrefix    = "feed:"
+	SitemapPrefix = "sitemap:"
 )
 
+// Page is a cached response with the headers needed to serve it again.
+type Page struct {
+	Body        string
+	ContentType string
+	// ModifiedAt - latest change of the content, zero when unknown
+	ModifiedAt time.Time
+}
+
 type PagesCache struct {
-	pages *xsync.Map[string, string]
+	pages *xsync.Map[string, Page]
 }
 
 func NewPagesCache() *PagesCache {
 	return &PagesCache{
-		pages: xsync.NewMap[string, string](),
+		pages: xsync.NewMap[string, Page](),
 	}
 }
 
-func (c *PagesCache) Set(key string, value string) {
-	c.pages.Store(key, value)
+func (c *PagesCache) Set(key string, page Page) {
+	c.pages.Store(key, page)
 }
 
-func (c *PagesCache) Get(key string) (string, bool) {
+func (c *PagesCache) Get(key string) (Page, bool) {
 	return c.pages.Load(key)
 }
 
-func (c *PagesCache) Invalidate(event Event) {
-	switch event {
-	case PostsUpdated:
-		c.invalidateForPosts()
-	default:
-		zap.L().Warn("invalid event", zap.String("event", string(event)))
-	}
+// Subscribe drops the pages showing posts whenever a post changes.
+func (c *PagesCache) Subscribe(bus *events.Bus) {
+	events.Subscribe(bus, "cache", func(context.Context, events.PostCreated) error {
+		c.InvalidatePosts()
+		return nil
+	})
+	events.Subscribe(bus, "cache", func(context.Context, events.PostUpdated) error {
+		c.InvalidatePosts()
+		return nil
+	})
+	events.Subscribe(bus, "cache", func(context.Context, events.PostDeleted) error {
+		c.InvalidatePosts()
+		return nil
+	})
 }
 
-func (c *PagesCache) invalidateForPosts() {
-	c.pages.Range(func(k, v string) bool {
-		if strings.Contains(k, "/home") || strings.Contains(k, "/posts") {
+// InvalidatePosts drops the pages, feeds and sitemaps listing posts.
+func (c *PagesCache) InvalidatePosts() {
+	c.pages.Range(func(k string, _ Page) bool {
+		if strings.Contains(k, "/home") || strings.Contains(k, "/posts") ||
+			strings.HasPrefix(k, FeedPrefix) || strings.HasPrefix(k, SitemapPrefix) {
 			zap.L().Info("invalidated page with key:", zap.String("key", k))
 			c.pages.Delete(k)
 		}
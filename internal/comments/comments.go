@@ -0,0 +1,306 @@
+package comments
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"newsteller/internal/antispam"
+	"newsteller/internal/config"
+	"newsteller/internal/events"
+	"newsteller/internal/mailer"
+	"newsteller/internal/models"
+	"newsteller/internal/repositories"
+	"newsteller/internal/templates"
+	"strings"
+	"time"
+)
+
+/**
+Reader comments of posts. Comments are shown below their post once approved, new ones
+wait in the moderation queue unless moderation is disabled. Comments the spam filter
+rates at least ANTISPAM_SPAM_THRESHOLD are filed as spam at once, moderation decisions
+train the filter. Readers reply to approved
+comments only, replies nest up to MaxDepth levels, deeper replies are attached to the
+deepest level. The author of the post, or the MAIL_NOTIFY addresses for posts without
+an author address, are emailed about every new comment.
+*/
+
+var (
+	// ErrClosed is returned for comments on posts not accepting any.
+	ErrClosed = errors.New("comments are closed")
+	// ErrInvalidParent is returned for replies to comments which are not shown on the post.
+	ErrInvalidParent = errors.New("invalid parent comment")
+)
+
+// Thread orders the comments depth first, every comment followed by its replies oldest
+// first. Replies to comments missing from the list, e.g. deleted or marked as spam, are
+// left out with their own replies.
+func Thread(comments []models.Comment) []models.Comment {
+	known := make(map[primitive.ObjectID]bool, len(comments))
+	for _, comment := range comments {
+		known[comment.ID] = true
+	}
+
+	replies := make(map[primitive.ObjectID][]models.Comment)
+	for _, comment := range comments {
+		if comment.IsReply() && !known[comment.ParentID] {
+			continue
+		}
+		replies[comment.ParentID] = append(replies[comment.ParentID], comment)
+	}
+
+	thread := make([]models.Comment, 0, len(comments))
+	var walk func(parent primitive.ObjectID)
+	walk = func(parent primitive.ObjectID) {
+		for _, comment := range replies[parent] {
+			thread = append(thread, comment)
+			walk(comment.ID)
+		}
+	}
+	walk(primitive.NilObjectID)
+
+	return thread
+}
+
+// Queue is a page of the moderation queue.
+type Queue struct {
+	Status   models.CommentStatus
+	Comments []models.Comment
+	// Posts - titles of the commented posts by ID
+	Posts  map[primitive.ObjectID]string
+	Counts map[models.CommentStatus]int64
+	Total  int64
+}
+
+type Comments struct {
+	cfg        *config.Config
+	repo       *repositories.Comment
+	posts      *repositories.Post
+	mailer     *mailer.Mailer
+	scorer     *antispam.Scorer
+	recipients []string
+}
+
+func New(
+	cfg *config.Config,
+	comments *mongo.Collection,
+	posts *mongo.Collection,
+	mailer *mailer.Mailer,
+	scorer *antispam.Scorer,
+) *Comments {
+	var recipients []string
+	for _, address := range strings.Split(cfg.Mail.Notify, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			recipients = append(recipients, address)
+		}
+	}
+
+	return &Comments{
+		cfg:        cfg,
+		repo:       repositories.NewCommentRepository(comments),
+		posts:      repositories.NewPostRepository(posts),
+		mailer:     mailer,
+		scorer:     scorer,
+		recipients: recipients,
+	}
+}
+
+// Open reports whether readers may comment on the post.
+func (c *Comments) Open(post *models.Post) bool {
+	return c.cfg.Comments.Enabled && !post.CommentsDisabled
+}
+
+// Thread returns the approved comments of the post in thread order.
+func (c *Comments) Thread(ctx context.Context, post *models.Post) ([]models.Comment, error) {
+	comments, err := c.repo.FindByPost(ctx, post.ID, models.CommentApproved)
+	if err != nil {
+		return nil, err
+	}
+
+	return Thread(comments), nil
+}
+
+// Submit saves the comment on the post, approved at once when moderation is disabled,
+// and notifies the author of the post. parentID is empty for comments on the post itself.
+func (c *Comments) Submit(ctx context.Context, post *models.Post, comment *models.Comment, parentID string) error {
+	if !c.Open(post) {
+		return ErrClosed
+	}
+
+	comment.PostID = post.ID
+	if parentID != "" {
+		parent, err := c.repo.FindByID(ctx, parentID)
+		if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+			return ErrInvalidParent
+		}
+		if err != nil {
+			return err
+		}
+		if parent.PostID != post.ID || parent.Status != models.CommentApproved {
+			return ErrInvalidParent
+		}
+
+		comment.ParentID, comment.Depth = parent.ID, parent.Depth+1
+		if comment.Depth > c.cfg.Comments.MaxDepth {
+			// the reply becomes a sibling of the deepest comment
+			comment.ParentID, comment.Depth = parent.ParentID, parent.Depth
+		}
+	}
+	comment.Status = models.CommentApproved
+	if c.cfg.Comments.Moderation {
+		comment.Status = models.CommentPending
+	}
+	score, err := c.scorer.Score(ctx, spamText(comment))
+	if err != nil {
+		// an unavailable filter leaves the decision to moderators
+		zap.L().Error("could not score comment", zap.Error(err))
+		comment.Status = models.CommentPending
+	}
+	comment.SpamScore = score
+	if score*100 >= float64(c.cfg.Antispam.SpamThreshold) {
+		comment.Status = models.CommentSpam
+	}
+	comment.CreatedAt = time.Now()
+
+	id, err := c.repo.Create(ctx, comment)
+	if err != nil {
+		return err
+	}
+	comment.ID = *id
+	if comment.Status == models.CommentSpam {
+		// spam is reviewed in the moderation queue, nobody is notified
+		return nil
+	}
+
+	// the comment is saved, a failing notification must not fail the submission
+	if err = c.notify(ctx, post, comment); err != nil {
+		zap.L().Error("could not notify about comment", zap.String("id", comment.ID.Hex()), zap.Error(err))
+	}
+
+	return nil
+}
+
+// notify enqueues the email once per comment and recipient.
+func (c *Comments) notify(ctx context.Context, post *models.Post, comment *models.Comment) error {
+	recipients := c.recipients
+	if post.AuthorEmail != "" {
+		recipients = []string{post.AuthorEmail}
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	url := c.cfg.BaseURL + post.Path() + "#comment-" + comment.ID.Hex()
+	if comment.Status == models.CommentPending {
+		url = c.cfg.BaseURL + "/posts/edit#comments"
+	}
+	email, err := templates.NewCommentNotificationEmail(post, comment, url).GenerateEmail()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, to := range recipients {
+		err = c.mailer.EnqueueOnce(ctx, "comment:"+comment.ID.Hex()+":"+to, &mailer.Message{
+			To:      to,
+			Subject: email.Subject,
+			HTML:    email.HTML,
+			Text:    email.Text,
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Moderate sets the status of the comment and trains the spam filter with the decision,
+// undoing the training with an earlier decision.
+func (c *Comments) Moderate(ctx context.Context, id string, status models.CommentStatus) error {
+	comment, err := c.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = c.repo.SetStatus(ctx, comment.ID, status)
+	if err != nil || comment.Trained == status {
+		return err
+	}
+
+	text := spamText(comment)
+	if comment.Trained != "" {
+		err = c.scorer.Forget(ctx, text, comment.Trained == models.CommentSpam)
+	}
+	if err == nil {
+		err = c.scorer.Learn(ctx, text, status == models.CommentSpam)
+	}
+	if err == nil {
+		err = c.repo.SetTrained(ctx, comment.ID, status)
+	}
+	if err != nil {
+		// the decision is saved, the filter only misses it
+		zap.L().Error("could not train spam filter", zap.String("id", comment.ID.Hex()), zap.Error(err))
+	}
+
+	return nil
+}
+
+// spamText returns the text of the comment the spam filter rates.
+func spamText(comment *models.Comment) string {
+	return comment.Author + "\n" + comment.Content
+}
+
+// Delete removes the comment, its replies are no longer shown.
+func (c *Comments) Delete(ctx context.Context, id string) error {
+	comment, err := c.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return c.repo.Delete(ctx, comment.ID)
+}
+
+// Queue returns a page of the comments with the status, newest first.
+func (c *Comments) Queue(ctx context.Context, status models.CommentStatus, page, limit int) (*Queue, error) {
+	comments, total, err := c.repo.FindByStatus(ctx, status, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := c.repo.CountByStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(comments))
+	for _, comment := range comments {
+		ids = append(ids, comment.PostID)
+	}
+	titles := make(map[primitive.ObjectID]string, len(ids))
+	if len(ids) > 0 {
+		posts, err := c.posts.FindByIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, post := range posts {
+			titles[post.ID] = post.Title
+		}
+	}
+
+	return &Queue{
+		Status:   status,
+		Comments: comments,
+		Posts:    titles,
+		Counts:   counts,
+		Total:    total,
+	}, nil
+}
+
+// Subscribe removes the comments of deleted posts.
+func (c *Comments) Subscribe(bus *events.Bus) {
+	events.Subscribe(bus, "comments", func(ctx context.Context, event events.PostDeleted) error {
+		_, err := c.repo.DeleteByPost(ctx, event.Post.ID)
+		return err
+	})
+}
@@ -0,0 +1,51 @@
+package comments
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"newsteller/internal/config"
+	"newsteller/internal/models"
+)
+
+func comment(author string, parent primitive.ObjectID) models.Comment {
+	return models.Comment{ID: primitive.NewObjectID(), Author: author, ParentID: parent}
+}
+
+func authors(comments []models.Comment) []string {
+	var authors []string
+	for _, comment := range comments {
+		authors = append(authors, comment.Author)
+	}
+	return authors
+}
+
+func TestThread(t *testing.T) {
+	ann := comment("Ann", primitive.NilObjectID)
+	bob := comment("Bob", ann.ID)
+	cid := comment("Cid", primitive.NilObjectID)
+	dan := comment("Dan", bob.ID)
+	eve := comment("Eve", ann.ID)
+	// replies to a comment marked as spam are hidden with their replies
+	orphan := comment("Orphan", primitive.NewObjectID())
+	orphanReply := comment("Orphan reply", orphan.ID)
+	// oldest first, as the repository returns them
+	comments := []models.Comment{ann, bob, orphanReply, cid, dan, orphan, eve}
+
+	assert.Equal(t, []string{"Ann", "Bob", "Dan", "Eve", "Cid"}, authors(Thread(comments)))
+	assert.Empty(t, Thread(nil))
+}
+
+func TestComments_Open(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Comments.Enabled = true
+	c := &Comments{cfg: cfg}
+
+	assert.True(t, c.Open(&models.Post{}))
+	assert.False(t, c.Open(&models.Post{CommentsDisabled: true}))
+
+	cfg.Comments.Enabled = false
+	assert.False(t, c.Open(&models.Post{}))
+}
@@ -1,10 +1,180 @@
 package config
 
+import "time"
+
 type Config struct {
-	DNS          string   `mapstructure:"DNS" json:"DNS" yaml:"DNS"`
-	Database     database `mapstructure:"DATABASE" json:"DATABASE" yaml:"DATABASE"`
-	Port         string   `mapstructure:"PORT" yaml:"PORT" json:"PORT" default:"3000"`
-	PostsPerPage int      `mapstructure:"PORT_PER_PAGE" json:"PORT_PER_PAGE" yaml:"PORT_PER_PAGE" default:"12"`
+	DNS              string      `mapstructure:"DNS" json:"DNS" yaml:"DNS"`
+	Database         database    `mapstructure:"DATABASE" json:"DATABASE" yaml:"DATABASE"`
+	Port             string      `mapstructure:"PORT" yaml:"PORT" json:"PORT" default:"3000"`
+	PostsPerPage     int         `mapstructure:"PORT_PER_PAGE" json:"PORT_PER_PAGE" yaml:"PORT_PER_PAGE" default:"12"`
+	SuggestionsLimit int         `mapstructure:"SUGGESTIONS_LIMIT" json:"SUGGESTIONS_LIMIT" yaml:"SUGGESTIONS_LIMIT" default:"5"`
+	BaseURL          string      `mapstructure:"BASE_URL" json:"BASE_URL" yaml:"BASE_URL" default:"http://localhost:3000"`
+	Secret           string      `mapstructure:"SECRET" json:"-" yaml:"SECRET"`
+	SiteName         string      `mapstructure:"SITE_NAME" json:"SITE_NAME" yaml:"SITE_NAME" default:"Newsteller"`
+	SiteDescription  string      `mapstructure:"SITE_DESCRIPTION" json:"SITE_DESCRIPTION" yaml:"SITE_DESCRIPTION" default:"Latest posts"`
+	Feeds            feeds       `mapstructure:"FEEDS" json:"FEEDS" yaml:"FEEDS"`
+	SEO              seo         `mapstructure:"SEO" json:"SEO" yaml:"SEO"`
+	OGImages         ogImages    `mapstructure:"OG_IMAGES" json:"OG_IMAGES" yaml:"OG_IMAGES"`
+	Robots           robots      `mapstructure:"ROBOTS" json:"ROBOTS" yaml:"ROBOTS"`
+	Newsletter       newsletter  `mapstructure:"NEWSLETTER" json:"NEWSLETTER" yaml:"NEWSLETTER"`
+	Mail             mail        `mapstructure:"MAIL" json:"MAIL" yaml:"MAIL"`
+	Webhooks         webhooks    `mapstructure:"WEBHOOKS" json:"WEBHOOKS" yaml:"WEBHOOKS"`
+	Events           events      `mapstructure:"EVENTS" json:"EVENTS" yaml:"EVENTS"`
+	Attachments      attachments `mapstructure:"ATTACHMENTS" json:"ATTACHMENTS" yaml:"ATTACHMENTS"`
+	Images           images      `mapstructure:"IMAGES" json:"IMAGES" yaml:"IMAGES"`
+	Comments         comments    `mapstructure:"COMMENTS" json:"COMMENTS" yaml:"COMMENTS"`
+	Antispam         antispam    `mapstructure:"ANTISPAM" json:"ANTISPAM" yaml:"ANTISPAM"`
+	// ProxyHeader - header a reverse proxy passes the client IP address in, e.g. X-Forwarded-For,
+	// clients are told apart by the address of the connection when empty
+	ProxyHeader string `mapstructure:"PROXY_HEADER" json:"PROXY_HEADER" yaml:"PROXY_HEADER"`
+	// TrustedProxies - comma separated addresses or CIDR ranges of the proxies, required with
+	// ProxyHeader: the header of other connections is ignored, anyone could send it
+	TrustedProxies string    `mapstructure:"TRUSTED_PROXIES" json:"TRUSTED_PROXIES" yaml:"TRUSTED_PROXIES"`
+	RateLimit      rateLimit `mapstructure:"RATE_LIMIT" json:"RATE_LIMIT" yaml:"RATE_LIMIT"`
+	Security       security  `mapstructure:"SECURITY" json:"SECURITY" yaml:"SECURITY"`
+	Templates      templates `mapstructure:"TEMPLATES" json:"TEMPLATES" yaml:"TEMPLATES"`
+	Theme          theme     `mapstructure:"THEME" json:"THEME" yaml:"THEME"`
+}
+
+type feeds struct {
+	// FullContent - include the whole post into feed items, only a summary otherwise
+	FullContent bool `mapstructure:"FULL_CONTENT" yaml:"FULL_CONTENT" default:"true"`
+	Items       int  `mapstructure:"ITEMS" yaml:"ITEMS" default:"20"`
+	// SummaryLength - max characters of item summaries
+	SummaryLength int `mapstructure:"SUMMARY_LENGTH" yaml:"SUMMARY_LENGTH" default:"300"`
+}
+
+type seo struct {
+	// Image - social preview of pages without their own, absolute or relative to BASE_URL
+	Image string `mapstructure:"IMAGE" yaml:"IMAGE"`
+	// Logo - publisher logo of the structured data, absolute or relative to BASE_URL
+	Logo string `mapstructure:"LOGO" yaml:"LOGO"`
+	// TwitterSite - @username of the site on X/Twitter
+	TwitterSite string `mapstructure:"TWITTER_SITE" yaml:"TWITTER_SITE"`
+	Locale      string `mapstructure:"LOCALE" yaml:"LOCALE" default:"en_US"`
+	// DefaultAuthor - author of posts without one, the site name when empty
+	DefaultAuthor string `mapstructure:"DEFAULT_AUTHOR" yaml:"DEFAULT_AUTHOR"`
+	// DescriptionLength - max characters of the excerpt describing a post
+	DescriptionLength int `mapstructure:"DESCRIPTION_LENGTH" yaml:"DESCRIPTION_LENGTH" default:"160"`
+}
+
+type ogImages struct {
+	// Enabled - generate preview cards of posts, the SEO image is used otherwise
+	Enabled bool `mapstructure:"ENABLED" yaml:"ENABLED" default:"true"`
+	// Store - cache of the rendered cards, one of "disk" or "gridfs"
+	Store     string `mapstructure:"STORE" yaml:"STORE" default:"disk"`
+	Directory string `mapstructure:"DIRECTORY" yaml:"DIRECTORY" default:"./cache/og"`
+}
+
+type robots struct {
+	// File - robots.txt served as is, generated from Disallow when empty
+	File string `mapstructure:"FILE" yaml:"FILE"`
+	// Disallow - comma separated path prefixes crawlers should skip, the admin pages by default
+	Disallow string `mapstructure:"DISALLOW" yaml:"DISALLOW" default:"/posts/create,/posts/edit,/posts/*/edit,/subscribers,/digests,/issues,/stats,/track,/webhooks"`
+}
+
+type mail struct {
+	// Transport - one of "smtp", "file" or "log"
+	Transport    string        `mapstructure:"TRANSPORT" yaml:"TRANSPORT" default:"log"`
+	From         string        `mapstructure:"FROM" yaml:"FROM" default:"Newsteller <no-reply@localhost>"`
+	Directory    string        `mapstructure:"DIRECTORY" yaml:"DIRECTORY" default:"./mail"`
+	MaxAttempts  int           `mapstructure:"MAX_ATTEMPTS" yaml:"MAX_ATTEMPTS" default:"6"`
+	RetryDelay   time.Duration `mapstructure:"RETRY_DELAY" yaml:"RETRY_DELAY" default:"1m"`
+	PollInterval time.Duration `mapstructure:"POLL_INTERVAL" yaml:"POLL_INTERVAL" default:"10s"`
+	SMTP         smtp          `mapstructure:"SMTP" yaml:"SMTP"`
+	Bounces      bounces       `mapstructure:"BOUNCES" yaml:"BOUNCES"`
+	// Notify - comma separated addresses told about published and deleted posts
+	Notify string `mapstructure:"NOTIFY" yaml:"NOTIFY"`
+}
+
+type bounces struct {
+	// Secret - token the bounce webhook must be called with, the webhook is disabled when empty
+	Secret string `mapstructure:"SECRET" json:"-" yaml:"SECRET"`
+	// Maildir - mailbox bounce notifications are delivered to, not watched when empty
+	Maildir      string        `mapstructure:"MAILDIR" yaml:"MAILDIR"`
+	PollInterval time.Duration `mapstructure:"POLL_INTERVAL" yaml:"POLL_INTERVAL" default:"1m"`
+	// HardLimit, SoftLimit and ComplaintLimit - reports within the window suppressing
+	// the subscriber, 0 never suppresses
+	HardLimit      int           `mapstructure:"HARD_LIMIT" yaml:"HARD_LIMIT" default:"1"`
+	SoftLimit      int           `mapstructure:"SOFT_LIMIT" yaml:"SOFT_LIMIT" default:"5"`
+	ComplaintLimit int           `mapstructure:"COMPLAINT_LIMIT" yaml:"COMPLAINT_LIMIT" default:"1"`
+	Window         time.Duration `mapstructure:"WINDOW" yaml:"WINDOW" default:"720h"`
+}
+
+type smtp struct {
+	Host     string `mapstructure:"HOST" yaml:"HOST" default:"localhost"`
+	Port     string `mapstructure:"PORT" yaml:"PORT" default:"587"`
+	Username string `mapstructure:"USERNAME" yaml:"USERNAME"`
+	Password string `mapstructure:"PASSWORD" json:"-" yaml:"PASSWORD"`
+	// StartTLS - refuse to send mail when the server does not support STARTTLS
+	StartTLS bool          `mapstructure:"STARTTLS" yaml:"STARTTLS" default:"true"`
+	Timeout  time.Duration `mapstructure:"TIMEOUT" yaml:"TIMEOUT" default:"30s"`
+}
+
+type webhooks struct {
+	MaxAttempts  int           `mapstructure:"MAX_ATTEMPTS" yaml:"MAX_ATTEMPTS" default:"8"`
+	RetryDelay   time.Duration `mapstructure:"RETRY_DELAY" yaml:"RETRY_DELAY" default:"30s"`
+	PollInterval time.Duration `mapstructure:"POLL_INTERVAL" yaml:"POLL_INTERVAL" default:"5s"`
+	// Timeout - max time an endpoint may take to answer a delivery
+	Timeout time.Duration `mapstructure:"TIMEOUT" yaml:"TIMEOUT" default:"10s"`
+	// AllowPrivate - deliver to loopback, private and link-local addresses, e.g. to endpoints
+	// running next to the site in development
+	AllowPrivate bool `mapstructure:"ALLOW_PRIVATE" yaml:"ALLOW_PRIVATE" default:"false"`
+}
+
+type events struct {
+	// MaxAttempts - deliveries of an event before subscribers still failing give up on it
+	MaxAttempts  int           `mapstructure:"MAX_ATTEMPTS" yaml:"MAX_ATTEMPTS" default:"10"`
+	RetryDelay   time.Duration `mapstructure:"RETRY_DELAY" yaml:"RETRY_DELAY" default:"30s"`
+	PollInterval time.Duration `mapstructure:"POLL_INTERVAL" yaml:"POLL_INTERVAL" default:"10s"`
+}
+
+type attachments struct {
+	// Store - storage of uploaded files, one of "local", "gridfs" or "s3"
+	Store     string `mapstructure:"STORE" yaml:"STORE" default:"local"`
+	Directory string `mapstructure:"DIRECTORY" yaml:"DIRECTORY" default:"./uploads"`
+	// MaxSize - largest accepted upload in bytes
+	MaxSize int64 `mapstructure:"MAX_SIZE" yaml:"MAX_SIZE" default:"10485760"`
+	// Types - comma separated accepted MIME types, sniffed from the content of uploads
+	Types string        `mapstructure:"TYPES" yaml:"TYPES" default:"image/jpeg,image/png,image/gif,image/webp,application/pdf"`
+	S3    s3Attachments `mapstructure:"S3" yaml:"S3"`
+}
+
+// s3Attachments - bucket of an S3-compatible service, e.g. AWS S3 or MinIO
+type s3Attachments struct {
+	Endpoint  string `mapstructure:"ENDPOINT" yaml:"ENDPOINT"`
+	Region    string `mapstructure:"REGION" yaml:"REGION"`
+	Bucket    string `mapstructure:"BUCKET" yaml:"BUCKET" default:"newsteller"`
+	AccessKey string `mapstructure:"ACCESS_KEY" json:"-" yaml:"ACCESS_KEY"`
+	SecretKey string `mapstructure:"SECRET_KEY" json:"-" yaml:"SECRET_KEY"`
+	UseSSL    bool   `mapstructure:"USE_SSL" yaml:"USE_SSL" default:"true"`
+}
+
+type images struct {
+	// Directory - cache of the resized variants of uploaded images
+	Directory string `mapstructure:"DIRECTORY" yaml:"DIRECTORY" default:"./cache/images"`
+	// Quality - JPEG quality of the variants, 1 to 100
+	Quality int `mapstructure:"QUALITY" yaml:"QUALITY" default:"82"`
+	// WebP - serve WebP variants to browsers accepting them, they are lossless, so
+	// smaller than PNG but usually larger than JPEG for photos
+	WebP bool `mapstructure:"WEBP" yaml:"WEBP" default:"false"`
+}
+
+type newsletter struct {
+	ConfirmationTTL time.Duration `mapstructure:"CONFIRMATION_TTL" yaml:"CONFIRMATION_TTL" default:"48h"`
+	// DigestHour - local hour digests of new subscribers are sent at
+	DigestHour int `mapstructure:"DIGEST_HOUR" yaml:"DIGEST_HOUR" default:"8"`
+	// DigestWeekday - day weekly digests of new subscribers are sent on, 0 is Sunday
+	DigestWeekday  int           `mapstructure:"DIGEST_WEEKDAY" yaml:"DIGEST_WEEKDAY" default:"1"`
+	DigestMaxPosts int           `mapstructure:"DIGEST_MAX_POSTS" yaml:"DIGEST_MAX_POSTS" default:"10"`
+	DigestInterval time.Duration `mapstructure:"DIGEST_INTERVAL" yaml:"DIGEST_INTERVAL" default:"1m"`
+	// IssueRate - max issue messages handed to the mail transport per minute, 0 disables throttling
+	IssueRate         int           `mapstructure:"ISSUE_RATE" yaml:"ISSUE_RATE" default:"60"`
+	IssuePollInterval time.Duration `mapstructure:"ISSUE_POLL_INTERVAL" yaml:"ISSUE_POLL_INTERVAL" default:"30s"`
+	// TrackOpens - embed an open pixel into digests and issues
+	TrackOpens bool `mapstructure:"TRACK_OPENS" yaml:"TRACK_OPENS" default:"false"`
+	// TrackClicks - route post links of digests and issues through signed click redirects
+	TrackClicks bool `mapstructure:"TRACK_CLICKS" yaml:"TRACK_CLICKS" default:"false"`
 }
 
 type database struct {
@@ -13,3 +183,93 @@ type database struct {
 	User     string `mapstructure:"USER" yaml:"USER"`
 	Password string `mapstructure:"PASSWORD" yaml:"PASSWORD"`
 }
+
+type comments struct {
+	// Enabled - readers may comment on posts, each post may still disable comments
+	Enabled bool `mapstructure:"ENABLED" yaml:"ENABLED" default:"true"`
+	// Moderation - new comments wait in the moderation queue, they are shown at once otherwise
+	Moderation bool `mapstructure:"MODERATION" yaml:"MODERATION" default:"true"`
+	// MaxDepth - deepest reply level, deeper replies are attached to the last level
+	MaxDepth int `mapstructure:"MAX_DEPTH" yaml:"MAX_DEPTH" default:"3"`
+}
+
+type antispam struct {
+	// Enabled - check the submissions of the public comment and subscribe forms
+	Enabled bool `mapstructure:"ENABLED" yaml:"ENABLED" default:"true"`
+	// MinSubmitTime - forms submitted sooner after being shown are rejected, bots fill them at once
+	MinSubmitTime time.Duration `mapstructure:"MIN_SUBMIT_TIME" yaml:"MIN_SUBMIT_TIME" default:"3s"`
+	// MaxFormAge - forms shown longer ago must be reloaded
+	MaxFormAge time.Duration `mapstructure:"MAX_FORM_AGE" yaml:"MAX_FORM_AGE" default:"24h"`
+	// Difficulty - leading zero bits of the proof of work, at most 32, every bit doubles the work of browsers
+	Difficulty int `mapstructure:"DIFFICULTY" yaml:"DIFFICULTY" default:"16"`
+	// SpamThreshold - comments scoring at least this percent are filed as spam without moderation
+	SpamThreshold int `mapstructure:"SPAM_THRESHOLD" yaml:"SPAM_THRESHOLD" default:"90"`
+	// Submissions - max submissions of a form per IP address within Window, 0 disables the limit
+	Submissions int           `mapstructure:"SUBMISSIONS" yaml:"SUBMISSIONS" default:"5"`
+	Window      time.Duration `mapstructure:"WINDOW" yaml:"WINDOW" default:"10m"`
+}
+
+// rateLimit - requests allowed per client within sliding windows, per route group.
+// Limits of groups matching a request all apply, 0 requests disable a group.
+type rateLimit struct {
+	Enabled bool `mapstructure:"ENABLED" yaml:"ENABLED" default:"true"`
+	// Store - counters of the windows, one of "memory" or "redis", shared by the replicas
+	Store string `mapstructure:"STORE" yaml:"STORE" default:"memory"`
+	// RedisURL - redis://[user:password@]host:port/db of the redis store
+	RedisURL string `mapstructure:"REDIS_URL" json:"-" yaml:"REDIS_URL" default:"redis://localhost:6379/0"`
+	// Requests - every request, per IP address
+	Requests int           `mapstructure:"REQUESTS" yaml:"REQUESTS" default:"300"`
+	Window   time.Duration `mapstructure:"WINDOW" yaml:"WINDOW" default:"1m"`
+	// WriteRequests - requests changing data (POST, PUT, PATCH and DELETE), per IP address
+	WriteRequests int           `mapstructure:"WRITE_REQUESTS" yaml:"WRITE_REQUESTS" default:"30"`
+	WriteWindow   time.Duration `mapstructure:"WRITE_WINDOW" yaml:"WRITE_WINDOW" default:"1m"`
+	// SearchRequests - searches and search suggestions, per IP address
+	SearchRequests int           `mapstructure:"SEARCH_REQUESTS" yaml:"SEARCH_REQUESTS" default:"60"`
+	SearchWindow   time.Duration `mapstructure:"SEARCH_WINDOW" yaml:"SEARCH_WINDOW" default:"1m"`
+	// TokenRequests - requests carrying a token, as a bearer token or the token query, per token
+	TokenRequests int           `mapstructure:"TOKEN_REQUESTS" yaml:"TOKEN_REQUESTS" default:"60"`
+	TokenWindow   time.Duration `mapstructure:"TOKEN_WINDOW" yaml:"TOKEN_WINDOW" default:"1m"`
+}
+
+// security - protection of the pages against cross-site attacks, empty headers are not sent
+type security struct {
+	// CSRF - requests changing data must carry the token of the csrf cookie in the X-CSRF-Token header
+	CSRF bool `mapstructure:"CSRF" yaml:"CSRF" default:"true"`
+	// CSP - Content-Security-Policy, {nonce} is replaced by the nonce of the inline scripts of the response
+	CSP string `mapstructure:"CSP" yaml:"CSP" default:"default-src 'self'; script-src 'nonce-{nonce}' 'strict-dynamic'; style-src 'self' 'unsafe-inline'; img-src 'self' data: https:; object-src 'none'; base-uri 'self'; form-action 'self'; frame-ancestors 'none'"`
+	// HSTSMaxAge - browsers keep using HTTPS this long, sent only when BASE_URL is https, 0 disables it
+	HSTSMaxAge     time.Duration `mapstructure:"HSTS_MAX_AGE" yaml:"HSTS_MAX_AGE" default:"8760h"`
+	FrameOptions   string        `mapstructure:"FRAME_OPTIONS" yaml:"FRAME_OPTIONS" default:"DENY"`
+	ReferrerPolicy string        `mapstructure:"REFERRER_POLICY" yaml:"REFERRER_POLICY" default:"strict-origin-when-cross-origin"`
+}
+
+type templates struct {
+	// Dir - templates replacing the embedded ones of the same path, e.g. <dir>/pages/post.html,
+	// none are replaced when empty
+	Dir string `mapstructure:"DIR" yaml:"DIR"`
+	// Reload - parse the templates on every render and skip the pages cache, for development
+	// with DIR set to ./internal/templates/html
+	Reload bool `mapstructure:"RELOAD" yaml:"RELOAD" default:"false"`
+}
+
+// theme - branding of the pages, the keys of <DIR>/theme.yaml replace the values set here
+type theme struct {
+	// Dir - theme directory holding theme.yaml, the files it references, e.g. LOGO: logo.svg,
+	// and theme.css added to the styles of every page
+	Dir string `mapstructure:"DIR" yaml:"DIR"`
+	// SiteName - name shown in the header of pages, SITE_NAME when empty
+	SiteName string `mapstructure:"SITE_NAME" yaml:"SITE_NAME"`
+	// Logo - image shown next to the site name, a file of the theme directory or a URL
+	Logo string `mapstructure:"LOGO" yaml:"LOGO"`
+	// Font - CSS font-family of the pages
+	Font string `mapstructure:"FONT" yaml:"FONT"`
+	// Colors and DarkColors - comma separated name=value colours replacing those of the light
+	// and the dark palette, e.g. primary=#e63946, see internal/theme for the names
+	Colors     string `mapstructure:"COLORS" yaml:"COLORS"`
+	DarkColors string `mapstructure:"DARK_COLORS" yaml:"DARK_COLORS"`
+	// FooterLinks - comma separated label=URL links in the footer of pages
+	FooterLinks string `mapstructure:"FOOTER_LINKS" yaml:"FOOTER_LINKS"`
+	// Mode - palette of readers without a preference of their own: light, dark or auto,
+	// following their system
+	Mode string `mapstructure:"MODE" yaml:"MODE" default:"auto"`
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"github.com/joho/godotenv"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 
@@ -18,13 +19,41 @@ const (
 	defaultEnvFileName    = ".env"
 )
 
+const themeFileName = "theme.yaml"
+
 func Read() (*Config, error) {
 	var cfg Config
 	err := read(&cfg)
+	if err == nil && cfg.Theme.Dir != "" {
+		err = readTheme(&cfg.Theme)
+	}
 
 	return &cfg, err
 }
 
+// readTheme replaces the theme values with the keys of the theme file, when the theme
+// directory has one.
+func readTheme(t *theme) error {
+	file := filepath.Join(t.Dir, themeFileName)
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		return nil
+	}
+
+	reader := viper.New()
+	reader.SetConfigFile(file)
+	if err := reader.ReadInConfig(); err != nil {
+		return errors.Wrap(err, "read theme")
+	}
+	dir := t.Dir
+	if err := reader.Unmarshal(t); err != nil {
+		return errors.WithMessage(err, "failed to parse theme")
+	}
+	// files of the theme are relative to its directory
+	t.Dir = dir
+
+	return nil
+}
+
 func read(config any, opts ...viper.DecoderConfigOption) error {
 	reader := viper.New()
 	// replace default viper delimiter for env vars
@@ -26,6 +26,21 @@ func Connect(ctx context.Context, cfg *config.Config) (*mongo.Client, error) {
 		cfg,
 		client,
 		models.Post{},
+		models.SearchQuery{},
+		models.Subscriber{},
+		models.OutboxMessage{},
+		models.DigestSend{},
+		models.Issue{},
+		models.TrackingEvent{},
+		models.Bounce{},
+		models.Webhook{},
+		models.WebhookDelivery{},
+		models.OutboxEvent{},
+		models.Tag{},
+		models.Category{},
+		models.Attachment{},
+		models.Comment{},
+		models.SpamToken{},
 	)
 	if err != nil {
 		zap.L().Error("failed to migrate database", zap.Error(err))
@@ -0,0 +1,85 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"slices"
+	"sync"
+)
+
+type handler struct {
+	subscriber string
+	handle     func(ctx context.Context, event Event) error
+}
+
+// Bus delivers events to the subscribers of their type, in the order of subscription.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[Name][]handler
+}
+
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[Name][]handler)}
+}
+
+// Subscribe registers the handler of the events of type E. The subscriber names the
+// handler in the outbox, it must be unique per event type and stable across restarts.
+func Subscribe[E Event](bus *Bus, subscriber string, handle func(ctx context.Context, event E) error) {
+	var zero E
+
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.handlers[zero.Name()] = append(bus.handlers[zero.Name()], handler{
+		subscriber: subscriber,
+		handle: func(ctx context.Context, event Event) error {
+			return handle(ctx, event.(E))
+		},
+	})
+}
+
+// Publish delivers the event to its subscribers, skipping the handled ones, and returns
+// every subscriber done with the event. Failures of subscribers are joined into the error,
+// they get the event again when it is published with the returned handled subscribers.
+func (b *Bus) Publish(ctx context.Context, event Event, handled []string) ([]string, error) {
+	b.mu.RLock()
+	handlers := b.handlers[event.Name()]
+	b.mu.RUnlock()
+
+	done := slices.Clone(handled)
+	var errs []error
+	for _, h := range handlers {
+		if slices.Contains(done, h.subscriber) {
+			continue
+		}
+
+		err := call(ctx, h, event)
+		if err != nil {
+			zap.L().Error(
+				"event subscriber failed",
+				zap.String("event", string(event.Name())),
+				zap.String("id", event.Meta().ID),
+				zap.String("subscriber", h.subscriber),
+				zap.Error(err),
+			)
+			errs = append(errs, fmt.Errorf("%s: %w", h.subscriber, err))
+			continue
+		}
+		done = append(done, h.subscriber)
+	}
+
+	return done, errors.Join(errs...)
+}
+
+// call runs the handler turning its panics into errors, a broken subscriber must not
+// take down the request or the relay publishing the event.
+func call(ctx context.Context, h handler, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return h.handle(ctx, event)
+}
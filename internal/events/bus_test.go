@@ -0,0 +1,70 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"newsteller/internal/models"
+)
+
+func TestBus_Publish(t *testing.T) {
+	bus := NewBus()
+	var calls []string
+	Subscribe(bus, "cache", func(_ context.Context, event PostCreated) error {
+		calls = append(calls, "cache:"+event.Post.Title)
+		return nil
+	})
+	Subscribe(bus, "webhooks", func(context.Context, PostCreated) error {
+		calls = append(calls, "webhooks")
+		return errors.New("endpoint is down")
+	})
+	Subscribe(bus, "search", func(context.Context, PostDeleted) error {
+		calls = append(calls, "search")
+		return nil
+	})
+
+	event := PostCreated{Metadata: Metadata{ID: "1"}, Post: models.Post{Title: "Hello"}}
+
+	t.Run("Negative: Failures are reported after every subscriber ran", func(t *testing.T) {
+		calls = nil
+
+		handled, err := bus.Publish(context.Background(), event, nil)
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "webhooks: endpoint is down")
+		assert.Equal(t, []string{"cache"}, handled)
+		assert.Equal(t, []string{"cache:Hello", "webhooks"}, calls, "Subscribers of other events should not run")
+	})
+
+	t.Run("Positive: Handled subscribers are skipped", func(t *testing.T) {
+		calls = nil
+
+		handled, err := bus.Publish(context.Background(), event, []string{"cache", "webhooks"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"cache", "webhooks"}, handled)
+		assert.Empty(t, calls)
+	})
+
+	t.Run("Positive: Events without subscribers are handled", func(t *testing.T) {
+		handled, err := bus.Publish(context.Background(), PostPublished{}, nil)
+		require.NoError(t, err)
+		assert.Empty(t, handled)
+	})
+}
+
+func TestBus_PublishRecoversPanics(t *testing.T) {
+	bus := NewBus()
+	Subscribe(bus, "broken", func(context.Context, PostUpdated) error {
+		panic("nil map")
+	})
+	Subscribe(bus, "search", func(context.Context, PostUpdated) error {
+		return nil
+	})
+
+	handled, err := bus.Publish(context.Background(), PostUpdated{}, nil)
+	assert.ErrorContains(t, err, "broken: panic: nil map")
+	assert.Equal(t, []string{"search"}, handled)
+}
@@ -0,0 +1,79 @@
+package events
+
+import (
+	"newsteller/internal/models"
+	"time"
+)
+
+/**
+Domain events of posts. The state layer records every change of a post in the outbox
+ahead of writing it and publishes the events on the Bus right after, subscribers keep
+the page cache, the search index, webhooks and mail notifications in sync. Events of a
+process that crashed in between are published by the Relay, so they are never lost.
+*/
+
+type Name string
+
+const (
+	NamePostCreated Name = "post.created"
+	NamePostUpdated Name = "post.updated"
+	// NamePostPublished - post became visible to readers, posts are public once created
+	NamePostPublished Name = "post.published"
+	NamePostDeleted   Name = "post.deleted"
+)
+
+// Event is a change subscribers are told about.
+type Event interface {
+	Name() Name
+	Meta() Metadata
+}
+
+// Metadata identifies an occurrence of an event, it stays the same when the event
+// is delivered again, so subscribers can skip duplicates.
+type Metadata struct {
+	ID         string
+	OccurredAt time.Time
+}
+
+func (m Metadata) Meta() Metadata {
+	return m
+}
+
+type PostCreated struct {
+	Metadata
+	Post models.Post
+}
+
+func (PostCreated) Name() Name {
+	return NamePostCreated
+}
+
+type PostUpdated struct {
+	Metadata
+	Post models.Post
+	// Previous - the post before the update
+	Previous models.Post
+}
+
+func (PostUpdated) Name() Name {
+	return NamePostUpdated
+}
+
+type PostPublished struct {
+	Metadata
+	Post models.Post
+}
+
+func (PostPublished) Name() Name {
+	return NamePostPublished
+}
+
+type PostDeleted struct {
+	Metadata
+	// Post - the post as it was before the deletion
+	Post models.Post
+}
+
+func (PostDeleted) Name() Name {
+	return NamePostDeleted
+}
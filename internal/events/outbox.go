@@ -0,0 +1,221 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"newsteller/internal/config"
+	"newsteller/internal/models"
+	"newsteller/internal/repositories"
+	"time"
+)
+
+const (
+	// prepareTimeout - time a change has to be written before the relay checks whether it happened
+	prepareTimeout = time.Minute
+	// deliveryLease - time the publisher of an event has before the relay takes the event over
+	deliveryLease = time.Minute
+	// maxRetryDelay - upper bound of the delay between deliveries of an event
+	maxRetryDelay = time.Hour
+)
+
+// Outbox stores events until every subscriber handled them. MongoDB runs without
+// transactions here, so events are recorded ahead of the change as prepared, committed
+// once the change was written and discarded when it failed. The Relay settles prepared
+// events left behind by a crash by checking whether their change happened.
+type Outbox struct {
+	cfg   *config.Config
+	repo  *repositories.OutboxEvent
+	posts *repositories.Post
+	bus   *Bus
+}
+
+func NewOutbox(cfg *config.Config, events *mongo.Collection, posts *mongo.Collection, bus *Bus) *Outbox {
+	return &Outbox{
+		cfg:   cfg,
+		repo:  repositories.NewOutboxEventRepository(events),
+		posts: repositories.NewPostRepository(posts),
+		bus:   bus,
+	}
+}
+
+// Batch is a group of events prepared for a single change.
+type Batch struct {
+	outbox  *Outbox
+	records []models.OutboxEvent
+}
+
+// Prepare records the events ahead of the change they describe. A nil outbox records
+// nothing and returns a nil batch, which is safe to commit and discard.
+func (o *Outbox) Prepare(ctx context.Context, events ...Event) (*Batch, error) {
+	if o == nil {
+		return nil, nil
+	}
+
+	// stored with millisecond precision, kept the same for the first and later deliveries
+	now := time.Now().Truncate(time.Millisecond)
+	records := make([]models.OutboxEvent, 0, len(events))
+	for _, event := range events {
+		record, err := newRecord(event, now)
+		if err != nil {
+			return nil, err
+		}
+		record.NextAttemptAt = now.Add(prepareTimeout)
+		records = append(records, record)
+	}
+
+	err := o.repo.Create(ctx, records)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Batch{outbox: o, records: records}, nil
+}
+
+// Commit marks the events as happened and publishes them. Subscribers failing are
+// retried by the Relay, so the change is never failed by them.
+func (b *Batch) Commit(ctx context.Context) {
+	if b == nil {
+		return
+	}
+
+	err := b.outbox.repo.Commit(ctx, b.ids(), time.Now().Add(deliveryLease))
+	if err != nil {
+		// the events stay prepared, the relay finds the change happened and publishes them
+		return
+	}
+	for i := range b.records {
+		b.records[i].Status = models.OutboxEventPending
+		b.outbox.deliver(ctx, &b.records[i])
+	}
+}
+
+// Discard drops the events of a change that failed.
+func (b *Batch) Discard(ctx context.Context) {
+	if b == nil {
+		return
+	}
+
+	// events left behind are discarded by the relay, their change is not found
+	_ = b.outbox.repo.Discard(ctx, b.ids())
+}
+
+func (b *Batch) ids() []primitive.ObjectID {
+	ids := make([]primitive.ObjectID, len(b.records))
+	for i := range b.records {
+		ids[i] = b.records[i].ID
+	}
+
+	return ids
+}
+
+// deliver publishes the event and records the subscribers done with it.
+func (o *Outbox) deliver(ctx context.Context, record *models.OutboxEvent) {
+	attempts := record.Attempts + 1
+	event, err := decode(record)
+	if err != nil {
+		zap.L().Error("could not decode outbox event", zap.String("id", record.ID.Hex()), zap.Error(err))
+		_ = o.repo.MarkFailed(ctx, record.ID, record.Handled, attempts, err.Error())
+		return
+	}
+
+	handled, err := o.bus.Publish(ctx, event, record.Handled)
+	switch {
+	case err == nil:
+		_ = o.repo.MarkPublished(ctx, record.ID, handled, attempts, time.Now())
+	case attempts >= o.cfg.Events.MaxAttempts:
+		zap.L().Error(
+			"giving up on outbox event",
+			zap.String("id", record.ID.Hex()),
+			zap.String("event", record.Name),
+			zap.Int("attempts", attempts),
+			zap.Error(err),
+		)
+		_ = o.repo.MarkFailed(ctx, record.ID, handled, attempts, err.Error())
+	default:
+		next := time.Now().Add(RetryDelay(o.cfg.Events.RetryDelay, attempts))
+		_ = o.repo.MarkRetry(ctx, record.ID, handled, attempts, next, err.Error())
+	}
+}
+
+// happened reports whether the change of a prepared event was written.
+func (o *Outbox) happened(ctx context.Context, record *models.OutboxEvent) (bool, error) {
+	post, err := o.posts.FindByID(ctx, record.Post.ID.Hex())
+	missing := errors.Is(err, mongo.ErrNoDocuments)
+	if err != nil && !missing {
+		return false, err
+	}
+
+	switch Name(record.Name) {
+	case NamePostDeleted:
+		return missing, nil
+	case NamePostUpdated:
+		// every update moves UpdatedAt, an older or newer one means a different change
+		return !missing && post.UpdatedAt.Equal(record.Post.UpdatedAt), nil
+	default:
+		return !missing, nil
+	}
+}
+
+// RetryDelay returns the delay before the next delivery, doubling the base delay
+// after every failed one.
+func RetryDelay(base time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+
+	return delay
+}
+
+func newRecord(event Event, now time.Time) (models.OutboxEvent, error) {
+	record := models.OutboxEvent{
+		ID:        primitive.NewObjectID(),
+		Name:      string(event.Name()),
+		Status:    models.OutboxEventPrepared,
+		CreatedAt: now,
+	}
+
+	switch e := event.(type) {
+	case PostCreated:
+		record.Post = e.Post
+	case PostUpdated:
+		record.Post, record.Previous = e.Post, &e.Previous
+	case PostPublished:
+		record.Post = e.Post
+	case PostDeleted:
+		record.Post = e.Post
+	default:
+		return models.OutboxEvent{}, fmt.Errorf("unknown event %q", event.Name())
+	}
+
+	return record, nil
+}
+
+// decode restores the event of the record, its metadata comes from the record.
+func decode(record *models.OutboxEvent) (Event, error) {
+	meta := Metadata{ID: record.ID.Hex(), OccurredAt: record.CreatedAt}
+
+	switch Name(record.Name) {
+	case NamePostCreated:
+		return PostCreated{Metadata: meta, Post: record.Post}, nil
+	case NamePostUpdated:
+		event := PostUpdated{Metadata: meta, Post: record.Post}
+		if record.Previous != nil {
+			event.Previous = *record.Previous
+		}
+		return event, nil
+	case NamePostPublished:
+		return PostPublished{Metadata: meta, Post: record.Post}, nil
+	case NamePostDeleted:
+		return PostDeleted{Metadata: meta, Post: record.Post}, nil
+	default:
+		return nil, fmt.Errorf("unknown event %q", record.Name)
+	}
+}
@@ -0,0 +1,79 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"newsteller/internal/models"
+)
+
+func TestRecord_RoundTrip(t *testing.T) {
+	now := time.Date(2025, time.March, 4, 15, 30, 0, 0, time.UTC)
+	post := models.Post{ID: primitive.NewObjectID(), Title: "After"}
+	previous := models.Post{ID: post.ID, Title: "Before"}
+
+	tests := []struct {
+		name  string
+		event Event
+	}{
+		{"Positive: Created", PostCreated{Post: post}},
+		{"Positive: Updated", PostUpdated{Post: post, Previous: previous}},
+		{"Positive: Published", PostPublished{Post: post}},
+		{"Positive: Deleted", PostDeleted{Post: post}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record, err := newRecord(tt.event, now)
+			require.NoError(t, err)
+			assert.Equal(t, string(tt.event.Name()), record.Name)
+			assert.Equal(t, models.OutboxEventPrepared, record.Status)
+			assert.False(t, record.ID.IsZero())
+
+			event, err := decode(&record)
+			require.NoError(t, err)
+			assert.Equal(t, tt.event.Name(), event.Name())
+			assert.Equal(t, Metadata{ID: record.ID.Hex(), OccurredAt: now}, event.Meta())
+		})
+	}
+
+	t.Run("Positive: Updated keeps the previous post", func(t *testing.T) {
+		record, err := newRecord(PostUpdated{Post: post, Previous: previous}, now)
+		require.NoError(t, err)
+
+		event, err := decode(&record)
+		require.NoError(t, err)
+		updated := event.(PostUpdated)
+		assert.Equal(t, "After", updated.Post.Title)
+		assert.Equal(t, "Before", updated.Previous.Title)
+	})
+
+	t.Run("Negative: Unknown event", func(t *testing.T) {
+		_, err := decode(&models.OutboxEvent{Name: "post.archived"})
+		assert.ErrorContains(t, err, `unknown event "post.archived"`)
+	})
+}
+
+func TestOutbox_Disabled(t *testing.T) {
+	var outbox *Outbox
+
+	batch, err := outbox.Prepare(context.Background(), PostCreated{})
+	require.NoError(t, err)
+	assert.Nil(t, batch)
+
+	// a nil batch is committed and discarded without effect
+	batch.Commit(context.Background())
+	batch.Discard(context.Background())
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, RetryDelay(30*time.Second, 1))
+	assert.Equal(t, time.Minute, RetryDelay(30*time.Second, 2))
+	assert.Equal(t, 4*time.Minute, RetryDelay(30*time.Second, 4))
+	assert.Equal(t, maxRetryDelay, RetryDelay(30*time.Second, 20))
+}
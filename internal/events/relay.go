@@ -0,0 +1,73 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"newsteller/internal/models"
+	"time"
+)
+
+// Relay publishes the events the process writing them did not: events of a crash
+// between the change and its publication, and events some subscribers failed.
+type Relay struct {
+	outbox *Outbox
+}
+
+func NewRelay(outbox *Outbox) *Relay {
+	return &Relay{outbox: outbox}
+}
+
+// Run publishes due events every poll interval until the context is cancelled.
+// Subscribers must be registered on the bus before, events are not kept for late ones.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.outbox.cfg.Events.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		r.publishDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Relay) publishDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		record, err := r.outbox.repo.ClaimDue(ctx, time.Now(), deliveryLease)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return
+		}
+		if err != nil {
+			zap.L().Error("could not claim outbox event", zap.Error(err))
+			return
+		}
+
+		if record.Status == models.OutboxEventPrepared && !r.settle(ctx, record) {
+			continue
+		}
+		r.outbox.deliver(ctx, record)
+	}
+}
+
+// settle decides the fate of an event prepared by a process that never committed or
+// discarded it, returning whether the event should be published.
+func (r *Relay) settle(ctx context.Context, record *models.OutboxEvent) bool {
+	happened, err := r.outbox.happened(ctx, record)
+	if err != nil {
+		zap.L().Error("could not check outbox event", zap.String("id", record.ID.Hex()), zap.Error(err))
+		return false
+	}
+	if !happened {
+		zap.L().Info("discarding event of a change that did not happen", zap.String("id", record.ID.Hex()))
+		_ = r.outbox.repo.Discard(ctx, []primitive.ObjectID{record.ID})
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,133 @@
+package feeds
+
+import (
+	"html"
+	"net/url"
+	"newsteller/internal/config"
+	"newsteller/internal/models"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+/**
+Syndication feeds of the posts. A Feed is built once from the posts and rendered
+as RSS 2.0, Atom 1.0 or JSON Feed 1.1, all links are absolute.
+*/
+
+// Feed is a format independent syndication feed.
+type Feed struct {
+	Title       string
+	Description string
+	// HomeURL - page the feed belongs to
+	HomeURL string
+	// FeedURL - URL the feed is served at
+	FeedURL string
+	// Updated - latest change of the items, zero for empty feeds
+	Updated time.Time
+	Items   []Item
+}
+
+type Item struct {
+	ID      string
+	Title   string
+	URL     string
+	Author  string
+	Tags    []string
+	Summary string
+	// ContentHTML - whole post as HTML, empty when feeds publish summaries only
+	ContentHTML string
+	Published   time.Time
+	Updated     time.Time
+}
+
+// Builder turns posts into feeds according to the configuration.
+type Builder struct {
+	cfg *config.Config
+}
+
+func NewBuilder(cfg *config.Config) *Builder {
+	return &Builder{cfg: cfg}
+}
+
+// Build returns the feed of the posts served at the path, e.g. "/tags/go/feed.xml".
+func (b *Builder) Build(title, description, path string, posts []models.Post) *Feed {
+	feed := &Feed{
+		Title:       title,
+		Description: description,
+		HomeURL:     b.cfg.BaseURL + "/home",
+		FeedURL:     b.cfg.BaseURL + path,
+		Items:       make([]Item, 0, len(posts)),
+	}
+
+	for _, post := range posts {
+		item := Item{
+			// IDs outlive slugs, so readers do not see renamed posts as new ones
+			ID:        b.cfg.BaseURL + "/posts/" + post.ID.Hex(),
+			Title:     post.Title,
+			URL:       b.cfg.BaseURL + post.Path(),
+			Author:    post.Author,
+			Tags:      post.Tags,
+			Summary:   Summary(post.Content, b.cfg.Feeds.SummaryLength),
+			Published: post.CreatedAt,
+			Updated:   post.UpdatedAt,
+		}
+		if item.Updated.Before(item.Published) {
+			item.Updated = item.Published
+		}
+		if b.cfg.Feeds.FullContent {
+			item.ContentHTML = ContentHTML(post.Content)
+		}
+		if item.Updated.After(feed.Updated) {
+			feed.Updated = item.Updated
+		}
+
+		feed.Items = append(feed.Items, item)
+	}
+
+	return feed
+}
+
+// Path returns the feed path of the site, a tag or an author for the file name, e.g. "feed.xml".
+func Path(tag, author, file string) string {
+	switch {
+	case tag != "":
+		return "/tags/" + url.PathEscape(tag) + "/" + file
+	case author != "":
+		return "/authors/" + url.PathEscape(author) + "/" + file
+	default:
+		return "/" + file
+	}
+}
+
+// ContentHTML renders the plain text post content as HTML paragraphs.
+func ContentHTML(content string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	var paragraphs []string
+	for _, paragraph := range strings.Split(content, "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		lines := strings.Split(html.EscapeString(paragraph), "\n")
+		paragraphs = append(paragraphs, "<p>"+strings.Join(lines, "<br>")+"</p>")
+	}
+
+	return strings.Join(paragraphs, "\n")
+}
+
+// Summary collapses the whitespace of the content and cuts it at a word boundary.
+func Summary(content string, maxLength int) string {
+	content = strings.Join(strings.Fields(content), " ")
+	if utf8.RuneCountInString(content) <= maxLength {
+		return content
+	}
+
+	cut := string([]rune(content)[:maxLength])
+	if space := strings.LastIndex(cut, " "); space > 0 {
+		cut = cut[:space]
+	}
+
+	return strings.TrimRight(cut, " ,.;:") + "…"
+}
@@ -0,0 +1,150 @@
+package feeds
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"newsteller/internal/config"
+	"newsteller/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func testFeed(fullContent bool) (*Feed, []models.Post) {
+	cfg := &config.Config{BaseURL: "https://news.example.com"}
+	cfg.Feeds.FullContent = fullContent
+	cfg.Feeds.SummaryLength = 40
+
+	created := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	posts := []models.Post{
+		{
+			ID:        primitive.NewObjectID(),
+			Title:     "Go & Mongo",
+			Content:   "First paragraph about <generics>.\n\nSecond paragraph\nwith a line break.",
+			CreatedAt: created,
+			UpdatedAt: created.Add(time.Hour),
+			Tags:      []string{"go", "databases"},
+			Author:    "Jane Doe",
+			Slug:      "go-mongo",
+		},
+		{
+			ID:        primitive.NewObjectID(),
+			Title:     "Untagged",
+			Content:   "Short",
+			CreatedAt: created.Add(-24 * time.Hour),
+		},
+	}
+
+	return NewBuilder(cfg).Build("Newsteller", "Latest posts", "/feed.xml", posts), posts
+}
+
+func TestBuilder_Build(t *testing.T) {
+	feed, posts := testFeed(true)
+
+	assert.Equal(t, "https://news.example.com/home", feed.HomeURL)
+	assert.Equal(t, "https://news.example.com/feed.xml", feed.FeedURL)
+	assert.Equal(t, posts[0].UpdatedAt, feed.Updated)
+	require.Len(t, feed.Items, 2)
+
+	item := feed.Items[0]
+	assert.Equal(t, "https://news.example.com/posts/go-mongo", item.URL)
+	assert.Equal(t, "https://news.example.com/posts/"+posts[0].ID.Hex(), item.ID, "IDs do not change with the slug")
+	assert.Equal(t, "First paragraph about <generics>…", item.Summary)
+	assert.Equal(t,
+		"<p>First paragraph about &lt;generics&gt;.</p>\n<p>Second paragraph<br>with a line break.</p>",
+		item.ContentHTML,
+	)
+	assert.Equal(t, posts[1].CreatedAt, feed.Items[1].Updated, "never updated posts are updated when created")
+
+	t.Run("Positive: Summaries only", func(t *testing.T) {
+		feed, _ := testFeed(false)
+		assert.Empty(t, feed.Items[0].ContentHTML)
+		assert.NotEmpty(t, feed.Items[0].Summary)
+	})
+}
+
+func TestPath(t *testing.T) {
+	assert.Equal(t, "/feed.xml", Path("", "", "feed.xml"))
+	assert.Equal(t, "/tags/machine%20learning/atom.xml", Path("machine learning", "", "atom.xml"))
+	assert.Equal(t, "/authors/Jane%20Doe/feed.json", Path("", "Jane Doe", "feed.json"))
+}
+
+func TestSummary(t *testing.T) {
+	assert.Equal(t, "Short text", Summary("  Short\n text ", 20))
+	assert.Equal(t, "Über die…", Summary("Über die Brücke gehen", 12))
+}
+
+func TestFormats(t *testing.T) {
+	feed, posts := testFeed(true)
+
+	t.Run("Positive: RSS", func(t *testing.T) {
+		body, err := RSS.Render(feed)
+		require.NoError(t, err)
+		document := string(body)
+
+		assert.True(t, strings.HasPrefix(document, xml.Header))
+		assert.Contains(t, document, `<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom"`)
+		assert.Contains(t, document, `<atom:link href="https://news.example.com/feed.xml" rel="self" type="application/rss+xml"></atom:link>`)
+		assert.Contains(t, document, "<title>Go &amp; Mongo</title>")
+		assert.Contains(t, document, "<link>https://news.example.com/posts/go-mongo</link>")
+		assert.Contains(t, document, `<guid isPermaLink="false">https://news.example.com/posts/`+posts[0].ID.Hex()+`</guid>`)
+		assert.Contains(t, document, `<guid isPermaLink="true">https://news.example.com/posts/`+posts[1].ID.Hex()+`</guid>`)
+		assert.Contains(t, document, "<pubDate>Thu, 01 Oct 2026 08:00:00 +0000</pubDate>")
+		assert.Contains(t, document, "<dc:creator>Jane Doe</dc:creator>")
+		assert.Contains(t, document, "<category>databases</category>")
+		assert.Contains(t, document, "<content:encoded>&lt;p&gt;First paragraph")
+		assert.Equal(t, 1, strings.Count(document, "<dc:creator>"), "posts without author have no creator")
+
+		var parsed struct {
+			Items []struct {
+				Title string `xml:"title"`
+			} `xml:"channel>item"`
+		}
+		require.NoError(t, xml.Unmarshal(body, &parsed))
+		assert.Len(t, parsed.Items, 2)
+	})
+
+	t.Run("Positive: Atom", func(t *testing.T) {
+		body, err := Atom.Render(feed)
+		require.NoError(t, err)
+		document := string(body)
+
+		assert.Contains(t, document, `<feed xmlns="http://www.w3.org/2005/Atom">`)
+		assert.Contains(t, document, "<updated>2026-10-01T09:00:00Z</updated>")
+		assert.Contains(t, document, `<link href="https://news.example.com/feed.xml" rel="self" type="application/atom+xml"></link>`)
+		assert.Contains(t, document, "<published>2026-10-01T08:00:00Z</published>")
+		assert.Contains(t, document, `<category term="go"></category>`)
+		assert.Contains(t, document, `<content type="html">&lt;p&gt;First paragraph`)
+		assert.Contains(t, document, `<summary type="text">Short</summary>`)
+	})
+
+	t.Run("Positive: JSON Feed", func(t *testing.T) {
+		body, err := JSON.Render(feed)
+		require.NoError(t, err)
+
+		var parsed jsonFeed
+		require.NoError(t, json.Unmarshal(body, &parsed))
+		assert.Equal(t, "https://jsonfeed.org/version/1.1", parsed.Version)
+		assert.Equal(t, "https://news.example.com/feed.xml", parsed.FeedURL)
+		require.Len(t, parsed.Items, 2)
+		assert.Equal(t, []jsonAuthor{{Name: "Jane Doe"}}, parsed.Items[0].Authors)
+		assert.Equal(t, []string{"go", "databases"}, parsed.Items[0].Tags)
+		assert.Empty(t, parsed.Items[0].ContentText)
+		assert.Equal(t, "2026-10-01T09:00:00Z", parsed.Items[0].DateModified)
+	})
+
+	t.Run("Positive: JSON Feed with summaries only", func(t *testing.T) {
+		feed, _ := testFeed(false)
+		body, err := JSON.Render(feed)
+		require.NoError(t, err)
+
+		var parsed jsonFeed
+		require.NoError(t, json.Unmarshal(body, &parsed))
+		assert.Empty(t, parsed.Items[0].ContentHTML)
+		assert.Equal(t, parsed.Items[0].Summary, parsed.Items[0].ContentText)
+	})
+}
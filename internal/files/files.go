@@ -0,0 +1,31 @@
+package files
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// WriteAtomic writes the data to the file at path, creating its directory. The data is
+// written aside and renamed over the file, so readers never see a partial file.
+func WriteAtomic(path string, data []byte) error {
+	directory := filepath.Dir(path)
+	err := os.MkdirAll(directory, 0o755)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(directory, filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,26 @@
+package files
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteAtomic(t *testing.T) {
+	directory := filepath.Join(t.TempDir(), "nested")
+	path := filepath.Join(directory, "file.txt")
+
+	require.NoError(t, WriteAtomic(path, []byte("first")))
+	require.NoError(t, WriteAtomic(path, []byte("second")))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("second"), data)
+
+	entries, err := os.ReadDir(directory)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary files are left")
+}
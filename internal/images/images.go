@@ -0,0 +1,89 @@
+package images
+
+import (
+	"errors"
+	"path"
+	"strconv"
+	"strings"
+)
+
+/**
+Responsive variants of uploaded images. Variants are resized to one of the Widths,
+never enlarged, and re-encoded without metadata; they are generated on first request
+and cached on disk. JPEG photos stay JPEG, other images become PNG to keep their
+transparency, and browsers accepting WebP may get WebP variants instead.
+*/
+
+// Widths - widths of the variants listed in srcset
+var Widths = []int{320, 640, 960, 1280, 1920}
+
+const (
+	// DefaultWidth - width of the src of images, for browsers ignoring srcset
+	DefaultWidth = 960
+	// OpenGraphWidth - width of the hero images of link previews
+	OpenGraphWidth = 1280
+)
+
+const (
+	FormatJPEG = "jpg"
+	FormatPNG  = "png"
+	FormatWebP = "webp"
+)
+
+// maxPixels - largest decoded image, larger ones are rejected before allocating them
+const maxPixels = 50_000_000
+
+var (
+	ErrUnsupportedWidth = errors.New("unsupported image width")
+	ErrTooLarge         = errors.New("image is too large")
+)
+
+// Resizable reports whether the attachment key names an image with variants, animated
+// GIFs are served as uploaded.
+func Resizable(key string) bool {
+	switch strings.ToLower(path.Ext(key)) {
+	case ".jpg", ".jpeg", ".png", ".webp":
+		return true
+	default:
+		return false
+	}
+}
+
+// Path returns the path of the variant of the attachment of the given width.
+func Path(key string, width int) string {
+	return "/images/" + key + "/" + strconv.Itoa(width)
+}
+
+// Srcset returns the srcset attribute listing every variant of the attachment.
+func Srcset(key string) string {
+	candidates := make([]string, 0, len(Widths))
+	for _, width := range Widths {
+		candidates = append(candidates, Path(key, width)+" "+strconv.Itoa(width)+"w")
+	}
+
+	return strings.Join(candidates, ", ")
+}
+
+// Format returns the format of the variants of an image of the content type.
+func Format(contentType string, webp bool) string {
+	switch {
+	case webp:
+		return FormatWebP
+	case contentType == "image/jpeg":
+		return FormatJPEG
+	default:
+		return FormatPNG
+	}
+}
+
+// ContentType returns the MIME type of the format.
+func ContentType(format string) string {
+	switch format {
+	case FormatJPEG:
+		return "image/jpeg"
+	case FormatWebP:
+		return "image/webp"
+	default:
+		return "image/png"
+	}
+}
@@ -0,0 +1,219 @@
+package images
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"newsteller/internal/config"
+	"newsteller/internal/models"
+)
+
+// testImage returns a w×h image, red on the left half and blue on the right one.
+func testImage(w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if x < w/2 {
+				img.Set(x, y, color.NRGBA{R: 255, A: 255})
+			} else {
+				img.Set(x, y, color.NRGBA{B: 255, A: 255})
+			}
+		}
+	}
+
+	return img
+}
+
+// testJPEG returns a JPEG with an EXIF segment holding the orientation and a location.
+func testJPEG(t *testing.T, w, h, orientation int) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, testImage(w, h), nil))
+	data := buf.Bytes()
+
+	exif := orientationSegment(orientation)
+	exif = append(exif, []byte("GPS 48.8584 N 2.2945 E")...)
+	length := len(exif) - 2
+	exif[2], exif[3] = byte(length>>8), byte(length)
+	comment := []byte{0xFF, jpegCOM, 0x00, 0x08, 'h', 'o', 'm', 'e', '!', '!'}
+
+	out := append([]byte{0xFF, jpegSOI}, exif...)
+	out = append(out, comment...)
+	return append(out, data[2:]...)
+}
+
+func TestStripMetadata(t *testing.T) {
+	t.Run("Positive: JPEG keeps only the orientation", func(t *testing.T) {
+		data := testJPEG(t, 40, 20, 6)
+		require.Equal(t, 6, Orientation(data))
+
+		stripped, err := StripMetadata("image/jpeg", data)
+		require.NoError(t, err)
+		assert.NotContains(t, string(stripped), "GPS")
+		assert.NotContains(t, string(stripped), "home!!")
+		assert.Equal(t, 6, Orientation(stripped))
+
+		img, err := jpeg.Decode(bytes.NewReader(stripped))
+		require.NoError(t, err)
+		assert.Equal(t, 40, img.Bounds().Dx())
+	})
+
+	t.Run("Positive: JPEG upright has no EXIF", func(t *testing.T) {
+		stripped, err := StripMetadata("image/jpeg", testJPEG(t, 40, 20, 1))
+		require.NoError(t, err)
+		assert.NotContains(t, string(stripped), "Exif")
+	})
+
+	t.Run("Positive: PNG text chunks", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, png.Encode(&buf, testImage(8, 8)))
+		data := buf.Bytes()
+		text := []byte("\x00\x00\x00\x0CtEXtAuthor\x00Alice\x00\x00\x00\x00")
+		// after the IHDR chunk
+		data = append(append(append([]byte{}, data[:33]...), text...), data[33:]...)
+
+		stripped, err := StripMetadata("image/png", data)
+		require.NoError(t, err)
+		assert.NotContains(t, string(stripped), "Alice")
+		_, err = png.Decode(bytes.NewReader(stripped))
+		assert.NoError(t, err)
+	})
+
+	t.Run("Positive: WebP chunks", func(t *testing.T) {
+		data := []byte("RIFF\x00\x00\x00\x00WEBP" +
+			"VP8X\x0A\x00\x00\x00\x0C\x00\x00\x00\x00\x00\x00\x00\x00\x00" +
+			"EXIF\x03\x00\x00\x00GPS\x00" +
+			"VP8L\x02\x00\x00\x00ab")
+
+		stripped, err := StripMetadata("image/webp", data)
+		require.NoError(t, err)
+		assert.NotContains(t, string(stripped), "GPS")
+		assert.Equal(t, byte(0), stripped[20], "EXIF and XMP flags should be cleared")
+		assert.Equal(t, uint32(len(stripped)-8), uint32(stripped[4])|uint32(stripped[5])<<8)
+	})
+
+	t.Run("Negative: Malformed", func(t *testing.T) {
+		_, err := StripMetadata("image/jpeg", []byte{0xFF, jpegSOI, 0xFF, 0xE1, 0xFF, 0xFF})
+		assert.ErrorIs(t, err, ErrMalformed)
+
+		_, err = StripMetadata("image/png", []byte("not a png"))
+		assert.ErrorIs(t, err, ErrMalformed)
+	})
+
+	t.Run("Positive: Other types", func(t *testing.T) {
+		data := []byte("%PDF-1.7")
+		stripped, err := StripMetadata("application/pdf", data)
+		require.NoError(t, err)
+		assert.Equal(t, data, stripped)
+	})
+}
+
+func TestResize(t *testing.T) {
+	t.Run("Positive: Narrower keeping the ratio", func(t *testing.T) {
+		data := testJPEG(t, 400, 200, 1)
+
+		resized, err := Resize(data, 100, FormatJPEG, 80)
+		require.NoError(t, err)
+		img, err := jpeg.Decode(bytes.NewReader(resized))
+		require.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, 100, 50), img.Bounds())
+	})
+
+	t.Run("Positive: Never enlarged", func(t *testing.T) {
+		resized, err := Resize(testJPEG(t, 60, 30, 1), 640, FormatPNG, 80)
+		require.NoError(t, err)
+		img, err := png.Decode(bytes.NewReader(resized))
+		require.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, 60, 30), img.Bounds())
+	})
+
+	t.Run("Positive: Turned upright", func(t *testing.T) {
+		// rotated 90° clockwise, the red half ends up on top
+		resized, err := Resize(testJPEG(t, 80, 40, 6), 20, FormatPNG, 80)
+		require.NoError(t, err)
+		img, err := png.Decode(bytes.NewReader(resized))
+		require.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, 20, 40), img.Bounds())
+
+		r, _, b, _ := img.At(10, 5).RGBA()
+		assert.Greater(t, r, b, "top should be red")
+		r, _, b, _ = img.At(10, 35).RGBA()
+		assert.Greater(t, b, r, "bottom should be blue")
+	})
+
+	t.Run("Positive: WebP", func(t *testing.T) {
+		resized, err := Resize(testJPEG(t, 40, 20, 1), 320, FormatWebP, 80)
+		require.NoError(t, err)
+		assert.Equal(t, "WEBP", string(resized[8:12]))
+	})
+
+	t.Run("Negative: Not an image", func(t *testing.T) {
+		_, err := Resize([]byte("%PDF-1.7"), 320, FormatPNG, 80)
+		assert.Error(t, err)
+	})
+}
+
+func TestSrcset(t *testing.T) {
+	assert.Equal(t, "/images/abc.jpg/640", Path("abc.jpg", 640))
+	assert.Equal(
+		t,
+		"/images/abc.jpg/320 320w, /images/abc.jpg/640 640w, /images/abc.jpg/960 960w, /images/abc.jpg/1280 1280w, /images/abc.jpg/1920 1920w",
+		Srcset("abc.jpg"),
+	)
+
+	assert.True(t, Resizable("abc.JPG"))
+	assert.True(t, Resizable("abc.webp"))
+	assert.False(t, Resizable("abc.gif"))
+	assert.False(t, Resizable("abc.pdf"))
+
+	assert.Equal(t, FormatJPEG, Format("image/jpeg", false))
+	assert.Equal(t, FormatPNG, Format("image/webp", false))
+	assert.Equal(t, FormatWebP, Format("image/png", true))
+}
+
+type countingSource struct {
+	data  []byte
+	opens int
+}
+
+func (s *countingSource) Open(context.Context, *models.Attachment) (io.ReadCloser, error) {
+	s.opens++
+	return io.NopCloser(bytes.NewReader(s.data)), nil
+}
+
+func TestVariants(t *testing.T) {
+	ctx := context.Background()
+	directory := t.TempDir()
+	source := &countingSource{data: testJPEG(t, 800, 400, 1)}
+	cfg := &config.Config{}
+	cfg.Images.Directory = directory
+	cfg.Images.Quality = 80
+	variants := NewVariants(cfg, source)
+	attachment := &models.Attachment{Hash: "abc", Key: "abc.jpg", ContentType: "image/jpeg"}
+
+	t.Run("Positive: Generated once", func(t *testing.T) {
+		first, err := variants.Variant(ctx, attachment, 320, FormatJPEG)
+		require.NoError(t, err)
+		second, err := variants.Variant(ctx, attachment, 320, FormatJPEG)
+		require.NoError(t, err)
+
+		assert.Equal(t, first, second)
+		assert.Equal(t, 1, source.opens)
+		_, err = os.Stat(filepath.Join(directory, "abc-320.jpg"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("Negative: Width not offered", func(t *testing.T) {
+		_, err := variants.Variant(ctx, attachment, 321, FormatJPEG)
+		assert.ErrorIs(t, err, ErrUnsupportedWidth)
+	})
+}
@@ -0,0 +1,269 @@
+package images
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+// ErrMalformed is returned for images whose metadata cannot be located, they may
+// carry metadata which would be published with them.
+var ErrMalformed = errors.New("malformed image")
+
+const (
+	jpegSOI  = 0xD8
+	jpegSOS  = 0xDA
+	jpegAPP0 = 0xE0
+	jpegAPP1 = 0xE1
+	// jpegAPP13 - Photoshop IRB holding IPTC captions, bylines and locations
+	jpegAPP13 = 0xED
+	jpegCOM   = 0xFE
+	// exifOrientation - TIFF tag of the rotation of the image
+	exifOrientation = 0x0112
+)
+
+var (
+	exifHeader = []byte("Exif\x00\x00")
+	pngHeader  = []byte("\x89PNG\r\n\x1a\n")
+)
+
+// StripMetadata removes the EXIF and XMP metadata, holding camera details and
+// locations, from JPEG, PNG and WebP images. The EXIF orientation of JPEG images is
+// kept so they are still shown upright. Other types are returned as they are.
+func StripMetadata(contentType string, data []byte) ([]byte, error) {
+	switch contentType {
+	case "image/jpeg":
+		return stripJPEG(data)
+	case "image/png":
+		return stripPNG(data)
+	case "image/webp":
+		return stripWebP(data)
+	default:
+		return data, nil
+	}
+}
+
+// Orientation returns the EXIF orientation of a JPEG image, 1 (upright) when it has none.
+func Orientation(data []byte) int {
+	orientation := 1
+	_ = walkJPEG(data, func(marker byte, segment []byte) bool {
+		if marker == jpegAPP1 {
+			if o := exifOrientationOf(segment); o != 0 {
+				orientation = o
+			}
+		}
+		return true
+	})
+
+	return orientation
+}
+
+func stripJPEG(data []byte) ([]byte, error) {
+	orientation := Orientation(data)
+	var out bytes.Buffer
+	out.Grow(len(data))
+	out.Write([]byte{0xFF, jpegSOI})
+
+	inserted := orientation == 1
+	err := walkJPEG(data, func(marker byte, segment []byte) bool {
+		switch marker {
+		case jpegAPP1, jpegAPP13, jpegCOM:
+		default:
+			// viewers expect the EXIF segment first, only preceded by the JFIF one
+			if !inserted && marker != jpegAPP0 {
+				out.Write(orientationSegment(orientation))
+				inserted = true
+			}
+			// the start of scan extends over the compressed data, holding no metadata
+			out.Write(segment)
+		}
+		return marker != jpegSOS
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+// walkJPEG calls fn with the marker and the bytes of every segment up to the start of
+// scan, whose segment extends to the end of the data. fn returns false to stop.
+func walkJPEG(data []byte, fn func(marker byte, segment []byte) bool) error {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != jpegSOI {
+		return ErrMalformed
+	}
+
+	for i := 2; i < len(data); {
+		if data[i] != 0xFF {
+			return ErrMalformed
+		}
+		start := i
+		// markers may be preceded by fill bytes
+		for i < len(data) && data[i] == 0xFF {
+			i++
+		}
+		if i >= len(data) {
+			return ErrMalformed
+		}
+		marker := data[i]
+		i++
+
+		if marker == jpegSOS {
+			fn(marker, data[start:])
+			return nil
+		}
+		if marker >= 0xD0 && marker <= 0xD7 || marker == 0x01 {
+			// markers without a length
+			if !fn(marker, data[start:i]) {
+				return nil
+			}
+			continue
+		}
+		if i+2 > len(data) {
+			return ErrMalformed
+		}
+		end := i + int(binary.BigEndian.Uint16(data[i:]))
+		if end > len(data) || end < i+2 {
+			return ErrMalformed
+		}
+		if !fn(marker, data[start:end]) {
+			return nil
+		}
+		i = end
+	}
+
+	return ErrMalformed
+}
+
+// exifOrientationOf returns the orientation of an APP1 segment, 0 when it has none.
+func exifOrientationOf(segment []byte) int {
+	// marker and length precede the payload
+	payload := segment[min(len(segment), 4):]
+	if !bytes.HasPrefix(payload, exifHeader) {
+		return 0
+	}
+	tiff := payload[len(exifHeader):]
+	if len(tiff) < 8 {
+		return 0
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 0
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for n := 0; n < count; n++ {
+		entry := ifd + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:]) == exifOrientation {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+			if orientation < 1 || orientation > 8 {
+				return 0
+			}
+			return orientation
+		}
+	}
+
+	return 0
+}
+
+// orientationSegment returns an APP1 segment holding only the orientation.
+func orientationSegment(orientation int) []byte {
+	tiff := []byte{
+		'M', 'M', 0x00, 0x2A, // big endian TIFF
+		0x00, 0x00, 0x00, 0x08, // offset of IFD0
+		0x00, 0x01, // one entry
+		0x01, 0x12, 0x00, 0x03, // orientation, SHORT
+		0x00, 0x00, 0x00, 0x01, // one value
+		0x00, byte(orientation), 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, // no next IFD
+	}
+	length := 2 + len(exifHeader) + len(tiff)
+
+	segment := []byte{0xFF, jpegAPP1, byte(length >> 8), byte(length)}
+	segment = append(segment, exifHeader...)
+	return append(segment, tiff...)
+}
+
+// stripPNG drops the eXIf chunk and the text chunks, which hold XMP and comments.
+func stripPNG(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngHeader) {
+		return nil, ErrMalformed
+	}
+
+	var out bytes.Buffer
+	out.Grow(len(data))
+	out.Write(pngHeader)
+	for i := len(pngHeader); i < len(data); {
+		if i+8 > len(data) {
+			return nil, ErrMalformed
+		}
+		length := int(binary.BigEndian.Uint32(data[i:]))
+		chunkType := string(data[i+4 : i+8])
+		// length, type, data and CRC
+		end := i + 12 + length
+		if length < 0 || end > len(data) || end < i {
+			return nil, ErrMalformed
+		}
+		switch chunkType {
+		case "eXIf", "tEXt", "zTXt", "iTXt", "tIME":
+		default:
+			out.Write(data[i:end])
+		}
+		i = end
+		if chunkType == "IEND" {
+			break
+		}
+	}
+
+	return out.Bytes(), nil
+}
+
+// stripWebP drops the EXIF and XMP chunks and their flags of the extended header.
+func stripWebP(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, ErrMalformed
+	}
+
+	out := make([]byte, 12, len(data))
+	copy(out, data[:12])
+	for i := 12; i < len(data); {
+		if i+8 > len(data) {
+			return nil, ErrMalformed
+		}
+		chunkType := string(data[i : i+4])
+		size := int(binary.LittleEndian.Uint32(data[i+4:]))
+		// chunks are padded to an even size
+		end := i + 8 + size + size&1
+		if size < 0 || end > len(data) || end < i {
+			return nil, ErrMalformed
+		}
+		switch chunkType {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			chunk := append([]byte(nil), data[i:end]...)
+			if len(chunk) > 8 {
+				// clear the EXIF and XMP flags
+				chunk[8] &^= 0x08 | 0x04
+			}
+			out = append(out, chunk...)
+		default:
+			out = append(out, data[i:end]...)
+		}
+		i = end
+	}
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(out)-8))
+
+	return out, nil
+}
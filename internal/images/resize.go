@@ -0,0 +1,99 @@
+package images
+
+import (
+	"bytes"
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+)
+
+// Resize decodes the image and returns it at most width pixels wide, upright and
+// encoded in the format without metadata.
+func Resize(data []byte, width int, format string, quality int) ([]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width*config.Height > maxPixels {
+		return nil, ErrTooLarge
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	orientation := Orientation(data)
+	bounds := src.Bounds()
+	// orientations 5 to 8 turn the image a quarter
+	sourceWidth, sourceHeight := bounds.Dx(), bounds.Dy()
+	if orientation >= 5 {
+		sourceWidth, sourceHeight = sourceHeight, sourceWidth
+	}
+	targetWidth := min(width, sourceWidth)
+	targetHeight := max(1, (sourceHeight*targetWidth+sourceWidth/2)/sourceWidth)
+	if orientation >= 5 {
+		targetWidth, targetHeight = targetHeight, targetWidth
+	}
+
+	resized := image.NewNRGBA(image.Rect(0, 0, targetWidth, targetHeight))
+	draw.CatmullRom.Scale(resized, resized.Bounds(), src, bounds, draw.Src, nil)
+
+	var buf bytes.Buffer
+	switch format {
+	case FormatJPEG:
+		err = jpeg.Encode(&buf, orient(resized, orientation), &jpeg.Options{Quality: quality})
+	case FormatWebP:
+		err = nativewebp.Encode(&buf, orient(resized, orientation), nil)
+	default:
+		err = png.Encode(&buf, orient(resized, orientation))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// orient applies the EXIF orientation, the transformation turning the stored pixels upright.
+func orient(img *image.NRGBA, orientation int) *image.NRGBA {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirrored
+				dx, dy = w-1-x, y
+			case 3: // rotated 180°
+				dx, dy = w-1-x, h-1-y
+			case 4: // mirrored vertically
+				dx, dy = x, h-1-y
+			case 5: // mirrored along the main diagonal
+				dx, dy = y, x
+			case 6: // rotated 90° clockwise
+				dx, dy = h-1-y, x
+			case 7: // mirrored along the other diagonal
+				dx, dy = h-1-y, w-1-x
+			case 8: // rotated 90° counterclockwise
+				dx, dy = y, w-1-x
+			}
+			s := img.PixOffset(x, y)
+			d := dst.PixOffset(dx, dy)
+			copy(dst.Pix[d:d+4], img.Pix[s:s+4])
+		}
+	}
+
+	return dst
+}
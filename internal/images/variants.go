@@ -0,0 +1,106 @@
+package images
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+	"io"
+	"newsteller/internal/config"
+	"newsteller/internal/files"
+	"newsteller/internal/models"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// Source opens the uploaded files of attachments.
+type Source interface {
+	Open(ctx context.Context, attachment *models.Attachment) (io.ReadCloser, error)
+}
+
+// Variants generates the variants of attachments, caching them as
+// "<hash>-<width>.<format>" files of a directory.
+type Variants struct {
+	source    Source
+	directory string
+	quality   int
+	webp      bool
+	// group - requests of a variant being generated wait for it
+	group singleflight.Group
+}
+
+func NewVariants(cfg *config.Config, source Source) *Variants {
+	return &Variants{
+		source:    source,
+		directory: cfg.Images.Directory,
+		quality:   cfg.Images.Quality,
+		webp:      cfg.Images.WebP,
+	}
+}
+
+// WebP reports whether WebP variants are served to browsers accepting them.
+func (v *Variants) WebP() bool {
+	return v.webp
+}
+
+// Variant returns the attachment resized to the width in the format, generating it when
+// it is not cached.
+func (v *Variants) Variant(ctx context.Context, attachment *models.Attachment, width int, format string) ([]byte, error) {
+	if !slices.Contains(Widths, width) {
+		return nil, ErrUnsupportedWidth
+	}
+
+	name := fmt.Sprintf("%s-%d.%s", attachment.Hash, width, format)
+	data, err := os.ReadFile(filepath.Join(v.directory, name))
+	if err == nil {
+		return data, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		// an unreadable variant is generated again
+		zap.L().Error("could not read cached image", zap.String("name", name), zap.Error(err))
+	}
+
+	result, err, _ := v.group.Do(name, func() (any, error) {
+		return v.generate(ctx, attachment, width, format, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.([]byte), nil
+}
+
+func (v *Variants) generate(
+	ctx context.Context,
+	attachment *models.Attachment,
+	width int,
+	format string,
+	name string,
+) ([]byte, error) {
+	reader, err := v.source.Open(ctx, attachment)
+	if err != nil {
+		return nil, err
+	}
+	original, err := io.ReadAll(reader)
+	reader.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := Resize(original, width, format, v.quality)
+	if err != nil {
+		return nil, err
+	}
+	err = v.put(name, data)
+	if err != nil {
+		zap.L().Error("could not cache image", zap.String("name", name), zap.Error(err))
+	}
+
+	return data, nil
+}
+
+func (v *Variants) put(name string, data []byte) error {
+	return files.WriteAtomic(filepath.Join(v.directory, name), data)
+}
@@ -0,0 +1,57 @@
+package mailer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"go.uber.org/zap"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileTransport writes every message as an .eml file into a directory, for development.
+type FileTransport struct {
+	directory string
+}
+
+func NewFileTransport(directory string) *FileTransport {
+	return &FileTransport{directory: directory}
+}
+
+func (f *FileTransport) Send(_ context.Context, message *Message) error {
+	data, err := message.Bytes()
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(f.directory, 0o755)
+	if err != nil {
+		return err
+	}
+
+	random := make([]byte, 4)
+	_, _ = rand.Read(random)
+	name := time.Now().Format("20060102-150405.000") + "-" + hex.EncodeToString(random) + ".eml"
+
+	return os.WriteFile(filepath.Join(f.directory, name), data, 0o644)
+}
+
+// LogTransport only logs messages, for development.
+type LogTransport struct{}
+
+func NewLogTransport() *LogTransport {
+	return &LogTransport{}
+}
+
+func (l *LogTransport) Send(_ context.Context, message *Message) error {
+	zap.L().Info(
+		"mail sent to log",
+		zap.String("from", message.From),
+		zap.String("to", message.To),
+		zap.String("subject", message.Subject),
+		zap.String("text", message.Text),
+	)
+
+	return nil
+}
@@ -0,0 +1,206 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"newsteller/internal/config"
+	"newsteller/internal/models"
+	"newsteller/internal/repositories"
+	"time"
+)
+
+/**
+Mail is never sent from request handlers directly. Handlers enqueue messages into
+the persistent outbox and the Worker delivers them in background, retrying failed
+deliveries with exponential backoff, so messages survive SMTP outages and restarts.
+*/
+
+const (
+	// sendTimeout - max time a single delivery attempt may take
+	sendTimeout = time.Minute
+	// maxRetryDelay - upper bound of the exponential backoff
+	maxRetryDelay = 6 * time.Hour
+)
+
+// Mailer puts messages into the outbox.
+type Mailer struct {
+	outbox *repositories.Outbox
+}
+
+func New(c *mongo.Collection) *Mailer {
+	return &Mailer{
+		outbox: repositories.NewOutboxRepository(c),
+	}
+}
+
+// Enqueue stores the message in the outbox for delivery as soon as possible.
+func (m *Mailer) Enqueue(ctx context.Context, message *Message) error {
+	return m.enqueue(ctx, message, "", time.Now())
+}
+
+// EnqueueOnce is Enqueue that ignores repeated calls with the same key, so callers
+// retrying after a crash never put the same message into the outbox twice.
+func (m *Mailer) EnqueueOnce(ctx context.Context, key string, message *Message) error {
+	return m.EnqueueOnceAt(ctx, key, message, time.Now())
+}
+
+// EnqueueOnceAt is EnqueueOnce delaying delivery until the provided time,
+// used to spread bulk sends over time.
+func (m *Mailer) EnqueueOnceAt(ctx context.Context, key string, message *Message, at time.Time) error {
+	err := m.enqueue(ctx, message, key, at)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+
+	return err
+}
+
+func (m *Mailer) enqueue(ctx context.Context, message *Message, key string, at time.Time) error {
+	now := time.Now()
+	_, err := m.outbox.Create(ctx, &models.OutboxMessage{
+		To:            message.To,
+		Subject:       message.Subject,
+		HTML:          message.HTML,
+		Text:          message.Text,
+		Headers:       message.Headers,
+		Status:        models.OutboxPending,
+		NextAttemptAt: at,
+		CreatedAt:     now,
+		DedupeKey:     key,
+	})
+
+	return err
+}
+
+// Worker delivers outbox messages using the transport.
+type Worker struct {
+	cfg         *config.Config
+	outbox      *repositories.Outbox
+	subscribers *repositories.Subscriber
+	transport   Transport
+}
+
+func NewWorker(cfg *config.Config, c *mongo.Collection, subscribers *mongo.Collection, transport Transport) *Worker {
+	return &Worker{
+		cfg:         cfg,
+		outbox:      repositories.NewOutboxRepository(c),
+		subscribers: repositories.NewSubscriberRepository(subscribers),
+		transport:   transport,
+	}
+}
+
+// Run delivers due messages every poll interval until the context is cancelled.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.Mail.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		w.deliverDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Worker) deliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		message, err := w.outbox.ClaimDue(ctx, time.Now(), 2*sendTimeout)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return
+		}
+		if err != nil {
+			zap.L().Error("could not claim outbox message", zap.Error(err))
+			return
+		}
+
+		w.deliver(ctx, message)
+	}
+}
+
+func (w *Worker) deliver(ctx context.Context, message *models.OutboxMessage) {
+	sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	attempts := message.Attempts + 1
+	reason, err := w.cancelReason(ctx, message)
+	if reason != "" {
+		zap.L().Info("cancelled message", zap.String("id", message.ID.Hex()), zap.String("reason", reason))
+		err = w.outbox.MarkCancelled(ctx, message.ID, reason)
+		if err != nil {
+			zap.L().Error("could not mark message as cancelled", zap.String("id", message.ID.Hex()), zap.Error(err))
+		}
+		return
+	}
+	if err == nil {
+		err = w.transport.Send(sendCtx, &Message{
+			From:    w.cfg.Mail.From,
+			To:      message.To,
+			Subject: message.Subject,
+			HTML:    message.HTML,
+			Text:    message.Text,
+			Headers: message.Headers,
+		})
+	}
+	if err == nil {
+		err = w.outbox.MarkSent(ctx, message.ID, attempts, time.Now())
+		if err != nil {
+			zap.L().Error("could not mark message as sent", zap.String("id", message.ID.Hex()), zap.Error(err))
+		}
+		return
+	}
+
+	zap.L().Warn(
+		"could not deliver message",
+		zap.String("id", message.ID.Hex()),
+		zap.Int("attempt", attempts),
+		zap.Error(err),
+	)
+	if attempts >= w.cfg.Mail.MaxAttempts {
+		err = w.outbox.MarkFailed(ctx, message.ID, attempts, err.Error())
+	} else {
+		err = w.outbox.MarkRetry(ctx, message.ID, attempts, time.Now().Add(RetryDelay(w.cfg.Mail.RetryDelay, attempts)), err.Error())
+	}
+	if err != nil {
+		zap.L().Error("could not reschedule message", zap.String("id", message.ID.Hex()), zap.Error(err))
+	}
+}
+
+// cancelReason returns why the message may no longer be sent, it is empty when it may.
+// Throttled issues wait in the outbox for hours, so the recipient is checked on delivery:
+// nothing is sent to suppressed addresses and newsletters, carrying List-Unsubscribe,
+// only to active subscribers.
+func (w *Worker) cancelReason(ctx context.Context, message *models.OutboxMessage) (string, error) {
+	subscriber, err := w.subscribers.FindByEmail(ctx, message.To)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	_, newsletter := message.Headers[HeaderListUnsubscribe]
+	if subscriber.Suppressed() || newsletter && subscriber.Status != models.SubscriberActive {
+		return "recipient " + string(subscriber.Status), nil
+	}
+
+	return "", nil
+}
+
+// RetryDelay returns the delay before the next attempt, doubling the base delay
+// after every failed attempt.
+func RetryDelay(base time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+
+	return delay
+}
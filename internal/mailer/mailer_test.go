@@ -0,0 +1,54 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"newsteller/internal/config"
+)
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, time.Minute, RetryDelay(time.Minute, 1))
+	assert.Equal(t, 2*time.Minute, RetryDelay(time.Minute, 2))
+	assert.Equal(t, 16*time.Minute, RetryDelay(time.Minute, 5))
+	assert.Equal(t, maxRetryDelay, RetryDelay(time.Minute, 50), "Delay should be capped")
+}
+
+func TestNewTransport(t *testing.T) {
+	cfg := &config.Config{}
+	for transport, expected := range map[string]Transport{
+		TransportSMTP: &SMTPTransport{},
+		TransportFile: &FileTransport{},
+		TransportLog:  &LogTransport{},
+	} {
+		cfg.Mail.Transport = transport
+		res, err := NewTransport(cfg)
+		require.NoError(t, err)
+		assert.IsType(t, expected, res)
+	}
+
+	cfg.Mail.Transport = "pigeon"
+	_, err := NewTransport(cfg)
+	assert.ErrorContains(t, err, "unknown mail transport")
+}
+
+func TestFileTransport_Send(t *testing.T) {
+	directory := filepath.Join(t.TempDir(), "mail")
+
+	err := NewFileTransport(directory).Send(context.Background(), testMessage())
+	require.NoError(t, err)
+
+	files, err := os.ReadDir(directory)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, ".eml", filepath.Ext(files[0].Name()))
+
+	data, err := os.ReadFile(filepath.Join(directory, files[0].Name()))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "To: <reader@example.com>")
+}
@@ -0,0 +1,115 @@
+package mailer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HeaderListUnsubscribe - header of the newsletters sent to subscribers
+const HeaderListUnsubscribe = "List-Unsubscribe"
+
+// Message is an email with an HTML and a plain-text alternative.
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	HTML    string
+	Text    string
+	// Headers - additional headers, e.g. List-Unsubscribe
+	Headers map[string]string
+}
+
+// Bytes encodes the message as a multipart/alternative MIME document.
+func (m *Message) Bytes() ([]byte, error) {
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address: %w", err)
+	}
+	to, err := mail.ParseAddress(m.To)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient address: %w", err)
+	}
+
+	var buf bytes.Buffer
+	body := multipart.NewWriter(&buf)
+
+	header := textproto.MIMEHeader{}
+	header.Set("From", from.String())
+	header.Set("To", to.String())
+	header.Set("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	header.Set("Date", time.Now().Format(time.RFC1123Z))
+	header.Set("Message-Id", messageID(from.Address))
+	header.Set("Mime-Version", "1.0")
+	header.Set("Content-Type", "multipart/alternative; boundary="+body.Boundary())
+	for key, value := range m.Headers {
+		header.Set(key, value)
+	}
+
+	var message bytes.Buffer
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&message, "%s: %s\r\n", key, header.Get(key))
+	}
+	message.WriteString("\r\n")
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", m.Text},
+		{"text/html; charset=utf-8", m.HTML},
+	}
+	for _, part := range parts {
+		if part.content == "" {
+			continue
+		}
+
+		w, err := body.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err = qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err = qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err = body.Close(); err != nil {
+		return nil, err
+	}
+
+	message.Write(buf.Bytes())
+
+	return message.Bytes(), nil
+}
+
+func messageID(sender string) string {
+	domain := "localhost"
+	if _, d, ok := strings.Cut(sender, "@"); ok {
+		domain = d
+	}
+
+	random := make([]byte, 16)
+	_, _ = rand.Read(random)
+
+	return "<" + hex.EncodeToString(random) + "@" + domain + ">"
+}
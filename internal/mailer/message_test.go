@@ -0,0 +1,72 @@
+package mailer
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessage_Bytes(t *testing.T) {
+	message := &Message{
+		From:    "Newsteller <no-reply@example.com>",
+		To:      "Jürgen <reader@example.com>",
+		Subject: "Grüße aus der Redaktion",
+		HTML:    "<p>Hallo Jürgen</p>",
+		Text:    "Hallo Jürgen",
+		Headers: map[string]string{"List-Unsubscribe": "<https://example.com/unsubscribe>"},
+	}
+
+	data, err := message.Bytes()
+	require.NoError(t, err)
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	decoder := new(mime.WordDecoder)
+	subject, err := decoder.DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Grüße aus der Redaktion", subject)
+
+	to, err := parsed.Header.AddressList("To")
+	require.NoError(t, err)
+	assert.Equal(t, "Jürgen", to[0].Name)
+	assert.Equal(t, "reader@example.com", to[0].Address)
+	assert.Equal(t, "<https://example.com/unsubscribe>", parsed.Header.Get("List-Unsubscribe"))
+	assert.True(t, strings.HasSuffix(parsed.Header.Get("Message-Id"), "@example.com>"))
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	var contentTypes, contents []string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(part)
+		require.NoError(t, err)
+		contentTypes = append(contentTypes, part.Header.Get("Content-Type"))
+		contents = append(contents, string(content))
+	}
+
+	assert.Equal(t, []string{"text/plain; charset=utf-8", "text/html; charset=utf-8"}, contentTypes)
+	assert.Equal(t, []string{"Hallo Jürgen", "<p>Hallo Jürgen</p>"}, contents)
+}
+
+func TestMessage_Bytes_InvalidAddress(t *testing.T) {
+	_, err := (&Message{From: "no-reply@example.com", To: "not an address"}).Bytes()
+	assert.ErrorContains(t, err, "invalid recipient address")
+
+	_, err = (&Message{From: "", To: "reader@example.com"}).Bytes()
+	assert.ErrorContains(t, err, "invalid sender address")
+}
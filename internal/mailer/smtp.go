@@ -0,0 +1,106 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"newsteller/internal/config"
+	"time"
+)
+
+var ErrStartTLSUnsupported = errors.New("smtp server does not support STARTTLS")
+
+type SMTPTransport struct {
+	host      string
+	addr      string
+	username  string
+	password  string
+	startTLS  bool
+	timeout   time.Duration
+	tlsConfig *tls.Config
+}
+
+func NewSMTPTransport(cfg *config.Config) *SMTPTransport {
+	return &SMTPTransport{
+		host:      cfg.Mail.SMTP.Host,
+		addr:      net.JoinHostPort(cfg.Mail.SMTP.Host, cfg.Mail.SMTP.Port),
+		username:  cfg.Mail.SMTP.Username,
+		password:  cfg.Mail.SMTP.Password,
+		startTLS:  cfg.Mail.SMTP.StartTLS,
+		timeout:   cfg.Mail.SMTP.Timeout,
+		tlsConfig: &tls.Config{ServerName: cfg.Mail.SMTP.Host},
+	}
+}
+
+func (s *SMTPTransport) Send(ctx context.Context, message *Message) error {
+	from, err := mail.ParseAddress(message.From)
+	if err != nil {
+		return err
+	}
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return err
+	}
+	data, err := message.Bytes()
+	if err != nil {
+		return err
+	}
+
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(s.timeout)
+	}
+	if err = conn.SetDeadline(deadline); err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(s.tlsConfig); err != nil {
+			return err
+		}
+	} else if s.startTLS {
+		return ErrStartTLSUnsupported
+	}
+
+	if s.username != "" {
+		err = client.Auth(smtp.PlainAuth("", s.username, s.password, s.host))
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = client.Mail(from.Address); err != nil {
+		return err
+	}
+	if err = client.Rcpt(to.Address); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(data); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
@@ -0,0 +1,239 @@
+package mailer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"newsteller/internal/config"
+)
+
+// smtpServer is a minimal local SMTP stand-in, recording received mail.
+type smtpServer struct {
+	listener  net.Listener
+	tlsConfig *tls.Config
+
+	mu       sync.Mutex
+	tls      bool
+	auth     string
+	from     string
+	rcpt     []string
+	data     string
+	received chan struct{}
+}
+
+func newSMTPServer(t *testing.T, tlsConfig *tls.Config) *smtpServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &smtpServer{
+		listener:  listener,
+		tlsConfig: tlsConfig,
+		received:  make(chan struct{}, 1),
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	go s.serve()
+
+	return s
+}
+
+func (s *smtpServer) port() string {
+	_, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	return port
+}
+
+func (s *smtpServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *smtpServer) handle(conn net.Conn) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	_ = text.PrintfLine("220 localhost ESMTP stand-in")
+
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		command, arg, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(command) {
+		case "EHLO", "HELO":
+			extensions := []string{"250-localhost", "250-AUTH PLAIN"}
+			if s.tlsConfig != nil && !s.isTLS() {
+				extensions = append(extensions, "250-STARTTLS")
+			}
+			for _, ext := range extensions {
+				_ = text.PrintfLine("%s", ext)
+			}
+			_ = text.PrintfLine("250 8BITMIME")
+		case "STARTTLS":
+			_ = text.PrintfLine("220 Ready to start TLS")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err = tlsConn.Handshake(); err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.tls = true
+			s.mu.Unlock()
+			conn = tlsConn
+			text = textproto.NewConn(conn)
+		case "AUTH":
+			_, credentials, _ := strings.Cut(arg, " ")
+			decoded, _ := base64.StdEncoding.DecodeString(credentials)
+			s.mu.Lock()
+			s.auth = string(decoded)
+			s.mu.Unlock()
+			_ = text.PrintfLine("235 Authentication successful")
+		case "MAIL":
+			s.mu.Lock()
+			s.from = arg
+			s.mu.Unlock()
+			_ = text.PrintfLine("250 OK")
+		case "RCPT":
+			s.mu.Lock()
+			s.rcpt = append(s.rcpt, arg)
+			s.mu.Unlock()
+			_ = text.PrintfLine("250 OK")
+		case "DATA":
+			_ = text.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			data, err := text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.data = string(data)
+			s.mu.Unlock()
+			_ = text.PrintfLine("250 OK")
+			s.received <- struct{}{}
+		case "QUIT":
+			_ = text.PrintfLine("221 Bye")
+			return
+		default:
+			_ = text.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+func (s *smtpServer) isTLS() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tls
+}
+
+func selfSignedTLS(t *testing.T) (*tls.Config, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	}, pool
+}
+
+func testSMTPConfig(port string, startTLS bool) *config.Config {
+	cfg := &config.Config{}
+	cfg.Mail.SMTP.Host = "127.0.0.1"
+	cfg.Mail.SMTP.Port = port
+	cfg.Mail.SMTP.Username = "user"
+	cfg.Mail.SMTP.Password = "password"
+	cfg.Mail.SMTP.StartTLS = startTLS
+	cfg.Mail.SMTP.Timeout = 5 * time.Second
+	return cfg
+}
+
+func testMessage() *Message {
+	return &Message{
+		From:    "Newsteller <no-reply@example.com>",
+		To:      "reader@example.com",
+		Subject: "Hello",
+		HTML:    "<p>Hello</p>",
+		Text:    "Hello",
+	}
+}
+
+func TestSMTPTransport_Send(t *testing.T) {
+	t.Run("Positive: Deliver with authentication", func(t *testing.T) {
+		server := newSMTPServer(t, nil)
+		transport := NewSMTPTransport(testSMTPConfig(server.port(), false))
+
+		err := transport.Send(context.Background(), testMessage())
+		require.NoError(t, err)
+		<-server.received
+
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		assert.Equal(t, "\x00user\x00password", server.auth)
+		assert.Equal(t, "FROM:<no-reply@example.com>", strings.Fields(server.from)[0])
+		assert.Equal(t, []string{"TO:<reader@example.com>"}, server.rcpt)
+		assert.Contains(t, server.data, "Subject: Hello")
+		assert.Contains(t, server.data, "<p>Hello</p>")
+		assert.False(t, server.tls)
+	})
+
+	t.Run("Positive: Upgrade connection with STARTTLS", func(t *testing.T) {
+		serverTLS, pool := selfSignedTLS(t)
+		server := newSMTPServer(t, serverTLS)
+		transport := NewSMTPTransport(testSMTPConfig(server.port(), true))
+		transport.tlsConfig = &tls.Config{ServerName: "127.0.0.1", RootCAs: pool}
+
+		err := transport.Send(context.Background(), testMessage())
+		require.NoError(t, err)
+		<-server.received
+
+		assert.True(t, server.isTLS(), "Message should be sent over TLS")
+	})
+
+	t.Run("Negative: STARTTLS required but not supported", func(t *testing.T) {
+		server := newSMTPServer(t, nil)
+		transport := NewSMTPTransport(testSMTPConfig(server.port(), true))
+
+		err := transport.Send(context.Background(), testMessage())
+		assert.ErrorIs(t, err, ErrStartTLSUnsupported)
+	})
+
+	t.Run("Negative: Server unavailable", func(t *testing.T) {
+		server := newSMTPServer(t, nil)
+		port := server.port()
+		_ = server.listener.Close()
+
+		err := NewSMTPTransport(testSMTPConfig(port, false)).Send(context.Background(), testMessage())
+		assert.Error(t, err)
+	})
+}
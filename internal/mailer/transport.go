@@ -0,0 +1,32 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"newsteller/internal/config"
+)
+
+const (
+	TransportSMTP = "smtp"
+	TransportFile = "file"
+	TransportLog  = "log"
+)
+
+// Transport delivers a single message.
+type Transport interface {
+	Send(ctx context.Context, message *Message) error
+}
+
+// NewTransport creates the transport selected in the configuration.
+func NewTransport(cfg *config.Config) (Transport, error) {
+	switch cfg.Mail.Transport {
+	case TransportSMTP:
+		return NewSMTPTransport(cfg), nil
+	case TransportFile:
+		return NewFileTransport(cfg.Mail.Directory), nil
+	case TransportLog:
+		return NewLogTransport(), nil
+	default:
+		return nil, fmt.Errorf("unknown mail transport: %q", cfg.Mail.Transport)
+	}
+}
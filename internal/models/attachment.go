@@ -0,0 +1,74 @@
+package models
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"strings"
+	"time"
+)
+
+// Attachment is an uploaded file referenced from post bodies. Files are stored once
+// per content, uploading the same content again returns the existing attachment.
+type Attachment struct {
+	ID primitive.ObjectID `bson:"_id,omitempty"`
+	// Hash - hex SHA-256 of the content
+	Hash string `bson:"hash"`
+	// Key - name of the file in the storage, the hash with the extension of the type
+	Key string `bson:"key"`
+	// Name - file name given by the uploader
+	Name        string    `bson:"name"`
+	ContentType string    `bson:"content_type"`
+	Size        int64     `bson:"size"`
+	CreatedAt   time.Time `bson:"created_at"`
+}
+
+// Path returns the path the attachment is served at.
+func (a Attachment) Path() string {
+	return "/attachments/" + a.Key
+}
+
+// IsImage reports whether the attachment is shown inline in posts.
+func (a Attachment) IsImage() bool {
+	return strings.HasPrefix(a.ContentType, "image/")
+}
+
+// Reference returns the markup inserted into post bodies, an image for images and a
+// link for other files.
+func (a Attachment) Reference() string {
+	text := strings.NewReplacer("[", "(", "]", ")").Replace(a.Name)
+	if a.IsImage() {
+		return "![" + text + "](" + a.Path() + ")"
+	}
+
+	return "[" + text + "](" + a.Path() + ")"
+}
+
+func (Attachment) CollectionName() string {
+	return "attachments"
+}
+
+func (a Attachment) Migrate(ctx context.Context, db *mongo.Database) error {
+	err := createCollection(ctx, db, a.CollectionName())
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(a.CollectionName()).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "created_at", Value: -1}},
+		},
+	})
+
+	return err
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"time"
+)
+
+type BounceKind string
+
+const (
+	// BounceHard - the address does not exist or permanently rejects mail
+	BounceHard BounceKind = "hard"
+	// BounceSoft - temporary failure, e.g. a full mailbox or a delayed delivery
+	BounceSoft BounceKind = "soft"
+	// BounceComplaint - the recipient marked the message as spam
+	BounceComplaint BounceKind = "complaint"
+)
+
+type BounceSource string
+
+const (
+	BounceSourceWebhook BounceSource = "webhook"
+	BounceSourceMailbox BounceSource = "mailbox"
+)
+
+// Bounce is a delivery failure or a complaint reported for an address.
+type Bounce struct {
+	ID    primitive.ObjectID `bson:"_id,omitempty"`
+	Email string             `bson:"email"`
+	// SubscriberID - empty when the address does not belong to a subscriber
+	SubscriberID primitive.ObjectID `bson:"subscriber_id,omitempty"`
+	Kind         BounceKind         `bson:"kind"`
+	// Status - enhanced status code of the DSN, e.g. "5.1.1"
+	Status string `bson:"status,omitempty"`
+	// Diagnostic - remote server response or the feedback type of complaints
+	Diagnostic string       `bson:"diagnostic,omitempty"`
+	Source     BounceSource `bson:"source"`
+	CreatedAt  time.Time    `bson:"created_at"`
+}
+
+func (Bounce) CollectionName() string {
+	return "bounces"
+}
+
+func (b Bounce) Migrate(ctx context.Context, db *mongo.Database) error {
+	err := createCollection(ctx, db, b.CollectionName())
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(b.CollectionName()).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "email", Value: 1}, {Key: "kind", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+
+	return err
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// Category is a section of the site, categories nest under a parent category.
+type Category struct {
+	ID   primitive.ObjectID `bson:"_id,omitempty"`
+	Name string             `bson:"name"`
+	// Slug - unique URL name generated from the name
+	Slug        string `bson:"slug"`
+	Description string `bson:"description,omitempty"`
+	// ParentID - the enclosing category, zero for top level categories
+	ParentID  primitive.ObjectID `bson:"parent_id,omitempty"`
+	CreatedAt time.Time          `bson:"created_at"`
+}
+
+// Path returns the path of the category page.
+func (c Category) Path() string {
+	return "/categories/" + c.Slug
+}
+
+func (Category) CollectionName() string {
+	return "categories"
+}
+
+func (c Category) Migrate(ctx context.Context, db *mongo.Database) error {
+	err := createCollection(ctx, db, c.CollectionName())
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(c.CollectionName()).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "slug", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "parent_id", Value: 1}},
+		},
+	})
+
+	return err
+}
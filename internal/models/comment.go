@@ -0,0 +1,72 @@
+package models
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"time"
+)
+
+type CommentStatus string
+
+const (
+	// CommentPending - comment waits in the moderation queue, shown to nobody
+	CommentPending CommentStatus = "pending"
+	// CommentApproved - comment is shown below the post
+	CommentApproved CommentStatus = "approved"
+	// CommentSpam - comment was rejected, it is kept to recognize similar ones
+	CommentSpam CommentStatus = "spam"
+)
+
+// CommentStatuses - statuses in the order of the moderation queue tabs
+var CommentStatuses = []CommentStatus{CommentPending, CommentApproved, CommentSpam}
+
+// Comment is a reader response to a post, or a reply to another comment of the post.
+type Comment struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty"`
+	PostID primitive.ObjectID `bson:"post_id"`
+	// ParentID - the comment replied to, zero for comments on the post itself
+	ParentID primitive.ObjectID `bson:"parent_id,omitempty"`
+	// Depth - nesting level of the reply, 0 for comments on the post itself
+	Depth  int    `bson:"depth,omitempty"`
+	Author string `bson:"author"`
+	// Email - address of the author, only shown to moderators
+	Email   string        `bson:"email,omitempty"`
+	Content string        `bson:"content"`
+	Status  CommentStatus `bson:"status"`
+	// SpamScore - probability of the comment being spam when it was submitted
+	SpamScore float64 `bson:"spam_score"`
+	// Trained - status the spam filter learned the comment as, empty until moderated
+	Trained   CommentStatus `bson:"trained,omitempty"`
+	CreatedAt time.Time     `bson:"created_at"`
+}
+
+// IsReply reports whether the comment replies to another comment.
+func (c Comment) IsReply() bool {
+	return !c.ParentID.IsZero()
+}
+
+func (Comment) CollectionName() string {
+	return "comments"
+}
+
+func (c Comment) Migrate(ctx context.Context, db *mongo.Database) error {
+	err := createCollection(ctx, db, c.CollectionName())
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(c.CollectionName()).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// comments of a post page
+			Keys: bson.D{{Key: "post_id", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: 1}},
+		},
+		{
+			// moderation queue
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	})
+
+	return err
+}
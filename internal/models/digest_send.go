@@ -0,0 +1,50 @@
+package models
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type DigestSendStatus string
+
+const (
+	// DigestSendEnqueued - digest was put into the mail outbox
+	DigestSendEnqueued DigestSendStatus = "enqueued"
+	// DigestSendSkipped - nothing was published since the previous digest
+	DigestSendSkipped DigestSendStatus = "skipped"
+)
+
+// DigestSend records that the digest of a period was handled for a subscriber.
+// The subscriber and period pair is unique, so every period is handled at most once.
+type DigestSend struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"`
+	SubscriberID primitive.ObjectID `bson:"subscriber_id"`
+	// Period - "2006-01-02" for daily and "2006-W01" for weekly digests, in subscriber time zone
+	Period    string               `bson:"period"`
+	Frequency DigestFrequency      `bson:"frequency"`
+	Status    DigestSendStatus     `bson:"status"`
+	PostIDs   []primitive.ObjectID `bson:"post_ids,omitempty"`
+	CreatedAt time.Time            `bson:"created_at"`
+}
+
+func (DigestSend) CollectionName() string {
+	return "digest_sends"
+}
+
+func (d DigestSend) Migrate(ctx context.Context, db *mongo.Database) error {
+	err := createCollection(ctx, db, d.CollectionName())
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(d.CollectionName()).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "subscriber_id", Value: 1}, {Key: "period", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	return err
+}
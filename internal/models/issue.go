@@ -0,0 +1,122 @@
+package models
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"slices"
+	"time"
+)
+
+type IssueStatus string
+
+const (
+	// IssueDraft - issue is being composed and can be edited
+	IssueDraft IssueStatus = "draft"
+	// IssueScheduled - issue waits for ScheduledAt to be sent
+	IssueScheduled IssueStatus = "scheduled"
+	// IssueSending - issue is being enqueued for its recipients until LockedUntil
+	IssueSending IssueStatus = "sending"
+	// IssueSent - issue was enqueued for all recipients and is shown in the archive
+	IssueSent IssueStatus = "sent"
+	// IssueFailed - issue could not be sent, e.g. all of its posts were deleted, it can go back to drafts
+	IssueFailed IssueStatus = "failed"
+)
+
+// Segment narrows down the active subscribers receiving an issue. Empty fields match everybody.
+type Segment struct {
+	// Source - where the subscription was made, e.g. "home"
+	Source           string    `bson:"source,omitempty"`
+	SubscribedAfter  time.Time `bson:"subscribed_after,omitempty"`
+	SubscribedBefore time.Time `bson:"subscribed_before,omitempty"`
+	// Topics - subscribers following any of the topics, subscribers following everything included
+	Topics []string `bson:"topics,omitempty"`
+	// ActiveWithinDays - subscribers active in the last days before sending
+	ActiveWithinDays int `bson:"active_within_days,omitempty"`
+}
+
+// IsZero reports whether the segment matches all subscribers.
+func (s Segment) IsZero() bool {
+	return s.Source == "" &&
+		s.SubscribedAfter.IsZero() &&
+		s.SubscribedBefore.IsZero() &&
+		len(s.Topics) == 0 &&
+		s.ActiveWithinDays == 0
+}
+
+// Issue is a manually composed newsletter.
+type Issue struct {
+	ID    primitive.ObjectID `bson:"_id,omitempty"`
+	Name  string             `bson:"name"`
+	Intro string             `bson:"intro,omitempty"`
+	// PostIDs - posts of the issue in the order they are shown
+	PostIDs     []primitive.ObjectID `bson:"post_ids"`
+	Segment     Segment              `bson:"segment"`
+	Status      IssueStatus          `bson:"status"`
+	ScheduledAt time.Time            `bson:"scheduled_at,omitempty"`
+	LockedUntil time.Time            `bson:"locked_until,omitempty"`
+	SentAt      time.Time            `bson:"sent_at,omitempty"`
+	// Recipients - number of subscribers the issue was sent to
+	Recipients int `bson:"recipients,omitempty"`
+	// LastError - why a failed issue was not sent
+	LastError string    `bson:"last_error,omitempty"`
+	CreatedAt time.Time `bson:"created_at,omitempty"`
+	UpdatedAt time.Time `bson:"updated_at,omitempty"`
+}
+
+// AddPost appends the post to the issue, reporting false when it is already there.
+func (i *Issue) AddPost(id primitive.ObjectID) bool {
+	if slices.Contains(i.PostIDs, id) {
+		return false
+	}
+	i.PostIDs = append(i.PostIDs, id)
+
+	return true
+}
+
+// RemovePost removes the post from the issue, reporting false when it is not there.
+func (i *Issue) RemovePost(id primitive.ObjectID) bool {
+	index := slices.Index(i.PostIDs, id)
+	if index < 0 {
+		return false
+	}
+	i.PostIDs = slices.Delete(i.PostIDs, index, index+1)
+
+	return true
+}
+
+// MovePost moves the post by offset positions, -1 moves it one position up.
+// It reports false when the post is not in the issue or cannot move that far.
+func (i *Issue) MovePost(id primitive.ObjectID, offset int) bool {
+	index := slices.Index(i.PostIDs, id)
+	target := index + offset
+	if index < 0 || target < 0 || target >= len(i.PostIDs) {
+		return false
+	}
+	i.PostIDs[index], i.PostIDs[target] = i.PostIDs[target], i.PostIDs[index]
+
+	return true
+}
+
+func (Issue) CollectionName() string {
+	return "issues"
+}
+
+func (i Issue) Migrate(ctx context.Context, db *mongo.Database) error {
+	err := createCollection(ctx, db, i.CollectionName())
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(i.CollectionName()).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "scheduled_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "sent_at", Value: -1}},
+		},
+	})
+
+	return err
+}
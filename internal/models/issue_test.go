@@ -0,0 +1,47 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestIssue_Posts(t *testing.T) {
+	first, second, third := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	issue := &Issue{}
+
+	t.Run("Positive: Add posts", func(t *testing.T) {
+		assert.True(t, issue.AddPost(first))
+		assert.True(t, issue.AddPost(second))
+		assert.True(t, issue.AddPost(third))
+		assert.False(t, issue.AddPost(second), "Post should be added only once")
+		assert.Equal(t, []primitive.ObjectID{first, second, third}, issue.PostIDs)
+	})
+
+	t.Run("Positive: Move posts", func(t *testing.T) {
+		assert.True(t, issue.MovePost(third, -1))
+		assert.Equal(t, []primitive.ObjectID{first, third, second}, issue.PostIDs)
+
+		assert.True(t, issue.MovePost(first, 1))
+		assert.Equal(t, []primitive.ObjectID{third, first, second}, issue.PostIDs)
+	})
+
+	t.Run("Negative: Move past the edges", func(t *testing.T) {
+		assert.False(t, issue.MovePost(third, -1))
+		assert.False(t, issue.MovePost(second, 1))
+		assert.False(t, issue.MovePost(primitive.NewObjectID(), 1))
+		assert.Equal(t, []primitive.ObjectID{third, first, second}, issue.PostIDs)
+	})
+
+	t.Run("Positive: Remove posts", func(t *testing.T) {
+		assert.True(t, issue.RemovePost(first))
+		assert.False(t, issue.RemovePost(first))
+		assert.Equal(t, []primitive.ObjectID{third, second}, issue.PostIDs)
+	})
+}
+
+func TestSegment_IsZero(t *testing.T) {
+	assert.True(t, Segment{}.IsZero())
+	assert.False(t, Segment{Source: "home"}.IsZero())
+}
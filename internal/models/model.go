@@ -2,6 +2,7 @@ package models
 
 import (
 	"context"
+	"errors"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -13,3 +14,16 @@ type Model interface {
 //type Migratable interface {
 //	Migrate(ctx context.Context, db *mongo.Database) error
 //}
+
+// createCollection creates the collection if it does not exist yet,
+// so migrations can be safely re-run on every start.
+func createCollection(ctx context.Context, db *mongo.Database, name string) error {
+	err := db.CreateCollection(ctx, name)
+
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Name == "NamespaceExists" {
+		return nil
+	}
+
+	return err
+}
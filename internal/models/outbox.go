@@ -0,0 +1,68 @@
+package models
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type OutboxStatus string
+
+const (
+	// OutboxPending - message waits for the next delivery attempt
+	OutboxPending OutboxStatus = "pending"
+	// OutboxSending - message is being delivered by a worker until LockedUntil
+	OutboxSending OutboxStatus = "sending"
+	OutboxSent    OutboxStatus = "sent"
+	// OutboxFailed - message ran out of delivery attempts
+	OutboxFailed OutboxStatus = "failed"
+	// OutboxCancelled - recipient may no longer receive the message, it is never sent
+	OutboxCancelled OutboxStatus = "cancelled"
+)
+
+// OutboxMessage is a rendered email waiting for delivery.
+type OutboxMessage struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty"`
+	To            string             `bson:"to"`
+	Subject       string             `bson:"subject"`
+	HTML          string             `bson:"html,omitempty"`
+	Text          string             `bson:"text,omitempty"`
+	Headers       map[string]string  `bson:"headers,omitempty"`
+	Status        OutboxStatus       `bson:"status"`
+	Attempts      int                `bson:"attempts"`
+	LastError     string             `bson:"last_error,omitempty"`
+	NextAttemptAt time.Time          `bson:"next_attempt_at"`
+	LockedUntil   time.Time          `bson:"locked_until,omitempty"`
+	CreatedAt     time.Time          `bson:"created_at,omitempty"`
+	SentAt        time.Time          `bson:"sent_at,omitempty"`
+	// DedupeKey - optional, a message with the same key is enqueued only once
+	DedupeKey string `bson:"dedupe_key,omitempty"`
+}
+
+func (OutboxMessage) CollectionName() string {
+	return "mail_outbox"
+}
+
+func (m OutboxMessage) Migrate(ctx context.Context, db *mongo.Database) error {
+	err := createCollection(ctx, db, m.CollectionName())
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(m.CollectionName()).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "dedupe_key", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"dedupe_key": bson.M{"$exists": true}}),
+		},
+	})
+
+	return err
+}
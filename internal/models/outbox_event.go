@@ -0,0 +1,68 @@
+package models
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type OutboxEventStatus string
+
+const (
+	// OutboxEventPrepared - event was recorded ahead of the change it describes,
+	// the change may not have happened yet
+	OutboxEventPrepared OutboxEventStatus = "prepared"
+	// OutboxEventPending - change happened, the event waits for its subscribers
+	OutboxEventPending   OutboxEventStatus = "pending"
+	OutboxEventPublished OutboxEventStatus = "published"
+	// OutboxEventFailed - some subscribers kept failing until the attempts ran out
+	OutboxEventFailed OutboxEventStatus = "failed"
+)
+
+// publishedEventsTTL - how long published events are kept for troubleshooting
+const publishedEventsTTL = 7 * 24 * time.Hour
+
+// OutboxEvent is a domain event stored until every subscriber handled it.
+type OutboxEvent struct {
+	ID   primitive.ObjectID `bson:"_id,omitempty"`
+	Name string             `bson:"name"`
+	// Post - the post as it was after the change, before it for deletions
+	Post Post `bson:"post"`
+	// Previous - the post before an update
+	Previous *Post             `bson:"previous,omitempty"`
+	Status   OutboxEventStatus `bson:"status"`
+	// Handled - subscribers done with the event, skipped when it is delivered again
+	Handled   []string `bson:"handled,omitempty"`
+	Attempts  int      `bson:"attempts"`
+	LastError string   `bson:"last_error,omitempty"`
+	// NextAttemptAt - when the relay picks the event up, pushed forward while it is being delivered
+	NextAttemptAt time.Time `bson:"next_attempt_at"`
+	CreatedAt     time.Time `bson:"created_at"`
+	PublishedAt   time.Time `bson:"published_at,omitempty"`
+}
+
+func (OutboxEvent) CollectionName() string {
+	return "event_outbox"
+}
+
+func (e OutboxEvent) Migrate(ctx context.Context, db *mongo.Database) error {
+	err := createCollection(ctx, db, e.CollectionName())
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(e.CollectionName()).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+		},
+		{
+			Keys:    bson.D{{Key: "published_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(publishedEventsTTL.Seconds())),
+		},
+	})
+
+	return err
+}
@@ -5,6 +5,9 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -14,6 +17,60 @@ type Post struct {
 	Content   string             `bson:"content,omitempty"`
 	CreatedAt time.Time          `bson:"created_at,omitempty"`
 	UpdatedAt time.Time          `bson:"updated_at,omitempty"`
+	// Tags - topics of the post, subscribers may follow only some of them
+	Tags []string `bson:"tags,omitempty"`
+	// CategoryID - the category the post is filed under, zero when it has none
+	CategoryID primitive.ObjectID `bson:"category_id,omitempty"`
+	// Author - display name of the writer, empty for posts created before authors existed
+	Author string `bson:"author,omitempty"`
+	// AuthorEmail - address the writer is told about comments at, never shown to readers
+	AuthorEmail string `bson:"author_email,omitempty"`
+	// Slug - unique URL name generated from the title, empty for posts not backfilled yet
+	Slug string `bson:"slug,omitempty"`
+	// SlugHistory - former slugs of the post, they redirect to the current one
+	SlugHistory []string `bson:"slug_history,omitempty"`
+	// HeroImage - key of the attachment shown above the post and in link previews
+	HeroImage string `bson:"hero_image,omitempty"`
+	// CommentsDisabled - readers may not comment on the post, existing comments stay shown
+	CommentsDisabled bool `bson:"comments_disabled,omitempty"`
+}
+
+// Path returns the path of the post page, by slug when the post has one.
+func (p Post) Path() string {
+	if p.Slug != "" {
+		return "/posts/" + p.Slug
+	}
+
+	return "/posts/" + p.ID.Hex()
+}
+
+// HasAnyTag reports whether the post is tagged with one of the tags.
+func (p Post) HasAnyTag(tags []string) bool {
+	for _, tag := range tags {
+		if slices.Contains(p.Tags, tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseTags splits comma separated tags, normalizing and deduplicating them.
+func ParseTags(input string) []string {
+	return NormalizeTags(strings.Split(input, ","))
+}
+
+// NormalizeTags lowercases and trims the tags, dropping empty and repeated ones.
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.Join(strings.Fields(tag), " "))
+		if tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+
+	return normalized
 }
 
 func (Post) CollectionName() string {
@@ -21,15 +78,35 @@ func (Post) CollectionName() string {
 }
 
 func (p Post) Migrate(ctx context.Context, db *mongo.Database) error {
-	err := db.CreateCollection(ctx, p.CollectionName())
+	err := createCollection(ctx, db, p.CollectionName())
 	if err != nil {
 		return err
 	}
 
-	_, err = db.Collection(p.CollectionName()).Indexes().CreateOne(ctx, mongo.IndexModel{
-		Keys: bson.D{
-			{"title", "text"},
-			{"content", "text"},
+	_, err = db.Collection(p.CollectionName()).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{"title", "text"},
+				{"content", "text"},
+			},
+		},
+		{
+			Keys: bson.D{{Key: "tags", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "author", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "category_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "slug", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"slug": bson.M{"$gt": ""}}),
+		},
+		{
+			Keys: bson.D{{Key: "slug_history", Value: 1}},
 		},
 	})
 
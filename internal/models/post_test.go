@@ -0,0 +1,81 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParseTags(t *testing.T) {
+	t.Run("Positive: Normalize and deduplicate", func(t *testing.T) {
+		tags := ParseTags(" Go, databases ,go,,  Machine   Learning ")
+		assert.Equal(t, []string{"go", "databases", "machine learning"}, tags)
+	})
+
+	t.Run("Positive: Empty input", func(t *testing.T) {
+		assert.Empty(t, ParseTags(" , "))
+	})
+}
+
+func TestPost_Path(t *testing.T) {
+	id := primitive.NewObjectID()
+
+	t.Run("Positive: Slug", func(t *testing.T) {
+		assert.Equal(t, "/posts/hello-world", Post{ID: id, Slug: "hello-world"}.Path())
+	})
+
+	t.Run("Negative: Post without a slug", func(t *testing.T) {
+		assert.Equal(t, "/posts/"+id.Hex(), Post{ID: id}.Path())
+	})
+}
+
+func TestSubscriber_Follows(t *testing.T) {
+	tech := Category{ID: primitive.NewObjectID(), Name: "Tech"}
+	golang := Category{ID: primitive.NewObjectID(), Name: "Go", ParentID: tech.ID}
+	travel := Category{ID: primitive.NewObjectID(), Name: "Travel"}
+	categories := []Category{tech, golang, travel}
+	post := Post{Tags: []string{"go", "databases"}, CategoryID: golang.ID}
+
+	t.Run("Positive: Subscriber without topics follows every post", func(t *testing.T) {
+		assert.True(t, Subscriber{}.Follows(post, categories))
+		assert.True(t, Subscriber{}.Follows(Post{}, nil))
+	})
+
+	t.Run("Positive: Matching topic", func(t *testing.T) {
+		assert.True(t, Subscriber{Topics: []string{"rust", "go"}}.Follows(post, categories))
+	})
+
+	t.Run("Positive: Matching category", func(t *testing.T) {
+		assert.True(t, Subscriber{Categories: []primitive.ObjectID{golang.ID}}.Follows(post, categories))
+	})
+
+	t.Run("Positive: Post filed under a subcategory", func(t *testing.T) {
+		assert.True(t, Subscriber{Categories: []primitive.ObjectID{tech.ID}}.Follows(post, categories))
+	})
+
+	t.Run("Positive: Matching category without matching topic", func(t *testing.T) {
+		subscriber := Subscriber{Topics: []string{"rust"}, Categories: []primitive.ObjectID{tech.ID}}
+		assert.True(t, subscriber.Follows(post, categories))
+	})
+
+	t.Run("Negative: No matching topic", func(t *testing.T) {
+		assert.False(t, Subscriber{Topics: []string{"rust"}}.Follows(post, categories))
+		assert.False(t, Subscriber{Topics: []string{"rust"}}.Follows(Post{}, categories))
+	})
+
+	t.Run("Negative: No matching category", func(t *testing.T) {
+		assert.False(t, Subscriber{Categories: []primitive.ObjectID{travel.ID}}.Follows(post, categories))
+		assert.False(t, Subscriber{Categories: []primitive.ObjectID{golang.ID}}.Follows(Post{CategoryID: tech.ID}, categories))
+		assert.False(t, Subscriber{Categories: []primitive.ObjectID{tech.ID}}.Follows(Post{}, categories))
+	})
+
+	t.Run("Negative: Parents loop", func(t *testing.T) {
+		first := Category{ID: primitive.NewObjectID()}
+		second := Category{ID: primitive.NewObjectID(), ParentID: first.ID}
+		first.ParentID = second.ID
+
+		subscriber := Subscriber{Categories: []primitive.ObjectID{tech.ID}}
+		assert.False(t, subscriber.Follows(Post{CategoryID: first.ID}, []Category{first, second}))
+	})
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// SearchQuery is an entry of the search query log, used to suggest popular searches.
+type SearchQuery struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty"`
+	Query          string             `bson:"query"`
+	Count          int64              `bson:"count"`
+	LastSearchedAt time.Time          `bson:"last_searched_at"`
+}
+
+func (SearchQuery) CollectionName() string {
+	return "search_queries"
+}
+
+func (q SearchQuery) Migrate(ctx context.Context, db *mongo.Database) error {
+	err := createCollection(ctx, db, q.CollectionName())
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(q.CollectionName()).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "query", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "count", Value: -1}},
+		},
+	})
+
+	return err
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// SpamMessagesToken - token counting the trained messages themselves
+const SpamMessagesToken = "__messages__"
+
+// SpamToken counts the spam and ham (legitimate) messages a word of the content scorer
+// was seen in, as decided by moderators.
+type SpamToken struct {
+	Token string `bson:"_id"`
+	Spam  int64  `bson:"spam"`
+	Ham   int64  `bson:"ham"`
+}
+
+func (SpamToken) CollectionName() string {
+	return "spam_tokens"
+}
+
+func (t SpamToken) Migrate(ctx context.Context, db *mongo.Database) error {
+	// tokens are looked up by their ID only
+	return createCollection(ctx, db, t.CollectionName())
+}
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"slices"
+	"time"
+)
+
+type SubscriberStatus string
+
+const (
+	// SubscriberPending - subscriber did not confirm the email address yet
+	SubscriberPending SubscriberStatus = "pending"
+	// SubscriberActive - subscriber confirmed the email address and receives newsletters
+	SubscriberActive SubscriberStatus = "active"
+	// SubscriberUnsubscribed - subscriber opted out of the newsletter
+	SubscriberUnsubscribed SubscriberStatus = "unsubscribed"
+	// SubscriberBounced - mail to the address bounced too often, the address is suppressed
+	SubscriberBounced SubscriberStatus = "bounced"
+	// SubscriberComplained - the subscriber marked the newsletter as spam, the address is suppressed
+	SubscriberComplained SubscriberStatus = "complained"
+)
+
+type DigestFrequency string
+
+const (
+	// DigestNone - subscriber does not receive digests
+	DigestNone   DigestFrequency = "none"
+	DigestDaily  DigestFrequency = "daily"
+	DigestWeekly DigestFrequency = "weekly"
+)
+
+type Subscriber struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty"`
+	Email  string             `bson:"email"`
+	Status SubscriberStatus   `bson:"status"`
+	// Source - where the subscription was made, e.g. "home"
+	Source         string    `bson:"source,omitempty"`
+	CreatedAt      time.Time `bson:"created_at,omitempty"`
+	UpdatedAt      time.Time `bson:"updated_at,omitempty"`
+	ConfirmedAt    time.Time `bson:"confirmed_at,omitempty"`
+	UnsubscribedAt time.Time `bson:"unsubscribed_at,omitempty"`
+	// DigestFrequency - empty for subscribers created before digests existed, treated as weekly
+	DigestFrequency DigestFrequency `bson:"digest_frequency,omitempty"`
+	// DigestHour - local hour of the day the digest is sent at
+	DigestHour int `bson:"digest_hour"`
+	// DigestWeekday - local day of the week weekly digests are sent on
+	DigestWeekday time.Weekday `bson:"digest_weekday"`
+	// Timezone - IANA time zone name, UTC when empty
+	Timezone     string    `bson:"timezone,omitempty"`
+	LastDigestAt time.Time `bson:"last_digest_at,omitempty"`
+	// Topics - followed post tags, empty with Categories to receive posts of every topic
+	Topics []string `bson:"topics,omitempty"`
+	// Categories - followed categories, posts of their subcategories included
+	Categories []primitive.ObjectID `bson:"categories,omitempty"`
+	// LastActiveAt - last time the subscriber confirmed the address or changed preferences
+	LastActiveAt time.Time `bson:"last_active_at,omitempty"`
+	// TrackingOptOut - never track opens and clicks of the emails sent to the subscriber
+	TrackingOptOut bool      `bson:"tracking_opt_out,omitempty"`
+	SuppressedAt   time.Time `bson:"suppressed_at,omitempty"`
+	// SuppressionReason - diagnostic of the bounce or complaint that suppressed the address
+	SuppressionReason string `bson:"suppression_reason,omitempty"`
+}
+
+// Suppressed reports whether no mail may be sent to the subscriber because of bounces or complaints.
+func (s Subscriber) Suppressed() bool {
+	return s.Status == SubscriberBounced || s.Status == SubscriberComplained
+}
+
+// Follows reports whether the subscriber wants to receive the post: it is tagged with a
+// followed topic or filed under a followed category, directly or through its subcategories.
+// categories are all the categories, the parents of the category of the post are found in them.
+func (s Subscriber) Follows(post Post, categories []Category) bool {
+	if len(s.Topics) == 0 && len(s.Categories) == 0 {
+		return true
+	}
+	if post.HasAnyTag(s.Topics) {
+		return true
+	}
+
+	parents := make(map[primitive.ObjectID]primitive.ObjectID, len(categories))
+	for _, category := range categories {
+		parents[category.ID] = category.ParentID
+	}
+	// every category is visited at most once, even if the parents loop
+	id := post.CategoryID
+	for range len(categories) + 1 {
+		if id.IsZero() {
+			return false
+		}
+		if slices.Contains(s.Categories, id) {
+			return true
+		}
+		id = parents[id]
+	}
+
+	return false
+}
+
+// Frequency returns the digest frequency, defaulting to weekly.
+func (s Subscriber) Frequency() DigestFrequency {
+	if s.DigestFrequency == "" {
+		return DigestWeekly
+	}
+
+	return s.DigestFrequency
+}
+
+// Location returns the subscriber time zone, falling back to UTC for unknown names.
+func (s Subscriber) Location() *time.Location {
+	if s.Timezone == "" {
+		return time.UTC
+	}
+	location, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return location
+}
+
+func (Subscriber) CollectionName() string {
+	return "subscribers"
+}
+
+func (s Subscriber) Migrate(ctx context.Context, db *mongo.Database) error {
+	err := createCollection(ctx, db, s.CollectionName())
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(s.CollectionName()).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	})
+
+	return err
+}
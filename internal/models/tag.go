@@ -0,0 +1,42 @@
+package models
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// Tag is a known post tag, offered by the tag picker. Tags are free-form, every
+// normalized tag of a saved post is registered.
+type Tag struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Name      string             `bson:"name"`
+	CreatedAt time.Time          `bson:"created_at"`
+}
+
+// TagCount is a tag with the number of posts tagged with it.
+type TagCount struct {
+	Name  string `bson:"_id"`
+	Posts int    `bson:"posts"`
+}
+
+func (Tag) CollectionName() string {
+	return "tags"
+}
+
+func (t Tag) Migrate(ctx context.Context, db *mongo.Database) error {
+	err := createCollection(ctx, db, t.CollectionName())
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(t.CollectionName()).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	return err
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"time"
+)
+
+type TrackingEventKind string
+
+const (
+	// TrackingOpen - the open pixel of an email was loaded
+	TrackingOpen TrackingEventKind = "open"
+	// TrackingClick - a tracked link of an email was followed
+	TrackingClick TrackingEventKind = "click"
+)
+
+type TrackingSource string
+
+const (
+	TrackingSourceDigest TrackingSource = "digest"
+	TrackingSourceIssue  TrackingSource = "issue"
+)
+
+// TrackingEvent records an open or a click of a newsletter email. No IP addresses or
+// user agents are stored, the subscriber ID is kept only to count unique readers.
+type TrackingEvent struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"`
+	Kind         TrackingEventKind  `bson:"kind"`
+	Source       TrackingSource     `bson:"source"`
+	IssueID      primitive.ObjectID `bson:"issue_id,omitempty"`
+	SubscriberID primitive.ObjectID `bson:"subscriber_id"`
+	// PostID and URL - the followed link of click events
+	PostID    primitive.ObjectID `bson:"post_id,omitempty"`
+	URL       string             `bson:"url,omitempty"`
+	CreatedAt time.Time          `bson:"created_at"`
+}
+
+func (TrackingEvent) CollectionName() string {
+	return "tracking_events"
+}
+
+func (e TrackingEvent) Migrate(ctx context.Context, db *mongo.Database) error {
+	err := createCollection(ctx, db, e.CollectionName())
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(e.CollectionName()).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "issue_id", Value: 1}, {Key: "kind", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "post_id", Value: 1}, {Key: "kind", Value: 1}}},
+	})
+
+	return err
+}
+
+// TrackingTotals sums the events of an issue, unique counts are the numbers of distinct subscribers.
+type TrackingTotals struct {
+	Opens        int
+	UniqueOpens  int
+	Clicks       int
+	UniqueClicks int
+}
+
+// LinkStats sums the clicks of a link or of all links to a post.
+type LinkStats struct {
+	URL          string
+	PostID       primitive.ObjectID
+	Clicks       int
+	UniqueClicks int
+}
+
+// DayStats sums the events of a day, days are in UTC.
+type DayStats struct {
+	Day    time.Time
+	Opens  int
+	Clicks int
+}
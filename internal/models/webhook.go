@@ -0,0 +1,138 @@
+package models
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"slices"
+	"time"
+)
+
+type WebhookEvent string
+
+const (
+	WebhookPostCreated WebhookEvent = "post.created"
+	WebhookPostUpdated WebhookEvent = "post.updated"
+	// WebhookPostPublished - post became visible to readers, posts are public once created
+	WebhookPostPublished WebhookEvent = "post.published"
+	WebhookPostDeleted   WebhookEvent = "post.deleted"
+)
+
+// WebhookEvents - every event webhooks can subscribe to
+var WebhookEvents = []WebhookEvent{
+	WebhookPostCreated,
+	WebhookPostUpdated,
+	WebhookPostPublished,
+	WebhookPostDeleted,
+}
+
+// Webhook is an endpoint notified about changes of posts.
+type Webhook struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	URL         string             `bson:"url"`
+	Description string             `bson:"description,omitempty"`
+	// Secret - key of the HMAC-SHA256 signature of every delivery
+	Secret string `bson:"secret"`
+	// Events - events the endpoint is notified about, every event when empty
+	Events    []WebhookEvent `bson:"events,omitempty"`
+	Active    bool           `bson:"active"`
+	CreatedAt time.Time      `bson:"created_at"`
+	UpdatedAt time.Time      `bson:"updated_at"`
+}
+
+// Accepts reports whether the endpoint is notified about the event.
+func (w *Webhook) Accepts(event WebhookEvent) bool {
+	return w.Active && (len(w.Events) == 0 || slices.Contains(w.Events, event))
+}
+
+// Subscribes reports whether the event is selected explicitly, used by the settings form.
+func (w *Webhook) Subscribes(event WebhookEvent) bool {
+	return slices.Contains(w.Events, event)
+}
+
+func (Webhook) CollectionName() string {
+	return "webhooks"
+}
+
+func (w Webhook) Migrate(ctx context.Context, db *mongo.Database) error {
+	err := createCollection(ctx, db, w.CollectionName())
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(w.CollectionName()).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "active", Value: 1}},
+	})
+
+	return err
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending - delivery waits for the next attempt
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliverySending - delivery is being sent by a worker until LockedUntil
+	WebhookDeliverySending   WebhookDeliveryStatus = "sending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookDeliveryFailed - delivery ran out of attempts
+	WebhookDeliveryFailed WebhookDeliveryStatus = "failed"
+)
+
+// WebhookResponse is the answer of the endpoint to the last attempt.
+type WebhookResponse struct {
+	Status int `bson:"status,omitempty"`
+	// Body - beginning of the response body
+	Body string `bson:"body,omitempty"`
+}
+
+// WebhookDelivery is a signed event payload queued for an endpoint.
+type WebhookDelivery struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	WebhookID primitive.ObjectID `bson:"webhook_id"`
+	// EventID - same for every delivery of the event, so endpoints can skip duplicates
+	EventID string `bson:"event_id"`
+	// DedupeKey - unique key of the first delivery of the event to the webhook, empty for
+	// redeliveries, so an event published again queues no second delivery
+	DedupeKey     string                `bson:"dedupe_key,omitempty"`
+	Event         WebhookEvent          `bson:"event"`
+	Payload       string                `bson:"payload"`
+	Status        WebhookDeliveryStatus `bson:"status"`
+	Attempts      int                   `bson:"attempts"`
+	LastError     string                `bson:"last_error,omitempty"`
+	Response      WebhookResponse       `bson:"response,omitempty"`
+	NextAttemptAt time.Time             `bson:"next_attempt_at"`
+	LockedUntil   time.Time             `bson:"locked_until,omitempty"`
+	CreatedAt     time.Time             `bson:"created_at"`
+	DeliveredAt   time.Time             `bson:"delivered_at,omitempty"`
+}
+
+func (WebhookDelivery) CollectionName() string {
+	return "webhook_deliveries"
+}
+
+func (d WebhookDelivery) Migrate(ctx context.Context, db *mongo.Database) error {
+	err := createCollection(ctx, db, d.CollectionName())
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(d.CollectionName()).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "webhook_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "dedupe_key", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"dedupe_key": bson.M{"$exists": true}}),
+		},
+	})
+
+	return err
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhook_Accepts(t *testing.T) {
+	t.Run("Positive: Empty filter accepts every event", func(t *testing.T) {
+		webhook := &Webhook{Active: true}
+		for _, event := range WebhookEvents {
+			assert.True(t, webhook.Accepts(event))
+		}
+	})
+
+	t.Run("Positive: Filter accepts selected events only", func(t *testing.T) {
+		webhook := &Webhook{Active: true, Events: []WebhookEvent{WebhookPostCreated, WebhookPostDeleted}}
+		assert.True(t, webhook.Accepts(WebhookPostCreated))
+		assert.True(t, webhook.Accepts(WebhookPostDeleted))
+		assert.False(t, webhook.Accepts(WebhookPostUpdated))
+		assert.False(t, webhook.Subscribes(WebhookPostPublished))
+	})
+
+	t.Run("Negative: Inactive webhook accepts nothing", func(t *testing.T) {
+		webhook := &Webhook{Events: []WebhookEvent{WebhookPostCreated}}
+		assert.False(t, webhook.Accepts(WebhookPostCreated))
+	})
+}
@@ -0,0 +1,223 @@
+package newsletter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/internal/config"
+	"newsteller/internal/models"
+	"newsteller/internal/repositories"
+	"newsteller/internal/templates"
+	"time"
+)
+
+// postsPageSize - number of posts loaded at once while looking for the posts of a period
+const postsPageSize = 50
+
+// PostFinder loads the posts digests and issues are composed of, e.g. *repositories.Post.
+// Posts are read as they are stored, issues must not send outdated content.
+type PostFinder interface {
+	FindPaginated(ctx context.Context, q *repositories.PaginatedSearchQuery) ([]models.Post, int64, error)
+	FindByID(ctx context.Context, id string) (*models.Post, error)
+}
+
+// Composer builds digests of the posts published in a period.
+type Composer struct {
+	cfg   *config.Config
+	posts PostFinder
+	links *Links
+}
+
+func NewComposer(cfg *config.Config, posts PostFinder, links *Links) *Composer {
+	return &Composer{
+		cfg:   cfg,
+		posts: posts,
+		links: links,
+	}
+}
+
+// Posts returns the posts published after since and not after until, newest first.
+func (c *Composer) Posts(ctx context.Context, since, until time.Time) ([]models.Post, error) {
+	var published []models.Post
+	// posts are sorted by creation time, newest first, so paging stops at the first older post
+	for page := 1; ; page++ {
+		posts, _, err := c.posts.FindPaginated(ctx, &repositories.PaginatedSearchQuery{
+			Page:  page,
+			Limit: postsPageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, post := range posts {
+			if !post.CreatedAt.After(since) {
+				return published, nil
+			}
+			if !post.CreatedAt.After(until) {
+				published = append(published, post)
+			}
+		}
+		if len(posts) < postsPageSize {
+			return published, nil
+		}
+	}
+}
+
+// DigestPosts returns the posts the subscriber follows, at most the configured digest size.
+func (c *Composer) DigestPosts(subscriber *models.Subscriber, posts []models.Post, categories []models.Category) []models.Post {
+	followed := Followed(subscriber, posts, categories)
+
+	return followed[:min(len(followed), c.cfg.Newsletter.DigestMaxPosts)]
+}
+
+// Compose renders the digest of the posts for the subscriber.
+func (c *Composer) Compose(subscriber *models.Subscriber, posts []models.Post) (*templates.Email, error) {
+	ref := c.trackingRef(subscriber, TrackingRef{Source: models.TrackingSourceDigest})
+	email, err := templates.
+		NewDigestEmail(
+			subscriber.Frequency(),
+			c.items(posts, ref),
+			c.links.UnsubscribeURL(subscriber),
+			c.links.PreferencesURL(subscriber),
+		).
+		GenerateEmail()
+	if err != nil {
+		return nil, err
+	}
+	c.addOpenPixel(email, ref)
+
+	return email, nil
+}
+
+// IssuePosts loads the posts of the issue in the issue order.
+// Posts deleted after they were picked are skipped.
+func (c *Composer) IssuePosts(ctx context.Context, issue *models.Issue) ([]models.Post, error) {
+	posts := make([]models.Post, 0, len(issue.PostIDs))
+	for _, id := range issue.PostIDs {
+		post, err := c.posts.FindByID(ctx, id.Hex())
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, *post)
+	}
+
+	return posts, nil
+}
+
+// ComposeIssue renders the issue for the subscriber. Test sends pass a nil subscriber
+// and get no unsubscribe link.
+func (c *Composer) ComposeIssue(
+	issue *models.Issue,
+	posts []models.Post,
+	subscriber *models.Subscriber,
+) (*templates.Email, error) {
+	var archiveURL, unsubscribeURL, preferencesURL string
+	var ref *TrackingRef
+	if subscriber != nil {
+		archiveURL = c.cfg.BaseURL + "/newsletter/" + issue.ID.Hex()
+		unsubscribeURL = c.links.UnsubscribeURL(subscriber)
+		preferencesURL = c.links.PreferencesURL(subscriber)
+		ref = c.trackingRef(subscriber, TrackingRef{Source: models.TrackingSourceIssue, IssueID: issue.ID})
+	}
+
+	email, err := templates.
+		NewIssueEmail(issue, c.items(posts, ref), archiveURL, unsubscribeURL, preferencesURL).
+		GenerateEmail()
+	if err != nil {
+		return nil, err
+	}
+	c.addOpenPixel(email, ref)
+
+	return email, nil
+}
+
+// Followed returns the posts tagged with the topics or filed under the categories the subscriber
+// follows, keeping their order.
+func Followed(subscriber *models.Subscriber, posts []models.Post, categories []models.Category) []models.Post {
+	followed := make([]models.Post, 0, len(posts))
+	for _, post := range posts {
+		if subscriber.Follows(post, categories) {
+			followed = append(followed, post)
+		}
+	}
+
+	return followed
+}
+
+// items links the posts, through click redirects when clicks of the email are tracked.
+func (c *Composer) items(posts []models.Post, ref *TrackingRef) []templates.DigestItem {
+	items := make([]templates.DigestItem, len(posts))
+	for i := range posts {
+		url := c.cfg.BaseURL + posts[i].Path()
+		if ref != nil && c.cfg.Newsletter.TrackClicks {
+			postRef := *ref
+			postRef.PostID = posts[i].ID
+			url = c.links.ClickURL(postRef, url)
+		}
+		items[i] = templates.DigestItem{
+			Post: posts[i],
+			URL:  url,
+		}
+	}
+
+	return items
+}
+
+// trackingRef returns the reference of the email sent to the subscriber, or nil when it must
+// not be tracked: the subscriber opted out or it is a preview without a real subscriber.
+func (c *Composer) trackingRef(subscriber *models.Subscriber, ref TrackingRef) *TrackingRef {
+	if subscriber.ID.IsZero() || subscriber.TrackingOptOut {
+		return nil
+	}
+	ref.SubscriberID = subscriber.ID
+
+	return &ref
+}
+
+func (c *Composer) addOpenPixel(email *templates.Email, ref *TrackingRef) {
+	if ref != nil && c.cfg.Newsletter.TrackOpens {
+		email.AddOpenPixel(c.links.OpenURL(*ref))
+	}
+}
+
+// DuePeriod returns the digest period that is due for the subscriber at now. It reports false
+// while the send time of the current period has not come yet in the subscriber time zone.
+// A weekly digest missed on its weekday, e.g. because the service was down, is due until the week ends.
+func DuePeriod(subscriber *models.Subscriber, now time.Time) (string, bool) {
+	local := now.In(subscriber.Location())
+
+	switch subscriber.Frequency() {
+	case models.DigestDaily:
+		if local.Hour() < subscriber.DigestHour {
+			return "", false
+		}
+		return local.Format(time.DateOnly), true
+	case models.DigestWeekly:
+		today, sendDay := isoWeekday(local.Weekday()), isoWeekday(subscriber.DigestWeekday)
+		if today < sendDay || today == sendDay && local.Hour() < subscriber.DigestHour {
+			return "", false
+		}
+		year, week := local.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week), true
+	default:
+		return "", false
+	}
+}
+
+// PeriodLength returns the time covered by a digest of the frequency.
+func PeriodLength(frequency models.DigestFrequency) time.Duration {
+	if frequency == models.DigestDaily {
+		return 24 * time.Hour
+	}
+
+	return 7 * 24 * time.Hour
+}
+
+// isoWeekday numbers days from Monday, as ISO weeks start on Monday.
+func isoWeekday(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
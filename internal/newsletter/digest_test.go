@@ -0,0 +1,207 @@
+package newsletter
+
+import (
+	"context"
+	"newsteller/internal/config"
+	"newsteller/internal/models"
+	"newsteller/internal/repositories"
+	"newsteller/internal/state"
+	"newsteller/internal/tokens"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// postsState serves a fixed list of posts, sorted newest first.
+type postsState struct {
+	state.State[models.Post]
+	posts []models.Post
+}
+
+func (s *postsState) FindPaginated(_ context.Context, q *repositories.PaginatedSearchQuery) ([]models.Post, int64, error) {
+	end := min(q.Page*q.Limit, len(s.posts))
+	return s.posts[(q.Page-1)*q.Limit : end], int64(len(s.posts)), nil
+}
+
+func (s *postsState) FindByID(_ context.Context, id string) (*models.Post, error) {
+	for i := range s.posts {
+		if s.posts[i].ID.Hex() == id {
+			return &s.posts[i], nil
+		}
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+func TestDuePeriod(t *testing.T) {
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	require.NoError(t, err)
+
+	daily := &models.Subscriber{DigestFrequency: models.DigestDaily, DigestHour: 8, Timezone: "Europe/Kyiv"}
+	weekly := &models.Subscriber{DigestFrequency: models.DigestWeekly, DigestHour: 8, DigestWeekday: time.Wednesday}
+
+	tests := []struct {
+		name       string
+		subscriber *models.Subscriber
+		now        time.Time
+		period     string
+		due        bool
+	}{
+		{"Daily before send hour", daily, time.Date(2025, 3, 5, 7, 59, 0, 0, kyiv), "", false},
+		{"Daily at send hour", daily, time.Date(2025, 3, 5, 8, 0, 0, 0, kyiv), "2025-03-05", true},
+		{"Daily uses subscriber time zone", daily, time.Date(2025, 3, 5, 6, 30, 0, 0, time.UTC), "2025-03-05", true},
+		{"Weekly before send day", weekly, time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC), "", false},
+		{"Weekly before send hour", weekly, time.Date(2025, 3, 5, 7, 0, 0, 0, time.UTC), "", false},
+		{"Weekly at send hour", weekly, time.Date(2025, 3, 5, 8, 0, 0, 0, time.UTC), "2025-W10", true},
+		{"Weekly missed send day", weekly, time.Date(2025, 3, 9, 23, 0, 0, 0, time.UTC), "2025-W10", true},
+		{"Weekly next week", weekly, time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC), "", false},
+		{"No digest", &models.Subscriber{DigestFrequency: models.DigestNone}, time.Now(), "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			period, due := DuePeriod(tt.subscriber, tt.now)
+			assert.Equal(t, tt.due, due)
+			assert.Equal(t, tt.period, period)
+		})
+	}
+}
+
+func TestDuePeriod_UnknownTimezone(t *testing.T) {
+	subscriber := &models.Subscriber{DigestFrequency: models.DigestDaily, DigestHour: 8, Timezone: "Nowhere/Unknown"}
+
+	period, due := DuePeriod(subscriber, time.Date(2025, 3, 5, 8, 30, 0, 0, time.UTC))
+	assert.True(t, due, "Unknown time zone should fall back to UTC")
+	assert.Equal(t, "2025-03-05", period)
+}
+
+func TestComposer(t *testing.T) {
+	cfg := &config.Config{BaseURL: "https://news.example.com"}
+	cfg.Newsletter.DigestMaxPosts = 3
+	now := time.Date(2025, 3, 5, 8, 0, 0, 0, time.UTC)
+
+	posts := []models.Post{
+		{ID: primitive.NewObjectID(), Title: "Scheduled", CreatedAt: now.Add(time.Minute)},
+		{ID: primitive.NewObjectID(), Title: "Today", CreatedAt: now.Add(-time.Hour)},
+		{ID: primitive.NewObjectID(), Title: "Yesterday", CreatedAt: now.Add(-20 * time.Hour)},
+		{ID: primitive.NewObjectID(), Title: "Last week", CreatedAt: now.Add(-7 * 24 * time.Hour)},
+	}
+	composer := NewComposer(cfg, &postsState{posts: posts}, NewLinks(cfg, tokens.NewSigner("secret")))
+
+	t.Run("Positive: Posts of the period", func(t *testing.T) {
+		published, err := composer.Posts(context.Background(), now.Add(-24*time.Hour), now)
+		require.NoError(t, err)
+		require.Len(t, published, 2)
+		assert.Equal(t, "Today", published[0].Title)
+		assert.Equal(t, "Yesterday", published[1].Title)
+	})
+
+	t.Run("Negative: Nothing published", func(t *testing.T) {
+		published, err := composer.Posts(context.Background(), now, now)
+		require.NoError(t, err)
+		assert.Empty(t, published)
+	})
+
+	t.Run("Positive: Compose links posts and unsubscribe", func(t *testing.T) {
+		subscriber := &models.Subscriber{ID: primitive.NewObjectID(), DigestFrequency: models.DigestDaily}
+
+		email, err := composer.Compose(subscriber, posts[1:3])
+		require.NoError(t, err)
+		assert.Equal(t, "Your daily Newsteller digest: Today", email.Subject)
+		assert.Contains(t, email.Text, "https://news.example.com/posts/"+posts[1].ID.Hex())
+		assert.Contains(t, email.Text, "https://news.example.com/subscribers/unsubscribe?token=")
+	})
+}
+
+func TestComposer_Topics(t *testing.T) {
+	cfg := &config.Config{BaseURL: "https://news.example.com"}
+	cfg.Newsletter.DigestMaxPosts = 2
+	now := time.Date(2025, 3, 5, 8, 0, 0, 0, time.UTC)
+
+	posts := make([]models.Post, postsPageSize+10)
+	for i := range posts {
+		posts[i] = models.Post{ID: primitive.NewObjectID(), CreatedAt: now.Add(-time.Duration(i+1) * time.Minute)}
+		if i%2 == 0 {
+			posts[i].Tags = []string{"go"}
+		}
+	}
+	composer := NewComposer(cfg, &postsState{posts: posts}, NewLinks(cfg, tokens.NewSigner("secret")))
+
+	t.Run("Positive: Posts of the period span several pages", func(t *testing.T) {
+		published, err := composer.Posts(context.Background(), now.Add(-24*time.Hour), now)
+		require.NoError(t, err)
+		assert.Len(t, published, len(posts))
+	})
+
+	t.Run("Positive: Digest posts are limited to followed topics", func(t *testing.T) {
+		digestPosts := composer.DigestPosts(&models.Subscriber{Topics: []string{"go"}}, posts, nil)
+		require.Len(t, digestPosts, 2)
+		assert.Equal(t, posts[0].ID, digestPosts[0].ID)
+		assert.Equal(t, posts[2].ID, digestPosts[1].ID)
+	})
+
+	t.Run("Positive: Subscriber without topics follows every post", func(t *testing.T) {
+		assert.Len(t, Followed(&models.Subscriber{}, posts, nil), len(posts))
+	})
+
+	t.Run("Positive: Digest posts of followed categories", func(t *testing.T) {
+		parent := models.Category{ID: primitive.NewObjectID()}
+		child := models.Category{ID: primitive.NewObjectID(), ParentID: parent.ID}
+		filed := append([]models.Post(nil), posts[:4]...)
+		filed[3].CategoryID = child.ID
+
+		subscriber := &models.Subscriber{Categories: []primitive.ObjectID{parent.ID}}
+		digestPosts := composer.DigestPosts(subscriber, filed, []models.Category{parent, child})
+		require.Len(t, digestPosts, 1)
+		assert.Equal(t, filed[3].ID, digestPosts[0].ID)
+	})
+
+	t.Run("Negative: No followed posts", func(t *testing.T) {
+		assert.Empty(t, composer.DigestPosts(&models.Subscriber{Topics: []string{"rust"}}, posts, nil))
+	})
+
+	t.Run("Positive: Digest links the preferences page", func(t *testing.T) {
+		email, err := composer.Compose(&models.Subscriber{ID: primitive.NewObjectID()}, posts[:1])
+		require.NoError(t, err)
+		assert.Contains(t, email.Text, "https://news.example.com/subscribers/preferences?token=")
+	})
+}
+
+func TestComposer_Issue(t *testing.T) {
+	cfg := &config.Config{BaseURL: "https://news.example.com"}
+	posts := []models.Post{
+		{ID: primitive.NewObjectID(), Title: "First"},
+		{ID: primitive.NewObjectID(), Title: "Second"},
+	}
+	composer := NewComposer(cfg, &postsState{posts: posts}, NewLinks(cfg, tokens.NewSigner("secret")))
+	issue := &models.Issue{
+		ID:      primitive.NewObjectID(),
+		Name:    "Spring issue",
+		PostIDs: []primitive.ObjectID{posts[1].ID, primitive.NewObjectID(), posts[0].ID},
+	}
+
+	t.Run("Positive: Posts keep the issue order and skip deleted ones", func(t *testing.T) {
+		issuePosts, err := composer.IssuePosts(context.Background(), issue)
+		require.NoError(t, err)
+		require.Len(t, issuePosts, 2)
+		assert.Equal(t, "Second", issuePosts[0].Title)
+		assert.Equal(t, "First", issuePosts[1].Title)
+	})
+
+	t.Run("Positive: Issue for a subscriber links the archive", func(t *testing.T) {
+		email, err := composer.ComposeIssue(issue, posts, &models.Subscriber{ID: primitive.NewObjectID()})
+		require.NoError(t, err)
+		assert.Equal(t, "Spring issue", email.Subject)
+		assert.Contains(t, email.Text, "https://news.example.com/newsletter/"+issue.ID.Hex())
+		assert.Contains(t, email.Text, "https://news.example.com/subscribers/unsubscribe?token=")
+	})
+
+	t.Run("Positive: Test send", func(t *testing.T) {
+		email, err := composer.ComposeIssue(issue, posts, nil)
+		require.NoError(t, err)
+		assert.NotContains(t, email.Text, "/subscribers/unsubscribe")
+	})
+}
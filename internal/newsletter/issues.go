@@ -0,0 +1,159 @@
+package newsletter
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"newsteller/internal/config"
+	"newsteller/internal/mailer"
+	"newsteller/internal/models"
+	"newsteller/internal/repositories"
+	"newsteller/internal/tokens"
+	"time"
+)
+
+/**
+Issues are not sent at once. Every recipient gets an outbox message delayed by the send
+interval derived from the configured rate, so the outbox worker spreads the delivery over
+time and stays within the limits of the SMTP provider. Like digests, every message is
+enqueued with a key unique for the issue and subscriber, so an issue claimed again after
+a crash does not reach anybody twice.
+*/
+
+// issueLease - time a sending issue stays locked, it is claimed again afterwards
+const issueLease = 10 * time.Minute
+
+// ErrNoIssuePosts - every post of the issue was deleted after it was scheduled
+var ErrNoIssuePosts = errors.New("none of the issue posts exist anymore")
+
+// IssueSender enqueues scheduled issues that are due.
+type IssueSender struct {
+	cfg         *config.Config
+	issues      *repositories.Issue
+	subscribers *repositories.Subscriber
+	categories  *repositories.Category
+	composer    *Composer
+	links       *Links
+	mailer      *mailer.Mailer
+}
+
+func NewIssueSender(
+	cfg *config.Config,
+	issues *mongo.Collection,
+	subscribers *mongo.Collection,
+	posts *mongo.Collection,
+	categories *mongo.Collection,
+	signer *tokens.Signer,
+	mailer *mailer.Mailer,
+) *IssueSender {
+	links := NewLinks(cfg, signer)
+
+	return &IssueSender{
+		cfg:         cfg,
+		issues:      repositories.NewIssueRepository(issues),
+		subscribers: repositories.NewSubscriberRepository(subscribers),
+		categories:  repositories.NewCategoryRepository(categories),
+		composer:    NewComposer(cfg, repositories.NewPostRepository(posts), links),
+		links:       links,
+		mailer:      mailer,
+	}
+}
+
+// Run sends due issues every issue poll interval until the context is cancelled.
+func (s *IssueSender) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Newsletter.IssuePollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.SendDue(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendDue enqueues the issues scheduled before now.
+func (s *IssueSender) SendDue(ctx context.Context, now time.Time) {
+	for ctx.Err() == nil {
+		issue, err := s.issues.ClaimDue(ctx, now, issueLease)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return
+		}
+		if err != nil {
+			zap.L().Error("could not claim issue", zap.Error(err))
+			return
+		}
+
+		err = s.send(ctx, issue, now)
+		if err != nil {
+			zap.L().Error("could not send issue", zap.String("id", issue.ID.Hex()), zap.Error(err))
+		}
+	}
+}
+
+func (s *IssueSender) send(ctx context.Context, issue *models.Issue, now time.Time) error {
+	posts, err := s.composer.IssuePosts(ctx, issue)
+	if err != nil {
+		return err
+	}
+	if len(posts) == 0 {
+		// an empty issue must not reach anybody, editors add posts and schedule it again
+		return errors.Join(ErrNoIssuePosts, s.issues.MarkFailed(ctx, issue.ID, ErrNoIssuePosts.Error()))
+	}
+	subscribers, err := s.subscribers.FindBySegment(ctx, issue.Segment)
+	if err != nil {
+		return err
+	}
+	categories, err := s.categories.All(ctx)
+	if err != nil {
+		return err
+	}
+
+	interval := SendInterval(s.cfg.Newsletter.IssueRate)
+	recipients := 0
+	for i := range subscribers {
+		// subscribers following none of the issue topics and categories are skipped
+		followed := Followed(&subscribers[i], posts, categories)
+		if len(followed) == 0 {
+			continue
+		}
+
+		email, err := s.composer.ComposeIssue(issue, followed, &subscribers[i])
+		if err != nil {
+			return err
+		}
+
+		err = s.mailer.EnqueueOnceAt(
+			ctx,
+			"issue:"+issue.ID.Hex()+":"+subscribers[i].ID.Hex(),
+			&mailer.Message{
+				To:      subscribers[i].Email,
+				Subject: email.Subject,
+				HTML:    email.HTML,
+				Text:    email.Text,
+				Headers: s.links.UnsubscribeHeaders(&subscribers[i]),
+			},
+			now.Add(time.Duration(recipients)*interval),
+		)
+		if err != nil {
+			return err
+		}
+		recipients++
+	}
+
+	return s.issues.MarkSent(ctx, issue.ID, recipients, now)
+}
+
+// SendInterval returns the delay between two messages of an issue sent at the rate
+// of messages per minute. Zero rate disables throttling.
+func SendInterval(rate int) time.Duration {
+	if rate <= 0 {
+		return 0
+	}
+
+	return time.Minute / time.Duration(rate)
+}
@@ -0,0 +1,76 @@
+package newsletter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"newsteller/internal/config"
+	"newsteller/internal/mailer"
+	"newsteller/internal/models"
+	"newsteller/internal/repositories"
+	"newsteller/internal/tokens"
+)
+
+func TestSendInterval(t *testing.T) {
+	assert.Equal(t, time.Second, SendInterval(60))
+	assert.Equal(t, 500*time.Millisecond, SendInterval(120))
+	assert.Equal(t, time.Duration(0), SendInterval(0), "Zero rate should disable throttling")
+}
+
+func TestIssueSender_SendDue(t *testing.T) {
+	ctx := context.Background()
+	database := dbClient.Database("newsteller_test")
+	issues := database.Collection("issues_test")
+	subscribers := database.Collection("subscribers_test")
+	outbox := database.Collection("mail_outbox_test")
+	t.Cleanup(func() {
+		issues.DeleteMany(ctx, bson.M{})
+		subscribers.DeleteMany(ctx, bson.M{})
+		outbox.DeleteMany(ctx, bson.M{})
+	})
+
+	cfg := &config.Config{BaseURL: "http://localhost:3000"}
+	sender := NewIssueSender(
+		cfg,
+		issues,
+		subscribers,
+		database.Collection("posts_test"),
+		database.Collection("categories_test"),
+		tokens.NewSigner("secret"),
+		mailer.New(outbox),
+	)
+
+	t.Run("Negative: Issue of deleted posts is not sent", func(t *testing.T) {
+		_, err := repositories.NewSubscriberRepository(subscribers).Create(ctx, &models.Subscriber{
+			Email:  "reader@example.com",
+			Status: models.SubscriberActive,
+		})
+		require.NoError(t, err)
+		issueRepo := repositories.NewIssueRepository(issues)
+		now := time.Now()
+		id, err := issueRepo.Create(ctx, &models.Issue{
+			Name:        "Weekly",
+			PostIDs:     []primitive.ObjectID{primitive.NewObjectID()},
+			Status:      models.IssueScheduled,
+			ScheduledAt: now.Add(-time.Minute),
+		})
+		require.NoError(t, err)
+
+		sender.SendDue(ctx, now)
+
+		issue, err := issueRepo.FindByID(ctx, id.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, models.IssueFailed, issue.Status)
+		assert.Equal(t, ErrNoIssuePosts.Error(), issue.LastError)
+
+		count, err := outbox.CountDocuments(ctx, bson.M{})
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+}
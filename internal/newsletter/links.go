@@ -0,0 +1,59 @@
+package newsletter
+
+import (
+	"net/url"
+	"newsteller/internal/config"
+	"newsteller/internal/mailer"
+	"newsteller/internal/models"
+	"newsteller/internal/tokens"
+	"time"
+)
+
+// Links builds the signed links put into messages sent to subscribers.
+type Links struct {
+	cfg    *config.Config
+	signer *tokens.Signer
+}
+
+func NewLinks(cfg *config.Config, signer *tokens.Signer) *Links {
+	return &Links{
+		cfg:    cfg,
+		signer: signer,
+	}
+}
+
+// ConfirmURL returns the double opt-in link, valid for the configured confirmation TTL.
+func (l *Links) ConfirmURL(subscriber *models.Subscriber) string {
+	token := l.signer.Sign(
+		tokens.PurposeConfirmSubscription,
+		subscriber.ID.Hex(),
+		time.Now().Add(l.cfg.Newsletter.ConfirmationTTL),
+	)
+
+	return l.cfg.BaseURL + "/subscribers/confirm?token=" + url.QueryEscape(token)
+}
+
+// UnsubscribeURL returns the unsubscribe link, opening it asks to confirm and posting to it
+// unsubscribes in one click (RFC 8058). It never expires, so it keeps working in old newsletters.
+func (l *Links) UnsubscribeURL(subscriber *models.Subscriber) string {
+	token := l.signer.Sign(tokens.PurposeUnsubscribe, subscriber.ID.Hex(), time.Time{})
+
+	return l.cfg.BaseURL + "/subscribers/unsubscribe?token=" + url.QueryEscape(token)
+}
+
+// PreferencesURL returns the link of the subscriber preferences page. It is signed
+// like the unsubscribe link, which it is reachable from.
+func (l *Links) PreferencesURL(subscriber *models.Subscriber) string {
+	token := l.signer.Sign(tokens.PurposeUnsubscribe, subscriber.ID.Hex(), time.Time{})
+
+	return l.cfg.BaseURL + "/subscribers/preferences?token=" + url.QueryEscape(token)
+}
+
+// UnsubscribeHeaders returns the List-Unsubscribe headers letting mail clients
+// show their own unsubscribe button (RFC 2369 and RFC 8058).
+func (l *Links) UnsubscribeHeaders(subscriber *models.Subscriber) map[string]string {
+	return map[string]string{
+		mailer.HeaderListUnsubscribe: "<" + l.UnsubscribeURL(subscriber) + ">",
+		"List-Unsubscribe-Post":      "List-Unsubscribe=One-Click",
+	}
+}
@@ -0,0 +1,65 @@
+package newsletter
+
+import (
+	"net/url"
+	"newsteller/internal/config"
+	"newsteller/internal/models"
+	"newsteller/internal/tokens"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func tokenFrom(t *testing.T, link string) string {
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	return parsed.Query().Get("token")
+}
+
+func TestLinks(t *testing.T) {
+	cfg := &config.Config{BaseURL: "https://news.example.com"}
+	cfg.Newsletter.ConfirmationTTL = time.Hour
+	signer := tokens.NewSigner("secret")
+	links := NewLinks(cfg, signer)
+	subscriber := &models.Subscriber{ID: primitive.NewObjectID()}
+
+	t.Run("Positive: Confirmation link", func(t *testing.T) {
+		link := links.ConfirmURL(subscriber)
+		assert.True(t, strings.HasPrefix(link, "https://news.example.com/subscribers/confirm?token="))
+
+		id, err := signer.Verify(tokens.PurposeConfirmSubscription, tokenFrom(t, link))
+		require.NoError(t, err)
+		assert.Equal(t, subscriber.ID.Hex(), id)
+	})
+
+	t.Run("Positive: Unsubscribe link", func(t *testing.T) {
+		link := links.UnsubscribeURL(subscriber)
+		assert.True(t, strings.HasPrefix(link, "https://news.example.com/subscribers/unsubscribe?token="))
+
+		id, err := signer.Verify(tokens.PurposeUnsubscribe, tokenFrom(t, link))
+		require.NoError(t, err)
+		assert.Equal(t, subscriber.ID.Hex(), id)
+
+		_, err = signer.Verify(tokens.PurposeConfirmSubscription, tokenFrom(t, link))
+		assert.ErrorIs(t, err, tokens.ErrInvalidToken, "Unsubscribe token should not confirm subscriptions")
+	})
+
+	t.Run("Positive: Preferences link", func(t *testing.T) {
+		link := links.PreferencesURL(subscriber)
+		assert.True(t, strings.HasPrefix(link, "https://news.example.com/subscribers/preferences?token="))
+
+		id, err := signer.Verify(tokens.PurposeUnsubscribe, tokenFrom(t, link))
+		require.NoError(t, err)
+		assert.Equal(t, subscriber.ID.Hex(), id)
+	})
+
+	t.Run("Positive: Unsubscribe headers", func(t *testing.T) {
+		headers := links.UnsubscribeHeaders(subscriber)
+		assert.Equal(t, "<"+links.UnsubscribeURL(subscriber)+">", headers["List-Unsubscribe"])
+		assert.Equal(t, "List-Unsubscribe=One-Click", headers["List-Unsubscribe-Post"])
+	})
+}
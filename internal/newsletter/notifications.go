@@ -0,0 +1,69 @@
+package newsletter
+
+import (
+	"context"
+	"errors"
+	"newsteller/internal/config"
+	"newsteller/internal/events"
+	"newsteller/internal/mailer"
+	"newsteller/internal/models"
+	"newsteller/internal/templates"
+	"strings"
+)
+
+// PostNotifier emails the addresses configured in Mail.Notify about published and deleted posts.
+type PostNotifier struct {
+	cfg        *config.Config
+	mailer     *mailer.Mailer
+	recipients []string
+}
+
+func NewPostNotifier(cfg *config.Config, mailer *mailer.Mailer) *PostNotifier {
+	var recipients []string
+	for _, address := range strings.Split(cfg.Mail.Notify, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			recipients = append(recipients, address)
+		}
+	}
+
+	return &PostNotifier{cfg: cfg, mailer: mailer, recipients: recipients}
+}
+
+// Subscribe enqueues the notifications of post events, nothing is subscribed
+// when no addresses are configured.
+func (n *PostNotifier) Subscribe(bus *events.Bus) {
+	if len(n.recipients) == 0 {
+		return
+	}
+
+	events.Subscribe(bus, "mail", func(ctx context.Context, event events.PostPublished) error {
+		return n.notify(ctx, event.Meta(), "published", &event.Post, n.cfg.BaseURL+event.Post.Path())
+	})
+	events.Subscribe(bus, "mail", func(ctx context.Context, event events.PostDeleted) error {
+		return n.notify(ctx, event.Meta(), "deleted", &event.Post, "")
+	})
+}
+
+// notify enqueues the email once per event and recipient, so an event published
+// again reaches nobody twice.
+func (n *PostNotifier) notify(ctx context.Context, meta events.Metadata, action string, post *models.Post, url string) error {
+	email, err := templates.NewPostNotificationEmail(action, post, url, meta.OccurredAt).GenerateEmail()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, to := range n.recipients {
+		err = n.mailer.EnqueueOnce(ctx, "post-event:"+meta.ID+":"+to, &mailer.Message{
+			To:      to,
+			Subject: email.Subject,
+			HTML:    email.HTML,
+			Text:    email.Text,
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
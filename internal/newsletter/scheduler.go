@@ -0,0 +1,170 @@
+package newsletter
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"newsteller/internal/config"
+	"newsteller/internal/mailer"
+	"newsteller/internal/models"
+	"newsteller/internal/repositories"
+	"newsteller/internal/tokens"
+	"time"
+)
+
+/**
+Digests are sent at most once per subscriber and period. Before composing, the scheduler
+checks the send record of the period. The digest is enqueued with a key unique for the
+subscriber and period, so when the process crashes after enqueueing but before writing the
+send record, the next run composes the digest again but the outbox ignores the duplicate.
+*/
+
+// Scheduler enqueues the digests that are due.
+type Scheduler struct {
+	cfg         *config.Config
+	subscribers *repositories.Subscriber
+	categories  *repositories.Category
+	sends       *repositories.DigestSend
+	composer    *Composer
+	links       *Links
+	mailer      *mailer.Mailer
+}
+
+func NewScheduler(
+	cfg *config.Config,
+	subscribers *mongo.Collection,
+	sends *mongo.Collection,
+	posts *mongo.Collection,
+	categories *mongo.Collection,
+	signer *tokens.Signer,
+	mailer *mailer.Mailer,
+) *Scheduler {
+	links := NewLinks(cfg, signer)
+
+	return &Scheduler{
+		cfg:         cfg,
+		subscribers: repositories.NewSubscriberRepository(subscribers),
+		categories:  repositories.NewCategoryRepository(categories),
+		sends:       repositories.NewDigestSendRepository(sends),
+		composer:    NewComposer(cfg, repositories.NewPostRepository(posts), links),
+		links:       links,
+		mailer:      mailer,
+	}
+}
+
+// Run enqueues due digests every digest interval until the context is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Newsletter.DigestInterval)
+	defer ticker.Stop()
+
+	for {
+		s.SendDue(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendDue enqueues the digests due at now.
+func (s *Scheduler) SendDue(ctx context.Context, now time.Time) {
+	subscribers, err := s.subscribers.FindDigestRecipients(ctx)
+	if err != nil {
+		zap.L().Error("could not find digest recipients", zap.Error(err))
+		return
+	}
+	categories, err := s.categories.All(ctx)
+	if err != nil {
+		zap.L().Error("could not find categories", zap.Error(err))
+		return
+	}
+
+	for i := range subscribers {
+		if ctx.Err() != nil {
+			return
+		}
+
+		period, ok := DuePeriod(&subscribers[i], now)
+		if !ok {
+			continue
+		}
+		err = s.send(ctx, &subscribers[i], categories, period, now)
+		if err != nil {
+			zap.L().Error(
+				"could not send digest",
+				zap.String("subscriber", subscribers[i].ID.Hex()),
+				zap.String("period", period),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+func (s *Scheduler) send(
+	ctx context.Context,
+	subscriber *models.Subscriber,
+	categories []models.Category,
+	period string,
+	now time.Time,
+) error {
+	sent, err := s.sends.Exists(ctx, subscriber.ID, period)
+	if err != nil || sent {
+		return err
+	}
+
+	since := subscriber.LastDigestAt
+	if since.IsZero() {
+		since = subscriber.ConfirmedAt
+	}
+	if since.IsZero() {
+		since = now.Add(-PeriodLength(subscriber.Frequency()))
+	}
+
+	posts, err := s.composer.Posts(ctx, since, now)
+	if err != nil {
+		return err
+	}
+	posts = s.composer.DigestPosts(subscriber, posts, categories)
+
+	record := &models.DigestSend{
+		SubscriberID: subscriber.ID,
+		Period:       period,
+		Frequency:    subscriber.Frequency(),
+		Status:       models.DigestSendSkipped,
+		CreatedAt:    now,
+	}
+	if len(posts) == 0 {
+		return s.sends.Record(ctx, record)
+	}
+
+	email, err := s.composer.Compose(subscriber, posts)
+	if err != nil {
+		return err
+	}
+
+	err = s.mailer.EnqueueOnce(ctx, "digest:"+subscriber.ID.Hex()+":"+period, &mailer.Message{
+		To:      subscriber.Email,
+		Subject: email.Subject,
+		HTML:    email.HTML,
+		Text:    email.Text,
+		Headers: s.links.UnsubscribeHeaders(subscriber),
+	})
+	if err != nil {
+		return err
+	}
+
+	record.Status = models.DigestSendEnqueued
+	record.PostIDs = make([]primitive.ObjectID, len(posts))
+	for i := range posts {
+		record.PostIDs[i] = posts[i].ID
+	}
+	err = s.sends.Record(ctx, record)
+	if err != nil {
+		return err
+	}
+
+	return s.subscribers.SetLastDigestAt(ctx, subscriber.ID, now)
+}
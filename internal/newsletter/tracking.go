@@ -0,0 +1,93 @@
+package newsletter
+
+import (
+	"errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"net/url"
+	"newsteller/internal/models"
+	"newsteller/internal/tokens"
+	"strings"
+	"time"
+)
+
+/**
+Opens are tracked with a pixel image and clicks with redirects through the service. Both
+links carry a signed token naming the email, so they can not be forged to count fake
+events, and click tokens include the target URL, so the redirect can not be abused
+to send people to arbitrary sites.
+*/
+
+var ErrInvalidTrackingToken = errors.New("invalid tracking token")
+
+// TrackingRef identifies the email a tracking link belongs to.
+type TrackingRef struct {
+	Source       models.TrackingSource
+	IssueID      primitive.ObjectID
+	SubscriberID primitive.ObjectID
+	// PostID - the linked post of click links
+	PostID primitive.ObjectID
+}
+
+// OpenURL returns the link of the open pixel of the email.
+func (l *Links) OpenURL(ref TrackingRef) string {
+	token := l.signer.Sign(tokens.PurposeTrackOpen, ref.encode(""), time.Time{})
+
+	return l.cfg.BaseURL + "/track/open?token=" + url.QueryEscape(token)
+}
+
+// ClickURL returns a link redirecting to the target and counting the click.
+func (l *Links) ClickURL(ref TrackingRef, target string) string {
+	token := l.signer.Sign(tokens.PurposeTrackClick, ref.encode(target), time.Time{})
+
+	return l.cfg.BaseURL + "/track/click?token=" + url.QueryEscape(token)
+}
+
+// ParseOpenToken verifies the token of an open pixel link.
+func (l *Links) ParseOpenToken(token string) (TrackingRef, error) {
+	subject, err := l.signer.Verify(tokens.PurposeTrackOpen, token)
+	if err != nil {
+		return TrackingRef{}, err
+	}
+
+	ref, _, err := decodeTrackingRef(subject)
+	return ref, err
+}
+
+// ParseClickToken verifies the token of a click link and returns the target URL.
+func (l *Links) ParseClickToken(token string) (TrackingRef, string, error) {
+	subject, err := l.signer.Verify(tokens.PurposeTrackClick, token)
+	if err != nil {
+		return TrackingRef{}, "", err
+	}
+
+	return decodeTrackingRef(subject)
+}
+
+func (r TrackingRef) encode(target string) string {
+	return strings.Join([]string{
+		string(r.Source),
+		r.IssueID.Hex(),
+		r.SubscriberID.Hex(),
+		r.PostID.Hex(),
+		target,
+	}, " ")
+}
+
+// decodeTrackingRef parses an encoded reference, the target is last as it may contain anything.
+func decodeTrackingRef(subject string) (TrackingRef, string, error) {
+	parts := strings.SplitN(subject, " ", 5)
+	if len(parts) != 5 {
+		return TrackingRef{}, "", ErrInvalidTrackingToken
+	}
+
+	ref := TrackingRef{Source: models.TrackingSource(parts[0])}
+	var err error
+	for i, id := range []*primitive.ObjectID{&ref.IssueID, &ref.SubscriberID, &ref.PostID} {
+		*id, err = primitive.ObjectIDFromHex(parts[i+1])
+		if err != nil {
+			return TrackingRef{}, "", ErrInvalidTrackingToken
+		}
+	}
+
+	return ref, parts[4], nil
+}
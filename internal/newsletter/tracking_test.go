@@ -0,0 +1,105 @@
+package newsletter
+
+import (
+	"newsteller/internal/config"
+	"newsteller/internal/models"
+	"newsteller/internal/tokens"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestLinks_Tracking(t *testing.T) {
+	cfg := &config.Config{BaseURL: "https://news.example.com"}
+	links := NewLinks(cfg, tokens.NewSigner("secret"))
+	ref := TrackingRef{
+		Source:       models.TrackingSourceIssue,
+		IssueID:      primitive.NewObjectID(),
+		SubscriberID: primitive.NewObjectID(),
+		PostID:       primitive.NewObjectID(),
+	}
+	target := "https://news.example.com/posts/1?utm source=a|b"
+
+	t.Run("Positive: Open link", func(t *testing.T) {
+		link := links.OpenURL(ref)
+		assert.True(t, strings.HasPrefix(link, "https://news.example.com/track/open?token="))
+
+		parsed, err := links.ParseOpenToken(tokenFrom(t, link))
+		require.NoError(t, err)
+		assert.Equal(t, ref, parsed)
+	})
+
+	t.Run("Positive: Click link carries the target", func(t *testing.T) {
+		link := links.ClickURL(ref, target)
+		assert.True(t, strings.HasPrefix(link, "https://news.example.com/track/click?token="))
+
+		parsed, parsedTarget, err := links.ParseClickToken(tokenFrom(t, link))
+		require.NoError(t, err)
+		assert.Equal(t, ref, parsed)
+		assert.Equal(t, target, parsedTarget)
+	})
+
+	t.Run("Negative: Open token is not a click token", func(t *testing.T) {
+		_, _, err := links.ParseClickToken(tokenFrom(t, links.OpenURL(ref)))
+		assert.ErrorIs(t, err, tokens.ErrInvalidToken)
+	})
+
+	t.Run("Negative: Token signed with another secret", func(t *testing.T) {
+		forged := NewLinks(cfg, tokens.NewSigner("other")).ClickURL(ref, "https://evil.example.com")
+		_, _, err := links.ParseClickToken(tokenFrom(t, forged))
+		assert.ErrorIs(t, err, tokens.ErrInvalidToken)
+	})
+}
+
+func TestComposer_Tracking(t *testing.T) {
+	cfg := &config.Config{BaseURL: "https://news.example.com"}
+	cfg.Newsletter.TrackOpens = true
+	cfg.Newsletter.TrackClicks = true
+	posts := []models.Post{{ID: primitive.NewObjectID(), Title: "Tracked"}}
+	composer := NewComposer(cfg, &postsState{posts: posts}, NewLinks(cfg, tokens.NewSigner("secret")))
+	issue := &models.Issue{ID: primitive.NewObjectID(), Name: "Spring issue"}
+
+	t.Run("Positive: Issue links and pixel are tracked", func(t *testing.T) {
+		email, err := composer.ComposeIssue(issue, posts, &models.Subscriber{ID: primitive.NewObjectID()})
+		require.NoError(t, err)
+		assert.Contains(t, email.Text, "Read more: https://news.example.com/track/click?token=")
+		assert.NotContains(t, email.Text, "https://news.example.com/posts/"+posts[0].ID.Hex())
+		assert.Contains(t, email.HTML, `<img src="https://news.example.com/track/open?token=`)
+	})
+
+	t.Run("Positive: Digest links are tracked", func(t *testing.T) {
+		email, err := composer.Compose(&models.Subscriber{ID: primitive.NewObjectID()}, posts)
+		require.NoError(t, err)
+		assert.Contains(t, email.Text, "Read more: https://news.example.com/track/click?token=")
+		assert.Contains(t, email.HTML, "/track/open?token=")
+	})
+
+	t.Run("Negative: Subscriber opted out", func(t *testing.T) {
+		email, err := composer.ComposeIssue(issue, posts, &models.Subscriber{ID: primitive.NewObjectID(), TrackingOptOut: true})
+		require.NoError(t, err)
+		assert.Contains(t, email.Text, "Read more: https://news.example.com/posts/"+posts[0].ID.Hex())
+		assert.NotContains(t, email.HTML, "/track/")
+	})
+
+	t.Run("Negative: Test sends and previews are not tracked", func(t *testing.T) {
+		email, err := composer.ComposeIssue(issue, posts, nil)
+		require.NoError(t, err)
+		assert.NotContains(t, email.HTML, "/track/")
+
+		email, err = composer.Compose(&models.Subscriber{ID: primitive.NilObjectID}, posts)
+		require.NoError(t, err)
+		assert.NotContains(t, email.HTML, "/track/")
+	})
+
+	t.Run("Negative: Tracking disabled", func(t *testing.T) {
+		cfg := &config.Config{BaseURL: "https://news.example.com"}
+		composer := NewComposer(cfg, &postsState{posts: posts}, NewLinks(cfg, tokens.NewSigner("secret")))
+
+		email, err := composer.ComposeIssue(issue, posts, &models.Subscriber{ID: primitive.NewObjectID()})
+		require.NoError(t, err)
+		assert.NotContains(t, email.HTML, "/track/")
+	})
+}
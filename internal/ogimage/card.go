@@ -0,0 +1,140 @@
+package ogimage
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"newsteller/internal/slugs"
+	"strings"
+)
+
+// Width and Height - size of link previews recommended by OpenGraph consumers
+const (
+	Width  = 1200
+	Height = 630
+)
+
+const (
+	padding    = 80
+	accentBar  = 24
+	titleTop   = 160
+	titleLines = 450 - titleTop
+	footerTop  = 530
+	// lineSpacing - font pixels between title lines
+	lineSpacing = 2
+)
+
+var (
+	background = color.RGBA{R: 0x1f, G: 0x29, B: 0x37, A: 0xff}
+	accent     = color.RGBA{R: 0x00, G: 0x7b, B: 0xff, A: 0xff}
+	siteColor  = color.RGBA{R: 0x93, G: 0xc5, B: 0xfd, A: 0xff}
+	titleColor = color.White
+	metaColor  = color.RGBA{R: 0xd1, G: 0xd5, B: 0xdb, A: 0xff}
+)
+
+// titleScales - title sizes tried from the largest until the title fits
+var titleScales = []int{10, 8, 7, 6, 5}
+
+// Card is the text of a post preview.
+type Card struct {
+	Title    string
+	SiteName string
+	// Byline - date and author line under the title
+	Byline string
+}
+
+// Render draws the card.
+func Render(card Card) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(0, 0, accentBar, Height), image.NewUniform(accent), image.Point{}, draw.Src)
+
+	width := Width - 2*padding
+	drawText(img, fit(card.SiteName, width, 4), image.Pt(padding, padding), 4, siteColor)
+
+	lines, scale := wrapTitle(slugs.Transliterate(card.Title), width)
+	for i, line := range lines {
+		y := titleTop + i*(glyphHeight+lineSpacing)*scale
+		drawText(img, line, image.Pt(padding, y), scale, titleColor)
+	}
+
+	drawText(img, fit(card.Byline, width, 3), image.Pt(padding, footerTop), 3, metaColor)
+
+	return img
+}
+
+// Encode renders the card as PNG.
+func Encode(card Card) ([]byte, error) {
+	var buf bytes.Buffer
+	err := png.Encode(&buf, Render(card))
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// wrapTitle breaks the title into lines at the largest scale fitting the title area,
+// titles too long even for the smallest scale are cut.
+func wrapTitle(title string, width int) ([]string, int) {
+	for _, scale := range titleScales {
+		maxLines := titleLines / ((glyphHeight + lineSpacing) * scale)
+		lines := wrap(title, (width/scale+1)/cellWidth)
+		if len(lines) <= maxLines {
+			return lines, scale
+		}
+	}
+
+	scale := titleScales[len(titleScales)-1]
+	maxLines := titleLines / ((glyphHeight + lineSpacing) * scale)
+	lines := wrap(title, (width/scale+1)/cellWidth)[:maxLines]
+	lines[maxLines-1] = fit(lines[maxLines-1]+"...", width, scale)
+
+	return lines, scale
+}
+
+// wrap breaks the text into lines of at most the given characters at spaces,
+// splitting words longer than a line.
+func wrap(text string, maxChars int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		for len(word) > maxChars {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			lines = append(lines, word[:maxChars])
+			word = word[maxChars:]
+		}
+
+		switch {
+		case line == "":
+			line = word
+		case len(line)+1+len(word) <= maxChars:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// fit cuts the text ending with "..." when it is wider than the width at the scale.
+func fit(text string, width, scale int) string {
+	text = slugs.Transliterate(text)
+	if textWidth(text, scale) <= width {
+		return text
+	}
+
+	maxChars := (width/scale+1)/cellWidth - len("...")
+
+	return strings.TrimRight(text[:maxChars], " .") + "..."
+}
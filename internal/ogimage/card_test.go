@@ -0,0 +1,58 @@
+package ogimage
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	data, err := Encode(Card{Title: "Привет, мир", SiteName: "Newsteller", Byline: "October 1, 2026 | By Jane Doe"})
+	require.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, Width, img.Bounds().Dx())
+	assert.Equal(t, Height, img.Bounds().Dy())
+	assert.Equal(t, color.RGBAModel.Convert(titleColor), img.At(padding, titleTop), "the title starts at the top left of its area")
+	assert.Equal(t, accent, img.At(0, 0))
+}
+
+func TestWrapTitle(t *testing.T) {
+	t.Run("Positive: Short titles are drawn large", func(t *testing.T) {
+		lines, scale := wrapTitle("Hello World", Width-2*padding)
+		assert.Equal(t, []string{"Hello World"}, lines)
+		assert.Equal(t, titleScales[0], scale)
+	})
+
+	t.Run("Positive: Longer titles are wrapped smaller", func(t *testing.T) {
+		lines, scale := wrapTitle(strings.Repeat("word ", 20), Width-2*padding)
+		assert.Less(t, scale, titleScales[0])
+		for _, line := range lines {
+			assert.LessOrEqual(t, textWidth(line, scale), Width-2*padding)
+		}
+	})
+
+	t.Run("Negative: Titles too long are cut", func(t *testing.T) {
+		lines, scale := wrapTitle(strings.Repeat("word ", 100), Width-2*padding)
+		assert.Equal(t, titleScales[len(titleScales)-1], scale)
+		assert.True(t, strings.HasSuffix(lines[len(lines)-1], "..."))
+		assert.LessOrEqual(t, (len(lines)*(glyphHeight+lineSpacing)-lineSpacing)*scale, titleLines)
+	})
+}
+
+func TestWrap(t *testing.T) {
+	assert.Equal(t, []string{"one two", "three"}, wrap("one two three", 7))
+	assert.Equal(t, []string{"a", "abcde", "fgh b"}, wrap("a abcdefgh b", 5))
+	assert.Empty(t, wrap("  ", 5))
+}
+
+func TestFit(t *testing.T) {
+	assert.Equal(t, "Newsteller", fit("Newsteller", 1000, 4))
+	assert.Equal(t, "Newst...", fit("Newsteller", textWidth("Newst...", 1), 1))
+}
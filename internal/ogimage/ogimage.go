@@ -0,0 +1,92 @@
+package ogimage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"go.uber.org/zap"
+	"newsteller/internal/config"
+	"newsteller/internal/events"
+	"newsteller/internal/models"
+)
+
+/**
+Link preview cards of posts without their own image. Cards are rendered once per
+version of their text and cached, changing the title changes the version, so the
+card is rendered again and the URL referenced by the OpenGraph tags changes too.
+*/
+
+// layoutVersion - part of every card version, change it when the layout changes
+const layoutVersion = "1"
+
+// NewCard returns the card of the post.
+func NewCard(cfg *config.Config, post *models.Post) Card {
+	byline := post.CreatedAt.Format("January 2, 2006")
+	if post.Author != "" {
+		byline += " | By " + post.Author
+	}
+
+	return Card{Title: post.Title, SiteName: cfg.SiteName, Byline: byline}
+}
+
+// Version identifies the rendered image of the card.
+func (c Card) Version() string {
+	hash := sha256.New()
+	for _, part := range []string{layoutVersion, c.Title, c.SiteName, c.Byline} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))[:12]
+}
+
+// URL returns the URL of the card of the post, versioned so link previews are
+// fetched again when the card changes.
+func URL(cfg *config.Config, post *models.Post) string {
+	return cfg.BaseURL + "/posts/" + post.ID.Hex() + "/og.png?v=" + NewCard(cfg, post).Version()
+}
+
+// Generator renders the cards of posts through the store.
+type Generator struct {
+	cfg   *config.Config
+	store Store
+}
+
+func NewGenerator(cfg *config.Config, store Store) *Generator {
+	return &Generator{cfg: cfg, store: store}
+}
+
+// Subscribe removes the cached cards of deleted posts.
+func (g *Generator) Subscribe(bus *events.Bus) {
+	events.Subscribe(bus, "ogimages", func(ctx context.Context, event events.PostDeleted) error {
+		return g.store.Delete(ctx, event.Post.ID.Hex())
+	})
+}
+
+// Image returns the PNG card of the post and its version, rendering it when not cached.
+func (g *Generator) Image(ctx context.Context, post *models.Post) ([]byte, string, error) {
+	card := NewCard(g.cfg, post)
+	version := card.Version()
+	id := post.ID.Hex()
+
+	data, err := g.store.Get(ctx, id, version)
+	if err == nil {
+		return data, version, nil
+	}
+	if !errors.Is(err, ErrNotCached) {
+		// the cache is an optimization, a failing one must not break previews
+		zap.L().Error("could not read cached social image", zap.String("id", id), zap.Error(err))
+	}
+
+	data, err = Encode(card)
+	if err != nil {
+		return nil, "", err
+	}
+	err = g.store.Put(ctx, id, version, data)
+	if err != nil {
+		zap.L().Error("could not cache social image", zap.String("id", id), zap.Error(err))
+	}
+
+	return data, version, nil
+}
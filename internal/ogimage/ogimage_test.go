@@ -0,0 +1,68 @@
+package ogimage
+
+import (
+	"context"
+	"newsteller/internal/config"
+	"newsteller/internal/models"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func testPost() *models.Post {
+	return &models.Post{
+		ID:        primitive.NewObjectID(),
+		Title:     "Hello World",
+		Author:    "Jane Doe",
+		CreatedAt: time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestNewCard(t *testing.T) {
+	cfg := &config.Config{SiteName: "Newsteller", BaseURL: "https://news.example.com"}
+	post := testPost()
+
+	card := NewCard(cfg, post)
+	assert.Equal(t, Card{Title: "Hello World", SiteName: "Newsteller", Byline: "October 1, 2026 | By Jane Doe"}, card)
+
+	t.Run("Positive: Version follows the title", func(t *testing.T) {
+		renamed := *post
+		renamed.Title = "Hello Again"
+		assert.Equal(t, card.Version(), NewCard(cfg, post).Version())
+		assert.NotEqual(t, card.Version(), NewCard(cfg, &renamed).Version())
+	})
+
+	t.Run("Positive: Versioned URL", func(t *testing.T) {
+		url := URL(cfg, post)
+		assert.Equal(t, "https://news.example.com/posts/"+post.ID.Hex()+"/og.png?v="+card.Version(), url)
+	})
+}
+
+func TestGenerator_Image(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{SiteName: "Newsteller"}
+	directory := t.TempDir()
+	generator := NewGenerator(cfg, NewDiskStore(directory))
+	post := testPost()
+
+	data, version, err := generator.Image(ctx, post)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "\x89PNG"))
+	assert.Equal(t, NewCard(cfg, post).Version(), version)
+	assert.FileExists(t, filepath.Join(directory, post.ID.Hex()+"-"+version+".png"))
+
+	cached, _, err := generator.Image(ctx, post)
+	require.NoError(t, err)
+	assert.Equal(t, data, cached)
+
+	post.Title = "Renamed"
+	_, renamedVersion, err := generator.Image(ctx, post)
+	require.NoError(t, err)
+	assert.NotEqual(t, version, renamedVersion)
+	assert.NoFileExists(t, filepath.Join(directory, post.ID.Hex()+"-"+version+".png"), "stale cards are removed")
+}
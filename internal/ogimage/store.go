@@ -0,0 +1,195 @@
+package ogimage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"newsteller/internal/config"
+	"newsteller/internal/files"
+	"os"
+	"path/filepath"
+)
+
+const (
+	StoreDisk   = "disk"
+	StoreGridFS = "gridfs"
+)
+
+// ErrNotCached is returned by stores not having the version of the image.
+var ErrNotCached = errors.New("image not cached")
+
+// Store caches the rendered images of posts, only the latest version of each.
+type Store interface {
+	Get(ctx context.Context, id, version string) ([]byte, error)
+	// Put stores the version of the image replacing the others
+	Put(ctx context.Context, id, version string, data []byte) error
+	// Delete removes every version of the image
+	Delete(ctx context.Context, id string) error
+}
+
+// NewStore creates the store selected in the configuration.
+func NewStore(cfg *config.Config, db *mongo.Database) (Store, error) {
+	switch cfg.OGImages.Store {
+	case StoreDisk:
+		return NewDiskStore(cfg.OGImages.Directory), nil
+	case StoreGridFS:
+		return NewGridFSStore(db)
+	default:
+		return nil, fmt.Errorf("unknown social image store: %q", cfg.OGImages.Store)
+	}
+}
+
+// DiskStore keeps the images as "<id>-<version>.png" files of a directory.
+type DiskStore struct {
+	directory string
+}
+
+func NewDiskStore(directory string) *DiskStore {
+	return &DiskStore{directory: directory}
+}
+
+func (d *DiskStore) Get(_ context.Context, id, version string) ([]byte, error) {
+	data, err := os.ReadFile(d.path(id, version))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotCached
+	}
+
+	return data, err
+}
+
+func (d *DiskStore) Put(_ context.Context, id, version string, data []byte) error {
+	err := files.WriteAtomic(d.path(id, version), data)
+	if err != nil {
+		return err
+	}
+
+	stale, err := filepath.Glob(filepath.Join(d.directory, id+"-*.png"))
+	if err != nil {
+		return err
+	}
+	for _, path := range stale {
+		if path != d.path(id, version) {
+			_ = os.Remove(path)
+		}
+	}
+
+	return nil
+}
+
+func (d *DiskStore) Delete(_ context.Context, id string) error {
+	paths, err := filepath.Glob(filepath.Join(d.directory, id+"-*.png"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		err = os.Remove(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *DiskStore) path(id, version string) string {
+	return filepath.Join(d.directory, id+"-"+version+".png")
+}
+
+// GridFSStore keeps the images as "<id>.png" files of the og_images bucket,
+// the version in their metadata.
+type GridFSStore struct {
+	bucket *gridfs.Bucket
+}
+
+func NewGridFSStore(db *mongo.Database) (*GridFSStore, error) {
+	bucket, err := gridfs.NewBucket(db, options.GridFSBucket().SetName("og_images"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &GridFSStore{bucket: bucket}, nil
+}
+
+func (g *GridFSStore) Get(ctx context.Context, id, version string) ([]byte, error) {
+	files, err := g.find(ctx, bson.M{"filename": id + ".png", "metadata.version": version})
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, ErrNotCached
+	}
+
+	var buf bytes.Buffer
+	_, err = g.bucket.DownloadToStream(files[0].ID, &buf)
+	if errors.Is(err, gridfs.ErrFileNotFound) {
+		return nil, ErrNotCached
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (g *GridFSStore) Put(ctx context.Context, id, version string, data []byte) error {
+	name := id + ".png"
+	uploadOptions := options.GridFSUpload().SetMetadata(bson.M{"version": version})
+	fileID, err := g.bucket.UploadFromStream(name, bytes.NewReader(data), uploadOptions)
+	if err != nil {
+		return err
+	}
+
+	stale, err := g.find(ctx, bson.M{"filename": name, "_id": bson.M{"$ne": fileID}})
+	if err != nil {
+		return err
+	}
+	for _, file := range stale {
+		err = g.bucket.DeleteContext(ctx, file.ID)
+		if err != nil && !errors.Is(err, gridfs.ErrFileNotFound) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (g *GridFSStore) Delete(ctx context.Context, id string) error {
+	files, err := g.find(ctx, bson.M{"filename": id + ".png"})
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		err = g.bucket.DeleteContext(ctx, file.ID)
+		if err != nil && !errors.Is(err, gridfs.ErrFileNotFound) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type gridFSFile struct {
+	ID primitive.ObjectID `bson:"_id"`
+}
+
+func (g *GridFSStore) find(ctx context.Context, filter bson.M) ([]gridFSFile, error) {
+	cursor, err := g.bucket.FindContext(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var files []gridFSFile
+	err = cursor.All(ctx, &files)
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
@@ -0,0 +1,56 @@
+package ogimage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskStore(t *testing.T) {
+	ctx := context.Background()
+	directory := filepath.Join(t.TempDir(), "og")
+	store := NewDiskStore(directory)
+
+	t.Run("Negative: Not cached", func(t *testing.T) {
+		_, err := store.Get(ctx, "post", "v1")
+		assert.ErrorIs(t, err, ErrNotCached)
+	})
+
+	t.Run("Positive: Put replaces other versions", func(t *testing.T) {
+		require.NoError(t, store.Put(ctx, "post", "v1", []byte("first")))
+		require.NoError(t, store.Put(ctx, "other", "v1", []byte("other")))
+		require.NoError(t, store.Put(ctx, "post", "v2", []byte("second")))
+
+		data, err := store.Get(ctx, "post", "v2")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("second"), data)
+
+		_, err = store.Get(ctx, "post", "v1")
+		assert.ErrorIs(t, err, ErrNotCached)
+
+		data, err = store.Get(ctx, "other", "v1")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("other"), data)
+
+		entries, err := os.ReadDir(directory)
+		require.NoError(t, err)
+		assert.Len(t, entries, 2, "no temporary files are left")
+	})
+
+	t.Run("Positive: Delete removes the images of the post", func(t *testing.T) {
+		require.NoError(t, store.Delete(ctx, "post"))
+
+		_, err := store.Get(ctx, "post", "v2")
+		assert.ErrorIs(t, err, ErrNotCached)
+
+		data, err := store.Get(ctx, "other", "v1")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("other"), data)
+
+		require.NoError(t, store.Delete(ctx, "missing"))
+	})
+}
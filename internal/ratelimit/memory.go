@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps the counters in memory, each replica counts its own requests.
+type MemoryStore struct {
+	mu       sync.Mutex
+	counters map[string]*counter
+	pruned   time.Time
+}
+
+type counter struct {
+	start    time.Time
+	window   time.Duration
+	current  int64
+	previous int64
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{counters: make(map[string]*counter)}
+}
+
+func (m *MemoryStore) Take(_ context.Context, key string, limit int, window time.Duration, start time.Time, weight float64) (Counts, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// the weight is the part of the window left, rules of any window share the store
+	now := start.Add(time.Duration((1 - weight) * float64(window)))
+	if now.Sub(m.pruned) >= time.Minute {
+		// counters of clients gone for two of their own windows count nothing
+		for k, c := range m.counters {
+			if now.Sub(c.start) >= 2*c.window {
+				delete(m.counters, k)
+			}
+		}
+		m.pruned = now
+	}
+
+	c, ok := m.counters[key]
+	switch {
+	case !ok:
+		c = &counter{start: start, window: window}
+		m.counters[key] = c
+	case start.Sub(c.start) == window:
+		c.start, c.previous, c.current = start, c.current, 0
+	case !start.Equal(c.start):
+		c.start, c.previous, c.current = start, 0, 0
+	}
+
+	if float64(c.previous)*weight+float64(c.current) > float64(limit-1) {
+		return Counts{Current: c.current, Previous: c.previous}, nil
+	}
+	c.current++
+
+	return Counts{Allowed: true, Current: c.current, Previous: c.previous}, nil
+}
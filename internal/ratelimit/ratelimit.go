@@ -0,0 +1,126 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"newsteller/internal/config"
+	"time"
+)
+
+/**
+Request limits of clients within sliding windows. Windows are approximated with the counts of
+the current and the previous fixed window, the previous one weighted by its part still inside
+the sliding window, so a store keeps two counters per client and rule instead of a log of requests.
+Counters live in memory, or in redis when several replicas serve the site.
+*/
+
+const (
+	StoreMemory = "memory"
+	StoreRedis  = "redis"
+)
+
+// Rule allows a number of requests per client within a sliding window.
+type Rule struct {
+	// Name - part of the counter keys, rules are counted apart
+	Name     string
+	Requests int
+	Window   time.Duration
+}
+
+// Result of a request counted against a rule.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset - time until a request is allowed again when denied, until the current window
+	// ends otherwise
+	Reset time.Duration
+}
+
+// Store counts the requests of clients, a request is counted only when it is allowed.
+type Store interface {
+	// Take counts a request of the key within the window starting at start, unless the
+	// previous window weighted by weight and the current one hold limit requests already.
+	// It returns whether the request was counted and the counts of both windows.
+	Take(ctx context.Context, key string, limit int, window time.Duration, start time.Time, weight float64) (Counts, error)
+}
+
+// Counts of the windows of a key, Allowed when the request was counted.
+type Counts struct {
+	Allowed  bool
+	Current  int64
+	Previous int64
+}
+
+// NewStore creates the store selected in the configuration.
+func NewStore(ctx context.Context, cfg *config.Config) (Store, error) {
+	switch cfg.RateLimit.Store {
+	case StoreMemory:
+		return NewMemoryStore(), nil
+	case StoreRedis:
+		return NewRedisStore(ctx, cfg.RateLimit.RedisURL)
+	default:
+		return nil, fmt.Errorf("unknown rate limit store: %q", cfg.RateLimit.Store)
+	}
+}
+
+// Limiter counts the requests of clients against rules.
+type Limiter struct {
+	store Store
+	now   func() time.Time
+}
+
+func NewLimiter(store Store) *Limiter {
+	return &Limiter{store: store, now: time.Now}
+}
+
+// Take counts a request of the client against the rule, rules without requests allow
+// every request.
+func (l *Limiter) Take(ctx context.Context, rule Rule, client string) (Result, error) {
+	if rule.Requests <= 0 || rule.Window <= 0 {
+		return Result{Allowed: true}, nil
+	}
+
+	now := l.now()
+	start := now.Truncate(rule.Window)
+	elapsed := now.Sub(start)
+	weight := 1 - float64(elapsed)/float64(rule.Window)
+
+	c, err := l.store.Take(ctx, "ratelimit:"+rule.Name+":"+client, rule.Requests, rule.Window, start, weight)
+	if err != nil {
+		return Result{Allowed: true, Limit: rule.Requests, Remaining: rule.Requests}, err
+	}
+
+	return result(rule, c, elapsed), nil
+}
+
+func result(rule Rule, c Counts, elapsed time.Duration) Result {
+	window := float64(rule.Window)
+	weight := 1 - float64(elapsed)/window
+	used := int(math.Ceil(float64(c.Previous)*weight)) + int(c.Current)
+
+	r := Result{
+		Allowed:   c.Allowed,
+		Limit:     rule.Requests,
+		Remaining: max(rule.Requests-used, 0),
+		Reset:     rule.Window - elapsed,
+	}
+	if c.Allowed {
+		return r
+	}
+
+	// the previous window slides out until a request fits
+	limit := float64(rule.Requests)
+	if c.Current < int64(rule.Requests) && c.Previous > 0 {
+		// previous*(1-t/window) + current <= limit-1
+		at := window * (1 - (limit-1-float64(c.Current))/float64(c.Previous))
+		r.Reset = max(time.Duration(at)-elapsed, time.Second)
+		return r
+	}
+	// the current window becomes the previous one first
+	at := window * (1 - (limit-1)/float64(c.Current))
+	r.Reset += time.Duration(at)
+
+	return r
+}
@@ -0,0 +1,173 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"newsteller/internal/config"
+)
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, int, time.Duration, time.Time, float64) (Counts, error) {
+	return Counts{}, errors.New("store down")
+}
+
+func newTestLimiter() (*Limiter, *time.Time) {
+	// the start of a window, so tests control the part of the previous window counted
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	limiter := NewLimiter(NewMemoryStore())
+	limiter.now = func() time.Time { return now }
+
+	return limiter, &now
+}
+
+func TestLimiter_Take(t *testing.T) {
+	ctx := context.Background()
+	rule := Rule{Name: "test", Requests: 3, Window: time.Minute}
+
+	t.Run("Positive: Requests within the limit", func(t *testing.T) {
+		limiter, now := newTestLimiter()
+		*now = now.Add(10 * time.Second)
+
+		for remaining := 2; remaining >= 0; remaining-- {
+			result, err := limiter.Take(ctx, rule, "client")
+			require.NoError(t, err)
+			assert.True(t, result.Allowed)
+			assert.Equal(t, 3, result.Limit)
+			assert.Equal(t, remaining, result.Remaining)
+			assert.Equal(t, 50*time.Second, result.Reset, "reset is the end of the window")
+		}
+	})
+
+	t.Run("Negative: Request over the limit", func(t *testing.T) {
+		limiter, now := newTestLimiter()
+		*now = now.Add(10 * time.Second)
+		for range 3 {
+			_, err := limiter.Take(ctx, rule, "client")
+			require.NoError(t, err)
+		}
+
+		result, err := limiter.Take(ctx, rule, "client")
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, 0, result.Remaining)
+		// three requests of the previous window fit two once a third of it slid out
+		assert.Equal(t, 70*time.Second, result.Reset)
+
+		other, err := limiter.Take(ctx, rule, "other")
+		require.NoError(t, err)
+		assert.True(t, other.Allowed, "clients are counted apart")
+
+		otherRule, err := limiter.Take(ctx, Rule{Name: "other", Requests: 1, Window: time.Minute}, "client")
+		require.NoError(t, err)
+		assert.True(t, otherRule.Allowed, "rules are counted apart")
+	})
+
+	t.Run("Positive: Previous window slides out", func(t *testing.T) {
+		limiter, now := newTestLimiter()
+		for range 3 {
+			_, err := limiter.Take(ctx, rule, "client")
+			require.NoError(t, err)
+		}
+
+		// the three requests of the previous window still count as 2.5
+		*now = now.Add(70 * time.Second)
+		result, err := limiter.Take(ctx, rule, "client")
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, 10*time.Second, result.Reset)
+
+		*now = now.Add(10 * time.Second)
+		result, err = limiter.Take(ctx, rule, "client")
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 0, result.Remaining)
+
+		// nothing of windows older than the previous one counts
+		*now = now.Add(2 * time.Minute)
+		result, err = limiter.Take(ctx, rule, "client")
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 2, result.Remaining)
+	})
+
+	t.Run("Negative: Denied with previous requests sliding out", func(t *testing.T) {
+		limiter, now := newTestLimiter()
+		for range 2 {
+			_, err := limiter.Take(ctx, rule, "client")
+			require.NoError(t, err)
+		}
+		*now = now.Add(time.Minute)
+		_, err := limiter.Take(ctx, rule, "client")
+		require.NoError(t, err)
+
+		result, err := limiter.Take(ctx, rule, "client")
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+		// 2*(1-t/60s) + 1 <= 2 once half of the previous window slid out
+		assert.Equal(t, 30*time.Second, result.Reset)
+	})
+
+	t.Run("Negative: Shorter rules keep the counters of longer windows", func(t *testing.T) {
+		limiter, now := newTestLimiter()
+		long := Rule{Name: "long", Requests: 1, Window: 10 * time.Minute}
+		short := Rule{Name: "short", Requests: 3, Window: time.Minute}
+
+		*now = now.Add(9*time.Minute + 59*time.Second)
+		result, err := limiter.Take(ctx, long, "client")
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+
+		// past the boundary of the long window, the short rule prunes the store
+		*now = now.Add(91 * time.Second)
+		_, err = limiter.Take(ctx, short, "client")
+		require.NoError(t, err)
+
+		result, err = limiter.Take(ctx, long, "client")
+		require.NoError(t, err)
+		assert.False(t, result.Allowed, "the previous window of the long rule still counts")
+
+		// two long windows later the counter is pruned and counts nothing
+		*now = now.Add(20 * time.Minute)
+		_, err = limiter.Take(ctx, short, "client")
+		require.NoError(t, err)
+		assert.Len(t, limiter.store.(*MemoryStore).counters, 1)
+	})
+
+	t.Run("Positive: Rules without requests allow everything", func(t *testing.T) {
+		limiter, _ := newTestLimiter()
+		result, err := limiter.Take(ctx, Rule{Name: "off", Window: time.Minute}, "client")
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Zero(t, result.Limit)
+	})
+
+	t.Run("Negative: Store errors allow the request", func(t *testing.T) {
+		result, err := NewLimiter(failingStore{}).Take(ctx, rule, "client")
+		assert.Error(t, err)
+		assert.True(t, result.Allowed)
+	})
+}
+
+func TestNewStore(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.RateLimit.Store = StoreMemory
+	store, err := NewStore(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.IsType(t, &MemoryStore{}, store)
+
+	cfg.RateLimit.Store = StoreRedis
+	cfg.RateLimit.RedisURL = "not a url"
+	_, err = NewStore(context.Background(), cfg)
+	assert.ErrorContains(t, err, "invalid redis URL")
+
+	cfg.RateLimit.Store = "memcached"
+	_, err = NewStore(context.Background(), cfg)
+	assert.ErrorContains(t, err, "unknown rate limit store")
+}
@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+// takeScript counts a request in the current window unless both windows hold the limit
+// already, atomically, so replicas never allow more than the limit together.
+var takeScript = redis.NewScript(`
+local current = tonumber(redis.call('GET', KEYS[1]) or '0')
+local previous = tonumber(redis.call('GET', KEYS[2]) or '0')
+if previous * tonumber(ARGV[2]) + current > tonumber(ARGV[1]) - 1 then
+	return {0, current, previous}
+end
+current = redis.call('INCR', KEYS[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return {1, current, previous}
+`)
+
+// RedisStore keeps the counters in redis, shared by the replicas of the site.
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore connects to the redis server of the URL.
+func NewRedisStore(ctx context.Context, url string) (*RedisStore, error) {
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL: %w", err)
+	}
+	client := redis.NewClient(options)
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	return &RedisStore{client: client}, nil
+}
+
+func (r *RedisStore) Take(ctx context.Context, key string, limit int, window time.Duration, start time.Time, weight float64) (Counts, error) {
+	index := start.UnixMilli() / window.Milliseconds()
+	keys := []string{
+		key + ":" + strconv.FormatInt(index, 10),
+		key + ":" + strconv.FormatInt(index-1, 10),
+	}
+	// the current window is read as the previous one during the next window
+	expiry := (2 * window).Milliseconds()
+
+	values, err := takeScript.Run(ctx, r.client, keys, limit, strconv.FormatFloat(weight, 'f', 6, 64), expiry).Int64Slice()
+	if err != nil {
+		return Counts{}, err
+	}
+	if len(values) != 3 {
+		return Counts{}, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+
+	return Counts{Allowed: values[0] == 1, Current: values[1], Previous: values[2]}, nil
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	"newsteller/internal/models"
+)
+
+type Attachment struct {
+	c *mongo.Collection
+}
+
+func NewAttachmentRepository(collection *mongo.Collection) *Attachment {
+	return &Attachment{c: collection}
+}
+
+// Create inserts the attachment, a duplicate key error is returned when an attachment
+// with the same content exists.
+func (a *Attachment) Create(ctx context.Context, attachment *models.Attachment) error {
+	_, err := a.c.InsertOne(ctx, attachment)
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		zap.L().Error("could not insert attachment", zap.Error(err))
+	}
+
+	return err
+}
+
+func (a *Attachment) FindByHash(ctx context.Context, hash string) (*models.Attachment, error) {
+	return a.findOne(ctx, bson.M{"hash": hash})
+}
+
+func (a *Attachment) FindByKey(ctx context.Context, key string) (*models.Attachment, error) {
+	return a.findOne(ctx, bson.M{"key": key})
+}
+
+// Recent returns the newest attachments.
+func (a *Attachment) Recent(ctx context.Context, limit int) ([]models.Attachment, error) {
+	findOptions := options.Find().
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := a.c.Find(ctx, bson.M{}, findOptions)
+	if err != nil {
+		zap.L().Error("could not find attachments", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var attachments []models.Attachment
+	if err = cursor.All(ctx, &attachments); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+func (a *Attachment) Delete(ctx context.Context, id primitive.ObjectID) error {
+	res, err := a.c.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		zap.L().Error("could not delete attachment", zap.String("id", id.Hex()), zap.Error(err))
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return fmt.Errorf("no attachment found with ID: %s", id.Hex())
+	}
+
+	return nil
+}
+
+func (a *Attachment) findOne(ctx context.Context, filter bson.M) (*models.Attachment, error) {
+	var attachment models.Attachment
+	err := a.c.FindOne(ctx, filter).Decode(&attachment)
+	if err != nil {
+		return nil, err
+	}
+
+	return &attachment, nil
+}
@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/internal/models"
+)
+
+func TestAttachment_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	collection := dbClient.Database("newsteller_test").Collection("attachments_test")
+	repo := NewAttachmentRepository(collection)
+	defer collection.DeleteMany(ctx, bson.M{})
+
+	older := &models.Attachment{
+		ID:          primitive.NewObjectID(),
+		Hash:        "aaa",
+		Key:         "aaa.png",
+		Name:        "logo.png",
+		ContentType: "image/png",
+		Size:        120,
+		CreatedAt:   time.Now().Add(-time.Hour),
+	}
+	newer := &models.Attachment{
+		ID:          primitive.NewObjectID(),
+		Hash:        "bbb",
+		Key:         "bbb.pdf",
+		Name:        "report.pdf",
+		ContentType: "application/pdf",
+		Size:        2048,
+		CreatedAt:   time.Now(),
+	}
+	require.NoError(t, repo.Create(ctx, older))
+	require.NoError(t, repo.Create(ctx, newer))
+
+	t.Run("Positive: Find by hash and key", func(t *testing.T) {
+		attachment, err := repo.FindByHash(ctx, "aaa")
+		require.NoError(t, err)
+		assert.Equal(t, "logo.png", attachment.Name)
+
+		attachment, err = repo.FindByKey(ctx, "bbb.pdf")
+		require.NoError(t, err)
+		assert.Equal(t, "application/pdf", attachment.ContentType)
+		assert.Equal(t, int64(2048), attachment.Size)
+	})
+
+	t.Run("Negative: Unknown key", func(t *testing.T) {
+		_, err := repo.FindByKey(ctx, "ccc.gif")
+		assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+	})
+
+	t.Run("Positive: Recent newest first", func(t *testing.T) {
+		attachments, err := repo.Recent(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, attachments, 2)
+		assert.Equal(t, "bbb", attachments[0].Hash)
+		assert.Equal(t, "aaa", attachments[1].Hash)
+
+		attachments, err = repo.Recent(ctx, 1)
+		require.NoError(t, err)
+		assert.Len(t, attachments, 1)
+	})
+
+	t.Run("Positive: Delete", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, older.ID))
+		_, err := repo.FindByHash(ctx, "aaa")
+		assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+	})
+
+	t.Run("Negative: Delete unknown", func(t *testing.T) {
+		err := repo.Delete(ctx, primitive.NewObjectID())
+		assert.ErrorContains(t, err, "no attachment found with ID")
+	})
+}
@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"newsteller/internal/models"
+	"time"
+)
+
+type Bounce struct {
+	c *mongo.Collection
+}
+
+func NewBounceRepository(collection *mongo.Collection) *Bounce {
+	return &Bounce{c: collection}
+}
+
+func (b *Bounce) Create(ctx context.Context, bounce *models.Bounce) error {
+	_, err := b.c.InsertOne(ctx, bounce)
+	if err != nil {
+		zap.L().Error("could not insert bounce", zap.String("kind", string(bounce.Kind)), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// Count returns the number of reports of the kind received for the address since the provided time.
+func (b *Bounce) Count(ctx context.Context, email string, kind models.BounceKind, since time.Time) (int64, error) {
+	return b.c.CountDocuments(ctx, bson.M{
+		"email":      NormalizeEmail(email),
+		"kind":       kind,
+		"created_at": bson.M{"$gte": since},
+	})
+}
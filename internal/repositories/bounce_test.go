@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"newsteller/internal/models"
+)
+
+func TestBounce_Count(t *testing.T) {
+	ctx := context.Background()
+	bouncesCollection := dbClient.Database("newsteller_test").Collection("bounces_test")
+	repo := NewBounceRepository(bouncesCollection)
+	defer bouncesCollection.DeleteMany(ctx, bson.M{})
+
+	now := time.Now()
+	for _, bounce := range []models.Bounce{
+		{Email: "reader@example.com", Kind: models.BounceSoft, CreatedAt: now.Add(-48 * time.Hour)},
+		{Email: "reader@example.com", Kind: models.BounceSoft, CreatedAt: now.Add(-time.Hour)},
+		{Email: "reader@example.com", Kind: models.BounceSoft, CreatedAt: now},
+		{Email: "reader@example.com", Kind: models.BounceHard, CreatedAt: now},
+		{Email: "other@example.com", Kind: models.BounceSoft, CreatedAt: now},
+	} {
+		require.NoError(t, repo.Create(ctx, &bounce))
+	}
+
+	t.Run("Positive: Counts the kind within the window", func(t *testing.T) {
+		count, err := repo.Count(ctx, "Reader@Example.com", models.BounceSoft, now.Add(-24*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("Negative: No complaints", func(t *testing.T) {
+		count, err := repo.Count(ctx, "reader@example.com", models.BounceComplaint, time.Time{})
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+}
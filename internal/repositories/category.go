@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	"newsteller/internal/models"
+	"newsteller/internal/slugs"
+)
+
+type Category struct {
+	c *mongo.Collection
+}
+
+func NewCategoryRepository(collection *mongo.Collection) *Category {
+	return &Category{c: collection}
+}
+
+func (c *Category) Create(ctx context.Context, category *models.Category) (*primitive.ObjectID, error) {
+	res, err := c.c.InsertOne(ctx, category)
+	if err != nil {
+		zap.L().Error("could not insert category", zap.Error(err))
+		return nil, err
+	}
+
+	insertedID, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		zap.L().Error("failed to convert InsertedID to ObjectID")
+		return nil, fmt.Errorf("failed to convert InsertedID to ObjectID")
+	}
+
+	return &insertedID, nil
+}
+
+// All returns every category sorted by name.
+func (c *Category) All(ctx context.Context) ([]models.Category, error) {
+	cursor, err := c.c.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		zap.L().Error("could not find categories", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var categories []models.Category
+	if err = cursor.All(ctx, &categories); err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (c *Category) FindByID(ctx context.Context, id string) (*models.Category, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var category models.Category
+	err = c.c.FindOne(ctx, bson.M{"_id": objectID}).Decode(&category)
+	if err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+func (c *Category) FindBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	var category models.Category
+	err := c.c.FindOne(ctx, bson.M{"slug": slug}).Decode(&category)
+	if err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+// UniqueSlug returns the first slug of the name not used by another category.
+func (c *Category) UniqueSlug(ctx context.Context, name string) (string, error) {
+	base := slugs.Make(name)
+	for n := 1; ; n++ {
+		slug := slugs.WithSuffix(base, n)
+
+		count, err := c.c.CountDocuments(ctx, bson.M{"slug": slug}, options.Count().SetLimit(1))
+		if err != nil {
+			zap.L().Error("could not check category slug", zap.String("slug", slug), zap.Error(err))
+			return "", err
+		}
+		if count == 0 {
+			return slug, nil
+		}
+	}
+}
+
+func (c *Category) Delete(ctx context.Context, id primitive.ObjectID) error {
+	res, err := c.c.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		zap.L().Error("could not delete category", zap.String("id", id.Hex()), zap.Error(err))
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return fmt.Errorf("no category found with ID: %s", id.Hex())
+	}
+
+	return nil
+}
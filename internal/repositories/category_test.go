@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/internal/models"
+)
+
+func TestCategory_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	collection := dbClient.Database("newsteller_test").Collection("categories_test")
+	repo := NewCategoryRepository(collection)
+	defer collection.DeleteMany(ctx, bson.M{})
+
+	slug, err := repo.UniqueSlug(ctx, "World News")
+	require.NoError(t, err)
+	assert.Equal(t, "world-news", slug)
+
+	id, err := repo.Create(ctx, &models.Category{Name: "World News", Slug: slug, CreatedAt: time.Now()})
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, &models.Category{Name: "Europe", Slug: "europe", ParentID: *id, CreatedAt: time.Now()})
+	require.NoError(t, err)
+
+	t.Run("Positive: Taken slugs get a suffix", func(t *testing.T) {
+		slug, err := repo.UniqueSlug(ctx, "World news")
+		require.NoError(t, err)
+		assert.Equal(t, "world-news-2", slug)
+	})
+
+	t.Run("Positive: All sorted by name", func(t *testing.T) {
+		categories, err := repo.All(ctx)
+		require.NoError(t, err)
+		require.Len(t, categories, 2)
+		assert.Equal(t, "Europe", categories[0].Name)
+		assert.Equal(t, *id, categories[0].ParentID)
+		assert.Equal(t, "World News", categories[1].Name)
+		assert.True(t, categories[1].ParentID.IsZero())
+	})
+
+	t.Run("Positive: Find by ID and slug", func(t *testing.T) {
+		category, err := repo.FindByID(ctx, id.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, "world-news", category.Slug)
+
+		category, err = repo.FindBySlug(ctx, "europe")
+		require.NoError(t, err)
+		assert.Equal(t, "Europe", category.Name)
+
+		_, err = repo.FindBySlug(ctx, "missing")
+		assert.Equal(t, mongo.ErrNoDocuments, err)
+	})
+
+	t.Run("Positive: Delete", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, *id))
+
+		_, err := repo.FindByID(ctx, id.Hex())
+		assert.Equal(t, mongo.ErrNoDocuments, err)
+	})
+
+	t.Run("Negative: Delete a missing category", func(t *testing.T) {
+		missing := primitive.NewObjectID()
+		err := repo.Delete(ctx, missing)
+		assert.EqualError(t, err, "no category found with ID: "+missing.Hex())
+	})
+}
+
+func TestTag_Ensure(t *testing.T) {
+	ctx := context.Background()
+	// the unique name index makes concurrent upserts of a tag fail with duplicate keys
+	database := dbClient.Database("newsteller_test")
+	require.NoError(t, models.Tag{}.Migrate(ctx, database))
+	collection := database.Collection(models.Tag{}.CollectionName())
+	repo := NewTagRepository(collection)
+	defer collection.DeleteMany(ctx, bson.M{})
+
+	require.NoError(t, repo.Ensure(ctx, []string{"go", "databases"}))
+	require.NoError(t, repo.Ensure(ctx, []string{"go", "web"}), "Known tags should be ignored")
+	require.NoError(t, repo.Ensure(ctx, nil))
+
+	tags, err := repo.All(ctx)
+	require.NoError(t, err)
+	var names []string
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+		assert.False(t, tag.CreatedAt.IsZero())
+	}
+	assert.Equal(t, []string{"databases", "go", "web"}, names)
+}
@@ -0,0 +1,177 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	"newsteller/internal/models"
+)
+
+type Comment struct {
+	c *mongo.Collection
+}
+
+func NewCommentRepository(collection *mongo.Collection) *Comment {
+	return &Comment{c: collection}
+}
+
+func (c *Comment) Create(ctx context.Context, comment *models.Comment) (*primitive.ObjectID, error) {
+	res, err := c.c.InsertOne(ctx, comment)
+	if err != nil {
+		zap.L().Error("could not insert comment", zap.Error(err))
+		return nil, err
+	}
+
+	insertedID, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		zap.L().Error("failed to convert InsertedID to ObjectID")
+		return nil, fmt.Errorf("failed to convert InsertedID to ObjectID")
+	}
+
+	return &insertedID, nil
+}
+
+func (c *Comment) FindByID(ctx context.Context, id string) (*models.Comment, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var comment models.Comment
+	err = c.c.FindOne(ctx, bson.M{"_id": objectID}).Decode(&comment)
+	if err != nil {
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+// FindByPost returns the comments of the post with the status, oldest first.
+func (c *Comment) FindByPost(ctx context.Context, postID primitive.ObjectID, status models.CommentStatus) ([]models.Comment, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := c.c.Find(ctx, bson.M{"post_id": postID, "status": status}, findOptions)
+	if err != nil {
+		zap.L().Error("could not find comments", zap.String("post_id", postID.Hex()), zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var comments []models.Comment
+	if err = cursor.All(ctx, &comments); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// FindByStatus returns a page of the comments with the status, newest first, and their total.
+func (c *Comment) FindByStatus(
+	ctx context.Context,
+	status models.CommentStatus,
+	page, limit int,
+) ([]models.Comment, int64, error) {
+	filter := bson.M{"status": status}
+	total, err := c.c.CountDocuments(ctx, filter)
+	if err != nil {
+		zap.L().Error("could not count comments", zap.String("status", string(status)), zap.Error(err))
+		return nil, 0, err
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := c.c.Find(ctx, filter, findOptions)
+	if err != nil {
+		zap.L().Error("could not find comments", zap.String("status", string(status)), zap.Error(err))
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var comments []models.Comment
+	if err = cursor.All(ctx, &comments); err != nil {
+		return nil, 0, err
+	}
+
+	return comments, total, nil
+}
+
+// CountByStatus returns the number of comments of every status having some.
+func (c *Comment) CountByStatus(ctx context.Context) (map[models.CommentStatus]int64, error) {
+	cursor, err := c.c.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$status"}, {Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}}}}},
+	})
+	if err != nil {
+		zap.L().Error("could not count comments", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		Status models.CommentStatus `bson:"_id"`
+		Count  int64                `bson:"count"`
+	}
+	if err = cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[models.CommentStatus]int64, len(groups))
+	for _, group := range groups {
+		counts[group.Status] = group.Count
+	}
+
+	return counts, nil
+}
+
+func (c *Comment) SetStatus(ctx context.Context, id primitive.ObjectID, status models.CommentStatus) error {
+	res, err := c.c.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"status": status}})
+	if err != nil {
+		zap.L().Error("could not update comment", zap.String("id", id.Hex()), zap.Error(err))
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("no comment found with ID: %s", id.Hex())
+	}
+
+	return nil
+}
+
+// SetTrained records the status the spam filter learned the comment as.
+func (c *Comment) SetTrained(ctx context.Context, id primitive.ObjectID, status models.CommentStatus) error {
+	_, err := c.c.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"trained": status}})
+	if err != nil {
+		zap.L().Error("could not update comment", zap.String("id", id.Hex()), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// Delete removes the comment, replies to it are no longer shown.
+func (c *Comment) Delete(ctx context.Context, id primitive.ObjectID) error {
+	res, err := c.c.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		zap.L().Error("could not delete comment", zap.String("id", id.Hex()), zap.Error(err))
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return fmt.Errorf("no comment found with ID: %s", id.Hex())
+	}
+
+	return nil
+}
+
+// DeleteByPost removes every comment of the post.
+func (c *Comment) DeleteByPost(ctx context.Context, postID primitive.ObjectID) (int64, error) {
+	res, err := c.c.DeleteMany(ctx, bson.M{"post_id": postID})
+	if err != nil {
+		zap.L().Error("could not delete comments", zap.String("post_id", postID.Hex()), zap.Error(err))
+		return 0, err
+	}
+
+	return res.DeletedCount, nil
+}
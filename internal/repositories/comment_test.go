@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"newsteller/internal/models"
+)
+
+func TestComment_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	collection := dbClient.Database("newsteller_test").Collection("comments_test")
+	repo := NewCommentRepository(collection)
+	defer collection.DeleteMany(ctx, bson.M{})
+
+	postID, otherPostID := primitive.NewObjectID(), primitive.NewObjectID()
+	start := time.Now().Add(-time.Hour)
+	first, err := repo.Create(ctx, &models.Comment{
+		PostID: postID, Author: "Ann", Content: "First", Status: models.CommentApproved, CreatedAt: start,
+	})
+	require.NoError(t, err)
+	second, err := repo.Create(ctx, &models.Comment{
+		PostID: postID, ParentID: *first, Depth: 1, Author: "Bob", Content: "Reply",
+		Status: models.CommentPending, CreatedAt: start.Add(time.Minute),
+	})
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, &models.Comment{
+		PostID: otherPostID, Author: "Eve", Content: "Buy now", Status: models.CommentPending, CreatedAt: start.Add(2 * time.Minute),
+	})
+	require.NoError(t, err)
+
+	t.Run("Positive: Find the comments of a post with a status", func(t *testing.T) {
+		comments, err := repo.FindByPost(ctx, postID, models.CommentApproved)
+		require.NoError(t, err)
+		require.Len(t, comments, 1)
+		assert.Equal(t, "First", comments[0].Content)
+	})
+
+	t.Run("Positive: Queue is paginated newest first", func(t *testing.T) {
+		comments, total, err := repo.FindByStatus(ctx, models.CommentPending, 1, 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		require.Len(t, comments, 1)
+		assert.Equal(t, "Eve", comments[0].Author)
+
+		comments, _, err = repo.FindByStatus(ctx, models.CommentPending, 2, 1)
+		require.NoError(t, err)
+		require.Len(t, comments, 1)
+		assert.Equal(t, *second, comments[0].ID)
+		assert.Equal(t, *first, comments[0].ParentID)
+		assert.Equal(t, 1, comments[0].Depth)
+	})
+
+	t.Run("Positive: Count by status", func(t *testing.T) {
+		counts, err := repo.CountByStatus(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, map[models.CommentStatus]int64{
+			models.CommentApproved: 1,
+			models.CommentPending:  2,
+		}, counts)
+	})
+
+	t.Run("Positive: Set the status", func(t *testing.T) {
+		require.NoError(t, repo.SetStatus(ctx, *second, models.CommentSpam))
+
+		comment, err := repo.FindByID(ctx, second.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, models.CommentSpam, comment.Status)
+	})
+
+	t.Run("Negative: Moderate and delete a missing comment", func(t *testing.T) {
+		missing := primitive.NewObjectID()
+		err := repo.SetStatus(ctx, missing, models.CommentApproved)
+		assert.EqualError(t, err, "no comment found with ID: "+missing.Hex())
+		err = repo.Delete(ctx, missing)
+		assert.EqualError(t, err, "no comment found with ID: "+missing.Hex())
+	})
+
+	t.Run("Positive: Delete the comments of a post", func(t *testing.T) {
+		deleted, err := repo.DeleteByPost(ctx, postID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+
+		counts, err := repo.CountByStatus(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, map[models.CommentStatus]int64{models.CommentPending: 1}, counts)
+	})
+}
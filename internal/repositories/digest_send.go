@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"newsteller/internal/models"
+)
+
+type DigestSend struct {
+	c *mongo.Collection
+}
+
+func NewDigestSendRepository(collection *mongo.Collection) *DigestSend {
+	return &DigestSend{c: collection}
+}
+
+// Record stores the send record. Recording a period that is already recorded is not an error.
+func (d *DigestSend) Record(ctx context.Context, send *models.DigestSend) error {
+	_, err := d.c.InsertOne(ctx, send)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	if err != nil {
+		zap.L().Error("could not insert digest send", zap.String("subscriber", send.SubscriberID.Hex()), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// Exists reports whether the digest of the period was already handled for the subscriber.
+func (d *DigestSend) Exists(ctx context.Context, subscriberID primitive.ObjectID, period string) (bool, error) {
+	err := d.c.FindOne(ctx, bson.M{"subscriber_id": subscriberID, "period": period}).Err()
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
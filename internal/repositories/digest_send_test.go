@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"newsteller/internal/models"
+)
+
+func TestDigestSend_Record(t *testing.T) {
+	ctx := context.Background()
+	sendsCollection := dbClient.Database("newsteller_test").Collection("digest_sends_test")
+	repo := NewDigestSendRepository(sendsCollection)
+	defer sendsCollection.DeleteMany(ctx, bson.M{})
+
+	_, err := sendsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "subscriber_id", Value: 1}, {Key: "period", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	require.NoError(t, err)
+
+	subscriberID := primitive.NewObjectID()
+	send := &models.DigestSend{
+		SubscriberID: subscriberID,
+		Period:       "2025-W10",
+		Frequency:    models.DigestWeekly,
+		Status:       models.DigestSendEnqueued,
+		CreatedAt:    time.Now(),
+	}
+
+	t.Run("Positive: Record a period", func(t *testing.T) {
+		require.NoError(t, repo.Record(ctx, send))
+
+		exists, err := repo.Exists(ctx, subscriberID, "2025-W10")
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("Positive: Recording the same period again is ignored", func(t *testing.T) {
+		require.NoError(t, repo.Record(ctx, send))
+
+		count, err := sendsCollection.CountDocuments(ctx, bson.M{"subscriber_id": subscriberID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("Negative: Other period is not recorded", func(t *testing.T) {
+		exists, err := repo.Exists(ctx, subscriberID, "2025-W11")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+}
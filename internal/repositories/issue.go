@@ -0,0 +1,209 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	"newsteller/internal/models"
+	"time"
+)
+
+type Issue struct {
+	c *mongo.Collection
+}
+
+func NewIssueRepository(collection *mongo.Collection) *Issue {
+	return &Issue{c: collection}
+}
+
+func (i *Issue) Create(ctx context.Context, issue *models.Issue) (*primitive.ObjectID, error) {
+	res, err := i.c.InsertOne(ctx, issue)
+	if err != nil {
+		zap.L().Error("could not insert issue", zap.Error(err))
+		return nil, err
+	}
+
+	insertedID, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		zap.L().Error("failed to convert InsertedID to ObjectID")
+		return nil, fmt.Errorf("failed to convert InsertedID to ObjectID")
+	}
+
+	return &insertedID, nil
+}
+
+func (i *Issue) FindByID(ctx context.Context, id string) (*models.Issue, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		zap.L().Error("could not convert string ID to primitive.ObjectID", zap.Error(err))
+		return nil, err
+	}
+
+	var issue models.Issue
+	err = i.c.FindOne(ctx, bson.M{"_id": objectID}).Decode(&issue)
+	if err != nil {
+		zap.L().Error("could not find issue by id", zap.String("id", id), zap.Error(err))
+		return nil, err
+	}
+
+	return &issue, nil
+}
+
+// All returns issues of every status, the newest first.
+func (i *Issue) All(ctx context.Context) ([]models.Issue, error) {
+	return i.find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+}
+
+// FindSent returns the issues shown in the public archive, the latest sent first.
+func (i *Issue) FindSent(ctx context.Context) ([]models.Issue, error) {
+	return i.find(
+		ctx,
+		bson.M{"status": models.IssueSent},
+		options.Find().SetSort(bson.D{{Key: "sent_at", Value: -1}}),
+	)
+}
+
+// UpdateDraft stores the editable fields of a draft. Issues that are not drafts are left untouched.
+func (i *Issue) UpdateDraft(ctx context.Context, issue *models.Issue) error {
+	return i.setDraft(ctx, issue.ID, bson.M{
+		"name":       issue.Name,
+		"intro":      issue.Intro,
+		"post_ids":   issue.PostIDs,
+		"segment":    issue.Segment,
+		"updated_at": time.Now(),
+	})
+}
+
+// Schedule moves a draft to the scheduled state, it is sent at the provided time.
+func (i *Issue) Schedule(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	return i.setDraft(ctx, id, bson.M{
+		"status":       models.IssueScheduled,
+		"scheduled_at": at,
+		"updated_at":   time.Now(),
+	})
+}
+
+// Unschedule moves a scheduled or failed issue back to drafts.
+func (i *Issue) Unschedule(ctx context.Context, id primitive.ObjectID) error {
+	result, err := i.c.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "status": bson.M{"$in": []models.IssueStatus{models.IssueScheduled, models.IssueFailed}}},
+		bson.M{
+			"$set":   bson.M{"status": models.IssueDraft, "updated_at": time.Now()},
+			"$unset": bson.M{"scheduled_at": "", "last_error": ""},
+		},
+	)
+	if err != nil {
+		zap.L().Error("could not unschedule issue", zap.String("id", id.Hex()), zap.Error(err))
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("no scheduled issue found with ID: %s", id.Hex())
+	}
+
+	return nil
+}
+
+// ClaimDue locks the scheduled issue due for sending for the lease duration.
+// Issues stuck in sending state after their lease expired are claimed again.
+// Returns mongo.ErrNoDocuments when nothing is due.
+func (i *Issue) ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*models.Issue, error) {
+	filter := bson.M{
+		"$or": []bson.M{
+			{"status": models.IssueScheduled, "scheduled_at": bson.M{"$lte": now}},
+			{"status": models.IssueSending, "locked_until": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":       models.IssueSending,
+			"locked_until": now.Add(lease),
+		},
+	}
+	findOptions := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "scheduled_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var issue models.Issue
+	err := i.c.FindOneAndUpdate(ctx, filter, update, findOptions).Decode(&issue)
+	if err != nil {
+		return nil, err
+	}
+
+	return &issue, nil
+}
+
+func (i *Issue) MarkSent(ctx context.Context, id primitive.ObjectID, recipients int, at time.Time) error {
+	_, err := i.c.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{
+		"status":     models.IssueSent,
+		"recipients": recipients,
+		"sent_at":    at,
+	}})
+	if err != nil {
+		zap.L().Error("could not mark issue as sent", zap.String("id", id.Hex()), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// MarkFailed stops sending the issue, the reason is shown to editors.
+func (i *Issue) MarkFailed(ctx context.Context, id primitive.ObjectID, reason string) error {
+	_, err := i.c.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{
+		"status":     models.IssueFailed,
+		"last_error": reason,
+		"updated_at": time.Now(),
+	}})
+	if err != nil {
+		zap.L().Error("could not mark issue as failed", zap.String("id", id.Hex()), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// DeleteDraft removes a draft, issues that were scheduled or sent are kept.
+func (i *Issue) DeleteDraft(ctx context.Context, id primitive.ObjectID) error {
+	result, err := i.c.DeleteOne(ctx, bson.M{"_id": id, "status": models.IssueDraft})
+	if err != nil {
+		zap.L().Error("could not delete issue", zap.String("id", id.Hex()), zap.Error(err))
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("no draft issue found with ID: %s", id.Hex())
+	}
+
+	return nil
+}
+
+func (i *Issue) setDraft(ctx context.Context, id primitive.ObjectID, fields bson.M) error {
+	result, err := i.c.UpdateOne(ctx, bson.M{"_id": id, "status": models.IssueDraft}, bson.M{"$set": fields})
+	if err != nil {
+		zap.L().Error("could not update issue", zap.String("id", id.Hex()), zap.Error(err))
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("no draft issue found with ID: %s", id.Hex())
+	}
+
+	return nil
+}
+
+func (i *Issue) find(ctx context.Context, filter bson.M, findOptions *options.FindOptions) ([]models.Issue, error) {
+	cursor, err := i.c.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var issues []models.Issue
+	if err = cursor.All(ctx, &issues); err != nil {
+		return nil, err
+	}
+
+	return issues, nil
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/internal/models"
+)
+
+func TestIssue_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	issuesCollection := dbClient.Database("newsteller_test").Collection("issues_test")
+	repo := NewIssueRepository(issuesCollection)
+	defer issuesCollection.DeleteMany(ctx, bson.M{})
+
+	now := time.Now()
+	id, err := repo.Create(ctx, &models.Issue{Name: "Issue #1", Status: models.IssueDraft, CreatedAt: now})
+	require.NoError(t, err)
+
+	t.Run("Positive: Update draft", func(t *testing.T) {
+		postID := primitive.NewObjectID()
+		err := repo.UpdateDraft(ctx, &models.Issue{
+			ID:      *id,
+			Name:    "Spring issue",
+			Intro:   "Hello",
+			PostIDs: []primitive.ObjectID{postID},
+			Segment: models.Segment{Source: "home"},
+		})
+		require.NoError(t, err)
+
+		issue, err := repo.FindByID(ctx, id.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, "Spring issue", issue.Name)
+		assert.Equal(t, []primitive.ObjectID{postID}, issue.PostIDs)
+		assert.Equal(t, "home", issue.Segment.Source)
+	})
+
+	t.Run("Negative: Scheduled issue is not due yet", func(t *testing.T) {
+		require.NoError(t, repo.Schedule(ctx, *id, now.Add(time.Hour)))
+
+		_, err := repo.ClaimDue(ctx, now, time.Minute)
+		assert.Equal(t, mongo.ErrNoDocuments, err)
+	})
+
+	t.Run("Negative: Scheduled issue cannot be edited", func(t *testing.T) {
+		err := repo.UpdateDraft(ctx, &models.Issue{ID: *id, Name: "Too late"})
+		assert.ErrorContains(t, err, "no draft issue found")
+	})
+
+	t.Run("Positive: Claim due issue", func(t *testing.T) {
+		issue, err := repo.ClaimDue(ctx, now.Add(2*time.Hour), time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, *id, issue.ID)
+		assert.Equal(t, models.IssueSending, issue.Status)
+
+		_, err = repo.ClaimDue(ctx, now.Add(2*time.Hour), time.Minute)
+		assert.Equal(t, mongo.ErrNoDocuments, err, "Locked issue should not be claimed twice")
+	})
+
+	t.Run("Positive: Failed issue goes back to drafts", func(t *testing.T) {
+		require.NoError(t, repo.MarkFailed(ctx, *id, "no posts"))
+		_, err := repo.ClaimDue(ctx, now.Add(3*time.Hour), time.Minute)
+		assert.Equal(t, mongo.ErrNoDocuments, err, "Failed issue should not be claimed")
+
+		require.NoError(t, repo.Unschedule(ctx, *id))
+		issue, err := repo.FindByID(ctx, id.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, models.IssueDraft, issue.Status)
+		assert.Empty(t, issue.LastError)
+
+		require.NoError(t, repo.Schedule(ctx, *id, now))
+		_, err = repo.ClaimDue(ctx, now.Add(2*time.Hour), time.Minute)
+		require.NoError(t, err)
+	})
+
+	t.Run("Positive: Sent issue is archived", func(t *testing.T) {
+		require.NoError(t, repo.MarkSent(ctx, *id, 3, now))
+
+		issues, err := repo.FindSent(ctx)
+		require.NoError(t, err)
+		require.Len(t, issues, 1)
+		assert.Equal(t, 3, issues[0].Recipients)
+	})
+
+	t.Run("Negative: Sent issue cannot be deleted", func(t *testing.T) {
+		err := repo.DeleteDraft(ctx, *id)
+		assert.ErrorContains(t, err, "no draft issue found")
+	})
+}
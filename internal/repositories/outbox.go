@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	"newsteller/internal/models"
+	"time"
+)
+
+type Outbox struct {
+	c *mongo.Collection
+}
+
+func NewOutboxRepository(collection *mongo.Collection) *Outbox {
+	return &Outbox{c: collection}
+}
+
+func (o *Outbox) Create(ctx context.Context, message *models.OutboxMessage) (*primitive.ObjectID, error) {
+	res, err := o.c.InsertOne(ctx, message)
+	if err != nil {
+		zap.L().Error("could not insert outbox message", zap.Error(err))
+		return nil, err
+	}
+
+	insertedID, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		zap.L().Error("failed to convert InsertedID to ObjectID")
+		return nil, fmt.Errorf("failed to convert InsertedID to ObjectID")
+	}
+
+	return &insertedID, nil
+}
+
+// ClaimDue locks the oldest message due for delivery for the lease duration.
+// Messages stuck in sending state after their lease expired (e.g. the process crashed
+// while sending) are claimed again. Returns mongo.ErrNoDocuments when nothing is due.
+func (o *Outbox) ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*models.OutboxMessage, error) {
+	filter := bson.M{
+		"$or": []bson.M{
+			{"status": models.OutboxPending, "next_attempt_at": bson.M{"$lte": now}},
+			{"status": models.OutboxSending, "locked_until": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":       models.OutboxSending,
+			"locked_until": now.Add(lease),
+		},
+	}
+	findOptions := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var message models.OutboxMessage
+	err := o.c.FindOneAndUpdate(ctx, filter, update, findOptions).Decode(&message)
+	if err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+func (o *Outbox) MarkSent(ctx context.Context, id primitive.ObjectID, attempts int, at time.Time) error {
+	return o.set(ctx, id, bson.M{
+		"status":   models.OutboxSent,
+		"attempts": attempts,
+		"sent_at":  at,
+	})
+}
+
+// MarkRetry puts the message back into the queue after a failed attempt.
+func (o *Outbox) MarkRetry(
+	ctx context.Context,
+	id primitive.ObjectID,
+	attempts int,
+	nextAttemptAt time.Time,
+	lastError string,
+) error {
+	return o.set(ctx, id, bson.M{
+		"status":          models.OutboxPending,
+		"attempts":        attempts,
+		"next_attempt_at": nextAttemptAt,
+		"last_error":      lastError,
+	})
+}
+
+func (o *Outbox) MarkFailed(ctx context.Context, id primitive.ObjectID, attempts int, lastError string) error {
+	return o.set(ctx, id, bson.M{
+		"status":     models.OutboxFailed,
+		"attempts":   attempts,
+		"last_error": lastError,
+	})
+}
+
+// MarkCancelled drops the message without sending it, the reason is kept as its last error.
+func (o *Outbox) MarkCancelled(ctx context.Context, id primitive.ObjectID, reason string) error {
+	return o.set(ctx, id, bson.M{
+		"status":     models.OutboxCancelled,
+		"last_error": reason,
+	})
+}
+
+func (o *Outbox) set(ctx context.Context, id primitive.ObjectID, fields bson.M) error {
+	_, err := o.c.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": fields})
+	if err != nil {
+		zap.L().Error("could not update outbox message", zap.String("id", id.Hex()), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	"newsteller/internal/models"
+	"time"
+)
+
+type OutboxEvent struct {
+	c *mongo.Collection
+}
+
+func NewOutboxEventRepository(collection *mongo.Collection) *OutboxEvent {
+	return &OutboxEvent{c: collection}
+}
+
+// Create stores the events, their IDs must be set by the caller.
+func (o *OutboxEvent) Create(ctx context.Context, events []models.OutboxEvent) error {
+	documents := make([]any, len(events))
+	for i := range events {
+		documents[i] = events[i]
+	}
+
+	_, err := o.c.InsertMany(ctx, documents)
+	if err != nil {
+		zap.L().Error("could not insert outbox events", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// Commit marks prepared events as happened, the caller delivers them until the lease ends.
+func (o *OutboxEvent) Commit(ctx context.Context, ids []primitive.ObjectID, leaseUntil time.Time) error {
+	_, err := o.c.UpdateMany(
+		ctx,
+		bson.M{"_id": bson.M{"$in": ids}, "status": models.OutboxEventPrepared},
+		bson.M{"$set": bson.M{"status": models.OutboxEventPending, "next_attempt_at": leaseUntil}},
+	)
+	if err != nil {
+		zap.L().Error("could not commit outbox events", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// Discard removes events of changes that did not happen.
+func (o *OutboxEvent) Discard(ctx context.Context, ids []primitive.ObjectID) error {
+	_, err := o.c.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		zap.L().Error("could not discard outbox events", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// ClaimDue locks the oldest event due for delivery, or due for checking whether its
+// change happened when it is still prepared, by pushing its next attempt past the lease.
+// Returns mongo.ErrNoDocuments when nothing is due.
+func (o *OutboxEvent) ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*models.OutboxEvent, error) {
+	filter := bson.M{
+		"status":          bson.M{"$in": []models.OutboxEventStatus{models.OutboxEventPrepared, models.OutboxEventPending}},
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+	update := bson.M{"$set": bson.M{"next_attempt_at": now.Add(lease)}}
+	findOptions := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var event models.OutboxEvent
+	err := o.c.FindOneAndUpdate(ctx, filter, update, findOptions).Decode(&event)
+	if err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+func (o *OutboxEvent) MarkPublished(
+	ctx context.Context,
+	id primitive.ObjectID,
+	handled []string,
+	attempts int,
+	at time.Time,
+) error {
+	return o.set(ctx, id, bson.M{
+		"status":       models.OutboxEventPublished,
+		"handled":      handled,
+		"attempts":     attempts,
+		"published_at": at,
+	})
+}
+
+// MarkRetry keeps the event for another attempt of the subscribers that failed.
+func (o *OutboxEvent) MarkRetry(
+	ctx context.Context,
+	id primitive.ObjectID,
+	handled []string,
+	attempts int,
+	nextAttemptAt time.Time,
+	lastError string,
+) error {
+	return o.set(ctx, id, bson.M{
+		"status":          models.OutboxEventPending,
+		"handled":         handled,
+		"attempts":        attempts,
+		"next_attempt_at": nextAttemptAt,
+		"last_error":      lastError,
+	})
+}
+
+func (o *OutboxEvent) MarkFailed(
+	ctx context.Context,
+	id primitive.ObjectID,
+	handled []string,
+	attempts int,
+	lastError string,
+) error {
+	return o.set(ctx, id, bson.M{
+		"status":     models.OutboxEventFailed,
+		"handled":    handled,
+		"attempts":   attempts,
+		"last_error": lastError,
+	})
+}
+
+func (o *OutboxEvent) set(ctx context.Context, id primitive.ObjectID, fields bson.M) error {
+	_, err := o.c.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": fields})
+	if err != nil {
+		zap.L().Error("could not update outbox event", zap.String("id", id.Hex()), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/internal/models"
+)
+
+func TestOutboxEvent_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	collection := dbClient.Database("newsteller_test").Collection("event_outbox_test")
+	repo := NewOutboxEventRepository(collection)
+	defer collection.DeleteMany(ctx, bson.M{})
+
+	now := time.Now().Truncate(time.Millisecond)
+	prepared := models.OutboxEvent{
+		ID:            primitive.NewObjectID(),
+		Name:          "post.created",
+		Post:          models.Post{Title: "Created"},
+		Status:        models.OutboxEventPrepared,
+		NextAttemptAt: now.Add(time.Minute),
+		CreatedAt:     now,
+	}
+	discarded := prepared
+	discarded.ID = primitive.NewObjectID()
+	require.NoError(t, repo.Create(ctx, []models.OutboxEvent{prepared, discarded}))
+
+	t.Run("Negative: Prepared events are not due before their timeout", func(t *testing.T) {
+		_, err := repo.ClaimDue(ctx, now, time.Minute)
+		assert.Equal(t, mongo.ErrNoDocuments, err)
+	})
+
+	t.Run("Positive: Discard removes events", func(t *testing.T) {
+		require.NoError(t, repo.Discard(ctx, []primitive.ObjectID{discarded.ID}))
+
+		count, err := collection.CountDocuments(ctx, bson.M{})
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, count)
+	})
+
+	t.Run("Positive: Commit leases the event to the publisher", func(t *testing.T) {
+		require.NoError(t, repo.Commit(ctx, []primitive.ObjectID{prepared.ID}, now.Add(time.Minute)))
+
+		_, err := repo.ClaimDue(ctx, now.Add(30*time.Second), time.Minute)
+		assert.Equal(t, mongo.ErrNoDocuments, err)
+
+		event, err := repo.ClaimDue(ctx, now.Add(2*time.Minute), time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, prepared.ID, event.ID)
+		assert.Equal(t, models.OutboxEventPending, event.Status)
+		assert.Equal(t, "Created", event.Post.Title)
+
+		_, err = repo.ClaimDue(ctx, now.Add(2*time.Minute), time.Minute)
+		assert.Equal(t, mongo.ErrNoDocuments, err, "Claimed event should be leased")
+	})
+
+	t.Run("Positive: Retry keeps the handled subscribers", func(t *testing.T) {
+		next := now.Add(time.Hour)
+		require.NoError(t, repo.MarkRetry(ctx, prepared.ID, []string{"cache"}, 1, next, "webhooks: boom"))
+
+		event, err := repo.ClaimDue(ctx, next, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"cache"}, event.Handled)
+		assert.Equal(t, 1, event.Attempts)
+		assert.Equal(t, "webhooks: boom", event.LastError)
+	})
+
+	t.Run("Positive: Published events are never due", func(t *testing.T) {
+		require.NoError(t, repo.MarkPublished(ctx, prepared.ID, []string{"cache", "webhooks"}, 2, now))
+
+		_, err := repo.ClaimDue(ctx, now.Add(24*time.Hour), time.Minute)
+		assert.Equal(t, mongo.ErrNoDocuments, err)
+	})
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/internal/models"
+)
+
+func TestOutbox_ClaimDue(t *testing.T) {
+	ctx := context.Background()
+	outboxCollection := dbClient.Database("newsteller_test").Collection("mail_outbox_test")
+	repo := NewOutboxRepository(outboxCollection)
+	defer outboxCollection.DeleteMany(ctx, bson.M{})
+
+	now := time.Now()
+	dueID, err := repo.Create(ctx, &models.OutboxMessage{
+		To:            "due@example.com",
+		Status:        models.OutboxPending,
+		NextAttemptAt: now.Add(-time.Minute),
+	})
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, &models.OutboxMessage{
+		To:            "later@example.com",
+		Status:        models.OutboxPending,
+		NextAttemptAt: now.Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	t.Run("Positive: Claim due message", func(t *testing.T) {
+		message, err := repo.ClaimDue(ctx, now, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, *dueID, message.ID)
+		assert.Equal(t, models.OutboxSending, message.Status)
+	})
+
+	t.Run("Negative: Claimed and future messages are skipped", func(t *testing.T) {
+		_, err := repo.ClaimDue(ctx, now, time.Minute)
+		assert.Equal(t, mongo.ErrNoDocuments, err)
+	})
+
+	t.Run("Positive: Message with expired lease is claimed again", func(t *testing.T) {
+		message, err := repo.ClaimDue(ctx, now.Add(2*time.Minute), time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, *dueID, message.ID)
+	})
+
+	t.Run("Positive: Retry reschedules the message", func(t *testing.T) {
+		require.NoError(t, repo.MarkRetry(ctx, *dueID, 1, now.Add(10*time.Minute), "connection refused"))
+
+		_, err := repo.ClaimDue(ctx, now.Add(5*time.Minute), time.Minute)
+		assert.Equal(t, mongo.ErrNoDocuments, err)
+
+		message, err := repo.ClaimDue(ctx, now.Add(11*time.Minute), time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, *dueID, message.ID)
+		assert.Equal(t, 1, message.Attempts)
+		assert.Equal(t, "connection refused", message.LastError)
+	})
+
+	t.Run("Positive: Sent messages are not claimed", func(t *testing.T) {
+		require.NoError(t, repo.MarkSent(ctx, *dueID, 2, now))
+
+		message, err := repo.ClaimDue(ctx, now.Add(2*time.Hour), time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, "later@example.com", message.To)
+	})
+
+	t.Run("Negative: Cancelled messages are not claimed", func(t *testing.T) {
+		message, err := repo.ClaimDue(ctx, now.Add(3*time.Hour), time.Minute)
+		require.NoError(t, err)
+		require.NoError(t, repo.MarkCancelled(ctx, message.ID, "recipient unsubscribed"))
+
+		_, err = repo.ClaimDue(ctx, now.Add(4*time.Hour), time.Minute)
+		assert.Equal(t, mongo.ErrNoDocuments, err)
+	})
+}
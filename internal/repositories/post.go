@@ -9,8 +9,14 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 	"newsteller/internal/models"
+	"newsteller/internal/slugs"
+	"slices"
+	"sort"
 )
 
+// reservedSlugs - paths under /posts routed to other pages than posts
+var reservedSlugs = []string{"create", "edit", "search"}
+
 type Post struct {
 	c *mongo.Collection
 }
@@ -53,6 +59,79 @@ func (p *Post) FindByID(ctx context.Context, id string) (*models.Post, error) {
 	return &post, nil
 }
 
+// FindBySlug returns the post with the current or a former slug.
+func (p *Post) FindBySlug(ctx context.Context, slug string) (*models.Post, error) {
+	filter := bson.M{"$or": []bson.M{{"slug": slug}, {"slug_history": slug}}}
+
+	var post models.Post
+	err := p.c.FindOne(ctx, filter).Decode(&post)
+	if err != nil {
+		zap.L().Error("could not find post by slug", zap.String("slug", slug), zap.Error(err))
+		return nil, err
+	}
+
+	return &post, nil
+}
+
+// UniqueSlug returns the first slug of the title not used, currently or formerly,
+// by another post than the given one.
+func (p *Post) UniqueSlug(ctx context.Context, title string, id primitive.ObjectID) (string, error) {
+	base := slugs.Make(title)
+	for n := 1; ; n++ {
+		slug := slugs.WithSuffix(base, n)
+		if slices.Contains(reservedSlugs, slug) {
+			continue
+		}
+
+		filter := bson.M{
+			"_id": bson.M{"$ne": id},
+			"$or": []bson.M{{"slug": slug}, {"slug_history": slug}},
+		}
+		count, err := p.c.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+		if err != nil {
+			zap.L().Error("could not check post slug", zap.String("slug", slug), zap.Error(err))
+			return "", err
+		}
+		if count == 0 {
+			return slug, nil
+		}
+	}
+}
+
+// BackfillSlugs generates the slugs of posts created before slugs existed, oldest first
+// so older posts get the slugs without suffixes.
+func (p *Post) BackfillSlugs(ctx context.Context) (int, error) {
+	filter := bson.M{"slug": bson.M{"$in": bson.A{nil, ""}}}
+	findOptions := options.Find().
+		SetProjection(bson.M{"_id": 1, "title": 1}).
+		SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := p.c.Find(ctx, filter, findOptions)
+	if err != nil {
+		zap.L().Error("could not find posts without slugs", zap.Error(err))
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var posts []models.Post
+	if err = cursor.All(ctx, &posts); err != nil {
+		return 0, err
+	}
+
+	for i, post := range posts {
+		slug, err := p.UniqueSlug(ctx, post.Title, post.ID)
+		if err != nil {
+			return i, err
+		}
+		_, err = p.c.UpdateByID(ctx, post.ID, bson.M{"$set": bson.M{"slug": slug}})
+		if err != nil {
+			zap.L().Error("could not set post slug", zap.String("id", post.ID.Hex()), zap.Error(err))
+			return i, err
+		}
+	}
+
+	return len(posts), nil
+}
+
 func (p *Post) Create(ctx context.Context, post *models.Post) (*primitive.ObjectID, error) {
 	res, err := p.c.InsertOne(ctx, post)
 	if err != nil {
@@ -81,6 +160,12 @@ func (p *Post) FindPaginated(
 			{"content": bson.M{"$regex": query.Keyword, "$options": "i"}},
 		}
 	}
+	if query.Tag != "" {
+		filter["tags"] = query.Tag
+	}
+	if query.Categories != nil {
+		filter["category_id"] = bson.M{"$in": query.Categories}
+	}
 
 	skip := (query.Page - 1) * query.Limit
 	findOptions := options.Find().
@@ -132,12 +217,40 @@ func (p *Post) Update(ctx context.Context, post *models.Post) error {
 
 	filter := bson.D{{"_id", objectID}}
 
-	update := bson.D{
-		{"$set", bson.D{
-			{"title", post.Title},
-			{"content", post.Content},
-			{"updated_at", post.UpdatedAt},
-		}},
+	set := bson.D{
+		{Key: "title", Value: post.Title},
+		{Key: "content", Value: post.Content},
+		{Key: "tags", Value: post.Tags},
+		{Key: "author", Value: post.Author},
+		{Key: "slug", Value: post.Slug},
+		{Key: "slug_history", Value: post.SlugHistory},
+		{Key: "updated_at", Value: post.UpdatedAt},
+	}
+	// cleared fields are removed, e.g. posts taken out of their category keep no category_id
+	unset := bson.D{}
+	if post.CategoryID.IsZero() {
+		unset = append(unset, bson.E{Key: "category_id", Value: ""})
+	} else {
+		set = append(set, bson.E{Key: "category_id", Value: post.CategoryID})
+	}
+	if post.HeroImage == "" {
+		unset = append(unset, bson.E{Key: "hero_image", Value: ""})
+	} else {
+		set = append(set, bson.E{Key: "hero_image", Value: post.HeroImage})
+	}
+	if post.AuthorEmail == "" {
+		unset = append(unset, bson.E{Key: "author_email", Value: ""})
+	} else {
+		set = append(set, bson.E{Key: "author_email", Value: post.AuthorEmail})
+	}
+	if !post.CommentsDisabled {
+		unset = append(unset, bson.E{Key: "comments_disabled", Value: ""})
+	} else {
+		set = append(set, bson.E{Key: "comments_disabled", Value: true})
+	}
+	update := bson.D{{Key: "$set", Value: set}}
+	if len(unset) > 0 {
+		update = append(update, bson.E{Key: "$unset", Value: unset})
 	}
 
 	result, err := p.c.UpdateOne(ctx, filter, update)
@@ -155,3 +268,120 @@ func (p *Post) Update(ctx context.Context, post *models.Post) error {
 	zap.L().Info("post updated successfully", zap.String("id", post.ID.Hex()))
 	return nil
 }
+
+// AllDates returns every post with only the ID, slug and dates set, newest first.
+func (p *Post) AllDates(ctx context.Context) ([]models.Post, error) {
+	findOptions := options.Find().
+		SetProjection(bson.M{"_id": 1, "slug": 1, "created_at": 1, "updated_at": 1}).
+		SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := p.c.Find(ctx, bson.M{}, findOptions)
+	if err != nil {
+		zap.L().Error("could not find post dates", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var posts []models.Post
+	if err = cursor.All(ctx, &posts); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+// Recent returns the newest posts, optionally only those with the tag or by the author.
+func (p *Post) Recent(ctx context.Context, tag, author string, limit int) ([]models.Post, error) {
+	filter := bson.M{}
+	if tag != "" {
+		filter["tags"] = tag
+	}
+	if author != "" {
+		filter["author"] = author
+	}
+
+	findOptions := options.Find().
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := p.c.Find(ctx, filter, findOptions)
+	if err != nil {
+		zap.L().Error("could not find recent posts", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var posts []models.Post
+	if err = cursor.All(ctx, &posts); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+// FindByIDs returns the posts with the IDs, in no particular order.
+func (p *Post) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.Post, error) {
+	cursor, err := p.c.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		zap.L().Error("could not find posts", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var posts []models.Post
+	if err = cursor.All(ctx, &posts); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+// CountInCategory returns the number of posts filed under the category.
+func (p *Post) CountInCategory(ctx context.Context, id primitive.ObjectID) (int64, error) {
+	count, err := p.c.CountDocuments(ctx, bson.M{"category_id": id})
+	if err != nil {
+		zap.L().Error("could not count posts of category", zap.String("id", id.Hex()), zap.Error(err))
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// TagCloud returns the tags used by most posts with their post counts, most used first.
+func (p *Post) TagCloud(ctx context.Context, limit int) ([]models.TagCount, error) {
+	cursor, err := p.c.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$unwind", Value: "$tags"}},
+		{{Key: "$group", Value: bson.M{"_id": "$tags", "posts": bson.M{"$sum": 1}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "posts", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	})
+	if err != nil {
+		zap.L().Error("could not aggregate post tags", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tags []models.TagCount
+	if err = cursor.All(ctx, &tags); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// Tags returns every tag used by the posts, sorted alphabetically.
+func (p *Post) Tags(ctx context.Context) ([]string, error) {
+	values, err := p.c.Distinct(ctx, "tags", bson.M{})
+	if err != nil {
+		zap.L().Error("could not find post tags", zap.Error(err))
+		return nil, err
+	}
+
+	tags := make([]string, 0, len(values))
+	for _, value := range values {
+		if tag, ok := value.(string); ok {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+
+	return tags, nil
+}
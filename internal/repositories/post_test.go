@@ -388,3 +388,263 @@ func TestPost_FindPaginated(t *testing.T) {
 		assert.EqualValues(t, 27, total)
 	})
 }
+
+func TestPost_Tags(t *testing.T) {
+	ctx := context.Background()
+	defer clearCollection(ctx)
+
+	t.Run("Positive: Empty list when no posts are tagged", func(t *testing.T) {
+		tags, err := postRepo.Tags(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, tags)
+	})
+
+	t.Run("Positive: Distinct sorted tags", func(t *testing.T) {
+		for _, tags := range [][]string{{"go", "databases"}, {"go"}, nil} {
+			_, err := postRepo.Create(ctx, &models.Post{Title: "Tagged", Content: "Content", Tags: tags})
+			require.NoError(t, err)
+		}
+
+		tags, err := postRepo.Tags(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"databases", "go"}, tags)
+	})
+}
+
+func TestPost_Recent(t *testing.T) {
+	ctx := context.Background()
+	defer clearCollection(ctx)
+
+	now := time.Now()
+	for i, post := range []models.Post{
+		{Title: "Oldest", Tags: []string{"go"}, Author: "Jane"},
+		{Title: "Middle", Tags: []string{"databases"}, Author: "John"},
+		{Title: "Newest", Tags: []string{"go", "databases"}, Author: "Jane"},
+	} {
+		post.Content = "Content"
+		post.CreatedAt = now.Add(time.Duration(i) * time.Minute)
+		_, err := postRepo.Create(ctx, &post)
+		require.NoError(t, err)
+	}
+
+	titles := func(posts []models.Post) []string {
+		result := make([]string, 0, len(posts))
+		for _, post := range posts {
+			result = append(result, post.Title)
+		}
+		return result
+	}
+
+	t.Run("Positive: Newest first up to the limit", func(t *testing.T) {
+		posts, err := postRepo.Recent(ctx, "", "", 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Newest", "Middle"}, titles(posts))
+	})
+
+	t.Run("Positive: Filtered by tag and author", func(t *testing.T) {
+		posts, err := postRepo.Recent(ctx, "go", "", 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Newest", "Oldest"}, titles(posts))
+
+		posts, err = postRepo.Recent(ctx, "databases", "Jane", 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Newest"}, titles(posts))
+	})
+
+	t.Run("Negative: Unknown author", func(t *testing.T) {
+		posts, err := postRepo.Recent(ctx, "", "Nobody", 10)
+		require.NoError(t, err)
+		assert.Empty(t, posts)
+	})
+}
+
+func TestPost_AllDates(t *testing.T) {
+	ctx := context.Background()
+	defer clearCollection(ctx)
+
+	now := time.Now().Truncate(time.Millisecond)
+	for i := 0; i < 3; i++ {
+		_, err := postRepo.Create(ctx, &models.Post{
+			Title:     fmt.Sprintf("Post %d", i),
+			Content:   "Content",
+			CreatedAt: now.Add(time.Duration(i) * time.Minute),
+			UpdatedAt: now.Add(time.Hour),
+		})
+		require.NoError(t, err)
+	}
+
+	posts, err := postRepo.AllDates(ctx)
+	require.NoError(t, err)
+	require.Len(t, posts, 3)
+	assert.True(t, posts[0].CreatedAt.Equal(now.Add(2*time.Minute)), "newest first")
+	assert.True(t, posts[0].UpdatedAt.Equal(now.Add(time.Hour)))
+	assert.Empty(t, posts[0].Title, "only dates are loaded")
+	assert.Empty(t, posts[0].Content)
+}
+
+func TestPost_Slugs(t *testing.T) {
+	ctx := context.Background()
+	defer clearCollection(ctx)
+
+	now := time.Now()
+	first := &models.Post{Title: "Hello World", Content: "Content", CreatedAt: now}
+	second := &models.Post{Title: "Hello, world!", Content: "Content", CreatedAt: now.Add(time.Minute)}
+	for _, post := range []*models.Post{first, second} {
+		id, err := postRepo.Create(ctx, post)
+		require.NoError(t, err)
+		post.ID = *id
+	}
+
+	t.Run("Positive: Backfill oldest first", func(t *testing.T) {
+		count, err := postRepo.BackfillSlugs(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		found, err := postRepo.FindByID(ctx, first.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, "hello-world", found.Slug)
+		found, err = postRepo.FindByID(ctx, second.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, "hello-world-2", found.Slug)
+
+		count, err = postRepo.BackfillSlugs(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("Positive: Find by current and former slug", func(t *testing.T) {
+		found, err := postRepo.FindByID(ctx, first.ID.Hex())
+		require.NoError(t, err)
+		found.Slug = "renamed"
+		found.SlugHistory = []string{"hello-world"}
+		require.NoError(t, postRepo.Update(ctx, found))
+
+		post, err := postRepo.FindBySlug(ctx, "renamed")
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, post.ID)
+
+		post, err = postRepo.FindBySlug(ctx, "hello-world")
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, post.ID)
+		assert.Equal(t, "renamed", post.Slug)
+	})
+
+	t.Run("Positive: Former and reserved slugs are not reused", func(t *testing.T) {
+		slug, err := postRepo.UniqueSlug(ctx, "Hello World", primitive.NewObjectID())
+		require.NoError(t, err)
+		assert.Equal(t, "hello-world-3", slug)
+
+		slug, err = postRepo.UniqueSlug(ctx, "Search", primitive.NewObjectID())
+		require.NoError(t, err)
+		assert.Equal(t, "search-2", slug)
+	})
+
+	t.Run("Positive: The post keeps its own slug", func(t *testing.T) {
+		slug, err := postRepo.UniqueSlug(ctx, "Hello World", first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "hello-world", slug)
+	})
+
+	t.Run("Negative: Unknown slug", func(t *testing.T) {
+		_, err := postRepo.FindBySlug(ctx, "unknown")
+		assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+	})
+}
+
+func TestPost_Taxonomy(t *testing.T) {
+	ctx := context.Background()
+	defer clearCollection(ctx)
+
+	news, sports := primitive.NewObjectID(), primitive.NewObjectID()
+	now := time.Now()
+	for i, post := range []models.Post{
+		{Title: "Election", Tags: []string{"politics", "europe"}, CategoryID: news},
+		{Title: "Final", Tags: []string{"football", "europe"}, CategoryID: sports},
+		{Title: "Budget", Tags: []string{"politics", "economy", "europe"}, CategoryID: news},
+		{Title: "Uncategorised", Tags: []string{"economy"}},
+	} {
+		post.Content = "Content"
+		post.CreatedAt = now.Add(time.Duration(i) * time.Minute)
+		_, err := postRepo.Create(ctx, &post)
+		require.NoError(t, err)
+	}
+
+	titles := func(posts []models.Post) []string {
+		var titles []string
+		for _, post := range posts {
+			titles = append(titles, post.Title)
+		}
+		return titles
+	}
+
+	t.Run("Positive: Filter by tag", func(t *testing.T) {
+		posts, total, err := postRepo.FindPaginated(ctx, &PaginatedSearchQuery{Page: 1, Limit: 10, Tag: "politics"})
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, total)
+		assert.Equal(t, []string{"Budget", "Election"}, titles(posts))
+	})
+
+	t.Run("Positive: Filter by categories", func(t *testing.T) {
+		query := &PaginatedSearchQuery{Page: 1, Limit: 10, Categories: []primitive.ObjectID{news, sports}}
+		posts, total, err := postRepo.FindPaginated(ctx, query)
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, total)
+		assert.Equal(t, []string{"Budget", "Final", "Election"}, titles(posts))
+	})
+
+	t.Run("Positive: Count posts in category", func(t *testing.T) {
+		count, err := postRepo.CountInCategory(ctx, news)
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, count)
+
+		count, err = postRepo.CountInCategory(ctx, primitive.NewObjectID())
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("Positive: Tag cloud is sorted by usage then name", func(t *testing.T) {
+		tags, err := postRepo.TagCloud(ctx, 3)
+		require.NoError(t, err)
+		assert.Equal(t, []models.TagCount{
+			{Name: "europe", Posts: 3},
+			{Name: "economy", Posts: 2},
+			{Name: "politics", Posts: 2},
+		}, tags)
+	})
+
+	t.Run("Positive: Update removes the category", func(t *testing.T) {
+		posts, _, err := postRepo.FindPaginated(ctx, &PaginatedSearchQuery{Page: 1, Limit: 1, Keyword: "Final"})
+		require.NoError(t, err)
+		require.Len(t, posts, 1)
+
+		post := posts[0]
+		post.CategoryID = primitive.NilObjectID
+		require.NoError(t, postRepo.Update(ctx, &post))
+
+		count, err := postRepo.CountInCategory(ctx, sports)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+}
+
+func TestPost_HeroImage(t *testing.T) {
+	ctx := context.Background()
+	defer clearCollection(ctx)
+
+	post := &models.Post{Title: "Harbour", Content: "Boats", HeroImage: "abc.jpg", CreatedAt: time.Now()}
+	id, err := postRepo.Create(ctx, post)
+	require.NoError(t, err)
+
+	found, err := postRepo.FindByID(ctx, id.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, "abc.jpg", found.HeroImage)
+
+	t.Run("Positive: Removed by updates without one", func(t *testing.T) {
+		found.HeroImage = ""
+		require.NoError(t, postRepo.Update(ctx, found))
+
+		var raw bson.M
+		require.NoError(t, postRepo.c.FindOne(ctx, bson.M{"_id": *id}).Decode(&raw))
+		assert.NotContains(t, raw, "hero_image")
+	})
+}
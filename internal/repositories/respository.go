@@ -18,4 +18,9 @@ type PaginatedSearchQuery struct {
 	Page    int    `json:"page" valid:"required,gte=1"`
 	Limit   int    `json:"limit" validate:"required,gte=1"`
 	Keyword string `json:"keyword"`
+	// Tag - only posts with the tag
+	Tag string `json:"tag"`
+	// Categories - only posts filed under one of the categories, set by handlers resolving
+	// a category with its subcategories, nil for posts of any or no category
+	Categories []primitive.ObjectID `json:"-" query:"-"`
 }
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"newsteller/internal/models"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type SearchQuery struct {
+	c *mongo.Collection
+}
+
+func NewSearchQueryRepository(collection *mongo.Collection) *SearchQuery {
+	return &SearchQuery{c: collection}
+}
+
+// Record adds a hit for the query to the query log.
+func (s *SearchQuery) Record(ctx context.Context, query string) error {
+	query = NormalizeQuery(query)
+	if query == "" {
+		return nil
+	}
+
+	_, err := s.c.UpdateOne(
+		ctx,
+		bson.M{"query": query},
+		bson.M{
+			"$inc": bson.M{"count": 1},
+			"$set": bson.M{"last_searched_at": time.Now()},
+		},
+		options.Update().SetUpsert(true),
+	)
+
+	return err
+}
+
+// FindPopular returns the most searched queries starting with the prefix.
+func (s *SearchQuery) FindPopular(ctx context.Context, prefix string, limit int) ([]models.SearchQuery, error) {
+	filter := bson.M{}
+	if prefix = NormalizeQuery(prefix); prefix != "" {
+		filter["query"] = bson.M{"$regex": "^" + regexp.QuoteMeta(prefix)}
+	}
+
+	findOptions := options.Find().
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "count", Value: -1}, {Key: "last_searched_at", Value: -1}})
+
+	cursor, err := s.c.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var queries []models.SearchQuery
+	if err = cursor.All(ctx, &queries); err != nil {
+		return nil, err
+	}
+
+	return queries, nil
+}
+
+// NormalizeQuery lowercases the query and collapses whitespace,
+// so "Go  News" and "go news" are logged as the same query.
+func NormalizeQuery(query string) string {
+	return strings.ToLower(strings.Join(strings.Fields(query), " "))
+}
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSearchQuery_RecordAndFindPopular(t *testing.T) {
+	ctx := context.Background()
+	queriesCollection := dbClient.Database("newsteller_test").Collection("search_queries_test")
+	repo := NewSearchQueryRepository(queriesCollection)
+	defer queriesCollection.DeleteMany(ctx, bson.M{})
+
+	for _, q := range []string{"Go news", "go  NEWS", "golang", "go news", "weather"} {
+		require.NoError(t, repo.Record(ctx, q))
+	}
+	require.NoError(t, repo.Record(ctx, "   "), "Blank queries should be ignored")
+
+	t.Run("Positive: Queries are normalized and counted", func(t *testing.T) {
+		queries, err := repo.FindPopular(ctx, "", 10)
+		require.NoError(t, err)
+		require.Len(t, queries, 3)
+		assert.Equal(t, "go news", queries[0].Query)
+		assert.EqualValues(t, 3, queries[0].Count)
+	})
+
+	t.Run("Positive: Filter by prefix", func(t *testing.T) {
+		queries, err := repo.FindPopular(ctx, "GO", 10)
+		require.NoError(t, err)
+		require.Len(t, queries, 2)
+		assert.Equal(t, "go news", queries[0].Query)
+		assert.Equal(t, "golang", queries[1].Query)
+	})
+
+	t.Run("Positive: Regex characters in prefix are escaped", func(t *testing.T) {
+		queries, err := repo.FindPopular(ctx, "go.*", 10)
+		require.NoError(t, err)
+		assert.Empty(t, queries)
+	})
+}
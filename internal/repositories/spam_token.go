@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	"newsteller/internal/models"
+)
+
+type SpamToken struct {
+	c *mongo.Collection
+}
+
+func NewSpamTokenRepository(collection *mongo.Collection) *SpamToken {
+	return &SpamToken{c: collection}
+}
+
+// Find returns the counts of the known tokens by token.
+func (s *SpamToken) Find(ctx context.Context, tokens []string) (map[string]models.SpamToken, error) {
+	found := make(map[string]models.SpamToken, len(tokens))
+	if len(tokens) == 0 {
+		return found, nil
+	}
+
+	cursor, err := s.c.Find(ctx, bson.M{"_id": bson.M{"$in": tokens}})
+	if err != nil {
+		zap.L().Error("could not find spam tokens", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var counts []models.SpamToken
+	if err = cursor.All(ctx, &counts); err != nil {
+		return nil, err
+	}
+	for _, count := range counts {
+		found[count.Token] = count
+	}
+
+	return found, nil
+}
+
+// Increment adds spam and ham to the counts of the tokens, negative values undo
+// earlier increments.
+func (s *SpamToken) Increment(ctx context.Context, tokens []string, spam, ham int64) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	writes := make([]mongo.WriteModel, len(tokens))
+	for i, token := range tokens {
+		writes[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": token}).
+			SetUpdate(bson.M{"$inc": bson.M{"spam": spam, "ham": ham}}).
+			SetUpsert(true)
+	}
+
+	_, err := s.c.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		zap.L().Error("could not update spam tokens", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
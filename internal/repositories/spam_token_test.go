@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"newsteller/internal/models"
+)
+
+func TestSpamToken_IncrementAndFind(t *testing.T) {
+	ctx := context.Background()
+	collection := dbClient.Database("newsteller_test").Collection("spam_tokens_test")
+	repo := NewSpamTokenRepository(collection)
+	defer collection.DeleteMany(ctx, bson.M{})
+
+	require.NoError(t, repo.Increment(ctx, []string{"cheap", "pills"}, 1, 0))
+	require.NoError(t, repo.Increment(ctx, []string{"cheap", "thanks"}, 0, 1))
+	require.NoError(t, repo.Increment(ctx, []string{"pills"}, 1, 0))
+
+	t.Run("Positive: Counts are summed per token", func(t *testing.T) {
+		counts, err := repo.Find(ctx, []string{"cheap", "pills", "thanks", "unknown"})
+		require.NoError(t, err)
+		assert.Len(t, counts, 3)
+		assert.Equal(t, models.SpamToken{Token: "cheap", Spam: 1, Ham: 1}, counts["cheap"])
+		assert.Equal(t, models.SpamToken{Token: "pills", Spam: 2}, counts["pills"])
+		assert.Equal(t, models.SpamToken{Token: "thanks", Ham: 1}, counts["thanks"])
+	})
+
+	t.Run("Positive: Training is undone with negative counts", func(t *testing.T) {
+		require.NoError(t, repo.Increment(ctx, []string{"pills"}, -1, 0))
+
+		counts, err := repo.Find(ctx, []string{"pills"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), counts["pills"].Spam)
+	})
+
+	t.Run("Positive: Nothing to increment", func(t *testing.T) {
+		assert.NoError(t, repo.Increment(ctx, nil, 1, 0))
+	})
+}
@@ -0,0 +1,308 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	"newsteller/internal/models"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type Subscriber struct {
+	c *mongo.Collection
+}
+
+func NewSubscriberRepository(collection *mongo.Collection) *Subscriber {
+	return &Subscriber{c: collection}
+}
+
+func (s *Subscriber) Create(ctx context.Context, subscriber *models.Subscriber) (*primitive.ObjectID, error) {
+	res, err := s.c.InsertOne(ctx, subscriber)
+	if err != nil {
+		zap.L().Error("could not insert subscriber", zap.Error(err))
+		return nil, err
+	}
+
+	insertedID, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		zap.L().Error("failed to convert InsertedID to ObjectID")
+		return nil, fmt.Errorf("failed to convert InsertedID to ObjectID")
+	}
+
+	return &insertedID, nil
+}
+
+func (s *Subscriber) FindByID(ctx context.Context, id string) (*models.Subscriber, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		zap.L().Error("could not convert string ID to primitive.ObjectID", zap.Error(err))
+		return nil, err
+	}
+
+	var subscriber models.Subscriber
+	err = s.c.FindOne(ctx, bson.M{"_id": objectID}).Decode(&subscriber)
+	if err != nil {
+		zap.L().Error("could not find subscriber by id", zap.String("id", id), zap.Error(err))
+		return nil, err
+	}
+
+	return &subscriber, nil
+}
+
+// FindByEmail returns mongo.ErrNoDocuments when nobody subscribed with the email.
+func (s *Subscriber) FindByEmail(ctx context.Context, email string) (*models.Subscriber, error) {
+	var subscriber models.Subscriber
+	err := s.c.FindOne(ctx, bson.M{"email": NormalizeEmail(email)}).Decode(&subscriber)
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscriber, nil
+}
+
+func (s *Subscriber) FindPaginated(
+	ctx context.Context,
+	query *PaginatedSearchQuery,
+) ([]models.Subscriber, int64, error) {
+	filter := bson.M{}
+	if query.Keyword != "" {
+		filter["email"] = bson.M{"$regex": regexp.QuoteMeta(query.Keyword), "$options": "i"}
+	}
+
+	skip := (query.Page - 1) * query.Limit
+	findOptions := options.Find().
+		SetSkip(int64(skip)).
+		SetLimit(int64(query.Limit)).
+		SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := s.c.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var subscribers []models.Subscriber
+	if err = cursor.All(ctx, &subscribers); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.c.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return subscribers, total, nil
+}
+
+// UpdateStatus moves the subscriber to the provided status and stamps the matching timestamp.
+func (s *Subscriber) UpdateStatus(
+	ctx context.Context,
+	id primitive.ObjectID,
+	status models.SubscriberStatus,
+	at time.Time,
+) error {
+	set := bson.M{
+		"status":     status,
+		"updated_at": at,
+	}
+	switch status {
+	case models.SubscriberActive:
+		set["confirmed_at"] = at
+		set["last_active_at"] = at
+	case models.SubscriberUnsubscribed:
+		set["unsubscribed_at"] = at
+	}
+
+	result, err := s.c.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": set})
+	if err != nil {
+		zap.L().Error("could not update subscriber status", zap.String("id", id.Hex()), zap.Error(err))
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("no subscriber found with ID: %s", id.Hex())
+	}
+
+	return nil
+}
+
+// Suppress moves the subscriber to the bounced or complained status, so no more mail is sent to the address.
+func (s *Subscriber) Suppress(
+	ctx context.Context,
+	id primitive.ObjectID,
+	status models.SubscriberStatus,
+	reason string,
+	at time.Time,
+) error {
+	return s.set(ctx, id, bson.M{
+		"status":             status,
+		"suppressed_at":      at,
+		"suppression_reason": reason,
+		"updated_at":         at,
+	})
+}
+
+// FindSuppressed returns the most recently suppressed subscribers and the total of suppressed subscribers.
+func (s *Subscriber) FindSuppressed(ctx context.Context, limit int) ([]models.Subscriber, int64, error) {
+	filter := bson.M{"status": bson.M{"$in": []models.SubscriberStatus{
+		models.SubscriberBounced,
+		models.SubscriberComplained,
+	}}}
+
+	findOptions := options.Find().
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "suppressed_at", Value: -1}})
+	cursor, err := s.c.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var subscribers []models.Subscriber
+	if err = cursor.All(ctx, &subscribers); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.c.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return subscribers, total, nil
+}
+
+// FindDigestRecipients returns active subscribers that receive digests.
+func (s *Subscriber) FindDigestRecipients(ctx context.Context) ([]models.Subscriber, error) {
+	filter := bson.M{
+		"status":           models.SubscriberActive,
+		"digest_frequency": bson.M{"$ne": models.DigestNone},
+	}
+
+	cursor, err := s.c.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var subscribers []models.Subscriber
+	if err = cursor.All(ctx, &subscribers); err != nil {
+		return nil, err
+	}
+
+	return subscribers, nil
+}
+
+// FindBySegment returns active subscribers matching the segment.
+func (s *Subscriber) FindBySegment(ctx context.Context, segment models.Segment) ([]models.Subscriber, error) {
+	cursor, err := s.c.Find(ctx, SegmentFilter(segment), options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var subscribers []models.Subscriber
+	if err = cursor.All(ctx, &subscribers); err != nil {
+		return nil, err
+	}
+
+	return subscribers, nil
+}
+
+// CountBySegment returns the number of active subscribers matching the segment.
+func (s *Subscriber) CountBySegment(ctx context.Context, segment models.Segment) (int64, error) {
+	return s.c.CountDocuments(ctx, SegmentFilter(segment))
+}
+
+// SegmentFilter builds the filter of active subscribers matching the segment.
+func SegmentFilter(segment models.Segment) bson.M {
+	filter := bson.M{"status": models.SubscriberActive}
+	if segment.Source != "" {
+		filter["source"] = segment.Source
+	}
+
+	createdAt := bson.M{}
+	if !segment.SubscribedAfter.IsZero() {
+		createdAt["$gte"] = segment.SubscribedAfter
+	}
+	if !segment.SubscribedBefore.IsZero() {
+		createdAt["$lt"] = segment.SubscribedBefore
+	}
+	if len(createdAt) > 0 {
+		filter["created_at"] = createdAt
+	}
+
+	var and []bson.M
+	if len(segment.Topics) > 0 {
+		// subscribers following neither topics nor categories follow everything, like in Subscriber.Follows
+		and = append(and, bson.M{"$or": []bson.M{
+			{"topics": bson.M{"$in": segment.Topics}},
+			{"topics.0": bson.M{"$exists": false}, "categories.0": bson.M{"$exists": false}},
+		}})
+	}
+	if segment.ActiveWithinDays > 0 {
+		// subscribers confirmed before activity was tracked count as active since confirmation
+		since := time.Now().AddDate(0, 0, -segment.ActiveWithinDays)
+		and = append(and, bson.M{"$or": []bson.M{
+			{"last_active_at": bson.M{"$gte": since}},
+			{"last_active_at": bson.M{"$exists": false}, "confirmed_at": bson.M{"$gte": since}},
+		}})
+	}
+	if len(and) > 0 {
+		filter["$and"] = and
+	}
+
+	return filter
+}
+
+// UpdateDigestSchedule stores when the subscriber wants to receive digests.
+func (s *Subscriber) UpdateDigestSchedule(ctx context.Context, subscriber *models.Subscriber) error {
+	return s.set(ctx, subscriber.ID, bson.M{
+		"digest_frequency": subscriber.DigestFrequency,
+		"digest_hour":      subscriber.DigestHour,
+		"digest_weekday":   subscriber.DigestWeekday,
+		"timezone":         subscriber.Timezone,
+		"updated_at":       time.Now(),
+	})
+}
+
+// UpdatePreferences stores the digest schedule, followed topics and categories chosen on
+// the preferences page, marking the subscriber active.
+func (s *Subscriber) UpdatePreferences(ctx context.Context, subscriber *models.Subscriber) error {
+	now := time.Now()
+	return s.set(ctx, subscriber.ID, bson.M{
+		"digest_frequency": subscriber.DigestFrequency,
+		"digest_hour":      subscriber.DigestHour,
+		"timezone":         subscriber.Timezone,
+		"topics":           subscriber.Topics,
+		"categories":       subscriber.Categories,
+		"tracking_opt_out": subscriber.TrackingOptOut,
+		"last_active_at":   now,
+		"updated_at":       now,
+	})
+}
+
+func (s *Subscriber) SetLastDigestAt(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	return s.set(ctx, id, bson.M{"last_digest_at": at})
+}
+
+func (s *Subscriber) set(ctx context.Context, id primitive.ObjectID, fields bson.M) error {
+	result, err := s.c.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": fields})
+	if err != nil {
+		zap.L().Error("could not update subscriber", zap.String("id", id.Hex()), zap.Error(err))
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("no subscriber found with ID: %s", id.Hex())
+	}
+
+	return nil
+}
+
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
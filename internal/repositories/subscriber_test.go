@@ -0,0 +1,319 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/internal/models"
+)
+
+func TestSubscriber_CreateAndFind(t *testing.T) {
+	ctx := context.Background()
+	subscribersCollection := dbClient.Database("newsteller_test").Collection("subscribers_test")
+	repo := NewSubscriberRepository(subscribersCollection)
+	defer subscribersCollection.DeleteMany(ctx, bson.M{})
+
+	id, err := repo.Create(ctx, &models.Subscriber{
+		Email:     "reader@example.com",
+		Status:    models.SubscriberPending,
+		Source:    "home",
+		CreatedAt: time.Now(),
+	})
+	require.NoError(t, err)
+
+	t.Run("Positive: Find by ID", func(t *testing.T) {
+		subscriber, err := repo.FindByID(ctx, id.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, "reader@example.com", subscriber.Email)
+		assert.Equal(t, models.SubscriberPending, subscriber.Status)
+	})
+
+	t.Run("Positive: Find by email is case insensitive", func(t *testing.T) {
+		subscriber, err := repo.FindByEmail(ctx, "  Reader@Example.com ")
+		require.NoError(t, err)
+		assert.Equal(t, *id, subscriber.ID)
+	})
+
+	t.Run("Negative: Unknown email", func(t *testing.T) {
+		_, err := repo.FindByEmail(ctx, "nobody@example.com")
+		assert.Equal(t, mongo.ErrNoDocuments, err)
+	})
+}
+
+func TestSubscriber_UpdateStatus(t *testing.T) {
+	ctx := context.Background()
+	subscribersCollection := dbClient.Database("newsteller_test").Collection("subscribers_test")
+	repo := NewSubscriberRepository(subscribersCollection)
+	defer subscribersCollection.DeleteMany(ctx, bson.M{})
+
+	id, err := repo.Create(ctx, &models.Subscriber{Email: "reader@example.com", Status: models.SubscriberPending})
+	require.NoError(t, err)
+
+	t.Run("Positive: Confirm stamps confirmed_at", func(t *testing.T) {
+		require.NoError(t, repo.UpdateStatus(ctx, *id, models.SubscriberActive, time.Now()))
+		subscriber, err := repo.FindByID(ctx, id.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, models.SubscriberActive, subscriber.Status)
+		assert.False(t, subscriber.ConfirmedAt.IsZero())
+		assert.True(t, subscriber.UnsubscribedAt.IsZero())
+	})
+
+	t.Run("Positive: Unsubscribe stamps unsubscribed_at", func(t *testing.T) {
+		require.NoError(t, repo.UpdateStatus(ctx, *id, models.SubscriberUnsubscribed, time.Now()))
+		subscriber, err := repo.FindByID(ctx, id.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, models.SubscriberUnsubscribed, subscriber.Status)
+		assert.False(t, subscriber.UnsubscribedAt.IsZero())
+	})
+
+	t.Run("Negative: Unknown subscriber", func(t *testing.T) {
+		err := repo.UpdateStatus(ctx, primitive.NewObjectID(), models.SubscriberActive, time.Now())
+		assert.ErrorContains(t, err, "no subscriber found")
+	})
+}
+
+func TestSubscriber_FindPaginated(t *testing.T) {
+	ctx := context.Background()
+	subscribersCollection := dbClient.Database("newsteller_test").Collection("subscribers_test")
+	repo := NewSubscriberRepository(subscribersCollection)
+	defer subscribersCollection.DeleteMany(ctx, bson.M{})
+
+	for i, email := range []string{"a@example.com", "b@example.com", "c@other.org"} {
+		_, err := repo.Create(ctx, &models.Subscriber{
+			Email:     email,
+			Status:    models.SubscriberActive,
+			CreatedAt: time.Now().Add(time.Duration(i) * time.Minute),
+		})
+		require.NoError(t, err)
+	}
+
+	subscribers, total, err := repo.FindPaginated(ctx, &PaginatedSearchQuery{Page: 1, Limit: 2})
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, total)
+	require.Len(t, subscribers, 2)
+	assert.Equal(t, "c@other.org", subscribers[0].Email, "Newest subscribers should come first")
+
+	subscribers, total, err = repo.FindPaginated(ctx, &PaginatedSearchQuery{Page: 1, Limit: 10, Keyword: "example.com"})
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, total)
+	assert.Len(t, subscribers, 2)
+}
+
+func TestSubscriber_FindDigestRecipients(t *testing.T) {
+	ctx := context.Background()
+	subscribersCollection := dbClient.Database("newsteller_test").Collection("subscribers_test")
+	repo := NewSubscriberRepository(subscribersCollection)
+	defer subscribersCollection.DeleteMany(ctx, bson.M{})
+
+	weeklyID, err := repo.Create(ctx, &models.Subscriber{Email: "weekly@example.com", Status: models.SubscriberActive})
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, &models.Subscriber{
+		Email:           "none@example.com",
+		Status:          models.SubscriberActive,
+		DigestFrequency: models.DigestNone,
+	})
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, &models.Subscriber{Email: "pending@example.com", Status: models.SubscriberPending})
+	require.NoError(t, err)
+
+	t.Run("Positive: Only active subscribers receiving digests", func(t *testing.T) {
+		subscribers, err := repo.FindDigestRecipients(ctx)
+		require.NoError(t, err)
+		require.Len(t, subscribers, 1)
+		assert.Equal(t, *weeklyID, subscribers[0].ID)
+		assert.Equal(t, models.DigestWeekly, subscribers[0].Frequency())
+	})
+
+	t.Run("Positive: Update digest schedule", func(t *testing.T) {
+		err := repo.UpdateDigestSchedule(ctx, &models.Subscriber{
+			ID:              *weeklyID,
+			DigestFrequency: models.DigestDaily,
+			DigestHour:      7,
+			Timezone:        "Europe/Kyiv",
+		})
+		require.NoError(t, err)
+
+		subscriber, err := repo.FindByID(ctx, weeklyID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, models.DigestDaily, subscriber.DigestFrequency)
+		assert.Equal(t, 7, subscriber.DigestHour)
+		assert.Equal(t, "Europe/Kyiv", subscriber.Timezone)
+	})
+
+	t.Run("Negative: Unknown subscriber", func(t *testing.T) {
+		err := repo.SetLastDigestAt(ctx, primitive.NewObjectID(), time.Now())
+		assert.ErrorContains(t, err, "no subscriber found")
+	})
+}
+
+func TestSubscriber_FindBySegment(t *testing.T) {
+	ctx := context.Background()
+	subscribersCollection := dbClient.Database("newsteller_test").Collection("subscribers_test")
+	repo := NewSubscriberRepository(subscribersCollection)
+	defer subscribersCollection.DeleteMany(ctx, bson.M{})
+
+	now := time.Now()
+	oldID, err := repo.Create(ctx, &models.Subscriber{
+		Email:     "old@example.com",
+		Status:    models.SubscriberActive,
+		Source:    "home",
+		CreatedAt: now.Add(-30 * 24 * time.Hour),
+	})
+	require.NoError(t, err)
+	newID, err := repo.Create(ctx, &models.Subscriber{
+		Email:     "new@example.com",
+		Status:    models.SubscriberActive,
+		Source:    "post",
+		CreatedAt: now,
+	})
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, &models.Subscriber{Email: "gone@example.com", Status: models.SubscriberUnsubscribed})
+	require.NoError(t, err)
+
+	t.Run("Positive: Empty segment matches all active subscribers", func(t *testing.T) {
+		subscribers, err := repo.FindBySegment(ctx, models.Segment{})
+		require.NoError(t, err)
+		require.Len(t, subscribers, 2)
+		assert.Equal(t, *oldID, subscribers[0].ID)
+	})
+
+	t.Run("Positive: Segment by source", func(t *testing.T) {
+		subscribers, err := repo.FindBySegment(ctx, models.Segment{Source: "post"})
+		require.NoError(t, err)
+		require.Len(t, subscribers, 1)
+		assert.Equal(t, *newID, subscribers[0].ID)
+	})
+
+	t.Run("Positive: Segment by signup date", func(t *testing.T) {
+		count, err := repo.CountBySegment(ctx, models.Segment{SubscribedBefore: now.Add(-24 * time.Hour)})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("Positive: Segment by topic includes subscribers following everything", func(t *testing.T) {
+		_, err := repo.Create(ctx, &models.Subscriber{
+			Email:     "go@example.com",
+			Status:    models.SubscriberActive,
+			Topics:    []string{"go"},
+			CreatedAt: now,
+		})
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, &models.Subscriber{
+			Email:     "rust@example.com",
+			Status:    models.SubscriberActive,
+			Topics:    []string{"rust"},
+			CreatedAt: now,
+		})
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, &models.Subscriber{
+			Email:      "category@example.com",
+			Status:     models.SubscriberActive,
+			Categories: []primitive.ObjectID{primitive.NewObjectID()},
+			CreatedAt:  now,
+		})
+		require.NoError(t, err)
+
+		count, err := repo.CountBySegment(ctx, models.Segment{Topics: []string{"go"}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+	})
+
+	t.Run("Positive: Segment by activity", func(t *testing.T) {
+		require.NoError(t, repo.UpdateStatus(ctx, *newID, models.SubscriberActive, now))
+
+		subscribers, err := repo.FindBySegment(ctx, models.Segment{Source: "post", ActiveWithinDays: 7})
+		require.NoError(t, err)
+		require.Len(t, subscribers, 1)
+		assert.Equal(t, *newID, subscribers[0].ID)
+
+		count, err := repo.CountBySegment(ctx, models.Segment{Source: "home", ActiveWithinDays: 7})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+	})
+}
+
+func TestSubscriber_UpdatePreferences(t *testing.T) {
+	ctx := context.Background()
+	subscribersCollection := dbClient.Database("newsteller_test").Collection("subscribers_test")
+	repo := NewSubscriberRepository(subscribersCollection)
+	defer subscribersCollection.DeleteMany(ctx, bson.M{})
+
+	id, err := repo.Create(ctx, &models.Subscriber{Email: "reader@example.com", Status: models.SubscriberActive})
+	require.NoError(t, err)
+
+	t.Run("Positive: Store topics and schedule", func(t *testing.T) {
+		categoryID := primitive.NewObjectID()
+		err := repo.UpdatePreferences(ctx, &models.Subscriber{
+			ID:              *id,
+			DigestFrequency: models.DigestDaily,
+			DigestHour:      18,
+			Timezone:        "Europe/Kyiv",
+			Topics:          []string{"go", "databases"},
+			Categories:      []primitive.ObjectID{categoryID},
+			TrackingOptOut:  true,
+		})
+		require.NoError(t, err)
+
+		subscriber, err := repo.FindByID(ctx, id.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, models.DigestDaily, subscriber.DigestFrequency)
+		assert.Equal(t, 18, subscriber.DigestHour)
+		assert.Equal(t, []string{"go", "databases"}, subscriber.Topics)
+		assert.Equal(t, []primitive.ObjectID{categoryID}, subscriber.Categories)
+		assert.True(t, subscriber.TrackingOptOut)
+		assert.False(t, subscriber.LastActiveAt.IsZero())
+	})
+
+	t.Run("Negative: Unknown subscriber", func(t *testing.T) {
+		err := repo.UpdatePreferences(ctx, &models.Subscriber{ID: primitive.NewObjectID()})
+		assert.ErrorContains(t, err, "no subscriber found")
+	})
+}
+
+func TestSubscriber_Suppress(t *testing.T) {
+	ctx := context.Background()
+	subscribersCollection := dbClient.Database("newsteller_test").Collection("subscribers_test")
+	repo := NewSubscriberRepository(subscribersCollection)
+	defer subscribersCollection.DeleteMany(ctx, bson.M{})
+
+	bouncedID, err := repo.Create(ctx, &models.Subscriber{Email: "gone@example.com", Status: models.SubscriberActive})
+	require.NoError(t, err)
+	complainedID, err := repo.Create(ctx, &models.Subscriber{Email: "angry@example.com", Status: models.SubscriberActive})
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, &models.Subscriber{Email: "reader@example.com", Status: models.SubscriberActive})
+	require.NoError(t, err)
+
+	now := time.Now()
+	require.NoError(t, repo.Suppress(ctx, *bouncedID, models.SubscriberBounced, "550 5.1.1 user unknown", now.Add(-time.Hour)))
+	require.NoError(t, repo.Suppress(ctx, *complainedID, models.SubscriberComplained, "abuse", now))
+
+	t.Run("Positive: Suppressed subscribers are inactive", func(t *testing.T) {
+		subscriber, err := repo.FindByID(ctx, bouncedID.Hex())
+		require.NoError(t, err)
+		assert.True(t, subscriber.Suppressed())
+		assert.Equal(t, "550 5.1.1 user unknown", subscriber.SuppressionReason)
+
+		count, err := repo.CountBySegment(ctx, models.Segment{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("Positive: Most recently suppressed first", func(t *testing.T) {
+		subscribers, total, err := repo.FindSuppressed(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		require.Len(t, subscribers, 1)
+		assert.Equal(t, "angry@example.com", subscribers[0].Email)
+	})
+
+	t.Run("Negative: Unknown subscriber", func(t *testing.T) {
+		err := repo.Suppress(ctx, primitive.NewObjectID(), models.SubscriberBounced, "", now)
+		assert.ErrorContains(t, err, "no subscriber found")
+	})
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	"newsteller/internal/models"
+	"time"
+)
+
+type Tag struct {
+	c *mongo.Collection
+}
+
+func NewTagRepository(collection *mongo.Collection) *Tag {
+	return &Tag{c: collection}
+}
+
+// Ensure registers the tags not known yet, the names must be normalized.
+func (t *Tag) Ensure(ctx context.Context, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	writes := make([]mongo.WriteModel, len(names))
+	for i, name := range names {
+		writes[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"name": name}).
+			SetUpdate(bson.M{"$setOnInsert": bson.M{"name": name, "created_at": now}}).
+			SetUpsert(true)
+	}
+
+	_, err := t.c.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		zap.L().Error("could not register tags", zap.Strings("tags", names), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// All returns every known tag sorted by name.
+func (t *Tag) All(ctx context.Context) ([]models.Tag, error) {
+	cursor, err := t.c.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		zap.L().Error("could not find tags", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tags []models.Tag
+	if err = cursor.All(ctx, &tags); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
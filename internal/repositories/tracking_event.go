@@ -0,0 +1,185 @@
+package repositories
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"newsteller/internal/models"
+	"time"
+)
+
+type TrackingEvent struct {
+	c *mongo.Collection
+}
+
+func NewTrackingEventRepository(collection *mongo.Collection) *TrackingEvent {
+	return &TrackingEvent{c: collection}
+}
+
+func (t *TrackingEvent) Record(ctx context.Context, event *models.TrackingEvent) error {
+	_, err := t.c.InsertOne(ctx, event)
+	if err != nil {
+		zap.L().Error("could not insert tracking event", zap.String("kind", string(event.Kind)), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// IssueTotals returns the totals of the issues, issues without events are missing from the result.
+func (t *TrackingEvent) IssueTotals(
+	ctx context.Context,
+	issueIDs []primitive.ObjectID,
+) (map[primitive.ObjectID]models.TrackingTotals, error) {
+	var groups []struct {
+		ID struct {
+			IssueID primitive.ObjectID       `bson:"issue_id"`
+			Kind    models.TrackingEventKind `bson:"kind"`
+		} `bson:"_id"`
+		Count  int `bson:"count"`
+		Unique int `bson:"unique"`
+	}
+	err := t.aggregate(ctx, &groups, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"issue_id": bson.M{"$in": issueIDs}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":         bson.M{"issue_id": "$issue_id", "kind": "$kind"},
+			"count":       bson.M{"$sum": 1},
+			"subscribers": bson.M{"$addToSet": "$subscriber_id"},
+		}}},
+		{{Key: "$project", Value: bson.M{"count": 1, "unique": bson.M{"$size": "$subscribers"}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[primitive.ObjectID]models.TrackingTotals)
+	for _, group := range groups {
+		issueTotals := totals[group.ID.IssueID]
+		switch group.ID.Kind {
+		case models.TrackingOpen:
+			issueTotals.Opens, issueTotals.UniqueOpens = group.Count, group.Unique
+		case models.TrackingClick:
+			issueTotals.Clicks, issueTotals.UniqueClicks = group.Count, group.Unique
+		}
+		totals[group.ID.IssueID] = issueTotals
+	}
+
+	return totals, nil
+}
+
+// TopLinks returns the most clicked links of the issue.
+func (t *TrackingEvent) TopLinks(ctx context.Context, issueID primitive.ObjectID, limit int) ([]models.LinkStats, error) {
+	return t.topClicks(ctx, bson.M{"issue_id": issueID}, "$url", limit)
+}
+
+// TopPosts returns the most clicked posts over digests and issues since the provided time.
+func (t *TrackingEvent) TopPosts(ctx context.Context, since time.Time, limit int) ([]models.LinkStats, error) {
+	return t.topClicks(ctx, bson.M{
+		"post_id":    bson.M{"$exists": true},
+		"created_at": bson.M{"$gte": since},
+	}, "$post_id", limit)
+}
+
+// Daily returns the events per day since the provided time, for one issue or for all
+// emails when the issue ID is zero. Days without events are included.
+func (t *TrackingEvent) Daily(ctx context.Context, issueID primitive.ObjectID, since time.Time) ([]models.DayStats, error) {
+	since = since.UTC().Truncate(24 * time.Hour)
+	match := bson.M{"created_at": bson.M{"$gte": since}}
+	if !issueID.IsZero() {
+		match["issue_id"] = issueID
+	}
+
+	var groups []struct {
+		ID struct {
+			Day  string                   `bson:"day"`
+			Kind models.TrackingEventKind `bson:"kind"`
+		} `bson:"_id"`
+		Count int `bson:"count"`
+	}
+	err := t.aggregate(ctx, &groups, mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"day":  bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$created_at"}},
+				"kind": "$kind",
+			},
+			"count": bson.M{"$sum": 1},
+		}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var days []models.DayStats
+	index := make(map[string]int)
+	for day := since; !day.After(time.Now()); day = day.AddDate(0, 0, 1) {
+		index[day.Format(time.DateOnly)] = len(days)
+		days = append(days, models.DayStats{Day: day})
+	}
+	for _, group := range groups {
+		i, ok := index[group.ID.Day]
+		if !ok {
+			continue
+		}
+		switch group.ID.Kind {
+		case models.TrackingOpen:
+			days[i].Opens = group.Count
+		case models.TrackingClick:
+			days[i].Clicks = group.Count
+		}
+	}
+
+	return days, nil
+}
+
+func (t *TrackingEvent) topClicks(ctx context.Context, match bson.M, groupBy string, limit int) ([]models.LinkStats, error) {
+	match["kind"] = models.TrackingClick
+
+	var groups []struct {
+		URL    string             `bson:"url"`
+		PostID primitive.ObjectID `bson:"post_id"`
+		Clicks int                `bson:"clicks"`
+		Unique int                `bson:"unique"`
+	}
+	err := t.aggregate(ctx, &groups, mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":         groupBy,
+			"url":         bson.M{"$first": "$url"},
+			"post_id":     bson.M{"$first": "$post_id"},
+			"clicks":      bson.M{"$sum": 1},
+			"subscribers": bson.M{"$addToSet": "$subscriber_id"},
+		}}},
+		{{Key: "$project", Value: bson.M{"url": 1, "post_id": 1, "clicks": 1, "unique": bson.M{"$size": "$subscribers"}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "clicks", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	links := make([]models.LinkStats, len(groups))
+	for i, group := range groups {
+		links[i] = models.LinkStats{
+			URL:          group.URL,
+			PostID:       group.PostID,
+			Clicks:       group.Clicks,
+			UniqueClicks: group.Unique,
+		}
+	}
+
+	return links, nil
+}
+
+func (t *TrackingEvent) aggregate(ctx context.Context, result any, pipeline mongo.Pipeline) error {
+	cursor, err := t.c.Aggregate(ctx, pipeline)
+	if err != nil {
+		zap.L().Error("could not aggregate tracking events", zap.Error(err))
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	return cursor.All(ctx, result)
+}
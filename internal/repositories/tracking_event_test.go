@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"newsteller/internal/models"
+)
+
+func TestTrackingEvent_Stats(t *testing.T) {
+	ctx := context.Background()
+	eventsCollection := dbClient.Database("newsteller_test").Collection("tracking_events_test")
+	repo := NewTrackingEventRepository(eventsCollection)
+	defer eventsCollection.DeleteMany(ctx, bson.M{})
+
+	now := time.Now()
+	issueID, otherIssueID := primitive.NewObjectID(), primitive.NewObjectID()
+	reader, otherReader := primitive.NewObjectID(), primitive.NewObjectID()
+	post, otherPost := primitive.NewObjectID(), primitive.NewObjectID()
+
+	events := []models.TrackingEvent{
+		{Kind: models.TrackingOpen, Source: models.TrackingSourceIssue, IssueID: issueID, SubscriberID: reader},
+		{Kind: models.TrackingOpen, Source: models.TrackingSourceIssue, IssueID: issueID, SubscriberID: reader},
+		{Kind: models.TrackingOpen, Source: models.TrackingSourceIssue, IssueID: issueID, SubscriberID: otherReader},
+		{Kind: models.TrackingClick, Source: models.TrackingSourceIssue, IssueID: issueID, SubscriberID: reader, PostID: post, URL: "https://news.example.com/posts/1"},
+		{Kind: models.TrackingClick, Source: models.TrackingSourceIssue, IssueID: issueID, SubscriberID: otherReader, PostID: post, URL: "https://news.example.com/posts/1"},
+		{Kind: models.TrackingClick, Source: models.TrackingSourceIssue, IssueID: issueID, SubscriberID: reader, PostID: otherPost, URL: "https://news.example.com/posts/2"},
+		{Kind: models.TrackingClick, Source: models.TrackingSourceDigest, SubscriberID: reader, PostID: otherPost, URL: "https://news.example.com/posts/2"},
+		{Kind: models.TrackingClick, Source: models.TrackingSourceDigest, SubscriberID: otherReader, PostID: otherPost, URL: "https://news.example.com/posts/2"},
+	}
+	for i := range events {
+		events[i].CreatedAt = now
+		require.NoError(t, repo.Record(ctx, &events[i]))
+	}
+
+	t.Run("Positive: Issue totals", func(t *testing.T) {
+		totals, err := repo.IssueTotals(ctx, []primitive.ObjectID{issueID, otherIssueID})
+		require.NoError(t, err)
+		assert.Equal(t, models.TrackingTotals{Opens: 3, UniqueOpens: 2, Clicks: 3, UniqueClicks: 2}, totals[issueID])
+		assert.NotContains(t, totals, otherIssueID)
+	})
+
+	t.Run("Positive: Top links of the issue", func(t *testing.T) {
+		links, err := repo.TopLinks(ctx, issueID, 10)
+		require.NoError(t, err)
+		require.Len(t, links, 2)
+		assert.Equal(t, "https://news.example.com/posts/1", links[0].URL)
+		assert.Equal(t, 2, links[0].Clicks)
+		assert.Equal(t, 2, links[0].UniqueClicks)
+		assert.Equal(t, 1, links[1].Clicks)
+	})
+
+	t.Run("Positive: Top posts over digests and issues", func(t *testing.T) {
+		posts, err := repo.TopPosts(ctx, now.Add(-time.Hour), 1)
+		require.NoError(t, err)
+		require.Len(t, posts, 1)
+		assert.Equal(t, otherPost, posts[0].PostID)
+		assert.Equal(t, 3, posts[0].Clicks)
+		assert.Equal(t, 2, posts[0].UniqueClicks)
+	})
+
+	t.Run("Positive: Daily events include empty days", func(t *testing.T) {
+		days, err := repo.Daily(ctx, issueID, now.AddDate(0, 0, -2))
+		require.NoError(t, err)
+		require.Len(t, days, 3)
+		assert.Equal(t, models.DayStats{Day: days[0].Day}, days[0])
+		assert.Equal(t, 3, days[2].Opens)
+		assert.Equal(t, 3, days[2].Clicks)
+	})
+}
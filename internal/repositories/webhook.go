@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	"newsteller/internal/models"
+	"time"
+)
+
+type Webhook struct {
+	c *mongo.Collection
+}
+
+func NewWebhookRepository(collection *mongo.Collection) *Webhook {
+	return &Webhook{c: collection}
+}
+
+func (w *Webhook) Create(ctx context.Context, webhook *models.Webhook) (*primitive.ObjectID, error) {
+	res, err := w.c.InsertOne(ctx, webhook)
+	if err != nil {
+		zap.L().Error("could not insert webhook", zap.Error(err))
+		return nil, err
+	}
+
+	insertedID, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		zap.L().Error("failed to convert InsertedID to ObjectID")
+		return nil, fmt.Errorf("failed to convert InsertedID to ObjectID")
+	}
+
+	return &insertedID, nil
+}
+
+func (w *Webhook) FindByID(ctx context.Context, id string) (*models.Webhook, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		zap.L().Error("could not convert string ID to primitive.ObjectID", zap.Error(err))
+		return nil, err
+	}
+
+	var webhook models.Webhook
+	err = w.c.FindOne(ctx, bson.M{"_id": objectID}).Decode(&webhook)
+	if err != nil {
+		zap.L().Error("could not find webhook by id", zap.String("id", id), zap.Error(err))
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+// All returns every webhook, the newest first.
+func (w *Webhook) All(ctx context.Context) ([]models.Webhook, error) {
+	return w.find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+}
+
+// FindActive returns the enabled webhooks, the event filters are left to the caller.
+func (w *Webhook) FindActive(ctx context.Context) ([]models.Webhook, error) {
+	return w.find(ctx, bson.M{"active": true}, options.Find())
+}
+
+// Update stores the settings of the webhook, the secret never changes.
+func (w *Webhook) Update(ctx context.Context, webhook *models.Webhook) error {
+	result, err := w.c.UpdateOne(ctx, bson.M{"_id": webhook.ID}, bson.M{"$set": bson.M{
+		"url":         webhook.URL,
+		"description": webhook.Description,
+		"events":      webhook.Events,
+		"active":      webhook.Active,
+		"updated_at":  time.Now(),
+	}})
+	if err != nil {
+		zap.L().Error("could not update webhook", zap.String("id", webhook.ID.Hex()), zap.Error(err))
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("no webhook found with ID: %s", webhook.ID.Hex())
+	}
+
+	return nil
+}
+
+func (w *Webhook) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := w.c.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		zap.L().Error("could not delete webhook", zap.String("id", id.Hex()), zap.Error(err))
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("no webhook found with ID: %s", id.Hex())
+	}
+
+	return nil
+}
+
+func (w *Webhook) find(ctx context.Context, filter bson.M, findOptions *options.FindOptions) ([]models.Webhook, error) {
+	cursor, err := w.c.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var webhooks []models.Webhook
+	if err = cursor.All(ctx, &webhooks); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	"newsteller/internal/models"
+	"time"
+)
+
+type WebhookDelivery struct {
+	c *mongo.Collection
+}
+
+func NewWebhookDeliveryRepository(collection *mongo.Collection) *WebhookDelivery {
+	return &WebhookDelivery{c: collection}
+}
+
+func (d *WebhookDelivery) Create(ctx context.Context, delivery *models.WebhookDelivery) (*primitive.ObjectID, error) {
+	res, err := d.c.InsertOne(ctx, delivery)
+	if mongo.IsDuplicateKeyError(err) {
+		// the event was already queued for the webhook, the caller decides whether it is an error
+		return nil, err
+	}
+	if err != nil {
+		zap.L().Error("could not insert webhook delivery", zap.Error(err))
+		return nil, err
+	}
+
+	insertedID, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		zap.L().Error("failed to convert InsertedID to ObjectID")
+		return nil, fmt.Errorf("failed to convert InsertedID to ObjectID")
+	}
+
+	return &insertedID, nil
+}
+
+func (d *WebhookDelivery) FindByID(ctx context.Context, id string) (*models.WebhookDelivery, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		zap.L().Error("could not convert string ID to primitive.ObjectID", zap.Error(err))
+		return nil, err
+	}
+
+	var delivery models.WebhookDelivery
+	err = d.c.FindOne(ctx, bson.M{"_id": objectID}).Decode(&delivery)
+	if err != nil {
+		zap.L().Error("could not find webhook delivery by id", zap.String("id", id), zap.Error(err))
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+// FindByWebhook returns the latest deliveries of the webhook, the newest first.
+func (d *WebhookDelivery) FindByWebhook(
+	ctx context.Context,
+	webhookID primitive.ObjectID,
+	limit int,
+) ([]models.WebhookDelivery, error) {
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit))
+	cursor, err := d.c.Find(ctx, bson.M{"webhook_id": webhookID}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var deliveries []models.WebhookDelivery
+	if err = cursor.All(ctx, &deliveries); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// DeleteByWebhook removes the delivery log of a deleted webhook.
+func (d *WebhookDelivery) DeleteByWebhook(ctx context.Context, webhookID primitive.ObjectID) error {
+	_, err := d.c.DeleteMany(ctx, bson.M{"webhook_id": webhookID})
+	if err != nil {
+		zap.L().Error("could not delete webhook deliveries", zap.String("webhook_id", webhookID.Hex()), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// ClaimDue locks the oldest delivery due for sending for the lease duration.
+// Deliveries stuck in sending state after their lease expired are claimed again.
+// Returns mongo.ErrNoDocuments when nothing is due.
+func (d *WebhookDelivery) ClaimDue(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+) (*models.WebhookDelivery, error) {
+	filter := bson.M{
+		"$or": []bson.M{
+			{"status": models.WebhookDeliveryPending, "next_attempt_at": bson.M{"$lte": now}},
+			{"status": models.WebhookDeliverySending, "locked_until": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":       models.WebhookDeliverySending,
+			"locked_until": now.Add(lease),
+		},
+	}
+	findOptions := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var delivery models.WebhookDelivery
+	err := d.c.FindOneAndUpdate(ctx, filter, update, findOptions).Decode(&delivery)
+	if err != nil {
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+func (d *WebhookDelivery) MarkDelivered(
+	ctx context.Context,
+	id primitive.ObjectID,
+	attempts int,
+	response models.WebhookResponse,
+	at time.Time,
+) error {
+	return d.set(ctx, id, bson.M{
+		"status":       models.WebhookDeliveryDelivered,
+		"attempts":     attempts,
+		"response":     response,
+		"delivered_at": at,
+	})
+}
+
+// MarkRetry puts the delivery back into the queue after a failed attempt.
+func (d *WebhookDelivery) MarkRetry(
+	ctx context.Context,
+	id primitive.ObjectID,
+	attempts int,
+	response models.WebhookResponse,
+	nextAttemptAt time.Time,
+	lastError string,
+) error {
+	return d.set(ctx, id, bson.M{
+		"status":          models.WebhookDeliveryPending,
+		"attempts":        attempts,
+		"response":        response,
+		"next_attempt_at": nextAttemptAt,
+		"last_error":      lastError,
+	})
+}
+
+func (d *WebhookDelivery) MarkFailed(
+	ctx context.Context,
+	id primitive.ObjectID,
+	attempts int,
+	response models.WebhookResponse,
+	lastError string,
+) error {
+	return d.set(ctx, id, bson.M{
+		"status":     models.WebhookDeliveryFailed,
+		"attempts":   attempts,
+		"response":   response,
+		"last_error": lastError,
+	})
+}
+
+func (d *WebhookDelivery) set(ctx context.Context, id primitive.ObjectID, fields bson.M) error {
+	_, err := d.c.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": fields})
+	if err != nil {
+		zap.L().Error("could not update webhook delivery", zap.String("id", id.Hex()), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/internal/models"
+)
+
+func TestWebhook_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	webhooksCollection := dbClient.Database("newsteller_test").Collection("webhooks_test")
+	repo := NewWebhookRepository(webhooksCollection)
+	defer webhooksCollection.DeleteMany(ctx, bson.M{})
+
+	id, err := repo.Create(ctx, &models.Webhook{
+		URL:       "https://example.com/hook",
+		Secret:    "secret",
+		Active:    true,
+		CreatedAt: time.Now(),
+	})
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, &models.Webhook{URL: "https://example.com/disabled", CreatedAt: time.Now()})
+	require.NoError(t, err)
+
+	t.Run("Positive: Find active webhooks", func(t *testing.T) {
+		webhooks, err := repo.FindActive(ctx)
+		require.NoError(t, err)
+		require.Len(t, webhooks, 1)
+		assert.Equal(t, *id, webhooks[0].ID)
+	})
+
+	t.Run("Positive: Update keeps the secret", func(t *testing.T) {
+		err := repo.Update(ctx, &models.Webhook{
+			ID:     *id,
+			URL:    "https://example.com/renamed",
+			Events: []models.WebhookEvent{models.WebhookPostDeleted},
+		})
+		require.NoError(t, err)
+
+		webhook, err := repo.FindByID(ctx, id.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/renamed", webhook.URL)
+		assert.Equal(t, []models.WebhookEvent{models.WebhookPostDeleted}, webhook.Events)
+		assert.False(t, webhook.Active)
+		assert.Equal(t, "secret", webhook.Secret)
+	})
+
+	t.Run("Positive: Delete webhook", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, *id))
+
+		_, err := repo.FindByID(ctx, id.Hex())
+		assert.Equal(t, mongo.ErrNoDocuments, err)
+		assert.ErrorContains(t, repo.Delete(ctx, *id), "no webhook found")
+	})
+}
+
+func TestWebhookDelivery_ClaimDue(t *testing.T) {
+	ctx := context.Background()
+	deliveriesCollection := dbClient.Database("newsteller_test").Collection("webhook_deliveries_test")
+	repo := NewWebhookDeliveryRepository(deliveriesCollection)
+	defer deliveriesCollection.DeleteMany(ctx, bson.M{})
+
+	now := time.Now()
+	webhookID := primitive.NewObjectID()
+	dueID, err := repo.Create(ctx, &models.WebhookDelivery{
+		WebhookID:     webhookID,
+		Event:         models.WebhookPostCreated,
+		Status:        models.WebhookDeliveryPending,
+		NextAttemptAt: now.Add(-time.Minute),
+		CreatedAt:     now.Add(-time.Minute),
+	})
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, &models.WebhookDelivery{
+		WebhookID:     webhookID,
+		Event:         models.WebhookPostUpdated,
+		Status:        models.WebhookDeliveryPending,
+		NextAttemptAt: now.Add(time.Hour),
+		CreatedAt:     now,
+	})
+	require.NoError(t, err)
+
+	t.Run("Positive: Claim due delivery", func(t *testing.T) {
+		delivery, err := repo.ClaimDue(ctx, now, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, *dueID, delivery.ID)
+		assert.Equal(t, models.WebhookDeliverySending, delivery.Status)
+
+		_, err = repo.ClaimDue(ctx, now, time.Minute)
+		assert.Equal(t, mongo.ErrNoDocuments, err)
+	})
+
+	t.Run("Positive: Retry keeps the response", func(t *testing.T) {
+		response := models.WebhookResponse{Status: 503, Body: "unavailable"}
+		require.NoError(t, repo.MarkRetry(ctx, *dueID, 1, response, now.Add(10*time.Minute), "unexpected status 503"))
+
+		delivery, err := repo.ClaimDue(ctx, now.Add(11*time.Minute), time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, *dueID, delivery.ID)
+		assert.Equal(t, 1, delivery.Attempts)
+		assert.Equal(t, response, delivery.Response)
+	})
+
+	t.Run("Positive: Log lists the newest deliveries first", func(t *testing.T) {
+		require.NoError(t, repo.MarkDelivered(ctx, *dueID, 2, models.WebhookResponse{Status: 200}, now))
+
+		deliveries, err := repo.FindByWebhook(ctx, webhookID, 10)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 2)
+		assert.Equal(t, models.WebhookPostUpdated, deliveries[0].Event)
+		assert.Equal(t, models.WebhookDeliveryDelivered, deliveries[1].Status)
+	})
+
+	t.Run("Positive: Delete the log of a webhook", func(t *testing.T) {
+		require.NoError(t, repo.DeleteByWebhook(ctx, webhookID))
+
+		deliveries, err := repo.FindByWebhook(ctx, webhookID, 10)
+		require.NoError(t, err)
+		assert.Empty(t, deliveries)
+	})
+}
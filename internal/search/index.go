@@ -0,0 +1,243 @@
+package search
+
+import (
+	"context"
+	"newsteller/internal/events"
+	"newsteller/internal/models"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+/**
+An in-memory prefix index over post titles, used for search-as-you-type completions.
+Every word of a title is stored in a trie, so "news" completes both "News of the day"
+and "Breaking news".
+*/
+
+type TitleIndex struct {
+	mu    sync.RWMutex
+	root  *node
+	posts map[string]models.Post
+}
+
+type node struct {
+	children map[rune]*node
+	// ids - IDs of posts having a title word ending at this node
+	ids map[string]struct{}
+}
+
+func newNode() *node {
+	return &node{children: make(map[rune]*node)}
+}
+
+func NewTitleIndex() *TitleIndex {
+	return &TitleIndex{
+		root:  newNode(),
+		posts: make(map[string]models.Post),
+	}
+}
+
+// Subscribe keeps the index in sync with the changes of posts.
+func (i *TitleIndex) Subscribe(bus *events.Bus) {
+	events.Subscribe(bus, "search", func(_ context.Context, event events.PostCreated) error {
+		i.Put(event.Post)
+		return nil
+	})
+	events.Subscribe(bus, "search", func(_ context.Context, event events.PostUpdated) error {
+		i.Put(event.Post)
+		return nil
+	})
+	events.Subscribe(bus, "search", func(_ context.Context, event events.PostDeleted) error {
+		i.Remove(event.Post.ID.Hex())
+		return nil
+	})
+}
+
+// Rebuild replaces the whole index content with the provided posts.
+func (i *TitleIndex) Rebuild(posts []models.Post) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.root = newNode()
+	i.posts = make(map[string]models.Post, len(posts))
+	for _, post := range posts {
+		i.put(post)
+	}
+}
+
+// Put adds the post to the index or refreshes its title.
+func (i *TitleIndex) Put(post models.Post) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	id := post.ID.Hex()
+	if old, ok := i.posts[id]; ok {
+		if post.CreatedAt.IsZero() {
+			post.CreatedAt = old.CreatedAt
+		}
+		i.remove(id)
+	}
+	i.put(post)
+}
+
+// Remove drops the post with the provided ID from the index.
+func (i *TitleIndex) Remove(id string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.remove(id)
+}
+
+// Suggest returns up to limit posts whose title matches the query.
+// All query words but the last one must match title words exactly,
+// the last one is treated as a prefix.
+func (i *TitleIndex) Suggest(query string, limit int) []models.Post {
+	words := tokenize(query)
+	if len(words) == 0 || limit < 1 {
+		return nil
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	var candidates map[string]struct{}
+	for n, word := range words {
+		var matched map[string]struct{}
+		if n == len(words)-1 {
+			matched = i.withPrefix(word)
+		} else {
+			matched = i.withWord(word)
+		}
+		candidates = intersect(candidates, matched, n == 0)
+		if len(candidates) == 0 {
+			return nil
+		}
+	}
+
+	normalizedQuery := strings.Join(words, " ")
+	res := make([]models.Post, 0, len(candidates))
+	for id := range candidates {
+		res = append(res, i.posts[id])
+	}
+	sort.Slice(res, func(a, b int) bool {
+		aStarts := strings.HasPrefix(strings.Join(tokenize(res[a].Title), " "), normalizedQuery)
+		bStarts := strings.HasPrefix(strings.Join(tokenize(res[b].Title), " "), normalizedQuery)
+		if aStarts != bStarts {
+			return aStarts
+		}
+		return res[a].CreatedAt.After(res[b].CreatedAt)
+	})
+	if len(res) > limit {
+		res = res[:limit]
+	}
+
+	return res
+}
+
+func (i *TitleIndex) put(post models.Post) {
+	id := post.ID.Hex()
+	i.posts[id] = models.Post{
+		ID:    post.ID,
+		Title: post.Title,
+		// suggestions link to the slug, the path of the post
+		Slug:      post.Slug,
+		CreatedAt: post.CreatedAt,
+	}
+
+	for _, word := range tokenize(post.Title) {
+		current := i.root
+		for _, r := range word {
+			next, ok := current.children[r]
+			if !ok {
+				next = newNode()
+				current.children[r] = next
+			}
+			current = next
+		}
+		if current.ids == nil {
+			current.ids = make(map[string]struct{})
+		}
+		current.ids[id] = struct{}{}
+	}
+}
+
+func (i *TitleIndex) remove(id string) {
+	post, ok := i.posts[id]
+	if !ok {
+		return
+	}
+	delete(i.posts, id)
+
+	for _, word := range tokenize(post.Title) {
+		if n := i.find(word); n != nil {
+			delete(n.ids, id)
+		}
+	}
+}
+
+func (i *TitleIndex) find(word string) *node {
+	current := i.root
+	for _, r := range word {
+		next, ok := current.children[r]
+		if !ok {
+			return nil
+		}
+		current = next
+	}
+
+	return current
+}
+
+func (i *TitleIndex) withWord(word string) map[string]struct{} {
+	n := i.find(word)
+	if n == nil {
+		return nil
+	}
+
+	return n.ids
+}
+
+func (i *TitleIndex) withPrefix(prefix string) map[string]struct{} {
+	n := i.find(prefix)
+	if n == nil {
+		return nil
+	}
+
+	res := make(map[string]struct{})
+	stack := []*node{n}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for id := range current.ids {
+			res[id] = struct{}{}
+		}
+		for _, child := range current.children {
+			stack = append(stack, child)
+		}
+	}
+
+	return res
+}
+
+func intersect(current, matched map[string]struct{}, first bool) map[string]struct{} {
+	if first {
+		return matched
+	}
+
+	res := make(map[string]struct{})
+	for id := range current {
+		if _, ok := matched[id]; ok {
+			res[id] = struct{}{}
+		}
+	}
+
+	return res
+}
+
+func tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,90 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"newsteller/internal/models"
+)
+
+func newPost(title string, age time.Duration) models.Post {
+	return models.Post{
+		ID:        primitive.NewObjectID(),
+		Title:     title,
+		CreatedAt: time.Now().Add(-age),
+	}
+}
+
+func titles(posts []models.Post) []string {
+	res := make([]string, 0, len(posts))
+	for _, post := range posts {
+		res = append(res, post.Title)
+	}
+	return res
+}
+
+func TestTitleIndex_Suggest(t *testing.T) {
+	index := NewTitleIndex()
+	index.Rebuild([]models.Post{
+		newPost("Breaking news: Go 1.24 released", 3*time.Hour),
+		newPost("News of the day", 2*time.Hour),
+		newPost("Weather forecast", time.Hour),
+		newPost("Newsletter launch", 0),
+	})
+
+	t.Run("Positive: Prefix matches any title word", func(t *testing.T) {
+		res := index.Suggest("new", 10)
+		assert.Equal(t, []string{"Newsletter launch", "News of the day", "Breaking news: Go 1.24 released"}, titles(res))
+	})
+
+	t.Run("Positive: Titles starting with the query come first", func(t *testing.T) {
+		res := index.Suggest("news", 10)
+		assert.Equal(t, "Newsletter launch", res[0].Title)
+		assert.Equal(t, "News of the day", res[1].Title)
+		assert.Len(t, res, 3)
+	})
+
+	t.Run("Positive: Leading words must match exactly", func(t *testing.T) {
+		res := index.Suggest("Breaking NEWS g", 10)
+		assert.Equal(t, []string{"Breaking news: Go 1.24 released"}, titles(res))
+
+		assert.Empty(t, index.Suggest("break news", 10))
+	})
+
+	t.Run("Positive: Limit is applied", func(t *testing.T) {
+		assert.Len(t, index.Suggest("n", 2), 2)
+	})
+
+	t.Run("Positive: Suggestions link to the slug", func(t *testing.T) {
+		post := newPost("Slugged post", 0)
+		post.Slug = "slugged-post"
+		index.Put(post)
+
+		res := index.Suggest("slugged", 10)
+		assert.Equal(t, "/posts/slugged-post", res[0].Path())
+	})
+
+	t.Run("Negative: Empty query", func(t *testing.T) {
+		assert.Empty(t, index.Suggest("  ", 10))
+		assert.Empty(t, index.Suggest("news", 0))
+	})
+}
+
+func TestTitleIndex_PutAndRemove(t *testing.T) {
+	index := NewTitleIndex()
+	post := newPost("Old title", time.Hour)
+	index.Put(post)
+	assert.Len(t, index.Suggest("old", 5), 1)
+
+	index.Put(models.Post{ID: post.ID, Title: "Fresh title"})
+	assert.Empty(t, index.Suggest("old", 5))
+	res := index.Suggest("fresh", 5)
+	assert.Len(t, res, 1)
+	assert.Equal(t, post.CreatedAt, res[0].CreatedAt, "CreatedAt should be kept when the update does not carry it")
+
+	index.Remove(post.ID.Hex())
+	assert.Empty(t, index.Suggest("fresh", 5))
+	assert.Empty(t, index.Suggest("title", 5))
+}
@@ -0,0 +1,60 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"newsteller/internal/tokens"
+	"strings"
+	"time"
+)
+
+/**
+Protection of the pages against cross-site attacks. Requests changing data carry the token of
+the csrf cookie in a header, other sites can not read the cookie (double-submit). Tokens are
+signed, so cookies planted by other sites are rejected, and stateless, so any replica checks
+them. Inline scripts are allowed by the Content-Security-Policy through a nonce per response.
+*/
+
+// NoncePattern - placeholder of the nonce in Content-Security-Policy values
+const NoncePattern = "{nonce}"
+
+// Nonce returns a random nonce of a response.
+func Nonce() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// Policy returns the Content-Security-Policy allowing the scripts of the nonce.
+func Policy(policy, nonce string) string {
+	return strings.ReplaceAll(policy, NoncePattern, nonce)
+}
+
+// CSRF issues and checks the tokens of the csrf cookie.
+type CSRF struct {
+	signer *tokens.Signer
+}
+
+func NewCSRF(signer *tokens.Signer) *CSRF {
+	return &CSRF{signer: signer}
+}
+
+// Token returns a new token of a csrf cookie.
+func (c *CSRF) Token() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return c.signer.Sign(tokens.PurposeCSRF, hex.EncodeToString(b), time.Time{})
+}
+
+// Valid reports whether the token of a cookie was issued by the site.
+func (c *CSRF) Valid(token string) bool {
+	_, err := c.signer.Verify(tokens.PurposeCSRF, token)
+	return err == nil
+}
+
+// Matches reports whether the token submitted with a request is the valid token of the cookie.
+func (c *CSRF) Matches(cookie, submitted string) bool {
+	return c.Valid(cookie) && subtle.ConstantTimeCompare([]byte(cookie), []byte(submitted)) == 1
+}
@@ -0,0 +1,49 @@
+package security
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"newsteller/internal/tokens"
+)
+
+func TestPolicy(t *testing.T) {
+	policy := Policy("script-src 'nonce-{nonce}' 'strict-dynamic'; object-src 'none'", "abc")
+	assert.Equal(t, "script-src 'nonce-abc' 'strict-dynamic'; object-src 'none'", policy)
+}
+
+func TestNonce(t *testing.T) {
+	first, second := Nonce(), Nonce()
+	assert.Len(t, first, 24)
+	assert.NotEqual(t, first, second, "responses should get their own nonce")
+}
+
+func TestCSRF(t *testing.T) {
+	signer := tokens.NewSigner("secret")
+	csrf := NewCSRF(signer)
+	token := csrf.Token()
+
+	t.Run("Positive: Token of the cookie submitted", func(t *testing.T) {
+		assert.True(t, csrf.Valid(token))
+		assert.True(t, csrf.Matches(token, token))
+		assert.NotEqual(t, token, csrf.Token(), "cookies should get their own token")
+	})
+
+	t.Run("Negative: Missing or other token submitted", func(t *testing.T) {
+		assert.False(t, csrf.Matches(token, ""))
+		assert.False(t, csrf.Matches(token, csrf.Token()))
+	})
+
+	t.Run("Negative: Cookie not issued by the site", func(t *testing.T) {
+		assert.False(t, csrf.Valid("planted"))
+		assert.False(t, csrf.Matches("planted", "planted"))
+
+		forged := tokens.NewSigner("other").Sign(tokens.PurposeCSRF, "subject", time.Time{})
+		assert.False(t, csrf.Matches(forged, forged))
+
+		other := signer.Sign(tokens.PurposeUnsubscribe, "subject", time.Time{})
+		assert.False(t, csrf.Matches(other, other), "tokens of other purposes should not be accepted")
+	})
+}
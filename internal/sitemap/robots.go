@@ -0,0 +1,30 @@
+package sitemap
+
+import (
+	"strings"
+)
+
+// Robots renders a robots.txt allowing every crawler everything but the disallowed
+// path prefixes and pointing to the sitemap.
+func Robots(baseURL string, disallow []string) string {
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+
+	empty := true
+	for _, path := range disallow {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		b.WriteString("Disallow: " + path + "\n")
+		empty = false
+	}
+	if empty {
+		// an empty Disallow allows everything
+		b.WriteString("Disallow:\n")
+	}
+
+	b.WriteString("\nSitemap: " + baseURL + IndexPath + "\n")
+
+	return b.String()
+}
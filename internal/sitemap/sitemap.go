@@ -0,0 +1,159 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"fmt"
+	"newsteller/internal/models"
+	"time"
+)
+
+/**
+Sitemaps of the site (https://www.sitemaps.org/protocol.html). A sitemap lists at most
+50,000 URLs, larger sites are split into numbered parts listed by a sitemap index
+served at the same /sitemap.xml path.
+*/
+
+// MaxURLs - max URLs of a single sitemap
+const MaxURLs = 50000
+
+// IndexPath - path of the sitemap, or the sitemap index when the URLs are split
+const IndexPath = "/sitemap.xml"
+
+// URL is a page of the site, LastMod is omitted when zero.
+type URL struct {
+	Loc     string
+	LastMod time.Time
+}
+
+type urlSet struct {
+	XMLName xml.Name   `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []urlEntry `xml:"url"`
+}
+
+type urlEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name   `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 sitemapindex"`
+	Sitemaps []urlEntry `xml:"sitemap"`
+}
+
+// Document is a rendered sitemap or sitemap index.
+type Document struct {
+	Body    []byte
+	LastMod time.Time
+}
+
+// Build renders the sitemaps of the URLs keyed by their paths, e.g. "/sitemap.xml" and
+// "/sitemap-1.xml". baseURL is used for links between the sitemap index and the parts.
+func Build(baseURL string, urls []URL) (map[string]Document, error) {
+	return build(baseURL, urls, MaxURLs)
+}
+
+func build(baseURL string, urls []URL, maxURLs int) (map[string]Document, error) {
+	if len(urls) <= maxURLs {
+		document, err := renderURLSet(urls)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]Document{IndexPath: document}, nil
+	}
+
+	documents := make(map[string]Document)
+	index := sitemapIndex{}
+	var indexLastMod time.Time
+	for part := 1; len(urls) > 0; part++ {
+		size := min(maxURLs, len(urls))
+		document, err := renderURLSet(urls[:size])
+		if err != nil {
+			return nil, err
+		}
+		urls = urls[size:]
+
+		path := PartPath(part)
+		documents[path] = document
+		index.Sitemaps = append(index.Sitemaps, urlEntry{Loc: baseURL + path, LastMod: formatLastMod(document.LastMod)})
+		if document.LastMod.After(indexLastMod) {
+			indexLastMod = document.LastMod
+		}
+	}
+
+	body, err := marshal(index)
+	if err != nil {
+		return nil, err
+	}
+	documents[IndexPath] = Document{Body: body, LastMod: indexLastMod}
+
+	return documents, nil
+}
+
+// PartPath returns the path of the numbered part of a split sitemap, starting at 1.
+func PartPath(part int) string {
+	return fmt.Sprintf("/sitemap-%d.xml", part)
+}
+
+func renderURLSet(urls []URL) (Document, error) {
+	set := urlSet{URLs: make([]urlEntry, 0, len(urls))}
+	var lastMod time.Time
+	for _, url := range urls {
+		set.URLs = append(set.URLs, urlEntry{Loc: url.Loc, LastMod: formatLastMod(url.LastMod)})
+		if url.LastMod.After(lastMod) {
+			lastMod = url.LastMod
+		}
+	}
+
+	body, err := marshal(set)
+	if err != nil {
+		return Document{}, err
+	}
+
+	return Document{Body: body, LastMod: lastMod}, nil
+}
+
+func formatLastMod(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+func marshal(document any) ([]byte, error) {
+	body, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), body...), nil
+}
+
+// SiteURLs lists the home page, every page of the post list and every post,
+// the posts are expected newest first.
+func SiteURLs(baseURL string, posts []models.Post, postsPerPage int) []URL {
+	var newest time.Time
+	postURLs := make([]URL, 0, len(posts))
+	for _, post := range posts {
+		lastMod := post.UpdatedAt
+		if lastMod.Before(post.CreatedAt) {
+			lastMod = post.CreatedAt
+		}
+		if lastMod.After(newest) {
+			newest = lastMod
+		}
+		postURLs = append(postURLs, URL{Loc: baseURL + post.Path(), LastMod: lastMod})
+	}
+
+	urls := []URL{
+		{Loc: baseURL + "/home", LastMod: newest},
+		{Loc: baseURL + "/posts/search", LastMod: newest},
+	}
+	// older pages change whenever a post is added, so they have no meaningful lastmod
+	for page := 2; page <= (len(posts)+postsPerPage-1)/postsPerPage; page++ {
+		urls = append(urls, URL{Loc: fmt.Sprintf("%s/posts/search?page=%d", baseURL, page)})
+	}
+
+	return append(urls, postURLs...)
+}
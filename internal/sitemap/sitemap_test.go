@@ -0,0 +1,82 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"newsteller/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSiteURLs(t *testing.T) {
+	created := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	posts := []models.Post{
+		{ID: primitive.NewObjectID(), Slug: "hello-world", CreatedAt: created, UpdatedAt: created.Add(time.Hour)},
+		{ID: primitive.NewObjectID(), CreatedAt: created.Add(-time.Hour)},
+		{ID: primitive.NewObjectID(), CreatedAt: created.Add(-2 * time.Hour)},
+	}
+
+	urls := SiteURLs("https://news.example.com", posts, 2)
+
+	assert.Equal(t, []URL{
+		{Loc: "https://news.example.com/home", LastMod: created.Add(time.Hour)},
+		{Loc: "https://news.example.com/posts/search", LastMod: created.Add(time.Hour)},
+		{Loc: "https://news.example.com/posts/search?page=2"},
+		{Loc: "https://news.example.com/posts/hello-world", LastMod: created.Add(time.Hour)},
+		{Loc: "https://news.example.com/posts/" + posts[1].ID.Hex(), LastMod: created.Add(-time.Hour)},
+		{Loc: "https://news.example.com/posts/" + posts[2].ID.Hex(), LastMod: created.Add(-2 * time.Hour)},
+	}, urls)
+}
+
+func TestBuild(t *testing.T) {
+	lastMod := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	urls := []URL{
+		{Loc: "https://news.example.com/home", LastMod: lastMod},
+		{Loc: "https://news.example.com/posts/search?page=2"},
+		{Loc: "https://news.example.com/posts/1", LastMod: lastMod.Add(-time.Hour)},
+	}
+
+	t.Run("Positive: Single sitemap", func(t *testing.T) {
+		documents, err := Build("https://news.example.com", urls)
+		require.NoError(t, err)
+		require.Len(t, documents, 1)
+
+		document := documents[IndexPath]
+		body := string(document.Body)
+		assert.Equal(t, lastMod, document.LastMod)
+		assert.True(t, strings.HasPrefix(body, xml.Header))
+		assert.Contains(t, body, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+		assert.Contains(t, body, "<loc>https://news.example.com/home</loc>\n    <lastmod>2026-10-01T08:00:00Z</lastmod>")
+		assert.Contains(t, body, "<url>\n    <loc>https://news.example.com/posts/search?page=2</loc>\n  </url>")
+	})
+
+	t.Run("Positive: Split into an index", func(t *testing.T) {
+		documents, err := build("https://news.example.com", urls, 2)
+		require.NoError(t, err)
+		require.Len(t, documents, 3)
+
+		index := string(documents[IndexPath].Body)
+		assert.Contains(t, index, `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+		assert.Contains(t, index, "<loc>https://news.example.com/sitemap-1.xml</loc>\n    <lastmod>2026-10-01T08:00:00Z</lastmod>")
+		assert.Contains(t, index, "<loc>https://news.example.com/sitemap-2.xml</loc>\n    <lastmod>2026-10-01T07:00:00Z</lastmod>")
+
+		assert.Equal(t, 2, strings.Count(string(documents[PartPath(1)].Body), "<url>"))
+		assert.Equal(t, 1, strings.Count(string(documents[PartPath(2)].Body), "<url>"))
+	})
+}
+
+func TestRobots(t *testing.T) {
+	t.Run("Positive: Disallowed paths", func(t *testing.T) {
+		robots := Robots("https://news.example.com", []string{"/issues", " ", "/posts/*/edit"})
+		assert.Equal(t, "User-agent: *\nDisallow: /issues\nDisallow: /posts/*/edit\n\nSitemap: https://news.example.com/sitemap.xml\n", robots)
+	})
+
+	t.Run("Positive: Everything allowed", func(t *testing.T) {
+		robots := Robots("https://news.example.com", nil)
+		assert.Contains(t, robots, "User-agent: *\nDisallow:\n")
+	})
+}
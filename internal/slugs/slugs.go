@@ -0,0 +1,164 @@
+package slugs
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+/**
+URL slugs of titles: lowercase ASCII letters and digits separated by single dashes.
+Latin letters with diacritics, Cyrillic and Greek are transliterated, other scripts
+are dropped, so titles written only in them fall back to "post".
+*/
+
+const (
+	// MaxLength - slugs are cut at a word boundary past this length
+	MaxLength = 80
+	// Fallback - slug of titles without transliterable letters
+	Fallback = "post"
+)
+
+var transliterations = map[rune]string{}
+
+func init() {
+	latin := map[string]string{
+		"a": "àáâãäåāăą", "c": "çćĉċč", "d": "ďđð", "e": "èéêëēĕėęě", "g": "ĝğġģ",
+		"h": "ĥħ", "i": "ìíîïĩīĭįı", "j": "ĵ", "k": "ķ", "l": "ĺļľŀł", "n": "ñńņňŉ",
+		"o": "òóôõöøōŏő", "r": "ŕŗř", "s": "śŝşš", "t": "ţťŧ", "u": "ùúûüũūŭůűų",
+		"w": "ŵ", "y": "ýÿŷ", "z": "źżž", "ae": "æ", "oe": "œ", "ss": "ß", "th": "þ",
+	}
+	for ascii, letters := range latin {
+		for _, letter := range letters {
+			transliterations[letter] = ascii
+		}
+	}
+
+	for letter, ascii := range map[rune]string{
+		// Russian, Ukrainian and Belarusian
+		'а': "a", 'б': "b", 'в': "v", 'г': "g", 'ґ': "g", 'д': "d", 'е': "e", 'ё': "e", 'є': "ye",
+		'ж': "zh", 'з': "z", 'и': "i", 'і': "i", 'ї': "yi", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+		'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ў': "u", 'ф': "f",
+		'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "",
+		'э': "e", 'ю': "yu", 'я': "ya",
+		// Greek
+		'α': "a", 'ά': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'έ': "e", 'ζ': "z", 'η': "i",
+		'ή': "i", 'θ': "th", 'ι': "i", 'ί': "i", 'ϊ': "i", 'ΐ': "i", 'κ': "k", 'λ': "l", 'μ': "m",
+		'ν': "n", 'ξ': "x", 'ο': "o", 'ό': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t",
+		'υ': "y", 'ύ': "y", 'ϋ': "y", 'ΰ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o", 'ώ': "o",
+	} {
+		transliterations[letter] = ascii
+	}
+}
+
+// Make returns the slug of the title.
+func Make(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		ascii, ok := transliterations[r]
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			ascii = string(r)
+		case ok:
+		case unicode.IsLetter(r) || unicode.IsMark(r):
+			// letters of other scripts are dropped without separating words
+			continue
+		default:
+			dash = b.Len() > 0
+			continue
+		}
+		if ascii == "" {
+			continue
+		}
+		if dash {
+			b.WriteByte('-')
+			dash = false
+		}
+		b.WriteString(ascii)
+	}
+
+	slug := b.String()
+	if len(slug) > MaxLength {
+		slug = slug[:MaxLength]
+		if cut := strings.LastIndexByte(slug, '-'); cut > 0 {
+			slug = slug[:cut]
+		}
+	}
+	if slug == "" {
+		return Fallback
+	}
+	// slugs looking like IDs would be looked up as IDs
+	if isObjectID(slug) {
+		return slug + "-" + Fallback
+	}
+
+	return slug
+}
+
+// punctuation - ASCII spelling of common typographic punctuation
+var punctuation = map[rune]string{
+	'‘': "'", '’': "'", '“': `"`, '”': `"`, '«': `"`, '»': `"`, '–': "-", '—': "-", '…': "...",
+}
+
+// Transliterate returns the ASCII spelling of the text keeping the case of letters,
+// characters of other scripts are dropped.
+func Transliterate(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		lower := unicode.ToLower(r)
+		ascii, ok := transliterations[lower]
+		switch {
+		case r < unicode.MaxASCII:
+			b.WriteRune(r)
+		case ok && r != lower && ascii != "":
+			b.WriteString(strings.ToUpper(ascii[:1]) + ascii[1:])
+		case ok:
+			b.WriteString(ascii)
+		case unicode.IsSpace(r):
+			b.WriteByte(' ')
+		default:
+			b.WriteString(punctuation[r])
+		}
+	}
+
+	return b.String()
+}
+
+// WithSuffix returns the nth candidate of the base slug: the base itself, then "base-2", "base-3"...
+func WithSuffix(base string, n int) string {
+	if n <= 1 {
+		return base
+	}
+
+	return base + "-" + strconv.Itoa(n)
+}
+
+// Matches reports whether the slug is a candidate of the base, so a post keeps its slug
+// when the title changes without changing the slug.
+func Matches(slug, base string) bool {
+	if slug == base {
+		return true
+	}
+
+	suffix, ok := strings.CutPrefix(slug, base+"-")
+	if !ok {
+		return false
+	}
+	n, err := strconv.Atoi(suffix)
+
+	return err == nil && n > 1 && strconv.Itoa(n) == suffix
+}
+
+func isObjectID(slug string) bool {
+	if len(slug) != 24 {
+		return false
+	}
+	for _, r := range slug {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+
+	return true
+}
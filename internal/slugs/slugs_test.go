@@ -0,0 +1,57 @@
+package slugs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		name     string
+		title    string
+		expected string
+	}{
+		{name: "Positive: Latin", title: "Hello, World!", expected: "hello-world"},
+		{name: "Positive: Digits and punctuation", title: "  Go 1.27 -- what's new?  ", expected: "go-1-27-what-s-new"},
+		{name: "Positive: Diacritics", title: "Crème brûlée à Zürich, Straße", expected: "creme-brulee-a-zurich-strasse"},
+		{name: "Positive: Cyrillic", title: "Привет, мир", expected: "privet-mir"},
+		{name: "Positive: Ukrainian", title: "Їжак і ґанок", expected: "yizhak-i-ganok"},
+		{name: "Positive: Greek", title: "Καλημέρα κόσμε", expected: "kalimera-kosme"},
+		{name: "Positive: Mixed scripts", title: "Go 语言 tips", expected: "go-tips"},
+		{name: "Negative: Only untransliterable letters", title: "日本語", expected: Fallback},
+		{name: "Negative: Empty", title: " ?! ", expected: Fallback},
+		{name: "Negative: Looks like an ID", title: "64b7f0c2a1e4d3f2b1c0a9e8", expected: "64b7f0c2a1e4d3f2b1c0a9e8-post"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Make(tt.title))
+		})
+	}
+
+	t.Run("Positive: Long titles are cut at a word boundary", func(t *testing.T) {
+		slug := Make(strings.Repeat("word ", 30))
+		assert.LessOrEqual(t, len(slug), MaxLength)
+		assert.True(t, strings.HasSuffix(slug, "-word"))
+	})
+}
+
+func TestMatches(t *testing.T) {
+	assert.Equal(t, "hello", WithSuffix("hello", 1))
+	assert.Equal(t, "hello-3", WithSuffix("hello", 3))
+
+	assert.True(t, Matches("hello", "hello"))
+	assert.True(t, Matches("hello-3", "hello"))
+	assert.False(t, Matches("hello-world", "hello"))
+	assert.False(t, Matches("hello-1", "hello"))
+	assert.False(t, Matches("hello-03", "hello"))
+	assert.False(t, Matches("hello", "hello-world"))
+}
+
+func TestTransliterate(t *testing.T) {
+	assert.Equal(t, "Privet, Mir - Zurich...", Transliterate("Привет, Мир — Zürich…"))
+	assert.Equal(t, "Go  tips", Transliterate("Go 语言 tips"))
+	assert.Equal(t, "Shchuka", Transliterate("Щука"))
+}
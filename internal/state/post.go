@@ -2,15 +2,23 @@ package state
 
 import (
 	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/internal/events"
 	"newsteller/internal/models"
 	"newsteller/internal/repositories"
+	"newsteller/internal/slugs"
+	"slices"
+	"sync"
 )
 
 type Post struct {
 	repo *repositories.Post
+	mu   sync.RWMutex
 	// postsMap - map of posts with ID as a key
 	postsMap map[string]*models.Post
+	// outbox - records and publishes the changes of posts, none are when nil
+	outbox *events.Outbox
 }
 
 func (p *Post) FindAll(ctx context.Context) ([]models.Post, error) {
@@ -22,7 +30,9 @@ func (p *Post) FindPaginated(ctx context.Context, q *repositories.PaginatedSearc
 }
 
 func (p *Post) FindByID(ctx context.Context, id string) (*models.Post, error) {
+	p.mu.RLock()
 	post, ok := p.postsMap[id]
+	p.mu.RUnlock()
 	if ok {
 		return post, nil
 	}
@@ -31,34 +41,115 @@ func (p *Post) FindByID(ctx context.Context, id string) (*models.Post, error) {
 	if err != nil {
 		return nil, err
 	}
+	p.mu.Lock()
 	p.postsMap[id] = res
+	p.mu.Unlock()
 
 	return res, nil
 }
 
 func (p *Post) Insert(ctx context.Context, model *models.Post) error {
+	slug, err := p.repo.UniqueSlug(ctx, model.Title, primitive.NilObjectID)
+	if err != nil {
+		return err
+	}
+	model.Slug = slug
+	// the ID is known ahead, so the events are recorded before the post is
+	model.ID = primitive.NewObjectID()
+
+	// posts have no drafts, a created post is published right away
+	batch, err := p.outbox.Prepare(ctx, events.PostCreated{Post: *model}, events.PostPublished{Post: *model})
+	if err != nil {
+		return err
+	}
 	res, err := p.repo.Create(ctx, model)
 	if err != nil {
+		batch.Discard(ctx)
 		return err
 	}
 	model.ID = *res
+	batch.Commit(ctx)
 
 	return nil
 }
 
 func (p *Post) Delete(ctx context.Context, id string) error {
+	p.mu.Lock()
 	delete(p.postsMap, id)
-	return p.repo.Delete(ctx, id)
+	p.mu.Unlock()
+	if p.outbox == nil {
+		return p.repo.Delete(ctx, id)
+	}
+
+	// loaded before deleting, so subscribers are told what was deleted
+	post, err := p.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	batch, err := p.outbox.Prepare(ctx, events.PostDeleted{Post: *post})
+	if err != nil {
+		return err
+	}
+	err = p.repo.Delete(ctx, id)
+	if err != nil {
+		batch.Discard(ctx)
+		return err
+	}
+	batch.Commit(ctx)
+
+	return nil
 }
 
 func (p *Post) Update(ctx context.Context, model *models.Post) error {
+	current, err := p.repo.FindByID(ctx, model.ID.Hex())
+	if err != nil {
+		return err
+	}
+	model.CreatedAt = current.CreatedAt
+	model.Slug, model.SlugHistory = current.Slug, current.SlugHistory
+
+	// the slug follows the title, the former one keeps redirecting to the post
+	if model.Slug == "" || !slugs.Matches(model.Slug, slugs.Make(model.Title)) {
+		slug, err := p.repo.UniqueSlug(ctx, model.Title, model.ID)
+		if err != nil {
+			return err
+		}
+		if model.Slug != "" {
+			model.SlugHistory = append(model.SlugHistory, model.Slug)
+		}
+		model.Slug = slug
+		model.SlugHistory = slices.DeleteFunc(model.SlugHistory, func(former string) bool {
+			return former == slug
+		})
+	}
+
+	batch, err := p.outbox.Prepare(ctx, events.PostUpdated{Post: *model, Previous: *current})
+	if err != nil {
+		return err
+	}
+	err = p.repo.Update(ctx, model)
+	if err != nil {
+		batch.Discard(ctx)
+		return err
+	}
+	p.mu.Lock()
 	p.postsMap[model.ID.Hex()] = model
-	return p.repo.Update(ctx, model)
+	p.mu.Unlock()
+	batch.Commit(ctx)
+
+	return nil
 }
 
+// NewPostState returns the state of posts, changes of which publish no events.
 func NewPostState(c *mongo.Collection) State[models.Post] {
+	return NewPostStateWithEvents(c, nil)
+}
+
+// NewPostStateWithEvents returns the state of posts publishing every change through the outbox.
+func NewPostStateWithEvents(c *mongo.Collection, outbox *events.Outbox) State[models.Post] {
 	return &Post{
 		repo:     repositories.NewPostRepository(c),
 		postsMap: make(map[string]*models.Post),
+		outbox:   outbox,
 	}
 }
@@ -208,6 +208,7 @@ func TestPostState_Update(t *testing.T) {
 	err := postState.Insert(ctx, post)
 	require.NoError(t, err)
 	require.NotEmpty(t, post.ID)
+	assert.Equal(t, "original-title", post.Slug, "Insert should generate the slug")
 
 	updatedTime := now.Add(3 * time.Hour).Truncate(time.Millisecond)
 
@@ -236,6 +237,16 @@ func TestPostState_Update(t *testing.T) {
 	assert.Equal(t, "Updated Title", foundPostDB.Title)
 	assert.Equal(t, "Updated Content", foundPostDB.Content)
 	assert.WithinDuration(t, updatedTime, foundPostDB.UpdatedAt, time.Millisecond, "UpdatedAt in DB should match")
+	assert.Equal(t, "updated-title", foundPostDB.Slug, "Slug should follow the title")
+	assert.Equal(t, []string{"original-title"}, foundPostDB.SlugHistory, "Former slug should be kept")
+
+	revertData := &models.Post{ID: post.ID, Title: "Original Title", Content: "Original Content", UpdatedAt: updatedTime}
+	err = postState.Update(ctx, revertData)
+	require.NoError(t, err)
+	foundPostDB, err = repo.FindByID(ctx, post.ID.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, "original-title", foundPostDB.Slug, "Former slug should be reclaimed")
+	assert.Equal(t, []string{"updated-title"}, foundPostDB.SlugHistory)
 }
 
 func TestPostState_Delete(t *testing.T) {
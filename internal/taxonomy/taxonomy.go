@@ -0,0 +1,133 @@
+package taxonomy
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/internal/events"
+	"newsteller/internal/models"
+	"newsteller/internal/repositories"
+	"slices"
+)
+
+/**
+Organisation of posts. Tags are free-form, every tag of a saved post is registered in the
+tags collection, so the tag picker offers the tags in use. Categories form a tree, a
+category page lists the posts of the category and of all categories nested under it.
+*/
+
+// Node is a category with its depth in the tree, 0 for top level categories.
+type Node struct {
+	models.Category
+	Depth int
+}
+
+// Tree orders the categories depth first, every category followed by its children
+// sorted by name. Categories with a missing parent, or caught in a cycle of parents,
+// are treated as top level ones.
+func Tree(categories []models.Category) []Node {
+	known := make(map[primitive.ObjectID]bool, len(categories))
+	for _, category := range categories {
+		known[category.ID] = true
+	}
+
+	children := make(map[primitive.ObjectID][]models.Category)
+	for _, category := range categories {
+		parent := category.ParentID
+		if !known[parent] {
+			parent = primitive.NilObjectID
+		}
+		children[parent] = append(children[parent], category)
+	}
+
+	nodes := make([]Node, 0, len(categories))
+	visited := make(map[primitive.ObjectID]bool, len(categories))
+	var walk func(parent primitive.ObjectID, depth int)
+	walk = func(parent primitive.ObjectID, depth int) {
+		for _, category := range children[parent] {
+			if visited[category.ID] {
+				continue
+			}
+			visited[category.ID] = true
+			nodes = append(nodes, Node{Category: category, Depth: depth})
+			walk(category.ID, depth+1)
+		}
+	}
+	walk(primitive.NilObjectID, 0)
+	for _, category := range categories {
+		if !visited[category.ID] {
+			visited[category.ID] = true
+			nodes = append(nodes, Node{Category: category})
+			walk(category.ID, 1)
+		}
+	}
+
+	return nodes
+}
+
+// Subtree returns the ID of the category and of every category nested under it.
+func Subtree(categories []models.Category, id primitive.ObjectID) []primitive.ObjectID {
+	ids := []primitive.ObjectID{id}
+	for i := 0; i < len(ids); i++ {
+		for _, category := range categories {
+			if category.ParentID == ids[i] && !slices.Contains(ids, category.ID) {
+				ids = append(ids, category.ID)
+			}
+		}
+	}
+
+	return ids
+}
+
+// Ancestors returns the categories enclosing the category, top level one first.
+func Ancestors(categories []models.Category, category models.Category) []models.Category {
+	byID := make(map[primitive.ObjectID]models.Category, len(categories))
+	for _, c := range categories {
+		byID[c.ID] = c
+	}
+
+	var ancestors []models.Category
+	seen := map[primitive.ObjectID]bool{category.ID: true}
+	for parent, ok := byID[category.ParentID]; ok && !seen[parent.ID]; parent, ok = byID[parent.ParentID] {
+		seen[parent.ID] = true
+		ancestors = append([]models.Category{parent}, ancestors...)
+	}
+
+	return ancestors
+}
+
+// Children returns the categories directly under the category.
+func Children(categories []models.Category, id primitive.ObjectID) []models.Category {
+	var children []models.Category
+	for _, category := range categories {
+		if category.ParentID == id && category.ID != id {
+			children = append(children, category)
+		}
+	}
+
+	return children
+}
+
+// Tags registers the tags of saved posts.
+type Tags struct {
+	repo *repositories.Tag
+}
+
+func NewTags(c *mongo.Collection) *Tags {
+	return &Tags{repo: repositories.NewTagRepository(c)}
+}
+
+// Subscribe registers the tags of created and updated posts.
+func (t *Tags) Subscribe(bus *events.Bus) {
+	events.Subscribe(bus, "tags", func(ctx context.Context, event events.PostCreated) error {
+		return t.repo.Ensure(ctx, event.Post.Tags)
+	})
+	events.Subscribe(bus, "tags", func(ctx context.Context, event events.PostUpdated) error {
+		return t.repo.Ensure(ctx, event.Post.Tags)
+	})
+}
+
+// Register registers the tags, used for tags of posts saved before tags were registered.
+func (t *Tags) Register(ctx context.Context, tags []string) error {
+	return t.repo.Ensure(ctx, tags)
+}
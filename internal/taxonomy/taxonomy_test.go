@@ -0,0 +1,74 @@
+package taxonomy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"newsteller/internal/models"
+)
+
+func category(name string, parent primitive.ObjectID) models.Category {
+	return models.Category{ID: primitive.NewObjectID(), Name: name, ParentID: parent}
+}
+
+func names(categories []models.Category) []string {
+	var names []string
+	for _, category := range categories {
+		names = append(names, category.Name)
+	}
+	return names
+}
+
+func nodes(tree []Node) (names []string, depths []int) {
+	for _, node := range tree {
+		names = append(names, node.Name)
+		depths = append(depths, node.Depth)
+	}
+	return names, depths
+}
+
+func TestTree(t *testing.T) {
+	news := category("News", primitive.NilObjectID)
+	europe := category("Europe", news.ID)
+	france := category("France", europe.ID)
+	asia := category("Asia", news.ID)
+	sports := category("Sports", primitive.NilObjectID)
+	orphan := category("Orphan", primitive.NewObjectID())
+	// sorted by name, as the repository returns them
+	categories := []models.Category{asia, europe, france, news, orphan, sports}
+
+	t.Run("Positive: Depth first with children sorted by name", func(t *testing.T) {
+		names, depths := nodes(Tree(categories))
+
+		assert.Equal(t, []string{"News", "Asia", "Europe", "France", "Orphan", "Sports"}, names)
+		assert.Equal(t, []int{0, 1, 1, 2, 0, 0}, depths)
+	})
+
+	t.Run("Negative: Categories in a cycle are listed once", func(t *testing.T) {
+		a := category("A", primitive.NilObjectID)
+		b := category("B", a.ID)
+		a.ParentID = b.ID
+
+		names, depths := nodes(Tree([]models.Category{a, b, sports}))
+		assert.Equal(t, []string{"Sports", "A", "B"}, names)
+		assert.Equal(t, []int{0, 0, 1}, depths)
+	})
+
+	t.Run("Positive: Subtree contains the nested categories", func(t *testing.T) {
+		assert.ElementsMatch(t, []primitive.ObjectID{news.ID, europe.ID, france.ID, asia.ID}, Subtree(categories, news.ID))
+		assert.Equal(t, []primitive.ObjectID{france.ID}, Subtree(categories, france.ID))
+	})
+
+	t.Run("Positive: Ancestors from the top level", func(t *testing.T) {
+		assert.Equal(t, []string{"News", "Europe"}, names(Ancestors(categories, france)))
+		assert.Empty(t, Ancestors(categories, news))
+		assert.Empty(t, Ancestors(categories, orphan))
+	})
+
+	t.Run("Positive: Children are only the direct ones", func(t *testing.T) {
+		assert.Equal(t, []string{"Asia", "Europe"}, names(Children(categories, news.ID)))
+		assert.Empty(t, Children(categories, france.ID))
+	})
+}
@@ -0,0 +1,40 @@
+package templates
+
+import (
+	"newsteller/internal/antispam"
+)
+
+type antispamFieldsData struct {
+	antispam.Challenge
+	// OOB - the fields replace those of the form shown, with a fresh challenge
+	OOB bool
+}
+
+type messageWithChallengeData struct {
+	message
+	Antispam antispamFieldsData
+}
+
+// renderMessageWithChallenge renders the message followed by the anti-spam fields of
+// the challenge swapped out of band, when the challenge is not empty.
+func renderMessageWithChallenge(m message, challenge antispam.Challenge) (string, error) {
+	return render(
+		"message-fragment",
+		messageWithChallengeData{message: m, Antispam: antispamFieldsData{Challenge: challenge, OOB: true}},
+	)
+}
+
+// AntispamFields are the anti-spam fields of a form, loaded into forms of cached pages.
+type AntispamFields struct {
+	challenge antispam.Challenge
+}
+
+func NewAntispamFields(challenge antispam.Challenge) *AntispamFields {
+	return &AntispamFields{challenge: challenge}
+}
+
+func (f *AntispamFields) GeneratePage() (string, error) {
+	return render("antispam", antispamFieldsData{
+		Challenge: f.challenge,
+	})
+}
@@ -0,0 +1,30 @@
+package templates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"newsteller/internal/antispam"
+)
+
+func TestAntispamFields_GeneratePage(t *testing.T) {
+	t.Run("Positive: Fields of the challenge", func(t *testing.T) {
+		challenge := antispam.Challenge{Form: antispam.FormSubscribe, Token: "abc.def", Difficulty: 16}
+		html, err := NewAntispamFields(challenge).GeneratePage()
+		require.NoError(t, err)
+
+		assert.Contains(t, html, `<div id="antispam-subscribe" class="antispam"`)
+		assert.NotContains(t, html, "hx-swap-oob", "loaded fields replace their placeholder")
+		assert.Contains(t, html, `<input type="hidden" name="challenge" value="abc.def">`)
+		assert.Contains(t, html, `<input type="hidden" name="proof" value="">`)
+		assert.Contains(t, html, `window.solveAntispam(document.getElementById('antispam-subscribe'))`)
+	})
+
+	t.Run("Positive: No fields when the guard is disabled", func(t *testing.T) {
+		html, err := NewAntispamFields(antispam.Challenge{Form: antispam.FormSubscribe}).GeneratePage()
+		require.NoError(t, err)
+		assert.Empty(t, html)
+	})
+}
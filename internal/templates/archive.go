@@ -0,0 +1,40 @@
+package templates
+
+import (
+	"newsteller/internal/models"
+)
+
+// IssueArchive is the public list of sent issues.
+type IssueArchive struct {
+	issues []models.Issue
+}
+
+func NewIssueArchive(issues []models.Issue) *IssueArchive {
+	return &IssueArchive{issues: issues}
+}
+
+func (a *IssueArchive) GeneratePage() (string, error) {
+	return render("issue-archive", a.issues)
+}
+
+// ArchivedIssue is the web version of a sent issue.
+type ArchivedIssue struct {
+	issue *models.Issue
+	posts []models.Post
+}
+
+type archivedIssueData struct {
+	Issue *models.Issue
+	Posts []models.Post
+}
+
+func NewArchivedIssue(issue *models.Issue, posts []models.Post) *ArchivedIssue {
+	return &ArchivedIssue{
+		issue: issue,
+		posts: posts,
+	}
+}
+
+func (a *ArchivedIssue) GeneratePage() (string, error) {
+	return render("archived-issue", &archivedIssueData{Issue: a.issue, Posts: a.posts})
+}
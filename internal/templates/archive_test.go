@@ -0,0 +1,46 @@
+package templates
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"newsteller/internal/models"
+)
+
+func TestIssueArchive_GeneratePage(t *testing.T) {
+	issue := createMockIssue(models.IssueSent, createMockPosts(2))
+	issue.SentAt = time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	html, err := NewIssueArchive([]models.Issue{*issue}).GeneratePage()
+	require.NoError(t, err)
+	html = strings.Join(strings.Fields(html), " ")
+
+	assert.Contains(t, html, "<title>Newsletter Archive</title>")
+	assert.Contains(t, html, fmt.Sprintf(`<a href="/newsletter/%s"> <h2>Spring &lt;issue&gt;</h2>`, issue.ID.Hex()))
+	assert.Contains(t, html, `<div class="date">Mar 01, 2025 · 2 posts</div>`)
+
+	html, err = NewIssueArchive(nil).GeneratePage()
+	require.NoError(t, err)
+	assert.Contains(t, html, "No issues yet")
+}
+
+func TestArchivedIssue_GeneratePage(t *testing.T) {
+	posts := createMockPosts(2)
+	issue := createMockIssue(models.IssueSent, posts)
+	issue.SentAt = time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	html, err := NewArchivedIssue(issue, posts).GeneratePage()
+	require.NoError(t, err)
+	html = strings.Join(strings.Fields(html), " ")
+
+	assert.Contains(t, html, "<title>Spring &lt;issue&gt;</title>")
+	assert.Contains(t, html, `<p class="date">Mar 01, 2025</p>`)
+	assert.Contains(t, html, `<p class="intro">Hello readers</p>`)
+	firstPost := strings.Index(html, fmt.Sprintf(`href="/posts/%s"`, posts[0].ID.Hex()))
+	secondPost := strings.Index(html, fmt.Sprintf(`href="/posts/%s"`, posts[1].ID.Hex()))
+	assert.True(t, firstPost > 0 && firstPost < secondPost, "Posts should keep the issue order")
+}
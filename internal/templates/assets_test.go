@@ -0,0 +1,30 @@
+package templates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"newsteller/internal/assets"
+)
+
+func TestHeadScripts(t *testing.T) {
+	html, err := renderPage("head", `{{template "head-scripts"}}{{template "editor-assets"}}`, nil)
+	require.NoError(t, err)
+
+	htmxPath, err := assets.Path("htmx.min.js")
+	require.NoError(t, err)
+	assert.Contains(t, html, `<script src="`+htmxPath+`" integrity="sha384-D1Kt99CQMDuVetoL1lrYwg5t&#43;9QdHe7NLX/SoJYkXDFfX37iInKRy5xLSi8nO7UC" nonce="`+NoncePlaceholder+`"></script>`)
+	assert.NotContains(t, html, "unpkg.com", "pages should load nothing from other hosts")
+
+	editorPath, err := assets.Path("editor.css")
+	require.NoError(t, err)
+	assert.Contains(t, html, `<link rel="stylesheet" href="`+editorPath+`" integrity="sha384-`)
+	assert.Contains(t, html, `nonce="`+NoncePlaceholder+`" defer></script>`)
+}
+
+func TestAssetFuncs_UnknownAsset(t *testing.T) {
+	_, err := renderPage("missing", `<script src="{{asset "missing.js"}}"></script>`, nil)
+	assert.ErrorContains(t, err, "unknown asset: missing.js")
+}
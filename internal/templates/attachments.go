@@ -0,0 +1,92 @@
+package templates
+
+import (
+	"fmt"
+	"html/template"
+	"newsteller/internal/images"
+	"newsteller/internal/models"
+	"regexp"
+	"strings"
+)
+
+// attachmentReference matches the images, ![name](/attachments/key), and the links,
+// [name](/attachments/key), inserted into post bodies by the attachments panel.
+var attachmentReference = regexp.MustCompile(`(!?)\[([^\[\]\n]*)\]\((/attachments/[A-Za-z0-9._-]+)\)`)
+
+// AttachmentList is the fragment listing attachments in the attachments panel.
+type AttachmentList struct {
+	attachments []models.Attachment
+}
+
+func NewAttachmentList(attachments []models.Attachment) *AttachmentList {
+	return &AttachmentList{attachments: attachments}
+}
+
+// NewAttachmentItem returns the fragment of an uploaded attachment.
+func NewAttachmentItem(attachment *models.Attachment) *AttachmentList {
+	return &AttachmentList{attachments: []models.Attachment{*attachment}}
+}
+
+func (l *AttachmentList) GeneratePage() (string, error) {
+	return render("attachment-list", l.attachments)
+}
+
+// AttachmentMessage is the fragment explaining why an upload was rejected.
+type AttachmentMessage struct {
+	message
+}
+
+func NewAttachmentMessage(kind MessageKind, text string) *AttachmentMessage {
+	return &AttachmentMessage{message{Kind: kind, Text: text}}
+}
+
+func (m *AttachmentMessage) GeneratePage() (string, error) {
+	return renderMessage(m.message)
+}
+
+// contentImageSizes - displayed widths of images within post bodies, for srcset
+const contentImageSizes = "(max-width: 800px) 100vw, 800px"
+
+// renderContent escapes the post body, turning references to attachments into images
+// and links. Other markup is shown as typed.
+func renderContent(content string) template.HTML {
+	var b strings.Builder
+	last := 0
+	for _, match := range attachmentReference.FindAllStringSubmatchIndex(content, -1) {
+		b.WriteString(template.HTMLEscapeString(content[last:match[0]]))
+		text := template.HTMLEscapeString(content[match[4]:match[5]])
+		path := template.HTMLEscapeString(content[match[6]:match[7]])
+		key := strings.TrimPrefix(content[match[6]:match[7]], "/attachments/")
+		switch {
+		case match[3] > match[2] && images.Resizable(key):
+			fmt.Fprintf(
+				&b,
+				`<img src="%s" srcset="%s" sizes="%s" alt="%s" loading="lazy" decoding="async">`,
+				images.Path(key, images.DefaultWidth),
+				images.Srcset(key),
+				contentImageSizes,
+				text,
+			)
+		case match[3] > match[2]:
+			fmt.Fprintf(&b, `<img src="%s" alt="%s" loading="lazy">`, path, text)
+		default:
+			fmt.Fprintf(&b, `<a href="%s">%s</a>`, path, text)
+		}
+		last = match[1]
+	}
+	b.WriteString(template.HTMLEscapeString(content[last:]))
+
+	return template.HTML(b.String())
+}
+
+// FileSize formats a size in bytes, e.g. 1.5 MB.
+func FileSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.0f KB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
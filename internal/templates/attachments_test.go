@@ -0,0 +1,80 @@
+package templates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"newsteller/internal/models"
+)
+
+func TestRenderContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			"Escaped",
+			`<script>alert("x")</script>`,
+			`&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;`,
+		},
+		{
+			"Image",
+			"Look: ![A <b>cat</b>](/attachments/abc.png)!",
+			`Look: <img src="/images/abc.png/960" ` +
+				`srcset="/images/abc.png/320 320w, /images/abc.png/640 640w, /images/abc.png/960 960w, /images/abc.png/1280 1280w, /images/abc.png/1920 1920w" ` +
+				`sizes="(max-width: 800px) 100vw, 800px" alt="A &lt;b&gt;cat&lt;/b&gt;" loading="lazy" decoding="async">!`,
+		},
+		{
+			"Animated images are served as uploaded",
+			"![dance](/attachments/abc.gif)",
+			`<img src="/attachments/abc.gif" alt="dance" loading="lazy">`,
+		},
+		{
+			"Link",
+			"[Report](/attachments/abc.pdf) & more",
+			`<a href="/attachments/abc.pdf">Report</a> &amp; more`,
+		},
+		{
+			"Other URLs are text",
+			`[click](javascript:alert(1)) ![x](https://example.com/x.png)`,
+			`[click](javascript:alert(1)) ![x](https://example.com/x.png)`,
+		},
+		{
+			"Quotes cannot leave the attribute",
+			`![x](/attachments/a"onerror=alert(1).png)`,
+			`![x](/attachments/a&#34;onerror=alert(1).png)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, string(renderContent(tt.content)))
+		})
+	}
+}
+
+func TestAttachmentList_GeneratePage(t *testing.T) {
+	image := models.Attachment{ID: primitive.NewObjectID(), Key: "abc.png", Name: "cat.png", ContentType: "image/png", Size: 2048}
+	document := models.Attachment{ID: primitive.NewObjectID(), Key: "def.pdf", Name: "report.pdf", ContentType: "application/pdf", Size: 3 << 20}
+
+	t.Run("Positive: Images and files", func(t *testing.T) {
+		html, err := NewAttachmentList([]models.Attachment{image, document}).GeneratePage()
+		require.NoError(t, err)
+
+		assert.Contains(t, html, `<img src="/attachments/abc.png"`)
+		assert.Contains(t, html, `data-reference="![cat.png](/attachments/abc.png)"`)
+		assert.Contains(t, html, `data-reference="[report.pdf](/attachments/def.pdf)"`)
+		assert.Contains(t, html, "2 KB")
+		assert.Contains(t, html, "3.0 MB")
+		assert.Contains(t, html, `hx-delete="/attachments/def.pdf"`)
+		assert.NotContains(t, html, "No files uploaded yet.")
+	})
+
+	t.Run("Positive: Empty", func(t *testing.T) {
+		html, err := NewAttachmentList(nil).GeneratePage()
+		require.NoError(t, err)
+		assert.Contains(t, html, "No files uploaded yet.")
+	})
+}
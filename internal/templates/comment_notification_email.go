@@ -0,0 +1,79 @@
+package templates
+
+import (
+	"newsteller/internal/models"
+	"time"
+)
+
+const commentNotificationEmailHTML = `{{define "content"}}
+<h1 style="font-size: 22px; margin: 0 0 15px 0;">{{.Title}}</h1>
+<p style="margin: 0 0 15px 0; color: #666;">
+    {{.Author}} {{if .Reply}}replied to a comment{{else}}commented{{end}} on {{formatDateTime .At}}:
+</p>
+<blockquote style="margin: 0 0 25px 0; padding: 10px 15px; border-left: 4px solid #e9ecef; color: #333; white-space: pre-line;">{{.Content}}</blockquote>
+{{if .Pending}}
+<p style="margin: 0 0 25px 0; color: #666;">The comment waits for moderation before it is shown.</p>
+{{end}}
+<p style="margin: 0; text-align: center;">
+    <a href="{{.URL}}"
+       style="display: inline-block; padding: 12px 24px; background: #007bff; color: white; border-radius: 8px; text-decoration: none; font-weight: 500;">
+        {{if .Pending}}Moderate comments{{else}}Open the comment{{end}}
+    </a>
+</p>
+{{end}}`
+
+const commentNotificationEmailText = `{{define "content"}}{{.Title}}
+
+{{.Author}} {{if .Reply}}replied to a comment{{else}}commented{{end}} on {{formatDateTime .At}}:
+
+{{.Content}}
+{{if .Pending}}
+The comment waits for moderation before it is shown.
+{{end}}
+{{.URL}}{{end}}`
+
+// CommentNotificationEmail tells the author of a post about a new comment.
+type CommentNotificationEmail struct {
+	post    *models.Post
+	comment *models.Comment
+	url     string
+}
+
+type commentNotificationEmailData struct {
+	Subject        string
+	Title          string
+	Author         string
+	Content        string
+	Reply          bool
+	Pending        bool
+	URL            string
+	At             time.Time
+	UnsubscribeURL string
+}
+
+// NewCommentNotificationEmail returns the email about the comment on the post, the URL
+// leads to the moderation queue for pending comments and to the comment otherwise.
+func NewCommentNotificationEmail(post *models.Post, comment *models.Comment, url string) *CommentNotificationEmail {
+	return &CommentNotificationEmail{post: post, comment: comment, url: url}
+}
+
+func (e *CommentNotificationEmail) GenerateEmail() (*Email, error) {
+	subject := "New comment on " + e.post.Title
+
+	return renderEmail(
+		"comment_notification",
+		subject,
+		commentNotificationEmailHTML,
+		commentNotificationEmailText,
+		commentNotificationEmailData{
+			Subject: subject,
+			Title:   e.post.Title,
+			Author:  e.comment.Author,
+			Content: e.comment.Content,
+			Reply:   e.comment.IsReply(),
+			Pending: e.comment.Status == models.CommentPending,
+			URL:     e.url,
+			At:      e.comment.CreatedAt,
+		},
+	)
+}
@@ -0,0 +1,52 @@
+package templates
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"newsteller/internal/models"
+)
+
+func TestCommentNotificationEmail_GenerateEmail(t *testing.T) {
+	post := &models.Post{Title: "Rates & <markets>"}
+	comment := &models.Comment{
+		Author:    "Ann",
+		Content:   "Great <b>post</b>",
+		Status:    models.CommentPending,
+		CreatedAt: time.Date(2025, time.March, 4, 15, 30, 0, 0, time.UTC),
+	}
+
+	email, err := NewCommentNotificationEmail(post, comment, "https://news.example.com/posts/edit#comments").GenerateEmail()
+	require.NoError(t, err)
+
+	assert.Equal(t, "New comment on Rates & <markets>", email.Subject)
+	assert.Contains(t, email.HTML, "Ann commented on March 4, 2025 at 3:30 PM:")
+	assert.Contains(t, email.HTML, "Great &lt;b&gt;post&lt;/b&gt;")
+	assert.Contains(t, email.HTML, "The comment waits for moderation before it is shown.")
+	assert.Contains(t, email.HTML, "Moderate comments")
+	assert.Contains(t, email.Text, "Great <b>post</b>")
+	assert.Contains(t, email.Text, "https://news.example.com/posts/edit#comments")
+}
+
+func TestCommentNotificationEmail_ApprovedReply(t *testing.T) {
+	post := &models.Post{Title: "Rates"}
+	comment := &models.Comment{
+		ParentID:  primitive.NewObjectID(),
+		Author:    "Bob",
+		Content:   "Agreed",
+		Status:    models.CommentApproved,
+		CreatedAt: time.Now(),
+	}
+
+	email, err := NewCommentNotificationEmail(post, comment, "https://news.example.com/posts/rates#comment-1").GenerateEmail()
+	require.NoError(t, err)
+
+	assert.Contains(t, email.HTML, "Bob replied to a comment")
+	assert.Contains(t, email.HTML, "Open the comment")
+	assert.NotContains(t, email.HTML, "waits for moderation")
+	assert.NotContains(t, email.Text, "waits for moderation")
+}
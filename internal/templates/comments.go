@@ -0,0 +1,145 @@
+package templates
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"math"
+	"newsteller/internal/antispam"
+	"newsteller/internal/models"
+)
+
+// CommentSection is the thread of a post and the form to comment on it. It is loaded into
+// the post page and reloads itself when a published comment is added.
+type CommentSection struct {
+	post      *models.Post
+	comments  []models.Comment
+	open      bool
+	challenge antispam.Challenge
+}
+
+type commentSectionData struct {
+	PostID   string
+	Comments []models.Comment
+	Open     bool
+	Antispam antispamFieldsData
+}
+
+// NewCommentSection returns the section of the post, the comments are in thread order.
+// The form is left out when open is false.
+func NewCommentSection(post *models.Post, comments []models.Comment, open bool, challenge antispam.Challenge) *CommentSection {
+	return &CommentSection{post: post, comments: comments, open: open, challenge: challenge}
+}
+
+func (s *CommentSection) GeneratePage() (string, error) {
+	return render("comment-section", commentSectionData{
+		PostID:   s.post.ID.Hex(),
+		Comments: s.comments,
+		Open:     s.open,
+		Antispam: antispamFieldsData{Challenge: s.challenge},
+	})
+}
+
+// CommentMessage is the fragment swapped into the comment form.
+type CommentMessage struct {
+	message
+	challenge antispam.Challenge
+}
+
+func NewCommentMessage(kind MessageKind, text string) *CommentMessage {
+	return &CommentMessage{message: message{Kind: kind, Text: text}}
+}
+
+// WithChallenge replaces the anti-spam fields of the form, the challenge of a submitted
+// form can not be used again.
+func (m *CommentMessage) WithChallenge(challenge antispam.Challenge) *CommentMessage {
+	m.challenge = challenge
+	return m
+}
+
+func (m *CommentMessage) GeneratePage() (string, error) {
+	return renderMessageWithChallenge(m.message, m.challenge)
+}
+
+// CommentQueue is a page of the comment moderation queue. It is loaded into the post
+// moderation page and reloads itself after every moderation action.
+type CommentQueue struct {
+	status   models.CommentStatus
+	comments []models.Comment
+	posts    map[primitive.ObjectID]string
+	counts   map[models.CommentStatus]int64
+	page     int
+	limit    int
+	total    int64
+}
+
+type commentQueueTab struct {
+	Status models.CommentStatus
+	Label  string
+	Count  int64
+	Active bool
+}
+
+type commentQueueData struct {
+	Status      models.CommentStatus
+	Tabs        []commentQueueTab
+	Comments    []models.Comment
+	Posts       map[primitive.ObjectID]string
+	CurrentPage int
+	TotalPages  int
+	HasPrev     bool
+	HasNext     bool
+	PrevPage    int
+	NextPage    int
+}
+
+var commentStatusLabels = map[models.CommentStatus]string{
+	models.CommentPending:  "Pending",
+	models.CommentApproved: "Approved",
+	models.CommentSpam:     "Spam",
+}
+
+// NewCommentQueue returns the page of comments with the status, posts holds the titles of
+// the commented posts and counts the number of comments of every status.
+func NewCommentQueue(
+	status models.CommentStatus,
+	comments []models.Comment,
+	posts map[primitive.ObjectID]string,
+	counts map[models.CommentStatus]int64,
+	page, limit int,
+	total int64,
+) *CommentQueue {
+	return &CommentQueue{
+		status:   status,
+		comments: comments,
+		posts:    posts,
+		counts:   counts,
+		page:     page,
+		limit:    limit,
+		total:    total,
+	}
+}
+
+func (q *CommentQueue) GeneratePage() (string, error) {
+	totalPages := int(math.Ceil(float64(q.total) / float64(q.limit)))
+	tabs := make([]commentQueueTab, 0, len(models.CommentStatuses))
+	for _, status := range models.CommentStatuses {
+		tabs = append(tabs, commentQueueTab{
+			Status: status,
+			Label:  commentStatusLabels[status],
+			Count:  q.counts[status],
+			Active: status == q.status,
+		})
+	}
+
+	return render("comment-queue", commentQueueData{
+		Status:      q.status,
+		Tabs:        tabs,
+		Comments:    q.comments,
+		Posts:       q.posts,
+		CurrentPage: q.page,
+		TotalPages:  totalPages,
+		HasPrev:     q.page > 1,
+		HasNext:     q.page < totalPages,
+		PrevPage:    q.page - 1,
+		NextPage:    q.page + 1,
+	})
+}
@@ -0,0 +1,110 @@
+package templates
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"newsteller/internal/antispam"
+	"newsteller/internal/models"
+)
+
+func TestCommentSection_GeneratePage(t *testing.T) {
+	post := &models.Post{ID: primitive.NewObjectID(), Title: "Rates"}
+	parent := models.Comment{
+		ID:        primitive.NewObjectID(),
+		Author:    "Ann <admin>",
+		Content:   "Great <b>post</b>",
+		CreatedAt: time.Date(2025, time.March, 4, 15, 30, 0, 0, time.UTC),
+	}
+	reply := models.Comment{ID: primitive.NewObjectID(), ParentID: parent.ID, Depth: 1, Author: "Bob", Content: "Agreed"}
+
+	t.Run("Positive: Open", func(t *testing.T) {
+		challenge := antispam.Challenge{Form: antispam.FormComment, Token: "abc.def", Difficulty: 16}
+		html, err := NewCommentSection(post, []models.Comment{parent, reply}, true, challenge).GeneratePage()
+		require.NoError(t, err)
+
+		assert.Contains(t, html, `hx-get="/posts/`+post.ID.Hex()+`/comments"`)
+		assert.Contains(t, html, "Comments (2)")
+		assert.Contains(t, html, `<article id="comment-`+parent.ID.Hex()+`" class="comment" style="--depth: 0">`)
+		assert.Contains(t, html, `<article id="comment-`+reply.ID.Hex()+`" class="comment" style="--depth: 1">`)
+		assert.Contains(t, html, "Ann &lt;admin&gt;")
+		assert.Contains(t, html, "Great &lt;b&gt;post&lt;/b&gt;")
+		assert.Contains(t, html, "March 4, 2025 at 3:30 PM")
+		assert.Contains(t, html, `hx-post="/posts/`+post.ID.Hex()+`/comments"`)
+		assert.Contains(t, html, `data-reply-to="`+reply.ID.Hex()+`" data-reply-author="Bob"`)
+		assert.Contains(t, html, `data-reply-author="Ann &lt;admin&gt;"`, "reply authors should be escaped")
+		assert.NotContains(t, html, "onclick", "inline handlers are blocked by the CSP")
+		assert.Contains(t, html, `<div id="antispam-comment" class="antispam"`)
+		assert.Contains(t, html, `<input type="hidden" name="challenge" value="abc.def">`)
+		assert.NotContains(t, html, "hx-swap-oob")
+		assert.NotContains(t, html, "Comments are closed.")
+	})
+
+	t.Run("Positive: Closed", func(t *testing.T) {
+		html, err := NewCommentSection(post, nil, false, antispam.Challenge{}).GeneratePage()
+		require.NoError(t, err)
+
+		assert.Contains(t, html, "No comments yet.")
+		assert.Contains(t, html, "Comments are closed.")
+		assert.NotContains(t, html, "<form")
+		assert.NotContains(t, html, "Reply</button>")
+		assert.NotContains(t, html, "antispam")
+	})
+}
+
+func TestCommentQueue_GeneratePage(t *testing.T) {
+	postID := primitive.NewObjectID()
+	pending := models.Comment{
+		ID:        primitive.NewObjectID(),
+		PostID:    postID,
+		ParentID:  primitive.NewObjectID(),
+		Author:    "Eve",
+		Email:     "eve@example.com",
+		Content:   "Buy now",
+		Status:    models.CommentPending,
+		SpamScore: 0.734,
+	}
+	counts := map[models.CommentStatus]int64{models.CommentPending: 21, models.CommentSpam: 3}
+
+	html, err := NewCommentQueue(
+		models.CommentPending,
+		[]models.Comment{pending},
+		map[primitive.ObjectID]string{postID: "Rates & markets"},
+		counts,
+		2, 20, 21,
+	).GeneratePage()
+	require.NoError(t, err)
+
+	assert.Contains(t, html, `hx-get="/comments/moderation?status=pending&page=2"`)
+	assert.Contains(t, html, `<button class="active"`)
+	assert.Contains(t, html, "Pending (21)")
+	assert.Contains(t, html, "Approved (0)")
+	assert.Contains(t, html, "Spam (3)")
+	assert.Contains(t, html, "&lt;eve@example.com&gt;")
+	assert.Contains(t, html, `<a href="/posts/`+postID.Hex()+`#comments">Rates &amp; markets</a>`)
+	assert.Contains(t, html, ", in reply")
+	assert.Contains(t, html, "spam score 73%")
+	assert.Contains(t, html, `hx-post="/comments/`+pending.ID.Hex()+`/approve"`)
+	assert.Contains(t, html, `hx-post="/comments/`+pending.ID.Hex()+`/spam"`)
+	assert.Contains(t, html, `hx-delete="/comments/`+pending.ID.Hex()+`"`)
+	assert.Contains(t, html, "Page 2 of 2")
+}
+
+func TestCommentMessage_GeneratePage(t *testing.T) {
+	html, err := NewCommentMessage(MessageError, "Comments are closed.").GeneratePage()
+	require.NoError(t, err)
+
+	assert.Equal(t, `<div class="message error">Comments are closed.</div>`, html)
+
+	challenge := antispam.Challenge{Form: antispam.FormComment, Token: "abc.def", Difficulty: 16}
+	html, err = NewCommentMessage(MessageSuccess, "Thanks!").WithChallenge(challenge).GeneratePage()
+	require.NoError(t, err)
+
+	assert.Contains(t, html, `<div class="message success">Thanks!</div>`)
+	assert.Contains(t, html, `<div id="antispam-comment" class="antispam" hx-swap-oob="true"`, "the used challenge should be replaced")
+	assert.Contains(t, html, `value="abc.def"`)
+}
@@ -9,7 +9,7 @@ import (
 
 // todo
 func TestCreatePage_GeneratePage(t *testing.T) {
-	page := NewCreatePage()
+	page := NewCreatePage(nil, nil)
 	html, err := page.GeneratePage()
 	html = strings.Join(strings.Fields(html), " ")
 
@@ -23,6 +23,9 @@ func TestCreatePage_GeneratePage(t *testing.T) {
 	assert.Contains(t, html, `<input type="text" id="title" name="title"`, "HTML should contain title input")
 	assert.Contains(t, html, `<label for="content">Content</label>`, "HTML should contain content label")
 	assert.Contains(t, html, `<textarea id="content" name="content"`, "HTML should contain content textarea")
+	assert.Contains(t, html, `<input type="text" id="tags" name="tags"`, "HTML should contain tags input")
+	assert.Contains(t, html, `<input type="text" id="author" name="author"`, "HTML should contain author input")
+	assert.Contains(t, html, `hx-include="#title, #content, #tags, #category, #hero_image, #author, #author_email, #comments_disabled"`, "HTML should submit the tags, hero image, author and comment settings")
 	assert.Contains(t, html, `<button type="submit" id="submit-btn" class="btn btn-primary">`, "HTML should contain submit button")
 	assert.Contains(t, html, `<a href="/home" class="btn btn-secondary">Main Menu</a>`, "HTML should contain main menu button")
 
@@ -0,0 +1,103 @@
+package templates
+
+import (
+	"fmt"
+	"newsteller/internal/models"
+)
+
+const digestEmailHTML = `{{define "content"}}
+<h1 style="font-size: 22px; margin: 0 0 5px 0;">Your {{.Frequency}} digest</h1>
+<p style="margin: 0 0 25px 0; color: #666;">
+    {{len .Items}} new {{if eq (len .Items) 1}}post{{else}}posts{{end}} on Newsteller.
+</p>
+{{template "items" .Items}}
+{{template "preferences" .PreferencesURL}}
+{{end}}`
+
+// emailItemsHTML lists posts in emails, it is appended to the content templates using it.
+const emailItemsHTML = `{{define "items"}}{{range .}}
+<div style="padding: 15px 0; border-top: 1px solid #e9ecef;">
+    <a href="{{.URL}}" style="color: #333; font-size: 18px; font-weight: 600; text-decoration: none;">{{.Post.Title}}</a>
+    <div style="color: #999; font-size: 13px; margin: 5px 0;">{{formatDate .Post.CreatedAt}}</div>
+    <p style="margin: 0 0 10px 0; color: #666;">{{truncateContent .Post.Content 200}}</p>
+    <a href="{{.URL}}" style="color: #007bff; text-decoration: none; font-weight: 500;">Read more →</a>
+</div>
+{{end}}{{end}}`
+
+// emailPreferencesHTML links the preferences page of the subscriber when the URL is not empty.
+const emailPreferencesHTML = `{{define "preferences"}}{{if .}}
+<p style="margin: 20px 0 0 0; text-align: center; color: #999; font-size: 13px;">
+    Not interested in every topic? <a href="{{.}}" style="color: #007bff;">Choose the topics you follow</a>
+</p>
+{{end}}{{end}}`
+
+const digestEmailText = `{{define "content"}}Your {{.Frequency}} digest
+
+{{len .Items}} new {{if eq (len .Items) 1}}post{{else}}posts{{end}} on Newsteller.
+{{template "items" .Items}}{{template "preferences" .PreferencesURL}}{{end}}`
+
+const emailItemsText = `{{define "items"}}{{range .}}
+{{.Post.Title}}
+{{formatDate .Post.CreatedAt}}
+{{truncateContent .Post.Content 200}}
+Read more: {{.URL}}
+{{end}}{{end}}`
+
+const emailPreferencesText = `{{define "preferences"}}{{if .}}
+Choose the topics you follow: {{.}}{{end}}{{end}}`
+
+// DigestItem is a post listed in a digest with its absolute URL.
+type DigestItem struct {
+	Post models.Post
+	URL  string
+}
+
+type DigestEmail struct {
+	frequency      models.DigestFrequency
+	items          []DigestItem
+	unsubscribeURL string
+	preferencesURL string
+}
+
+type digestEmailData struct {
+	Subject        string
+	Frequency      models.DigestFrequency
+	Items          []DigestItem
+	UnsubscribeURL string
+	PreferencesURL string
+}
+
+func NewDigestEmail(
+	frequency models.DigestFrequency,
+	items []DigestItem,
+	unsubscribeURL string,
+	preferencesURL string,
+) *DigestEmail {
+	return &DigestEmail{
+		frequency:      frequency,
+		items:          items,
+		unsubscribeURL: unsubscribeURL,
+		preferencesURL: preferencesURL,
+	}
+}
+
+func (e *DigestEmail) GenerateEmail() (*Email, error) {
+	subject := fmt.Sprintf("Your %s Newsteller digest", e.frequency)
+	if len(e.items) > 0 {
+		subject += ": " + e.items[0].Post.Title
+	}
+
+	return renderEmail(
+		"digest",
+		subject,
+		digestEmailHTML+emailItemsHTML+emailPreferencesHTML,
+		digestEmailText+emailItemsText+emailPreferencesText,
+		digestEmailData{
+			Subject:        subject,
+			Frequency:      e.frequency,
+			Items:          e.items,
+			UnsubscribeURL: e.unsubscribeURL,
+			PreferencesURL: e.preferencesURL,
+		},
+	)
+}
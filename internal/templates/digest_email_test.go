@@ -0,0 +1,57 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"newsteller/internal/models"
+)
+
+func TestDigestEmail_GenerateEmail(t *testing.T) {
+	items := []DigestItem{
+		{
+			Post: models.Post{Title: "Newest <post>", Content: "Fresh content", CreatedAt: time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC)},
+			URL:  "https://news.example.com/posts/1",
+		},
+		{
+			Post: models.Post{Title: "Older post", Content: "Older content", CreatedAt: time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)},
+			URL:  "https://news.example.com/posts/2",
+		},
+	}
+	unsubscribeURL := "https://news.example.com/subscribers/unsubscribe?token=abc"
+	preferencesURL := "https://news.example.com/subscribers/preferences?token=abc"
+
+	email, err := NewDigestEmail(models.DigestWeekly, items, unsubscribeURL, preferencesURL).GenerateEmail()
+	require.NoError(t, err)
+
+	assert.Equal(t, "Your weekly Newsteller digest: Newest <post>", email.Subject)
+
+	// HTML alternative
+	html := strings.Join(strings.Fields(email.HTML), " ")
+	assert.Contains(t, html, "Your weekly digest")
+	assert.Contains(t, html, "2 new posts on Newsteller.")
+	assert.Contains(t, html, "Newest &lt;post&gt;")
+	assert.Contains(t, html, `<a href="https://news.example.com/posts/2"`)
+	assert.Contains(t, html, "Mar 04, 2025")
+	assert.Contains(t, html, "Unsubscribe</a>")
+	assert.Contains(t, html, `<a href="https://news.example.com/subscribers/preferences?token=abc" style="color: #007bff;">Choose the topics you follow</a>`)
+
+	// Plain-text alternative
+	assert.Contains(t, email.Text, "Newest <post>\nMar 04, 2025\nFresh content\nRead more: https://news.example.com/posts/1")
+	assert.Contains(t, email.Text, "Unsubscribe: "+unsubscribeURL)
+	assert.Contains(t, email.Text, "Choose the topics you follow: "+preferencesURL)
+}
+
+func TestDigestEmail_SinglePost(t *testing.T) {
+	items := []DigestItem{{Post: models.Post{Title: "Only post"}, URL: "https://news.example.com/posts/1"}}
+
+	email, err := NewDigestEmail(models.DigestDaily, items, "", "").GenerateEmail()
+	require.NoError(t, err)
+
+	assert.Equal(t, "Your daily Newsteller digest: Only post", email.Subject)
+	assert.Contains(t, email.Text, "1 new post on Newsteller.")
+	assert.NotContains(t, email.Text, "Choose the topics you follow")
+}
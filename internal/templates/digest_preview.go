@@ -0,0 +1,55 @@
+package templates
+
+import (
+	"newsteller/internal/models"
+	"time"
+)
+
+type DigestPreview struct {
+	frequency  models.DigestFrequency
+	since      time.Time
+	email      *Email
+	postsCount int
+	topic      string
+	topics     []string
+}
+
+type digestPreviewData struct {
+	Frequency  models.DigestFrequency
+	Since      time.Time
+	Email      *Email
+	PostsCount int
+	Topic      string
+	Topics     []string
+}
+
+// NewDigestPreview renders the digest preview page, email is nil when there is nothing to send.
+// The preview is built for a subscriber following the topic, or every topic when it is empty.
+func NewDigestPreview(
+	frequency models.DigestFrequency,
+	since time.Time,
+	email *Email,
+	postsCount int,
+	topic string,
+	topics []string,
+) *DigestPreview {
+	return &DigestPreview{
+		frequency:  frequency,
+		since:      since,
+		email:      email,
+		postsCount: postsCount,
+		topic:      topic,
+		topics:     topics,
+	}
+}
+
+func (d *DigestPreview) GeneratePage() (string, error) {
+	return render("digest-preview", &digestPreviewData{
+		Frequency:  d.frequency,
+		Since:      d.since,
+		Email:      d.email,
+		PostsCount: d.postsCount,
+		Topic:      d.topic,
+		Topics:     d.topics,
+	})
+}
@@ -0,0 +1,59 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"newsteller/internal/models"
+)
+
+func TestDigestPreview_GeneratePage(t *testing.T) {
+	since := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	t.Run("Positive: Preview with posts", func(t *testing.T) {
+		email := &Email{
+			Subject: "Your weekly Newsteller digest: Post",
+			HTML:    `<p style="color: #333;">Post</p>`,
+			Text:    "Post & more",
+		}
+
+		html, err := NewDigestPreview(models.DigestWeekly, since, email, 1, "", nil).GeneratePage()
+		require.NoError(t, err)
+		html = strings.Join(strings.Fields(html), " ")
+
+		assert.Contains(t, html, "<title>Digest Preview</title>")
+		assert.Contains(t, html, "published since March 1, 2025 at 8:00 AM")
+		assert.Contains(t, html, `<a href="/digests/preview?frequency=weekly" class="active">Weekly</a>`)
+		assert.Contains(t, html, "<h2>Your weekly Newsteller digest: Post</h2>")
+		assert.Contains(t, html, "1 post · HTML version")
+		assert.Contains(t, html, `srcdoc="&lt;p style=&#34;color: #333;&#34;&gt;Post&lt;/p&gt;"`)
+		assert.Contains(t, html, `<pre class="preview-text">Post &amp; more</pre>`)
+		assert.NotContains(t, html, "Nothing to send")
+		assert.NotContains(t, html, `<form class="topic-filter"`)
+	})
+
+	t.Run("Positive: Empty period", func(t *testing.T) {
+		html, err := NewDigestPreview(models.DigestDaily, since, nil, 0, "", nil).GeneratePage()
+		require.NoError(t, err)
+		html = strings.Join(strings.Fields(html), " ")
+
+		assert.Contains(t, html, `<a href="/digests/preview?frequency=daily" class="active">Daily</a>`)
+		assert.Contains(t, html, "Nothing to send")
+		assert.NotContains(t, html, "<iframe")
+	})
+
+	t.Run("Positive: Preview for a topic", func(t *testing.T) {
+		html, err := NewDigestPreview(models.DigestDaily, since, nil, 0, "go", []string{"databases", "go"}).GeneratePage()
+		require.NoError(t, err)
+		html = strings.Join(strings.Fields(html), " ")
+
+		assert.Contains(t, html, `<a href="/digests/preview?frequency=weekly&topic=go" >Weekly</a>`)
+		assert.Contains(t, html, `<input type="hidden" name="frequency" value="daily">`)
+		assert.Contains(t, html, `<option value="go" selected>go</option>`)
+		assert.Contains(t, html, `<option value="databases" >databases</option>`)
+		assert.Contains(t, html, "No posts tagged go were published in this period")
+	})
+}
@@ -20,9 +20,11 @@ func TestEdit_GeneratePage(t *testing.T) {
 		Content:   "This is the test post content.",
 		CreatedAt: now.Add(-24 * time.Hour), // Yesterday
 		UpdatedAt: now,                      // Now
+		Tags:      []string{"go", "databases"},
+		Author:    "Jane Doe",
 	}
 
-	editPage := NewEdit(mockPost)
+	editPage := NewEdit(mockPost, nil, nil)
 	html, err := editPage.GeneratePage()
 	html = strings.Join(strings.Fields(html), " ")
 
@@ -43,6 +45,8 @@ func TestEdit_GeneratePage(t *testing.T) {
 	// 2. Check changeable elements
 	assert.Contains(t, html, fmt.Sprintf(`value="%s"`, mockPost.Title), "HTML should display the correct post title in input")
 	assert.Contains(t, html, fmt.Sprintf(`>%s</textarea>`, mockPost.Content), "HTML should display the correct post content in textarea")
+	assert.Contains(t, html, `<input type="text" id="tags" name="tags" value="go, databases"`, "HTML should display the post tags")
+	assert.Contains(t, html, `<input type="text" id="author" name="author" value="Jane Doe"`, "HTML should display the post author")
 	assert.Contains(t, html, fmt.Sprintf(`<div class="metadata-value post-id">%s</div>`, mockPost.ID.Hex()), "HTML should display the correct Post ID")
 
 	// Check formatted dates using the funcMap logic
@@ -60,7 +64,7 @@ func TestEdit_GeneratePage(t *testing.T) {
 func TestEdit_GeneratePage_NilPost(t *testing.T) {
 	// Test with a nil post, though the template might panic.
 	// The `html/template` package will likely render empty strings for nil fields.
-	editPage := NewEdit(nil) // Pass nil post
+	editPage := NewEdit(nil, nil, nil) // Pass nil post
 	html, err := editPage.GeneratePage()
 
 	// Depending on how the template handles nil, it might error out or render empty.
@@ -74,7 +78,7 @@ func TestEdit_GeneratePage_NilPost(t *testing.T) {
 func TestEdit_GeneratePage_EmptyPost(t *testing.T) {
 	// Test with an empty (zero-value) post
 	emptyPost := &models.Post{} // Zero values for fields
-	editPage := NewEdit(emptyPost)
+	editPage := NewEdit(emptyPost, nil, nil)
 	html, err := editPage.GeneratePage()
 
 	assert.NoError(t, err, "GeneratePage should not return an error with an empty post")
@@ -0,0 +1,164 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+	textTemplate "text/template"
+)
+
+// Email is a rendered email with an HTML and a plain-text alternative.
+type Email struct {
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// AddOpenPixel embeds an invisible image loading the URL into the HTML alternative,
+// so opening the email can be counted.
+func (e *Email) AddOpenPixel(url string) {
+	pixel := `<img src="` + template.HTMLEscapeString(url) + `" width="1" height="1" alt="" style="display: block; border: 0;">`
+	if i := strings.LastIndex(e.HTML, "</body>"); i >= 0 {
+		e.HTML = e.HTML[:i] + pixel + "\n" + e.HTML[i:]
+		return
+	}
+
+	e.HTML += pixel
+}
+
+type EmailTemplate interface {
+	GenerateEmail() (*Email, error)
+}
+
+// emailLayoutHTML wraps the "content" block of every HTML email.
+// Email clients ignore <style> blocks, so styles are inlined.
+const emailLayoutHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.Subject}}</title>
+</head>
+<body style="margin: 0; padding: 20px; background-color: #f5f5f5; font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif;">
+<table role="presentation" width="100%" cellspacing="0" cellpadding="0">
+    <tr>
+        <td align="center">
+            <table role="presentation" width="600" cellspacing="0" cellpadding="0" style="max-width: 600px; width: 100%;">
+                <tr>
+                    <td style="padding: 20px 0; text-align: center; color: #333; font-size: 24px; font-weight: 600;">
+                        Newsteller
+                    </td>
+                </tr>
+                <tr>
+                    <td style="background: white; border-radius: 12px; padding: 30px; color: #333; line-height: 1.5;">
+                        {{template "content" .}}
+                    </td>
+                </tr>
+                <tr>
+                    <td style="padding: 20px; text-align: center; color: #999; font-size: 12px;">
+                        {{if .UnsubscribeURL}}
+                        You receive this email because you subscribed to Newsteller.
+                        <a href="{{.UnsubscribeURL}}" style="color: #999;">Unsubscribe</a>
+                        {{else}}
+                        You receive this email because this address was entered on Newsteller.
+                        {{end}}
+                    </td>
+                </tr>
+            </table>
+        </td>
+    </tr>
+</table>
+</body>
+</html>`
+
+const emailLayoutText = `{{template "content" .}}
+
+--
+Newsteller
+{{if .UnsubscribeURL}}Unsubscribe: {{.UnsubscribeURL}}
+{{end}}`
+
+const confirmationEmailHTML = `{{define "content"}}
+<h1 style="font-size: 22px; margin: 0 0 15px 0;">Confirm your subscription</h1>
+<p style="margin: 0 0 25px 0; color: #666;">
+    Please confirm that you want to receive the Newsteller newsletter.
+    If you did not subscribe, just ignore this email.
+</p>
+<p style="margin: 0; text-align: center;">
+    <a href="{{.ConfirmURL}}"
+       style="display: inline-block; padding: 12px 24px; background: #007bff; color: white; border-radius: 8px; text-decoration: none; font-weight: 500;">
+        Confirm subscription
+    </a>
+</p>
+{{end}}`
+
+const confirmationEmailText = `{{define "content"}}Confirm your subscription
+
+Please confirm that you want to receive the Newsteller newsletter
+by opening the link below. If you did not subscribe, just ignore this email.
+
+{{.ConfirmURL}}{{end}}`
+
+type ConfirmationEmail struct {
+	confirmURL string
+}
+
+type confirmationEmailData struct {
+	Subject        string
+	ConfirmURL     string
+	UnsubscribeURL string
+}
+
+func NewConfirmationEmail(confirmURL string) *ConfirmationEmail {
+	return &ConfirmationEmail{confirmURL: confirmURL}
+}
+
+func (e *ConfirmationEmail) GenerateEmail() (*Email, error) {
+	subject := "Confirm your Newsteller subscription"
+
+	return renderEmail(
+		"confirmation",
+		subject,
+		confirmationEmailHTML,
+		confirmationEmailText,
+		confirmationEmailData{
+			Subject:    subject,
+			ConfirmURL: e.confirmURL,
+		},
+	)
+}
+
+// renderEmail renders both alternatives of an email. The content templates must define
+// a "content" block, the data must provide Subject and UnsubscribeURL fields for the layout.
+func renderEmail(name, subject, htmlContent, textContent string, data any) (*Email, error) {
+	htmlTmpl, err := template.New(name).Funcs(funcMap).Parse(emailLayoutHTML)
+	if err == nil {
+		_, err = htmlTmpl.Parse(htmlContent)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse html template: %w", err)
+	}
+
+	textTmpl, err := textTemplate.New(name).Funcs(textTemplate.FuncMap(funcMap)).Parse(emailLayoutText)
+	if err == nil {
+		_, err = textTmpl.Parse(textContent)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse text template: %w", err)
+	}
+
+	var htmlBuf, textBuf bytes.Buffer
+	if err = htmlTmpl.Execute(&htmlBuf, data); err != nil {
+		return nil, fmt.Errorf("failed to execute html template: %w", err)
+	}
+	if err = textTmpl.Execute(&textBuf, data); err != nil {
+		return nil, fmt.Errorf("failed to execute text template: %w", err)
+	}
+
+	return &Email{
+		Subject: subject,
+		HTML:    htmlBuf.String(),
+		Text:    textBuf.String(),
+	}, nil
+}
@@ -0,0 +1,54 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirmationEmail_GenerateEmail(t *testing.T) {
+	confirmURL := "https://news.example.com/subscribers/confirm?token=abc&x=1"
+
+	email, err := NewConfirmationEmail(confirmURL).GenerateEmail()
+	require.NoError(t, err)
+
+	assert.Equal(t, "Confirm your Newsteller subscription", email.Subject)
+
+	// HTML alternative
+	assert.Contains(t, email.HTML, "<title>Confirm your Newsteller subscription</title>")
+	assert.Contains(t, email.HTML, `<a href="https://news.example.com/subscribers/confirm?token=abc&amp;x=1"`)
+	assert.Contains(t, email.HTML, "Confirm subscription")
+	assert.NotContains(t, email.HTML, "Unsubscribe", "Confirmation email should not contain unsubscribe link")
+
+	// Plain-text alternative
+	assert.True(t, strings.HasPrefix(email.Text, "Confirm your subscription"))
+	assert.Contains(t, email.Text, confirmURL, "Text alternative should not escape the link")
+	assert.NotContains(t, email.Text, "<")
+	assert.Contains(t, email.Text, "--\nNewsteller")
+}
+
+func TestRenderEmail_UnsubscribeLink(t *testing.T) {
+	data := struct {
+		Subject        string
+		UnsubscribeURL string
+	}{"Subject", "https://news.example.com/subscribers/unsubscribe?token=abc"}
+
+	email, err := renderEmail("test", data.Subject, `{{define "content"}}Hi{{end}}`, `{{define "content"}}Hi{{end}}`, data)
+	require.NoError(t, err)
+
+	assert.Contains(t, email.HTML, `<a href="https://news.example.com/subscribers/unsubscribe?token=abc" style="color: #999;">Unsubscribe</a>`)
+	assert.Contains(t, email.Text, "Unsubscribe: https://news.example.com/subscribers/unsubscribe?token=abc")
+}
+
+func TestEmail_AddOpenPixel(t *testing.T) {
+	email, err := NewConfirmationEmail("https://news.example.com/confirm").GenerateEmail()
+	require.NoError(t, err)
+	text := email.Text
+
+	email.AddOpenPixel("https://news.example.com/track/open?token=a&b")
+
+	assert.Contains(t, email.HTML, `<img src="https://news.example.com/track/open?token=a&amp;b" width="1" height="1" alt="" style="display: block; border: 0;">`+"\n</body>")
+	assert.Equal(t, text, email.Text, "Plain-text alternative should not change")
+}
@@ -0,0 +1,26 @@
+package templates
+
+// ErrorPage explains a rejected request, as a standalone page or as a message fragment
+// to htmx requests.
+type ErrorPage struct {
+	message
+	fragment bool
+}
+
+func NewErrorPage(title, text string) *ErrorPage {
+	return &ErrorPage{message: message{Kind: MessageError, Title: title, Text: text}}
+}
+
+// AsFragment renders the message only, for requests swapping it into a page.
+func (e *ErrorPage) AsFragment() *ErrorPage {
+	e.fragment = true
+	return e
+}
+
+func (e *ErrorPage) GeneratePage() (string, error) {
+	if e.fragment {
+		return renderMessage(e.message)
+	}
+
+	return render("subscription", e.message)
+}
@@ -0,0 +1,126 @@
+package templates
+
+import (
+	"newsteller/internal/models"
+	"slices"
+)
+
+// IssueEditor is the composer page of a newsletter issue.
+type IssueEditor struct {
+	issue      *models.Issue
+	posts      []models.Post
+	recipients int64
+}
+
+type issueEditorData struct {
+	Issue            *models.Issue
+	Editable         bool
+	PostsData        issuePostsData
+	Recipients       int64
+	SubscribedAfter  string
+	SubscribedBefore string
+}
+
+func NewIssueEditor(issue *models.Issue, posts []models.Post, recipients int64) *IssueEditor {
+	return &IssueEditor{
+		issue:      issue,
+		posts:      posts,
+		recipients: recipients,
+	}
+}
+
+func (e *IssueEditor) GeneratePage() (string, error) {
+	data := &issueEditorData{
+		Issue:      e.issue,
+		Editable:   e.issue.Status == models.IssueDraft,
+		PostsData:  newIssuePostsData(e.issue, e.posts),
+		Recipients: e.recipients,
+	}
+	if !e.issue.Segment.SubscribedAfter.IsZero() {
+		data.SubscribedAfter = e.issue.Segment.SubscribedAfter.Format("2006-01-02")
+	}
+	if !e.issue.Segment.SubscribedBefore.IsZero() {
+		data.SubscribedBefore = e.issue.Segment.SubscribedBefore.Format("2006-01-02")
+	}
+
+	return render("issue-editor", data)
+}
+
+// IssuePosts is the fragment listing the posts of an issue in the composer.
+type IssuePosts struct {
+	issue *models.Issue
+	posts []models.Post
+}
+
+type issuePostsData struct {
+	IssueID  string
+	Editable bool
+	Posts    []models.Post
+}
+
+func newIssuePostsData(issue *models.Issue, posts []models.Post) issuePostsData {
+	return issuePostsData{
+		IssueID:  issue.ID.Hex(),
+		Editable: issue.Status == models.IssueDraft,
+		Posts:    posts,
+	}
+}
+
+func NewIssuePosts(issue *models.Issue, posts []models.Post) *IssuePosts {
+	return &IssuePosts{
+		issue: issue,
+		posts: posts,
+	}
+}
+
+func (p *IssuePosts) GeneratePage() (string, error) {
+	return render("issue-posts-fragment", newIssuePostsData(p.issue, p.posts))
+}
+
+// IssuePostPicker is the fragment of posts found by the composer search.
+type IssuePostPicker struct {
+	issue *models.Issue
+	posts []models.Post
+}
+
+type issuePickerPost struct {
+	Post     models.Post
+	Selected bool
+}
+
+type issuePickerData struct {
+	IssueID string
+	Posts   []issuePickerPost
+}
+
+func NewIssuePostPicker(issue *models.Issue, posts []models.Post) *IssuePostPicker {
+	return &IssuePostPicker{
+		issue: issue,
+		posts: posts,
+	}
+}
+
+func (p *IssuePostPicker) GeneratePage() (string, error) {
+	data := &issuePickerData{IssueID: p.issue.ID.Hex()}
+	for _, post := range p.posts {
+		data.Posts = append(data.Posts, issuePickerPost{
+			Post:     post,
+			Selected: slices.Contains(p.issue.PostIDs, post.ID),
+		})
+	}
+
+	return render("issue-post-picker", data)
+}
+
+// IssueMessage is the fragment swapped into the forms of the composer.
+type IssueMessage struct {
+	message
+}
+
+func NewIssueMessage(kind MessageKind, text string) *IssueMessage {
+	return &IssueMessage{message{Kind: kind, Text: text}}
+}
+
+func (m *IssueMessage) GeneratePage() (string, error) {
+	return renderMessage(m.message)
+}
@@ -0,0 +1,114 @@
+package templates
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"newsteller/internal/models"
+)
+
+func createMockIssue(status models.IssueStatus, posts []models.Post) *models.Issue {
+	issue := &models.Issue{
+		ID:     primitive.NewObjectID(),
+		Name:   "Spring <issue>",
+		Intro:  "Hello readers",
+		Status: status,
+		Segment: models.Segment{
+			Source:          "home",
+			SubscribedAfter: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+			Topics:          []string{"go", "databases"},
+		},
+	}
+	for _, post := range posts {
+		issue.PostIDs = append(issue.PostIDs, post.ID)
+	}
+	return issue
+}
+
+func TestIssueEditor_GeneratePage_Draft(t *testing.T) {
+	posts := createMockPosts(2)
+	issue := createMockIssue(models.IssueDraft, posts)
+	id := issue.ID.Hex()
+
+	html, err := NewIssueEditor(issue, posts, 3).GeneratePage()
+	require.NoError(t, err)
+	html = strings.Join(strings.Fields(html), " ")
+
+	assert.Contains(t, html, "<title>Spring &lt;issue&gt; - Issue Composer</title>")
+	assert.Contains(t, html, fmt.Sprintf(`<form hx-put="/issues/%s" hx-target="#details-message">`, id))
+	assert.Contains(t, html, `<textarea id="intro" name="intro" placeholder="A few words opening the issue..." >Hello readers</textarea>`)
+	assert.Contains(t, html, `value="home"`)
+	assert.Contains(t, html, `<input type="date" id="subscribed_after" name="subscribed_after" value="2025-01-15" >`)
+	assert.Contains(t, html, `<input type="date" id="subscribed_before" name="subscribed_before" value="" >`)
+	assert.Contains(t, html, `name="topics" value="go, databases"`)
+	assert.Contains(t, html, `<input type="number" id="active_within_days" name="active_within_days" min="0" value="" placeholder="Any time" >`)
+	assert.Contains(t, html, "Currently 3 subscribers.")
+
+	// posts in the issue order with reorder controls
+	assert.Contains(t, html, `<div id="issue-posts">`)
+	assert.Contains(t, html, fmt.Sprintf(`hx-post="/issues/%s/posts/%s/move?direction=down"`, id, posts[0].ID.Hex()))
+	assert.Contains(t, html, fmt.Sprintf(`hx-delete="/issues/%s/posts/%s"`, id, posts[1].ID.Hex()))
+	assert.Contains(t, html, `<span class="issue-post-position">2.</span>`)
+	assert.Contains(t, html, fmt.Sprintf(`hx-get="/issues/%s/picker"`, id))
+
+	assert.Contains(t, html, fmt.Sprintf(`hx-post="/issues/%s/test"`, id))
+	assert.Contains(t, html, fmt.Sprintf(`hx-post="/issues/%s/schedule"`, id))
+	assert.Contains(t, html, `hx-confirm="Send this issue to 3 subscribers now?"`)
+	assert.NotContains(t, html, "cannot be edited")
+}
+
+func TestIssueEditor_GeneratePage_Scheduled(t *testing.T) {
+	posts := createMockPosts(1)
+	issue := createMockIssue(models.IssueScheduled, posts)
+	issue.ScheduledAt = time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	html, err := NewIssueEditor(issue, posts, 1).GeneratePage()
+	require.NoError(t, err)
+	html = strings.Join(strings.Fields(html), " ")
+
+	assert.Contains(t, html, "This issue is scheduled for March 1, 2025 at 9:00 AM and cannot be edited.")
+	assert.Contains(t, html, fmt.Sprintf(`hx-post="/issues/%s/unschedule"`, issue.ID.Hex()))
+	assert.Contains(t, html, "Currently 1 subscriber.")
+	assert.NotContains(t, html, "/schedule\"", "Scheduled issue should not be scheduled again")
+	assert.NotContains(t, html, "move?direction", "Scheduled issue should not be reordered")
+	assert.Contains(t, html, "/test\"", "Scheduled issue can still be test-sent")
+}
+
+func TestIssuePosts_GeneratePage(t *testing.T) {
+	posts := createMockPosts(2)
+	issue := createMockIssue(models.IssueDraft, posts)
+
+	html, err := NewIssuePosts(issue, posts).GeneratePage()
+	require.NoError(t, err)
+	html = strings.Join(strings.Fields(html), " ")
+
+	assert.True(t, strings.HasPrefix(html, `<div id="issue-posts">`), "Fragment should replace the posts list")
+	assert.Contains(t, html, `aria-label="Move up" disabled>↑</button>`, "First post cannot move up")
+	assert.Contains(t, html, `aria-label="Move down" disabled>↓</button>`, "Last post cannot move down")
+
+	html, err = NewIssuePosts(createMockIssue(models.IssueDraft, nil), nil).GeneratePage()
+	require.NoError(t, err)
+	assert.Contains(t, html, "No posts in this issue yet.")
+}
+
+func TestIssuePostPicker_GeneratePage(t *testing.T) {
+	posts := createMockPosts(2)
+	issue := createMockIssue(models.IssueDraft, posts[:1])
+
+	html, err := NewIssuePostPicker(issue, posts).GeneratePage()
+	require.NoError(t, err)
+	html = strings.Join(strings.Fields(html), " ")
+
+	assert.Contains(t, html, `<button class="btn-small" disabled>Added</button>`, "Selected post should not be added twice")
+	assert.Contains(t, html, fmt.Sprintf(`hx-vals='{"post_id": "%s"}'`, posts[1].ID.Hex()))
+	assert.NotContains(t, html, fmt.Sprintf(`hx-vals='{"post_id": "%s"}'`, posts[0].ID.Hex()))
+
+	html, err = NewIssuePostPicker(issue, nil).GeneratePage()
+	require.NoError(t, err)
+	assert.Contains(t, html, "No posts found.")
+}
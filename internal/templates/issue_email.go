@@ -0,0 +1,70 @@
+package templates
+
+import (
+	"newsteller/internal/models"
+)
+
+const issueEmailHTML = `{{define "content"}}
+<h1 style="font-size: 22px; margin: 0 0 15px 0;">{{.Issue.Name}}</h1>
+{{if .Issue.Intro}}
+<p style="margin: 0 0 25px 0; color: #333; white-space: pre-line;">{{.Issue.Intro}}</p>
+{{end}}
+{{template "items" .Items}}
+{{if .ArchiveURL}}
+<p style="margin: 20px 0 0 0; text-align: center; font-size: 13px;">
+    <a href="{{.ArchiveURL}}" style="color: #999;">View this issue in your browser</a>
+</p>
+{{end}}
+{{template "preferences" .PreferencesURL}}
+{{end}}`
+
+const issueEmailText = `{{define "content"}}{{.Issue.Name}}
+{{if .Issue.Intro}}
+{{.Issue.Intro}}
+{{end}}{{template "items" .Items}}{{if .ArchiveURL}}
+View this issue in your browser: {{.ArchiveURL}}{{end}}{{template "preferences" .PreferencesURL}}{{end}}`
+
+type IssueEmail struct {
+	issue          *models.Issue
+	items          []DigestItem
+	archiveURL     string
+	unsubscribeURL string
+	preferencesURL string
+}
+
+type issueEmailData struct {
+	Subject        string
+	Issue          *models.Issue
+	Items          []DigestItem
+	ArchiveURL     string
+	UnsubscribeURL string
+	PreferencesURL string
+}
+
+// NewIssueEmail renders a manually composed issue. Test sends pass no unsubscribe and preferences URLs.
+func NewIssueEmail(issue *models.Issue, items []DigestItem, archiveURL, unsubscribeURL, preferencesURL string) *IssueEmail {
+	return &IssueEmail{
+		issue:          issue,
+		items:          items,
+		archiveURL:     archiveURL,
+		unsubscribeURL: unsubscribeURL,
+		preferencesURL: preferencesURL,
+	}
+}
+
+func (e *IssueEmail) GenerateEmail() (*Email, error) {
+	return renderEmail(
+		"issue",
+		e.issue.Name,
+		issueEmailHTML+emailItemsHTML+emailPreferencesHTML,
+		issueEmailText+emailItemsText+emailPreferencesText,
+		issueEmailData{
+			Subject:        e.issue.Name,
+			Issue:          e.issue,
+			Items:          e.items,
+			ArchiveURL:     e.archiveURL,
+			UnsubscribeURL: e.unsubscribeURL,
+			PreferencesURL: e.preferencesURL,
+		},
+	)
+}
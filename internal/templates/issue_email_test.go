@@ -0,0 +1,55 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"newsteller/internal/models"
+)
+
+func TestIssueEmail_GenerateEmail(t *testing.T) {
+	issue := &models.Issue{Name: "Spring issue", Intro: "Hello readers,\nthis is our spring issue."}
+	items := []DigestItem{
+		{
+			Post: models.Post{Title: "Picked post", Content: "Content", CreatedAt: time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC)},
+			URL:  "https://news.example.com/posts/1",
+		},
+	}
+
+	t.Run("Positive: Issue for a subscriber", func(t *testing.T) {
+		email, err := NewIssueEmail(
+			issue,
+			items,
+			"https://news.example.com/newsletter/1",
+			"https://news.example.com/subscribers/unsubscribe?token=abc",
+			"https://news.example.com/subscribers/preferences?token=abc",
+		).GenerateEmail()
+		require.NoError(t, err)
+
+		assert.Equal(t, "Spring issue", email.Subject)
+		html := strings.Join(strings.Fields(email.HTML), " ")
+		assert.Contains(t, html, `<h1 style="font-size: 22px; margin: 0 0 15px 0;">Spring issue</h1>`)
+		assert.Contains(t, html, "Hello readers, this is our spring issue.")
+		assert.Contains(t, html, `<a href="https://news.example.com/posts/1"`)
+		assert.Contains(t, html, `<a href="https://news.example.com/newsletter/1" style="color: #999;">View this issue in your browser</a>`)
+		assert.Contains(t, html, "Unsubscribe</a>")
+
+		assert.True(t, strings.HasPrefix(email.Text, "Spring issue\n\nHello readers,\nthis is our spring issue.\n"))
+		assert.Contains(t, email.Text, "Picked post\nMar 04, 2025\nContent\nRead more: https://news.example.com/posts/1")
+		assert.Contains(t, email.Text, "View this issue in your browser: https://news.example.com/newsletter/1")
+		assert.Contains(t, email.Text, "Choose the topics you follow: https://news.example.com/subscribers/preferences?token=abc")
+	})
+
+	t.Run("Positive: Test send has no unsubscribe link", func(t *testing.T) {
+		email, err := NewIssueEmail(&models.Issue{Name: "Draft"}, items, "", "", "").GenerateEmail()
+		require.NoError(t, err)
+
+		assert.NotContains(t, email.HTML, "Unsubscribe")
+		assert.NotContains(t, email.HTML, "View this issue in your browser")
+		assert.NotContains(t, email.Text, "Unsubscribe")
+		assert.NotContains(t, email.Text, "Choose the topics you follow")
+	})
+}
@@ -0,0 +1,17 @@
+package templates
+
+import (
+	"newsteller/internal/models"
+)
+
+type Issues struct {
+	issues []models.Issue
+}
+
+func NewIssues(issues []models.Issue) *Issues {
+	return &Issues{issues: issues}
+}
+
+func (i *Issues) GeneratePage() (string, error) {
+	return render("issues", i.issues)
+}
@@ -0,0 +1,46 @@
+package templates
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"newsteller/internal/models"
+)
+
+func TestIssues_GeneratePage(t *testing.T) {
+	draft := models.Issue{ID: primitive.NewObjectID(), Name: "Draft issue", Status: models.IssueDraft}
+	sent := models.Issue{
+		ID:         primitive.NewObjectID(),
+		Name:       "Sent issue",
+		Status:     models.IssueSent,
+		PostIDs:    []primitive.ObjectID{primitive.NewObjectID()},
+		SentAt:     time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC),
+		Recipients: 42,
+	}
+
+	html, err := NewIssues([]models.Issue{draft, sent}).GeneratePage()
+	require.NoError(t, err)
+	html = strings.Join(strings.Fields(html), " ")
+
+	assert.Contains(t, html, "<title>Newsletter Issues</title>")
+	assert.Contains(t, html, `<form hx-post="/issues" hx-target="#issue-message">`)
+	assert.Contains(t, html, fmt.Sprintf(`<a href="/issues/%s/edit">Draft issue</a>`, draft.ID.Hex()), "Drafts should link the composer")
+	assert.Contains(t, html, fmt.Sprintf(`<a href="/newsletter/%s">Sent issue</a>`, sent.ID.Hex()), "Sent issues should link the archive")
+	assert.Contains(t, html, `<span class="status status-sent">sent</span>`)
+	assert.Contains(t, html, "<td>March 1, 2025 at 9:00 AM</td>")
+	assert.Contains(t, html, "<td>42</td>")
+	assert.Contains(t, html, fmt.Sprintf(`<a href="/stats/issues/%s">View</a>`, sent.ID.Hex()), "Sent issues should link their stats")
+}
+
+func TestIssues_GeneratePage_Empty(t *testing.T) {
+	html, err := NewIssues(nil).GeneratePage()
+	require.NoError(t, err)
+
+	assert.Contains(t, html, "No issues yet")
+	assert.NotContains(t, html, `<table class="issues-table">`)
+}
@@ -0,0 +1,167 @@
+package templates
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+/**
+Pages are laid out in HTML files embedded into the binary:
+  - layouts/base.html defines the "base" document, pages fill its "title", "head", "styles"
+    and "content" blocks
+  - partials/*.html define the blocks shared by pages, e.g. "head-scripts" or "post-list"
+  - pages/*.html are the pages, rendered by the name of their file without the extension
+
+Templates are parsed once at startup. Files of the override directory replace the embedded
+ones of the same path, e.g. <dir>/partials/feed-links.html, so a site can be rebranded
+without a fork. With reload the templates are parsed again on every render, for development.
+*/
+
+//go:embed html
+var embedded embed.FS
+
+// layouts - templates of the pages, the embedded ones until Load is called
+var layouts = mustParseLayouts()
+
+// Load parses the templates, files of dir replace the embedded ones when dir is not empty.
+// Templates are parsed again on every render when reload is set.
+func Load(dir string, reload bool) error {
+	set := &layoutSet{dir: dir, reload: reload}
+	if err := set.parse(); err != nil {
+		return err
+	}
+	layouts = set
+
+	return nil
+}
+
+func mustParseLayouts() *layoutSet {
+	set := &layoutSet{}
+	if err := set.parse(); err != nil {
+		panic(fmt.Sprintf("failed to parse embedded templates: %v", err))
+	}
+
+	return set
+}
+
+type layoutSet struct {
+	// dir - override directory, none when empty
+	dir    string
+	reload bool
+
+	mu sync.RWMutex
+	// partials - the layouts and partials, cloned by every page
+	partials *template.Template
+	pages    map[string]*template.Template
+}
+
+func (s *layoutSet) parse() error {
+	partials := template.New("partials").Funcs(funcMap)
+	for _, pattern := range []string{"layouts/*.html", "partials/*.html"} {
+		if err := s.parseFiles(partials, pattern); err != nil {
+			return err
+		}
+	}
+
+	names, err := fs.Glob(embedded, "html/pages/*.html")
+	if err != nil {
+		return err
+	}
+	pages := make(map[string]*template.Template, len(names))
+	for _, name := range names {
+		page, err := partials.Clone()
+		if err != nil {
+			return err
+		}
+		file := strings.TrimPrefix(name, "html/")
+		text, err := s.readFile(file)
+		if err != nil {
+			return err
+		}
+		pageName := strings.TrimSuffix(path.Base(file), ".html")
+		if _, err := page.New(pageName).Parse(text); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		pages[pageName] = page
+	}
+
+	s.mu.Lock()
+	s.partials, s.pages = partials, pages
+	s.mu.Unlock()
+
+	return nil
+}
+
+// parseFiles parses the embedded files matching the pattern into the template, the
+// blocks they define are named in the files.
+func (s *layoutSet) parseFiles(tmpl *template.Template, pattern string) error {
+	names, err := fs.Glob(embedded, "html/"+pattern)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		file := strings.TrimPrefix(name, "html/")
+		text, err := s.readFile(file)
+		if err != nil {
+			return err
+		}
+		if _, err := tmpl.New(file).Parse(text); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+	}
+
+	return nil
+}
+
+// readFile reads the template of the override directory, the embedded one when the
+// directory has none.
+func (s *layoutSet) readFile(file string) (string, error) {
+	if s.dir != "" {
+		data, err := os.ReadFile(filepath.Join(s.dir, filepath.FromSlash(file)))
+		if err == nil {
+			return string(data), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("failed to read %s: %w", file, err)
+		}
+	}
+
+	data, err := embedded.ReadFile("html/" + file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	return string(data), nil
+}
+
+func (s *layoutSet) reloaded() error {
+	if !s.reload {
+		return nil
+	}
+
+	return s.parse()
+}
+
+// page returns the parsed page, it must not be changed.
+func (s *layoutSet) page(name string) (*template.Template, error) {
+	if err := s.reloaded(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	page, ok := s.pages[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown page: %s", name)
+	}
+
+	return page.Lookup(name), nil
+}
@@ -0,0 +1,121 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"newsteller/internal/models"
+)
+
+// loadTemplates loads the templates of dir for the test, the embedded ones are restored after it.
+func loadTemplates(t *testing.T, dir string, reload bool) {
+	t.Helper()
+	embedded := layouts
+	t.Cleanup(func() { layouts = embedded })
+
+	require.NoError(t, Load(dir, reload))
+}
+
+// renderPage executes the text using the partials of the layouts, e.g. to render a partial alone.
+func renderPage(name, text string, data any) (string, error) {
+	layouts.mu.RLock()
+	tmpl, err := layouts.partials.Clone()
+	layouts.mu.RUnlock()
+	if err != nil {
+		return "", err
+	}
+	if _, err = tmpl.New(name).Parse(text); err != nil {
+		return "", err
+	}
+
+	return execute(tmpl.Lookup(name), data)
+}
+
+func writeTemplate(t *testing.T, dir, file, text string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(file))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(text), 0o644))
+}
+
+func TestLayouts_BaseLayout(t *testing.T) {
+	html, err := NewCreatePage(nil, nil).GeneratePage()
+	require.NoError(t, err)
+
+	assert.Regexp(t, `^<!DOCTYPE html>\n<html lang="en">`, html)
+	assert.Contains(t, html, "<title>Create New Post</title>")
+	assert.Contains(t, html, `<link rel="stylesheet" href="/static/site.`)
+	assert.Contains(t, html, `<div class="attachments-panel">`, "partials are shared by the pages")
+	assert.Contains(t, html, "</body>\n</html>")
+}
+
+func TestLayouts_Override(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "partials/feed-links.html", `{{define "feed-links"}}<link rel="alternate" href="/custom.xml">{{end}}`)
+	writeTemplate(t, dir, "layouts/base.html", `{{define "base"}}<main class="rebranded">{{block "content" .}}{{end}}</main>{{end}}`)
+	writeTemplate(t, dir, "partials/message.html", `{{define "message"}}<p class="notice {{.Kind}}">{{.Text}}</p>{{end}}`)
+	loadTemplates(t, dir, false)
+
+	html, err := NewHome([]models.Post{}, 1, 0, 10).GeneratePage()
+	require.NoError(t, err)
+	assert.Contains(t, html, `<main class="rebranded">`)
+	assert.NotContains(t, html, "<!DOCTYPE html>")
+
+	html, err = NewSubscriptionPage(MessageSuccess, "Subscribed", "Thanks.").GeneratePage()
+	require.NoError(t, err)
+	assert.Contains(t, html, `<main class="rebranded">`, "every page is laid out by the base layout")
+
+	html, err = NewErrorPage("Not found", "The page does not exist.").AsFragment().GeneratePage()
+	require.NoError(t, err)
+	assert.Equal(t, `<p class="notice error">The page does not exist.</p>`, html)
+
+	// pages not overridden keep the embedded templates
+	html, err = NewList([]models.Post{}, 1, 0, 10).GeneratePage()
+	require.NoError(t, err)
+	assert.Contains(t, html, "No posts found.")
+
+	// templates outside of the layouts use the overridden partials too
+	html, err = renderPage("feeds", `{{template "feed-links"}}`, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `<link rel="alternate" href="/custom.xml">`, html)
+}
+
+func TestLayouts_Reload(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "pages/list.html", `first`)
+	loadTemplates(t, dir, true)
+
+	html, err := NewList(nil, 1, 0, 10).GeneratePage()
+	require.NoError(t, err)
+	assert.Equal(t, "first", html)
+
+	writeTemplate(t, dir, "pages/list.html", `second`)
+	html, err = NewList(nil, 1, 0, 10).GeneratePage()
+	require.NoError(t, err)
+	assert.Equal(t, "second", html, "templates are parsed again on every render")
+}
+
+func TestLayouts_ParsedOnce(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "pages/list.html", `first`)
+	loadTemplates(t, dir, false)
+
+	writeTemplate(t, dir, "pages/list.html", `second`)
+	html, err := NewList(nil, 1, 0, 10).GeneratePage()
+	require.NoError(t, err)
+	assert.Equal(t, "first", html)
+}
+
+func TestLoad_InvalidOverride(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "pages/post.html", `{{if .Title}}`)
+
+	embedded := layouts
+	err := Load(dir, false)
+	assert.ErrorContains(t, err, "failed to parse pages/post.html")
+	assert.Same(t, embedded, layouts, "the templates in use are kept")
+}
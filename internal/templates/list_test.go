@@ -142,3 +142,16 @@ func truncateContent(content string, maxCharacters int) string {
 func formatDate(t time.Time) string {
 	return t.Format("Jan 02, 2006")
 }
+
+func TestList_GeneratePage_HeroImages(t *testing.T) {
+	mockPosts := createMockPosts(2)
+	mockPosts[0].HeroImage = "abc.png"
+
+	html, err := NewList(mockPosts, 1, 2, 10).GeneratePage()
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, strings.Count(html, `class="post-hero"`), "Only posts with a hero image should show one")
+	assert.Contains(t, html, `src="/images/abc.png/960"`)
+	assert.Contains(t, html, `/images/abc.png/320 320w`)
+	assert.Contains(t, html, `loading="lazy"`)
+}
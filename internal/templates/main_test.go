@@ -10,7 +10,7 @@ import (
 
 func TestMain_GeneratePage_WithPosts(t *testing.T) {
 	mockPosts := createMockPosts(3) // Create 3 mock posts
-	mainPage := NewMain(mockPosts)
+	mainPage := NewMain(mockPosts, nil)
 
 	html, err := mainPage.GeneratePage()
 	assert.NoError(t, err, "GeneratePage should not return an error")
@@ -36,10 +36,21 @@ func TestMain_GeneratePage_WithPosts(t *testing.T) {
 		assert.Contains(t, html, fmt.Sprintf(`<div class="post-date">%s</div>`, formatDate(post.CreatedAt)), "Post date mismatch")
 	}
 	assert.Contains(t, html, `<a href="/posts/search" class="view-all-link">View All Posts →</a>`, "HTML should contain view all posts link")
+	assert.Contains(t, html, `<a href="/subscribers/moderation" class="action-card">`, "HTML should contain subscribers action card")
+	assert.Contains(t, html, `<div class="newsletter-section">`, "HTML should contain newsletter section")
+	assert.Contains(t, html, `hx-post="/subscribers"`, "Subscribe form should post to subscribers endpoint")
+	assert.Contains(t, html, `<input type="hidden" name="source" value="home">`, "Subscribe form should report its source")
+	assert.Contains(t, html, `<select name="frequency" aria-label="Digest frequency">`, "Subscribe form should ask for digest frequency")
+	assert.Contains(t, html, `<input type="hidden" name="timezone" class="subscribe-timezone">`, "Subscribe form should report time zone")
+	assert.Contains(t, html, `<a href="/digests/preview" class="action-card">`, "HTML should contain digest preview action card")
+	assert.Contains(t, html, `<a href="/issues" class="action-card">`, "HTML should contain issues action card")
+	assert.Contains(t, html, `<a href="/stats" class="action-card">`, "HTML should contain stats action card")
+	assert.Contains(t, html, `<link rel="alternate" type="application/atom+xml" title="Atom" href="/atom.xml">`, "HTML should advertise the feeds")
+	assert.Contains(t, html, `<a href="/newsletter">Read past issues</a>`, "HTML should link the newsletter archive")
 }
 
 func TestMain_GeneratePage_NoPosts(t *testing.T) {
-	mainPage := NewMain([]models.Post{})
+	mainPage := NewMain([]models.Post{}, nil)
 
 	html, err := mainPage.GeneratePage()
 	assert.NoError(t, err, "GeneratePage should not return an error")
@@ -49,3 +60,14 @@ func TestMain_GeneratePage_NoPosts(t *testing.T) {
 	assert.Contains(t, html, `No posts yet. <a href="/posts/create">Create your first post!</a>`, "HTML should show 'No posts yet' message with create link")
 	assert.NotContains(t, html, `<div class="post-card">`, "HTML should not contain any post-card when no posts")
 }
+
+func TestMain_GeneratePage_HeroImage(t *testing.T) {
+	mockPosts := createMockPosts(1)
+	mockPosts[0].HeroImage = "abc.jpg"
+
+	html, err := NewMain(mockPosts, nil).GeneratePage()
+	assert.NoError(t, err)
+
+	assert.Contains(t, html, `<img class="post-hero" src="/images/abc.jpg/960"`)
+	assert.Contains(t, html, `sizes="(max-width: 768px) 100vw, 400px"`)
+}
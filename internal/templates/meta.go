@@ -0,0 +1,133 @@
+package templates
+
+import (
+	"newsteller/internal/feeds"
+	"newsteller/internal/models"
+	"strings"
+	"time"
+)
+
+// Site - site wide defaults of the link previews of reader facing pages
+type Site struct {
+	Name    string
+	BaseURL string
+	// Image - preview of pages without their own image, absolute or relative to BaseURL
+	Image string
+	// Logo - publisher logo, absolute or relative to BaseURL
+	Logo        string
+	TwitterSite string
+	Locale      string
+	// DefaultAuthor - author of posts without one, the site name when empty
+	DefaultAuthor     string
+	DescriptionLength int
+}
+
+// URL returns the path or URL as an absolute URL of the site.
+func (s Site) URL(path string) string {
+	if path == "" || strings.Contains(path, "://") {
+		return path
+	}
+
+	return s.BaseURL + path
+}
+
+// postMeta - OpenGraph, Twitter Card and JSON-LD description of a post page
+type postMeta struct {
+	CanonicalURL string
+	Description  string
+	Image        string
+	SiteName     string
+	Locale       string
+	TwitterSite  string
+	// TwitterCard - "summary_large_image" when the post has a preview image, "summary" otherwise
+	TwitterCard string
+	Published   string
+	Modified    string
+	Author      string
+	Tags        []string
+	JSONLD      newsArticle
+}
+
+// newsArticle - https://schema.org/NewsArticle
+type newsArticle struct {
+	Context          string        `json:"@context"`
+	Type             string        `json:"@type"`
+	Headline         string        `json:"headline"`
+	Description      string        `json:"description,omitempty"`
+	Image            []string      `json:"image,omitempty"`
+	DatePublished    string        `json:"datePublished"`
+	DateModified     string        `json:"dateModified"`
+	Author           []schemaThing `json:"author"`
+	Publisher        schemaThing   `json:"publisher"`
+	MainEntityOfPage schemaThing   `json:"mainEntityOfPage"`
+	Keywords         []string      `json:"keywords,omitempty"`
+}
+
+type schemaThing struct {
+	Type string       `json:"@type"`
+	ID   string       `json:"@id,omitempty"`
+	Name string       `json:"name,omitempty"`
+	URL  string       `json:"url,omitempty"`
+	Logo *schemaThing `json:"logo,omitempty"`
+}
+
+// maxHeadlineLength - longer headlines are ignored by search engines
+const maxHeadlineLength = 110
+
+func newPostMeta(post *models.Post, site Site, image string) postMeta {
+	published := post.CreatedAt
+	modified := post.UpdatedAt
+	if modified.Before(published) {
+		modified = published
+	}
+
+	meta := postMeta{
+		CanonicalURL: site.BaseURL + post.Path(),
+		Description:  feeds.Summary(post.Content, site.DescriptionLength),
+		Image:        site.URL(site.Image),
+		SiteName:     site.Name,
+		Locale:       site.Locale,
+		TwitterSite:  site.TwitterSite,
+		TwitterCard:  "summary",
+		Published:    published.UTC().Format(time.RFC3339),
+		Modified:     modified.UTC().Format(time.RFC3339),
+		Author:       post.Author,
+		Tags:         post.Tags,
+	}
+	if image != "" {
+		meta.Image = image
+	}
+	if meta.Image != "" {
+		meta.TwitterCard = "summary_large_image"
+	}
+
+	publisher := schemaThing{Type: "Organization", Name: site.Name, URL: site.URL("/home")}
+	if site.Logo != "" {
+		publisher.Logo = &schemaThing{Type: "ImageObject", URL: site.URL(site.Logo)}
+	}
+	author := schemaThing{Type: "Person", Name: post.Author}
+	if post.Author == "" {
+		author = schemaThing{Type: "Organization", Name: site.DefaultAuthor}
+		if author.Name == "" {
+			author = publisher
+		}
+	}
+
+	meta.JSONLD = newsArticle{
+		Context:          "https://schema.org",
+		Type:             "NewsArticle",
+		Headline:         feeds.Summary(post.Title, maxHeadlineLength),
+		Description:      meta.Description,
+		DatePublished:    meta.Published,
+		DateModified:     meta.Modified,
+		Author:           []schemaThing{author},
+		Publisher:        publisher,
+		MainEntityOfPage: schemaThing{Type: "WebPage", ID: meta.CanonicalURL},
+		Keywords:         post.Tags,
+	}
+	if meta.Image != "" {
+		meta.JSONLD.Image = []string{meta.Image}
+	}
+
+	return meta
+}
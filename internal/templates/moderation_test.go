@@ -33,7 +33,8 @@ func TestModeration_GeneratePage_WithPostsAndPagination(t *testing.T) {
 		assert.Contains(t, html, fmt.Sprintf(`<h3 class="post-title">%s</h3>`, truncateContent(post.Title, 45)), "Post title mismatch or not found")
 		assert.Contains(t, html, fmt.Sprintf(`<p class="post-date">Created: %s</p>`, formatDate(post.CreatedAt)), "Post date mismatch or not found")
 		assert.Contains(t, html, fmt.Sprintf(`href="/posts/%s/edit" class="btn btn-edit">Edit</a>`, post.ID.Hex()), "Edit button link incorrect")
-		assert.Contains(t, html, fmt.Sprintf(`onclick="confirmDelete('%s', '%s')`, post.ID.Hex(), post.Title), "Delete button onclick incorrect")
+		assert.Contains(t, html, fmt.Sprintf(`data-delete-id="%s"`, post.ID.Hex()), "Delete button post ID incorrect")
+		assert.Contains(t, html, fmt.Sprintf(`data-delete-title="%s"`, post.Title), "Delete button post title incorrect")
 	}
 
 	// Check pagination info
@@ -66,6 +67,7 @@ func TestModeration_GeneratePage_NoPosts(t *testing.T) {
 	assert.NotContains(t, html, `<ul class="posts-list">`, "Posts list should not be present")
 	assert.NotContains(t, html, `<div class="pagination">`, "Pagination should not be present if no posts or only one page")
 	assert.Contains(t, html, `<h2>All Posts (0 total)</h2>`, "HTML should show 0 total posts count")
+	assert.Contains(t, html, `<div id="comments-queue" hx-get="/comments/moderation" hx-trigger="load"`, "HTML should load the comment queue")
 }
 
 func TestModeration_GeneratePage_SinglePageOfPosts(t *testing.T) {
@@ -0,0 +1,70 @@
+package templates
+
+import (
+	"newsteller/internal/models"
+	"time"
+)
+
+const postNotificationEmailHTML = `{{define "content"}}
+<h1 style="font-size: 22px; margin: 0 0 15px 0;">{{.Title}}</h1>
+<p style="margin: 0 0 25px 0; color: #666;">
+    The post was {{.Action}}{{if .Author}} by {{.Author}}{{end}} on {{formatDateTime .At}}.
+</p>
+{{if .URL}}
+<p style="margin: 0; text-align: center;">
+    <a href="{{.URL}}"
+       style="display: inline-block; padding: 12px 24px; background: #007bff; color: white; border-radius: 8px; text-decoration: none; font-weight: 500;">
+        Open the post
+    </a>
+</p>
+{{end}}
+{{end}}`
+
+const postNotificationEmailText = `{{define "content"}}{{.Title}}
+
+The post was {{.Action}}{{if .Author}} by {{.Author}}{{end}} on {{formatDateTime .At}}.
+{{if .URL}}
+{{.URL}}{{end}}{{end}}`
+
+// PostNotificationEmail tells editors a post was published or deleted.
+type PostNotificationEmail struct {
+	action string
+	post   *models.Post
+	url    string
+	at     time.Time
+}
+
+type postNotificationEmailData struct {
+	Subject        string
+	Action         string
+	Title          string
+	Author         string
+	URL            string
+	At             time.Time
+	UnsubscribeURL string
+}
+
+// NewPostNotificationEmail returns the email about the action taken on the post at
+// the provided time, the URL is left out for deleted posts.
+func NewPostNotificationEmail(action string, post *models.Post, url string, at time.Time) *PostNotificationEmail {
+	return &PostNotificationEmail{action: action, post: post, url: url, at: at}
+}
+
+func (e *PostNotificationEmail) GenerateEmail() (*Email, error) {
+	subject := "Post " + e.action + ": " + e.post.Title
+
+	return renderEmail(
+		"post_notification",
+		subject,
+		postNotificationEmailHTML,
+		postNotificationEmailText,
+		postNotificationEmailData{
+			Subject: subject,
+			Action:  e.action,
+			Title:   e.post.Title,
+			Author:  e.post.Author,
+			URL:     e.url,
+			At:      e.at,
+		},
+	)
+}
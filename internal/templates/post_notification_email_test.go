@@ -0,0 +1,38 @@
+package templates
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"newsteller/internal/models"
+)
+
+func TestPostNotificationEmail_GenerateEmail(t *testing.T) {
+	post := &models.Post{Title: "Rates & <markets>", Author: "Jane"}
+	at := time.Date(2025, time.March, 4, 15, 30, 0, 0, time.UTC)
+
+	email, err := NewPostNotificationEmail("published", post, "https://news.example.com/posts/rates?a=1&b=2", at).GenerateEmail()
+	require.NoError(t, err)
+
+	assert.Equal(t, "Post published: Rates & <markets>", email.Subject)
+	assert.Contains(t, email.HTML, "Rates &amp; &lt;markets&gt;")
+	assert.Contains(t, email.HTML, "The post was published by Jane on March 4, 2025 at 3:30 PM.")
+	assert.Contains(t, email.HTML, `<a href="https://news.example.com/posts/rates?a=1&amp;b=2"`)
+	assert.Contains(t, email.Text, "Rates & <markets>\n\nThe post was published by Jane on March 4, 2025 at 3:30 PM.")
+	assert.Contains(t, email.Text, "https://news.example.com/posts/rates?a=1&b=2")
+}
+
+func TestPostNotificationEmail_WithoutURL(t *testing.T) {
+	post := &models.Post{Title: "Gone"}
+
+	email, err := NewPostNotificationEmail("deleted", post, "", time.Now()).GenerateEmail()
+	require.NoError(t, err)
+
+	assert.Equal(t, "Post deleted: Gone", email.Subject)
+	assert.NotContains(t, email.HTML, "Open the post")
+	assert.NotContains(t, email.Text, "http")
+	assert.NotContains(t, email.HTML, " by ")
+}
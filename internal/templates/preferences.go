@@ -0,0 +1,88 @@
+package templates
+
+import (
+	"newsteller/internal/models"
+	"newsteller/internal/taxonomy"
+	"slices"
+)
+
+type Preferences struct {
+	subscriber     *models.Subscriber
+	topics         []string
+	categories     []taxonomy.Node
+	token          string
+	unsubscribeURL string
+}
+
+type preferencesTopic struct {
+	Name     string
+	Followed bool
+}
+
+type preferencesCategory struct {
+	taxonomy.Node
+	Followed bool
+}
+
+type preferencesData struct {
+	Subscriber     *models.Subscriber
+	Frequency      models.DigestFrequency
+	Topics         []preferencesTopic
+	Categories     []preferencesCategory
+	Hours          []int
+	Token          string
+	UnsubscribeURL string
+}
+
+// NewPreferences renders the preferences page of the subscriber, reachable with the signed
+// unsubscribe token. Topics are the tags used by the posts, categories the category tree.
+func NewPreferences(
+	subscriber *models.Subscriber,
+	topics []string,
+	categories []taxonomy.Node,
+	token,
+	unsubscribeURL string,
+) *Preferences {
+	return &Preferences{
+		subscriber:     subscriber,
+		topics:         topics,
+		categories:     categories,
+		token:          token,
+		unsubscribeURL: unsubscribeURL,
+	}
+}
+
+func (p *Preferences) GeneratePage() (string, error) {
+	data := preferencesData{
+		Subscriber:     p.subscriber,
+		Frequency:      p.subscriber.Frequency(),
+		Topics:         make([]preferencesTopic, 0, len(p.topics)),
+		Categories:     make([]preferencesCategory, len(p.categories)),
+		Hours:          make([]int, 24),
+		Token:          p.token,
+		UnsubscribeURL: p.unsubscribeURL,
+	}
+	for i := range data.Hours {
+		data.Hours[i] = i
+	}
+	for _, topic := range p.topics {
+		data.Topics = append(data.Topics, preferencesTopic{
+			Name:     topic,
+			Followed: slices.Contains(p.subscriber.Topics, topic),
+		})
+	}
+	// followed topics no post uses anymore stay visible, so they can be unchecked
+	for _, topic := range p.subscriber.Topics {
+		if !slices.Contains(p.topics, topic) {
+			data.Topics = append(data.Topics, preferencesTopic{Name: topic, Followed: true})
+		}
+	}
+	for i, node := range p.categories {
+		data.Categories[i] = preferencesCategory{
+			Node:     node,
+			Followed: slices.Contains(p.subscriber.Categories, node.ID),
+		}
+	}
+
+	return render("preferences", data)
+}
@@ -0,0 +1,66 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"newsteller/internal/models"
+	"newsteller/internal/taxonomy"
+)
+
+func TestPreferences_GeneratePage(t *testing.T) {
+	unsubscribeURL := "https://news.example.com/subscribers/unsubscribe?token=abc"
+
+	t.Run("Positive: Active subscriber following topics", func(t *testing.T) {
+		tech := models.Category{ID: primitive.NewObjectID(), Name: "Tech"}
+		golang := models.Category{ID: primitive.NewObjectID(), Name: "Go", ParentID: tech.ID}
+		categories := taxonomy.Tree([]models.Category{tech, golang})
+		subscriber := &models.Subscriber{
+			Email:           "reader@example.com",
+			Status:          models.SubscriberActive,
+			DigestFrequency: models.DigestDaily,
+			DigestHour:      18,
+			Timezone:        "Europe/Kyiv",
+			Topics:          []string{"go", "retired"},
+			Categories:      []primitive.ObjectID{golang.ID},
+			TrackingOptOut:  true,
+		}
+
+		html, err := NewPreferences(subscriber, []string{"databases", "go"}, categories, "abc", unsubscribeURL).GeneratePage()
+		require.NoError(t, err)
+		html = strings.Join(strings.Fields(html), " ")
+
+		assert.Contains(t, html, "<title>Newsletter Preferences</title>")
+		assert.Contains(t, html, "Choose what reader@example.com receives.")
+		assert.NotContains(t, html, "subscribe again")
+		assert.Contains(t, html, `hx-post="/subscribers/preferences?token=abc"`)
+		assert.Contains(t, html, `<option value="daily" selected>Daily</option>`)
+		assert.Contains(t, html, `<option value="18" selected>18:00</option>`)
+		assert.Contains(t, html, `<input type="hidden" name="timezone" value="Europe/Kyiv" class="preferences-timezone">`)
+		assert.Contains(t, html, `<input type="checkbox" name="topics" value="databases" > databases`)
+		assert.Contains(t, html, `<input type="checkbox" name="topics" value="go" checked> go`)
+		assert.Contains(t, html, `<input type="checkbox" name="topics" value="retired" checked> retired`)
+		assert.Contains(t, html, `<input type="checkbox" name="categories" value="`+tech.ID.Hex()+`" > Tech`)
+		assert.Contains(t, html, `<input type="checkbox" name="categories" value="`+golang.ID.Hex()+`" checked> — Go`)
+		assert.Contains(t, html, `<input type="checkbox" name="tracking_opt_out" value="true" checked>`)
+		assert.Contains(t, html, `<a href="https://news.example.com/subscribers/unsubscribe?token=abc" class="back-link">`)
+	})
+
+	t.Run("Positive: Unsubscribed subscriber without topics", func(t *testing.T) {
+		subscriber := &models.Subscriber{Email: "reader@example.com", Status: models.SubscriberUnsubscribed}
+
+		html, err := NewPreferences(subscriber, nil, nil, "abc", unsubscribeURL).GeneratePage()
+		require.NoError(t, err)
+		html = strings.Join(strings.Fields(html), " ")
+
+		assert.Contains(t, html, `This address is unsubscribed, <a href="/">subscribe again</a> to receive the newsletter.`)
+		assert.Contains(t, html, `<option value="weekly" selected>Weekly</option>`)
+		assert.Contains(t, html, "Posts are not grouped into topics yet")
+		assert.NotContains(t, html, `name="topics"`)
+		assert.NotContains(t, html, `name="categories"`)
+		assert.Contains(t, html, `<input type="checkbox" name="tracking_opt_out" value="true" >`)
+	})
+}
@@ -0,0 +1,28 @@
+package templates
+
+import (
+	"fmt"
+	"time"
+)
+
+// NewTooManyRequests is shown to clients exceeding a rate limit.
+func NewTooManyRequests(retryAfter time.Duration) *ErrorPage {
+	return NewErrorPage(
+		"Too many requests",
+		fmt.Sprintf("You have sent too many requests, please try again in %s.", waitText(retryAfter)),
+	)
+}
+
+func waitText(d time.Duration) string {
+	seconds := max(int((d+time.Second-1)/time.Second), 1)
+	switch {
+	case seconds == 1:
+		return "a second"
+	case seconds < 60:
+		return fmt.Sprintf("%d seconds", seconds)
+	case seconds < 120:
+		return "a minute"
+	default:
+		return fmt.Sprintf("%d minutes", (seconds+59)/60)
+	}
+}
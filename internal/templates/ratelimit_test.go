@@ -0,0 +1,36 @@
+package templates
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTooManyRequests_GeneratePage(t *testing.T) {
+	t.Run("Positive: Standalone page", func(t *testing.T) {
+		html, err := NewTooManyRequests(30 * time.Second).GeneratePage()
+		require.NoError(t, err)
+
+		assert.Contains(t, html, "<title>Too many requests</title>")
+		assert.Contains(t, html, `<div class="card error">`)
+		assert.Contains(t, html, "please try again in 30 seconds.")
+		assert.Contains(t, html, `<a href="/home" class="back-link">← Back to Home</a>`)
+	})
+
+	t.Run("Positive: Fragment of htmx requests", func(t *testing.T) {
+		html, err := NewTooManyRequests(90 * time.Second).AsFragment().GeneratePage()
+		require.NoError(t, err)
+
+		assert.Equal(t, `<div class="message error">You have sent too many requests, please try again in a minute.</div>`, html)
+	})
+}
+
+func TestWaitText(t *testing.T) {
+	assert.Equal(t, "a second", waitText(0))
+	assert.Equal(t, "a second", waitText(300*time.Millisecond))
+	assert.Equal(t, "2 seconds", waitText(1100*time.Millisecond))
+	assert.Equal(t, "a minute", waitText(time.Minute))
+	assert.Equal(t, "3 minutes", waitText(150*time.Second))
+}
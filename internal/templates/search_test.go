@@ -26,6 +26,8 @@ func TestSearchHome_GeneratePage_WithPostsAndPagination(t *testing.T) {
 	assert.Contains(t, html, `<div id="posts-content">`, "HTML should contain posts-content div")
 	assert.Contains(t, html, `<div class="posts-container">`, "HTML should contain posts-container div")
 	assert.Contains(t, html, `<div class="pagination">`, "HTML should contain pagination div")
+	assert.Contains(t, html, `<div id="search-suggestions" hx-get="/search/suggestions"`, "HTML should contain suggestions dropdown")
+	assert.Contains(t, html, `hx-post="/search/queries" hx-trigger="change from:.search-field, search from:.search-field"`, "completed searches should be logged")
 
 	// 2. Check changeable elements (posts)
 	for _, post := range mockPosts {
@@ -0,0 +1,23 @@
+package templates
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// NoncePlaceholder marks the nonces of inline scripts, responses replace it with the nonce
+// of their Content-Security-Policy. It is random, so rendered content can not forge it,
+// and the same for every page, so cached pages stay valid.
+var NoncePlaceholder = func() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return "nonce-placeholder-" + hex.EncodeToString(b)
+}()
+
+// NewInvalidCSRF is shown to requests changing data without the token of their csrf cookie.
+func NewInvalidCSRF() *ErrorPage {
+	return NewErrorPage(
+		"Request expired",
+		"The page sending this request is out of date, please reload it and try again.",
+	)
+}
@@ -0,0 +1,61 @@
+package templates
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"newsteller/internal/antispam"
+	"newsteller/internal/models"
+)
+
+var (
+	scriptTagPattern     = regexp.MustCompile(`<script[^>]*>`)
+	inlineHandlerPattern = regexp.MustCompile(`\son[a-z]+="`)
+)
+
+// assertCSPCompatible checks that every script of the page may run under a nonce policy.
+func assertCSPCompatible(t *testing.T, html string) {
+	t.Helper()
+	for _, tag := range scriptTagPattern.FindAllString(html, -1) {
+		if tag == `<script type="application/ld+json">` {
+			continue
+		}
+		assert.Contains(t, tag, `nonce="`+NoncePlaceholder+`"`, "scripts should carry the nonce")
+	}
+	assert.NotRegexp(t, inlineHandlerPattern, html, "inline handlers are blocked by the CSP")
+}
+
+func TestPages_CSPCompatible(t *testing.T) {
+	post := models.Post{ID: primitive.NewObjectID(), Title: "Hello", Content: "World"}
+
+	pages := map[string]Template{
+		"home":       NewMain([]models.Post{post}, nil),
+		"moderation": NewModeration([]models.Post{post}, 1, 1, 1),
+		"edit":       NewEdit(&post, nil, nil),
+		"post":       &SingleTemplate{post: &post},
+		"comments": NewCommentSection(&post, nil, true, antispam.Challenge{
+			Form: antispam.FormComment, Token: "abc.def", Difficulty: 16,
+		}),
+	}
+	for name, page := range pages {
+		t.Run(name, func(t *testing.T) {
+			html, err := page.GeneratePage()
+			require.NoError(t, err)
+			assertCSPCompatible(t, html)
+		})
+	}
+}
+
+func TestInvalidCSRF_GeneratePage(t *testing.T) {
+	html, err := NewInvalidCSRF().GeneratePage()
+	require.NoError(t, err)
+	assert.Contains(t, html, "<title>Request expired</title>")
+
+	html, err = NewInvalidCSRF().AsFragment().GeneratePage()
+	require.NoError(t, err)
+	assert.Equal(t, `<div class="message error">The page sending this request is out of date, please reload it and try again.</div>`, html)
+}
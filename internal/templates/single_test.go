@@ -1,6 +1,7 @@
 package templates
 
 import (
+	"encoding/json"
 	"fmt"
 	"newsteller/internal/models"
 	"strings"
@@ -8,6 +9,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -34,7 +36,7 @@ func TestSingleTemplate_GeneratePage(t *testing.T) {
 	assert.Contains(t, html, `<header class="post-header">`, "HTML should contain post header")
 	assert.Contains(t, html, `<div class="post-content">`, "HTML should contain post content div")
 	assert.Contains(t, html, `<footer class="post-actions">`, "HTML should contain post actions footer")
-	assert.Contains(t, html, `<button class="btn-secondary" onclick="window.location.href='/posts/search'">Back to posts</button>`, "HTML should contain back to posts button")
+	assert.Contains(t, html, `<a class="btn-secondary" href="/posts/search">Back to posts</a>`, "HTML should contain back to posts link")
 
 	// 2. Check changeable elements
 	assert.Contains(t, html, fmt.Sprintf(`<h1 class="post-title">%s</h1>`, mockPost.Title), "HTML should display the correct post title in <h1>")
@@ -86,9 +88,160 @@ func TestRenderSinglePost_Function(t *testing.T) {
 		CreatedAt: now,
 	}
 
-	html, err := RenderSinglePost(mockPost)
+	html, err := RenderSinglePost(mockPost, Site{BaseURL: "https://example.com"}, "")
 	assert.NoError(t, err)
 	assert.NotEmpty(t, html)
 	assert.Contains(t, html, mockPost.Title)
 	assert.Contains(t, html, mockPost.Content)
+	assert.Contains(t, html, fmt.Sprintf(`<link rel="canonical" href="https://example.com/posts/%s">`, postID.Hex()))
+}
+
+func TestRenderSinglePost_CanonicalSlug(t *testing.T) {
+	mockPost := &models.Post{
+		ID:        primitive.NewObjectID(),
+		Title:     "Hello World",
+		Content:   "Content here.",
+		CreatedAt: time.Now(),
+		Slug:      "hello-world",
+	}
+
+	html, err := RenderSinglePost(mockPost, Site{BaseURL: "https://example.com"}, "")
+
+	assert.NoError(t, err)
+	assert.Contains(t, html, `<link rel="canonical" href="https://example.com/posts/hello-world">`)
+}
+
+func TestSingleTemplate_GeneratePage_FeedLinks(t *testing.T) {
+	mockPost := &models.Post{
+		ID:        primitive.NewObjectID(),
+		Title:     "Tagged Post",
+		Content:   "Content here.",
+		CreatedAt: time.Now(),
+		Tags:      []string{"go", "machine learning"},
+		Author:    "Jane Doe",
+	}
+
+	html, err := RenderSinglePost(mockPost, Site{}, "")
+
+	assert.NoError(t, err)
+	assert.Contains(t, html, `<link rel="alternate" type="application/rss+xml" title="RSS" href="/feed.xml">`)
+	assert.Contains(t, html, `<link rel="alternate" type="application/feed+json" title="JSON Feed" href="/feed.json">`)
+	assert.Contains(t, html, `title="Posts tagged go" href="/tags/go/feed.xml">`)
+	assert.Contains(t, html, `href="/tags/machine%20learning/feed.xml">`)
+	assert.Contains(t, html, `title="Posts by Jane Doe" href="/authors/Jane%20Doe/feed.xml">`)
+	assert.Contains(t, html, `<span>By Jane Doe | </span>`)
+}
+
+func TestRenderSinglePost_LinkPreviews(t *testing.T) {
+	created := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	site := Site{
+		Name:              "Newsteller",
+		BaseURL:           "https://news.example.com",
+		Image:             "/static/preview.png",
+		Logo:              "https://cdn.example.com/logo.png",
+		TwitterSite:       "@newsteller",
+		Locale:            "en_US",
+		DescriptionLength: 40,
+	}
+	mockPost := &models.Post{
+		ID:        primitive.NewObjectID(),
+		Title:     `Go & "Mongo"`,
+		Content:   "First paragraph about </script> tags.\n\nSecond paragraph.",
+		CreatedAt: created,
+		UpdatedAt: created.Add(time.Hour),
+		Tags:      []string{"go"},
+		Author:    "Jane Doe",
+		Slug:      "go-mongo",
+	}
+
+	t.Run("Positive: OpenGraph and Twitter Card", func(t *testing.T) {
+		html, err := RenderSinglePost(mockPost, site, "")
+		assert.NoError(t, err)
+
+		assert.Contains(t, html, `<meta name="description" content="First paragraph about &lt;/script&gt; tags…">`)
+		assert.Contains(t, html, `<meta property="og:title" content="Go &amp; &#34;Mongo&#34;">`)
+		assert.Contains(t, html, `<meta property="og:url" content="https://news.example.com/posts/go-mongo">`)
+		assert.Contains(t, html, `<meta property="og:site_name" content="Newsteller">`)
+		assert.Contains(t, html, `<meta property="og:image" content="https://news.example.com/static/preview.png">`)
+		assert.Contains(t, html, `<meta property="article:published_time" content="2026-10-01T08:00:00Z">`)
+		assert.Contains(t, html, `<meta property="article:modified_time" content="2026-10-01T09:00:00Z">`)
+		assert.Contains(t, html, `<meta property="article:author" content="Jane Doe">`)
+		assert.Contains(t, html, `<meta property="article:tag" content="go">`)
+		assert.Contains(t, html, `<meta name="twitter:card" content="summary_large_image">`)
+		assert.Contains(t, html, `<meta name="twitter:site" content="@newsteller">`)
+	})
+
+	t.Run("Positive: NewsArticle JSON-LD", func(t *testing.T) {
+		html, err := RenderSinglePost(mockPost, site, "")
+		assert.NoError(t, err)
+
+		start := strings.Index(html, `<script type="application/ld+json">`)
+		require.GreaterOrEqual(t, start, 0)
+		script := html[start+len(`<script type="application/ld+json">`):]
+		script = script[:strings.Index(script, "</script>")]
+
+		var article map[string]any
+		require.NoError(t, json.Unmarshal([]byte(script), &article))
+		assert.Equal(t, "NewsArticle", article["@type"])
+		assert.Equal(t, `Go & "Mongo"`, article["headline"])
+		assert.Equal(t, "First paragraph about </script> tags…", article["description"])
+		assert.Equal(t, "2026-10-01T08:00:00Z", article["datePublished"])
+		assert.Equal(t, []any{"https://news.example.com/static/preview.png"}, article["image"])
+		assert.Equal(t, []any{map[string]any{"@type": "Person", "name": "Jane Doe"}}, article["author"])
+		assert.Equal(t, "https://cdn.example.com/logo.png", article["publisher"].(map[string]any)["logo"].(map[string]any)["url"])
+		assert.Equal(t, "https://news.example.com/posts/go-mongo", article["mainEntityOfPage"].(map[string]any)["@id"])
+	})
+
+	t.Run("Negative: Site defaults without image and author", func(t *testing.T) {
+		anonymous := *mockPost
+		anonymous.Author = ""
+		html, err := RenderSinglePost(&anonymous, Site{Name: "Newsteller", BaseURL: "https://news.example.com"}, "")
+		assert.NoError(t, err)
+
+		assert.Contains(t, html, `<meta name="twitter:card" content="summary">`)
+		assert.NotContains(t, html, `og:image`)
+		assert.NotContains(t, html, `article:author`)
+		assert.Contains(t, html, `"author":[{"@type":"Organization","name":"Newsteller","url":"https://news.example.com/home"}]`)
+	})
+
+	t.Run("Positive: Post image replaces the site image", func(t *testing.T) {
+		html, err := RenderSinglePost(mockPost, site, "https://news.example.com/posts/1/og.png?v=abc")
+		assert.NoError(t, err)
+
+		assert.Contains(t, html, `<meta property="og:image" content="https://news.example.com/posts/1/og.png?v=abc">`)
+		assert.Contains(t, html, `<meta name="twitter:image" content="https://news.example.com/posts/1/og.png?v=abc">`)
+		assert.NotContains(t, html, "preview.png")
+	})
+}
+
+func TestRenderSinglePost_HeroImage(t *testing.T) {
+	mockPost := &models.Post{
+		ID:        primitive.NewObjectID(),
+		Title:     "Harbour",
+		Content:   "Boats.",
+		CreatedAt: time.Now(),
+		HeroImage: "abc.jpg",
+	}
+
+	html, err := RenderSinglePost(mockPost, Site{Name: "Newsteller"}, "")
+	require.NoError(t, err)
+	html = strings.Join(strings.Fields(html), " ")
+
+	assert.Contains(t, html, `<img class="post-hero" src="/images/abc.jpg/960" srcset="/images/abc.jpg/320 320w, /images/abc.jpg/640 640w,`)
+	assert.Contains(t, html, `sizes="(max-width: 800px) 100vw, 800px"`)
+
+	mockPost.HeroImage = ""
+	html, err = RenderSinglePost(mockPost, Site{Name: "Newsteller"}, "")
+	require.NoError(t, err)
+	assert.NotContains(t, html, `class="post-hero"`)
+}
+
+func TestRenderSinglePost_Comments(t *testing.T) {
+	mockPost := &models.Post{ID: primitive.NewObjectID(), Title: "Harbour", Content: "Boats.", CreatedAt: time.Now()}
+
+	html, err := RenderSinglePost(mockPost, Site{Name: "Newsteller"}, "")
+	require.NoError(t, err)
+
+	// the page is cached, comments are loaded separately
+	assert.Contains(t, html, `<section id="comments" hx-get="/posts/`+mockPost.ID.Hex()+`/comments" hx-trigger="load" hx-swap="outerHTML"></section>`)
 }
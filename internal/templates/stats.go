@@ -0,0 +1,108 @@
+package templates
+
+import (
+	"newsteller/internal/models"
+)
+
+// StatsPeriods - periods in days the stats page can be shown for
+var StatsPeriods = []int{7, 30, 90}
+
+// IssueStats is a sent issue with its tracking totals.
+type IssueStats struct {
+	Issue  models.Issue
+	Totals models.TrackingTotals
+}
+
+// LinkStats is a clicked link titled with the post it leads to, or the URL itself.
+type LinkStats struct {
+	models.LinkStats
+	Title string
+}
+
+type Stats struct {
+	days   int
+	issues []IssueStats
+	posts  []LinkStats
+	daily  []models.DayStats
+}
+
+// dailyData is embedded into the data of pages rendering the "daily" block.
+type dailyData struct {
+	Days   []models.DayStats
+	MaxDay int
+}
+
+type statsData struct {
+	dailyData
+	Period  int
+	Periods []int
+	Issues  []IssueStats
+	Posts   []LinkStats
+}
+
+type issueStatsData struct {
+	dailyData
+	Issue  *models.Issue
+	Totals models.TrackingTotals
+	Links  []LinkStats
+}
+
+// NewStats renders the stats overview of the last days.
+func NewStats(days int, issues []IssueStats, posts []LinkStats, daily []models.DayStats) *Stats {
+	return &Stats{
+		days:   days,
+		issues: issues,
+		posts:  posts,
+		daily:  daily,
+	}
+}
+
+func (s *Stats) GeneratePage() (string, error) {
+	return render("stats", statsData{
+		dailyData: newDailyData(s.daily),
+		Period:    s.days,
+		Periods:   StatsPeriods,
+		Issues:    s.issues,
+		Posts:     s.posts,
+	})
+}
+
+type IssueStatsPage struct {
+	issue  *models.Issue
+	totals models.TrackingTotals
+	links  []LinkStats
+	daily  []models.DayStats
+}
+
+// NewIssueStatsPage renders the stats of a sent issue.
+func NewIssueStatsPage(
+	issue *models.Issue,
+	totals models.TrackingTotals,
+	links []LinkStats,
+	daily []models.DayStats,
+) *IssueStatsPage {
+	return &IssueStatsPage{
+		issue:  issue,
+		totals: totals,
+		links:  links,
+		daily:  daily,
+	}
+}
+
+func (p *IssueStatsPage) GeneratePage() (string, error) {
+	return render("issue-stats", issueStatsData{
+		dailyData: newDailyData(p.daily),
+		Issue:     p.issue,
+		Totals:    p.totals,
+		Links:     p.links,
+	})
+}
+
+func newDailyData(days []models.DayStats) dailyData {
+	data := dailyData{Days: days}
+	for _, day := range days {
+		data.MaxDay = max(data.MaxDay, day.Opens, day.Clicks)
+	}
+
+	return data
+}
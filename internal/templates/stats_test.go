@@ -0,0 +1,89 @@
+package templates
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"newsteller/internal/models"
+)
+
+func createMockDays() []models.DayStats {
+	return []models.DayStats{
+		{Day: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), Opens: 40, Clicks: 10},
+		{Day: time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC), Opens: 20},
+	}
+}
+
+func TestStats_GeneratePage(t *testing.T) {
+	issue := models.Issue{
+		ID:         primitive.NewObjectID(),
+		Name:       "Spring <issue>",
+		Status:     models.IssueSent,
+		SentAt:     time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC),
+		Recipients: 200,
+	}
+	issues := []IssueStats{{
+		Issue:  issue,
+		Totals: models.TrackingTotals{Opens: 120, UniqueOpens: 100, Clicks: 40, UniqueClicks: 30},
+	}}
+	posts := []LinkStats{{
+		LinkStats: models.LinkStats{URL: "https://news.example.com/posts/1", Clicks: 12, UniqueClicks: 9},
+		Title:     "Popular post",
+	}}
+
+	html, err := NewStats(30, issues, posts, createMockDays()).GeneratePage()
+	require.NoError(t, err)
+	html = strings.Join(strings.Fields(html), " ")
+
+	assert.Contains(t, html, "<title>Newsletter Stats</title>")
+	assert.Contains(t, html, `<a href="/stats?days=30" class="active">30 days</a>`)
+	assert.Contains(t, html, `<a href="/stats?days=7" >7 days</a>`)
+	assert.Contains(t, html, fmt.Sprintf(`<a href="/stats/issues/%s">Spring &lt;issue&gt;</a>`, issue.ID.Hex()))
+	assert.Contains(t, html, "<td>100 (50%)</td> <td>30 (15%)</td>")
+	assert.Contains(t, html, `<td><a href="https://news.example.com/posts/1">Popular post</a></td> <td>12</td> <td>9</td>`)
+	assert.Contains(t, html, `<td>Mar 02, 2025</td> <td>20</td> <td>0</td>`)
+	assert.Contains(t, html, `<span class="opens" style="width: 50%;"></span> <span class="clicks" style="width: 0%;"></span>`)
+}
+
+func TestStats_GeneratePage_Empty(t *testing.T) {
+	html, err := NewStats(7, nil, nil, nil).GeneratePage()
+	require.NoError(t, err)
+
+	assert.Contains(t, html, "No issues were sent yet.")
+	assert.Contains(t, html, "No clicks in this period.")
+}
+
+func TestIssueStatsPage_GeneratePage(t *testing.T) {
+	issue := &models.Issue{
+		ID:         primitive.NewObjectID(),
+		Name:       "Spring issue",
+		Status:     models.IssueSent,
+		SentAt:     time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC),
+		Recipients: 4,
+	}
+	links := []LinkStats{{
+		LinkStats: models.LinkStats{URL: "https://news.example.com/posts/1", Clicks: 3, UniqueClicks: 2},
+		Title:     "Picked post",
+	}}
+
+	html, err := NewIssueStatsPage(
+		issue,
+		models.TrackingTotals{Opens: 5, UniqueOpens: 3, Clicks: 3, UniqueClicks: 2},
+		links,
+		createMockDays(),
+	).GeneratePage()
+	require.NoError(t, err)
+	html = strings.Join(strings.Fields(html), " ")
+
+	assert.Contains(t, html, "<title>Spring issue - Stats</title>")
+	assert.Contains(t, html, fmt.Sprintf(`<a href="/newsletter/%s">View issue</a>`, issue.ID.Hex()))
+	assert.Contains(t, html, `<div class="total-value">75%</div> <div class="total-label">Opened by 3 · 5 opens</div>`)
+	assert.Contains(t, html, `<div class="total-value">50%</div> <div class="total-label">Clicked by 2 · 3 clicks</div>`)
+	assert.Contains(t, html, `<td><a href="https://news.example.com/posts/1">Picked post</a></td> <td>3</td> <td>2</td>`)
+	assert.Contains(t, html, `<td>Mar 01, 2025</td> <td>40</td> <td>10</td>`)
+}
@@ -0,0 +1,67 @@
+package templates
+
+import (
+	"math"
+	"newsteller/internal/models"
+)
+
+type Subscribers struct {
+	subscribers      []models.Subscriber
+	page             int
+	limit            int
+	totalSubscribers int
+	suppressed       []models.Subscriber
+	totalSuppressed  int
+}
+
+type subscribersPageData struct {
+	Subscribers      []models.Subscriber
+	CurrentPage      int
+	TotalPages       int
+	TotalSubscribers int
+	HasPrev          bool
+	HasNext          bool
+	PrevPage         int
+	NextPage         int
+	Suppressed       []models.Subscriber
+	TotalSuppressed  int
+}
+
+func NewSubscribers(
+	subscribers []models.Subscriber,
+	page, limit int,
+	totalSubscribers int,
+) *Subscribers {
+	return &Subscribers{
+		subscribers:      subscribers,
+		page:             page,
+		limit:            limit,
+		totalSubscribers: totalSubscribers,
+	}
+}
+
+// WithSuppressed lists the most recently suppressed subscribers above all subscribers.
+func (s *Subscribers) WithSuppressed(suppressed []models.Subscriber, total int) *Subscribers {
+	s.suppressed = suppressed
+	s.totalSuppressed = total
+	return s
+}
+
+func (s *Subscribers) GeneratePage() (string, error) {
+	totalPages := int(math.Ceil(float64(s.totalSubscribers) / float64(s.limit)))
+
+	data := &subscribersPageData{
+		Subscribers:      s.subscribers,
+		CurrentPage:      s.page,
+		TotalPages:       totalPages,
+		TotalSubscribers: s.totalSubscribers,
+		HasPrev:          s.page > 1,
+		HasNext:          s.page < totalPages,
+		PrevPage:         s.page - 1,
+		NextPage:         s.page + 1,
+		Suppressed:       s.suppressed,
+		TotalSuppressed:  s.totalSuppressed,
+	}
+
+	return render("subscribers", data)
+}
@@ -0,0 +1,85 @@
+package templates
+
+import (
+	"fmt"
+	"newsteller/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func createMockSubscribers(count int) []models.Subscriber {
+	subscribers := make([]models.Subscriber, count)
+	for i := 0; i < count; i++ {
+		subscribers[i] = models.Subscriber{
+			ID:        primitive.NewObjectID(),
+			Email:     fmt.Sprintf("reader%d@example.com", i+1),
+			Status:    models.SubscriberPending,
+			Source:    "home",
+			CreatedAt: time.Now().Add(-time.Duration(i) * time.Hour),
+		}
+	}
+	return subscribers
+}
+
+func TestSubscribers_GeneratePage_WithSubscribersAndPagination(t *testing.T) {
+	mockSubscribers := createMockSubscribers(3)
+	mockSubscribers[0].Status = models.SubscriberActive
+	mockSubscribers[0].ConfirmedAt = time.Now()
+
+	html, err := NewSubscribers(mockSubscribers, 1, 3, 7).GeneratePage()
+	html = strings.Join(strings.Fields(html), " ")
+
+	assert.NoError(t, err)
+	assert.Contains(t, html, "<title>Subscribers</title>")
+	assert.Contains(t, html, "<h2>All Subscribers (7 total)</h2>")
+	assert.Contains(t, html, `<table class="subscribers-table">`)
+
+	for _, subscriber := range mockSubscribers {
+		assert.Contains(t, html, fmt.Sprintf("<td>%s</td>", subscriber.Email))
+		assert.Contains(t, html, fmt.Sprintf("<td>%s</td>", formatDateTime(subscriber.CreatedAt)))
+	}
+	assert.Contains(t, html, `<span class="status status-active">active</span>`)
+	assert.Contains(t, html, `<span class="status status-pending">pending</span>`)
+	assert.Contains(t, html, fmt.Sprintf("<td>%s</td>", formatDateTime(mockSubscribers[0].ConfirmedAt)))
+
+	assert.Contains(t, html, "Page 1 of 3")
+	assert.Contains(t, html, `hx-get="/subscribers/moderation?page=2"`)
+}
+
+func TestSubscribers_GeneratePage_NoSubscribers(t *testing.T) {
+	html, err := NewSubscribers(nil, 1, 10, 0).GeneratePage()
+
+	assert.NoError(t, err)
+	assert.Contains(t, html, "No subscribers yet")
+	assert.NotContains(t, html, `<table class="subscribers-table">`)
+	assert.NotContains(t, html, `<div class="pagination">`)
+	assert.NotContains(t, html, "Suppression List")
+}
+
+func TestSubscribers_GeneratePage_WithSuppressed(t *testing.T) {
+	mockSubscribers := createMockSubscribers(2)
+	suppressed := mockSubscribers[0]
+	suppressed.Status = models.SubscriberBounced
+	suppressed.SuppressedAt = time.Now()
+	suppressed.SuppressionReason = "550 5.1.1 user unknown"
+
+	html, err := NewSubscribers(mockSubscribers, 1, 10, 2).
+		WithSuppressed([]models.Subscriber{suppressed}, 4).
+		GeneratePage()
+	html = strings.Join(strings.Fields(html), " ")
+
+	assert.NoError(t, err)
+	assert.Contains(t, html, "<h2>Suppression List (4 total)</h2>")
+	assert.Contains(t, html, `<span class="status status-bounced">bounced</span>`)
+	assert.Contains(t, html, `<td class="reason">550 5.1.1 user unknown</td>`)
+	assert.Contains(t, html, fmt.Sprintf(`hx-post="/subscribers/%s/reactivate"`, suppressed.ID.Hex()))
+	assert.Contains(t, html, "<h2>All Subscribers (2 total)</h2>")
+}
+
+func formatDateTime(t time.Time) string {
+	return t.Format("January 2, 2006 at 3:04 PM")
+}
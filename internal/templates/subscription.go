@@ -0,0 +1,78 @@
+package templates
+
+import (
+	"newsteller/internal/antispam"
+)
+
+type MessageKind string
+
+const (
+	MessageSuccess MessageKind = "success"
+	MessageError   MessageKind = "error"
+)
+
+type message struct {
+	Kind  MessageKind
+	Title string
+	Text  string
+	// LinkURL and LinkText - optional follow-up action of standalone pages
+	LinkURL  string
+	LinkText string
+	// FormURL and FormText - optional form of standalone pages confirming their action
+	FormURL  string
+	FormText string
+}
+
+// SubscribeResponse is the fragment swapped into the home page subscribe form.
+type SubscribeResponse struct {
+	message
+	challenge antispam.Challenge
+}
+
+func NewSubscribeResponse(kind MessageKind, text string) *SubscribeResponse {
+	return &SubscribeResponse{message: message{Kind: kind, Text: text}}
+}
+
+// WithChallenge replaces the anti-spam fields of the form, the challenge of a submitted
+// form can not be used again.
+func (s *SubscribeResponse) WithChallenge(challenge antispam.Challenge) *SubscribeResponse {
+	s.challenge = challenge
+	return s
+}
+
+func (s *SubscribeResponse) GeneratePage() (string, error) {
+	return renderMessageWithChallenge(s.message, s.challenge)
+}
+
+// SubscriptionPage is the standalone page shown after following a confirmation or unsubscribe link.
+type SubscriptionPage struct {
+	message
+}
+
+func NewSubscriptionPage(kind MessageKind, title, text string) *SubscriptionPage {
+	return &SubscriptionPage{message{Kind: kind, Title: title, Text: text}}
+}
+
+// WithLink adds a follow-up link below the text.
+func (s *SubscriptionPage) WithLink(url, text string) *SubscriptionPage {
+	s.LinkURL = url
+	s.LinkText = text
+	return s
+}
+
+// WithForm adds a button posting to the URL below the text, so following a link
+// changes nothing until the reader confirms.
+func (s *SubscriptionPage) WithForm(url, text string) *SubscriptionPage {
+	s.FormURL = url
+	s.FormText = text
+	return s
+}
+
+func (s *SubscriptionPage) GeneratePage() (string, error) {
+	return render("subscription", s.message)
+}
+
+// renderMessage renders the message fragment of a form.
+func renderMessage(m message) (string, error) {
+	return renderMessageWithChallenge(m, antispam.Challenge{})
+}
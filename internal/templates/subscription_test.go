@@ -0,0 +1,58 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"newsteller/internal/antispam"
+)
+
+func TestSubscribeResponse_GeneratePage(t *testing.T) {
+	html, err := NewSubscribeResponse(MessageError, "Please enter a <valid> email address.").GeneratePage()
+
+	assert.NoError(t, err)
+	assert.Equal(t, `<div class="message error">Please enter a &lt;valid&gt; email address.</div>`, html)
+}
+
+func TestSubscribeResponse_WithChallenge(t *testing.T) {
+	challenge := antispam.Challenge{Form: antispam.FormSubscribe, Token: "abc.def", Difficulty: 16}
+	html, err := NewSubscribeResponse(MessageSuccess, "Please check your inbox.").WithChallenge(challenge).GeneratePage()
+
+	assert.NoError(t, err)
+	assert.Contains(t, html, `<div class="message success">Please check your inbox.</div>`)
+	assert.Contains(t, html, `<div id="antispam-subscribe" class="antispam" hx-swap-oob="true"`)
+	assert.Contains(t, html, `data-challenge="abc.def" data-difficulty="16"`)
+	assert.Contains(t, html, `<input type="text" name="website" tabindex="-1" autocomplete="off">`)
+}
+
+func TestSubscriptionPage_GeneratePage(t *testing.T) {
+	html, err := NewSubscriptionPage(MessageSuccess, "Subscription confirmed", "Thanks for subscribing!").GeneratePage()
+	html = strings.Join(strings.Fields(html), " ")
+
+	assert.NoError(t, err)
+	assert.Contains(t, html, "<title>Subscription confirmed</title>")
+	assert.Contains(t, html, `<div class="card success"> <h1>Subscription confirmed</h1> <p>Thanks for subscribing!</p>`)
+	assert.Contains(t, html, `<a href="/home" class="back-link">← Back to Home</a>`)
+}
+
+func TestSubscriptionPage_WithLink(t *testing.T) {
+	html, err := NewSubscriptionPage(MessageSuccess, "Unsubscribed", "Bye!").
+		WithLink("/subscribers/preferences?token=a&b", "Manage preferences").
+		GeneratePage()
+	html = strings.Join(strings.Fields(html), " ")
+
+	assert.NoError(t, err)
+	assert.Contains(t, html, `<p><a href="/subscribers/preferences?token=a&amp;b" class="back-link">Manage preferences</a></p>`)
+}
+
+func TestSubscriptionPage_WithForm(t *testing.T) {
+	html, err := NewSubscriptionPage(MessageSuccess, "Unsubscribe", "Stop receiving our newsletter?").
+		WithForm("/subscribers/unsubscribe?token=a&b", "Unsubscribe").
+		GeneratePage()
+	html = strings.Join(strings.Fields(html), " ")
+
+	assert.NoError(t, err)
+	assert.Contains(t, html, `<form method="post" action="/subscribers/unsubscribe?token=a&amp;b"> <button type="submit" class="btn">Unsubscribe</button> </form>`)
+}
@@ -0,0 +1,29 @@
+package templates
+
+import (
+	"newsteller/internal/models"
+)
+
+type Suggestions struct {
+	posts   []models.Post
+	queries []models.SearchQuery
+}
+
+type suggestionsData struct {
+	Posts   []models.Post
+	Queries []models.SearchQuery
+}
+
+func NewSuggestions(posts []models.Post, queries []models.SearchQuery) *Suggestions {
+	return &Suggestions{
+		posts:   posts,
+		queries: queries,
+	}
+}
+
+func (s *Suggestions) GeneratePage() (string, error) {
+	return render("suggestions", suggestionsData{
+		Posts:   s.posts,
+		Queries: s.queries,
+	})
+}
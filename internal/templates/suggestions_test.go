@@ -0,0 +1,45 @@
+package templates
+
+import (
+	"fmt"
+	"newsteller/internal/models"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuggestions_GeneratePage(t *testing.T) {
+	mockPosts := createMockPosts(2)
+	queries := []models.SearchQuery{{Query: "post title", Count: 3}, {Query: "post <b>", Count: 1}}
+
+	html, err := NewSuggestions(mockPosts, queries).GeneratePage()
+	html = strings.Join(strings.Fields(html), " ")
+
+	assert.NoError(t, err)
+	assert.Contains(t, html, `<ul class="suggestions-list" id="suggestions-list" role="listbox">`)
+	assert.Contains(t, html, `<li class="suggestions-group" role="presentation">Posts</li>`)
+	assert.Contains(t, html, `<li class="suggestions-group" role="presentation">Popular searches</li>`)
+
+	for _, post := range mockPosts {
+		assert.Contains(t, html, fmt.Sprintf(`data-href="/posts/%s"`, post.ID.Hex()), "Suggestion should link to post")
+		assert.Contains(t, html, fmt.Sprintf(`tabindex="-1">%s</a>`, post.Title), "Suggestion should show post title")
+	}
+	assert.Contains(t, html, `<li class="suggestion" role="option" data-query="post title">post title</li>`)
+	assert.Contains(t, html, `data-query="post &lt;b&gt;">post &lt;b&gt;</li>`, "Queries should be escaped")
+}
+
+func TestSuggestions_GeneratePage_OnlyPosts(t *testing.T) {
+	html, err := NewSuggestions(createMockPosts(1), nil).GeneratePage()
+
+	assert.NoError(t, err)
+	assert.Contains(t, html, "Posts</li>")
+	assert.NotContains(t, html, "Popular searches", "Queries group should be hidden when there are no queries")
+}
+
+func TestSuggestions_GeneratePage_Empty(t *testing.T) {
+	html, err := NewSuggestions(nil, nil).GeneratePage()
+
+	assert.NoError(t, err)
+	assert.Empty(t, strings.TrimSpace(html), "No dropdown should be rendered without suggestions")
+}
@@ -0,0 +1,149 @@
+package templates
+
+import (
+	"newsteller/internal/models"
+	"newsteller/internal/taxonomy"
+	"slices"
+	"strings"
+)
+
+type pickerData struct {
+	Categories []taxonomy.Node
+	KnownTags  []models.Tag
+}
+
+// listingData - the post-listing page of a tag or a category, pages are linked with ?page=N
+type listingData struct {
+	Heading     string
+	Description string
+	FeedURL     string
+	// Ancestors and Children - categories around the listed one, none for tags
+	Ancestors []models.Category
+	Children  []models.Category
+	Page      pageData
+}
+
+// TagPage lists the posts with a tag.
+type TagPage struct {
+	tag  string
+	page pageData
+}
+
+func NewTagPage(tag string, posts []models.Post, currentPage, totalPosts, postsPerPage int) *TagPage {
+	return &TagPage{tag: tag, page: newPageData(posts, currentPage, totalPosts, postsPerPage)}
+}
+
+func (p *TagPage) GeneratePage() (string, error) {
+	return render("post-listing", listingData{
+		Heading:     "Posts tagged " + p.tag,
+		Description: "Every post about " + p.tag + ", newest first.",
+		FeedURL:     tagPath(p.tag) + "/feed.xml",
+		Page:        p.page,
+	})
+}
+
+// CategoryPage lists the posts of a category and its subcategories.
+type CategoryPage struct {
+	category  *models.Category
+	ancestors []models.Category
+	children  []models.Category
+	page      pageData
+}
+
+func NewCategoryPage(
+	category *models.Category,
+	ancestors []models.Category,
+	children []models.Category,
+	posts []models.Post,
+	currentPage, totalPosts, postsPerPage int,
+) *CategoryPage {
+	return &CategoryPage{
+		category:  category,
+		ancestors: ancestors,
+		children:  children,
+		page:      newPageData(posts, currentPage, totalPosts, postsPerPage),
+	}
+}
+
+func (p *CategoryPage) GeneratePage() (string, error) {
+	return render("post-listing", listingData{
+		Heading:     p.category.Name,
+		Description: p.category.Description,
+		Ancestors:   p.ancestors,
+		Children:    p.children,
+		Page:        p.page,
+	})
+}
+
+// Categories is the category tree with the form adding a category.
+type Categories struct {
+	categories []taxonomy.Node
+}
+
+func NewCategories(categories []taxonomy.Node) *Categories {
+	return &Categories{categories: categories}
+}
+
+func (c *Categories) GeneratePage() (string, error) {
+	return render("categories", pickerData{Categories: c.categories})
+}
+
+// CategoryMessage is the fragment swapped into the forms of the categories page.
+type CategoryMessage struct {
+	message
+}
+
+func NewCategoryMessage(kind MessageKind, text string) *CategoryMessage {
+	return &CategoryMessage{message{Kind: kind, Text: text}}
+}
+
+func (m *CategoryMessage) GeneratePage() (string, error) {
+	return renderMessage(m.message)
+}
+
+// CloudTag is a tag of the tag cloud, Size grows from 1 to 5 with the number of posts.
+type CloudTag struct {
+	models.TagCount
+	Size int
+}
+
+// NewTagCloud sizes the tags between the least and the most used one, tags used equally
+// often get the middle size, and sorts them by name.
+func NewTagCloud(counts []models.TagCount) []CloudTag {
+	if len(counts) == 0 {
+		return nil
+	}
+
+	least, most := counts[0].Posts, counts[0].Posts
+	for _, count := range counts {
+		least, most = min(least, count.Posts), max(most, count.Posts)
+	}
+
+	cloud := make([]CloudTag, len(counts))
+	for i, count := range counts {
+		size := 3
+		if most > least {
+			size = 1 + 4*(count.Posts-least)/(most-least)
+		}
+		cloud[i] = CloudTag{TagCount: count, Size: size}
+	}
+	slices.SortFunc(cloud, func(a, b CloudTag) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return cloud
+}
+
+func newPageData(posts []models.Post, currentPage, totalPosts, postsPerPage int) pageData {
+	totalPages := max((totalPosts+postsPerPage-1)/postsPerPage, 1)
+
+	return pageData{
+		Posts:       posts,
+		CurrentPage: currentPage,
+		TotalPages:  totalPages,
+		HasPrev:     currentPage > 1,
+		HasNext:     currentPage < totalPages,
+		PrevPage:    currentPage - 1,
+		NextPage:    currentPage + 1,
+	}
+}
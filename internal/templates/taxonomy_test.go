@@ -0,0 +1,125 @@
+package templates
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"newsteller/internal/models"
+	"newsteller/internal/taxonomy"
+)
+
+func TestTagPage_GeneratePage(t *testing.T) {
+	posts := createMockPosts(2)
+
+	html, err := NewTagPage("machine learning", posts, 2, 7, 3).GeneratePage()
+	require.NoError(t, err)
+	html = strings.Join(strings.Fields(html), " ")
+
+	assert.Contains(t, html, "<title>Posts tagged machine learning</title>")
+	assert.Contains(t, html, `href="/tags/machine%20learning/feed.xml"`, "HTML should link the feed of the tag")
+	assert.Contains(t, html, fmt.Sprintf(`<a href="/posts/%s">`, posts[0].ID.Hex()))
+	assert.Contains(t, html, `<a href="?page=1">← Previous</a>`)
+	assert.Contains(t, html, "Page 2 of 3")
+	assert.Contains(t, html, `<a href="?page=3">Next →</a>`)
+	assert.NotContains(t, html, `class="breadcrumbs"`, "Tags have no breadcrumbs")
+}
+
+func TestCategoryPage_GeneratePage(t *testing.T) {
+	news := models.Category{ID: primitive.NewObjectID(), Name: "News", Slug: "news"}
+	europe := models.Category{ID: primitive.NewObjectID(), Name: "Europe", Slug: "europe", Description: "The <old> continent", ParentID: news.ID}
+	france := models.Category{ID: primitive.NewObjectID(), Name: "France", Slug: "france", ParentID: europe.ID}
+
+	t.Run("Positive: Breadcrumbs, subcategories and posts", func(t *testing.T) {
+		html, err := NewCategoryPage(&europe, []models.Category{news}, []models.Category{france}, createMockPosts(1), 1, 1, 10).GeneratePage()
+		require.NoError(t, err)
+		html = strings.Join(strings.Fields(html), " ")
+
+		assert.Contains(t, html, "<h1>Europe</h1>")
+		assert.Contains(t, html, "<p>The &lt;old&gt; continent</p>")
+		assert.Contains(t, html, `<a href="/categories">Categories</a> › <a href="/categories/news">News</a>`)
+		assert.Contains(t, html, `<div class="subcategories"> <a href="/categories/france">France</a> </div>`)
+		assert.NotContains(t, html, `class="pagination"`, "A single page should not be paginated")
+		assert.NotContains(t, html, "application/rss+xml", "Categories have no feed")
+	})
+
+	t.Run("Negative: Empty category", func(t *testing.T) {
+		html, err := NewCategoryPage(&news, nil, nil, nil, 1, 0, 10).GeneratePage()
+		require.NoError(t, err)
+
+		assert.Contains(t, html, "No posts yet.")
+		assert.NotContains(t, html, `class="breadcrumbs"`)
+	})
+}
+
+func TestCategories_GeneratePage(t *testing.T) {
+	news := models.Category{ID: primitive.NewObjectID(), Name: "News", Slug: "news"}
+	europe := models.Category{ID: primitive.NewObjectID(), Name: "Europe", Slug: "europe", ParentID: news.ID}
+
+	html, err := NewCategories(taxonomy.Tree([]models.Category{europe, news})).GeneratePage()
+	require.NoError(t, err)
+	html = strings.Join(strings.Fields(html), " ")
+
+	assert.Contains(t, html, `<form hx-post="/categories" hx-target="#category-message">`)
+	assert.Contains(t, html, fmt.Sprintf(`<option value="%s">— Europe</option>`, europe.ID.Hex()), "Nested categories should be indented")
+	assert.Contains(t, html, fmt.Sprintf(`hx-delete="/categories/%s"`, news.ID.Hex()))
+	assert.Contains(t, html, `<div style="padding-left: 1em;"> <a href="/categories/europe">Europe</a>`)
+}
+
+func TestTaxonomyPickers(t *testing.T) {
+	news := models.Category{ID: primitive.NewObjectID(), Name: "News", Slug: "news"}
+	sports := models.Category{ID: primitive.NewObjectID(), Name: "Sports", Slug: "sports"}
+	categories := taxonomy.Tree([]models.Category{news, sports})
+	tags := []models.Tag{{Name: "go"}, {Name: "databases"}}
+
+	t.Run("Positive: Create page selects no category", func(t *testing.T) {
+		html, err := NewCreatePage(tags, categories).GeneratePage()
+		require.NoError(t, err)
+		html = strings.Join(strings.Fields(html), " ")
+
+		assert.Contains(t, html, `<button type="button" class="tag-option" data-tag="go">go</button>`)
+		assert.Contains(t, html, `<select id="category" name="category"> <option value="">No category</option>`)
+		assert.NotContains(t, html, " selected>")
+	})
+
+	t.Run("Positive: Edit page selects the category of the post", func(t *testing.T) {
+		post := &models.Post{ID: primitive.NewObjectID(), Title: "Final", CategoryID: sports.ID}
+
+		html, err := NewEdit(post, tags, categories).GeneratePage()
+		require.NoError(t, err)
+		html = strings.Join(strings.Fields(html), " ")
+
+		assert.Contains(t, html, fmt.Sprintf(`<option value="%s" selected>Sports</option>`, sports.ID.Hex()))
+		assert.Contains(t, html, fmt.Sprintf(`<option value="%s">News</option>`, news.ID.Hex()))
+	})
+
+	t.Run("Negative: No tag picker without known tags", func(t *testing.T) {
+		html, err := NewCreatePage(nil, nil).GeneratePage()
+		require.NoError(t, err)
+
+		assert.NotContains(t, html, `class="tag-picker"`)
+	})
+}
+
+func TestNewTagCloud(t *testing.T) {
+	cloud := NewTagCloud([]models.TagCount{
+		{Name: "go", Posts: 9},
+		{Name: "web", Posts: 5},
+		{Name: "databases", Posts: 1},
+	})
+
+	require.Len(t, cloud, 3)
+	assert.Equal(t, "databases", cloud[0].Name, "Tags should be sorted by name")
+	assert.Equal(t, 1, cloud[0].Size)
+	assert.Equal(t, 5, cloud[1].Size)
+	assert.Equal(t, 3, cloud[2].Size)
+
+	assert.Empty(t, NewTagCloud(nil))
+
+	html, err := NewMain(nil, []models.TagCount{{Name: "c++", Posts: 2}}).GeneratePage()
+	require.NoError(t, err)
+	assert.Contains(t, html, `<a href="/tags/c&#43;&#43;" class="tag-size-3" title="2 posts">c&#43;&#43;</a>`, "Equally used tags should get the middle size")
+}
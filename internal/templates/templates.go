@@ -1,7 +1,13 @@
 package templates
 
 import (
+	"bytes"
+	"fmt"
 	"html/template"
+	"net/url"
+	"newsteller/internal/assets"
+	"newsteller/internal/images"
+	"newsteller/internal/theme"
 	"strings"
 	"time"
 )
@@ -30,4 +36,76 @@ var funcMap = template.FuncMap{
 	"formatDateTime": func(t time.Time) string {
 		return t.Format("January 2, 2006 at 3:04 PM")
 	},
+	"inc": func(i int) int {
+		return i + 1
+	},
+	"join": strings.Join,
+	// percent formats part of total, e.g. rates and bar widths
+	"percent": func(part, total int) string {
+		if total <= 0 {
+			return "0%"
+		}
+		return fmt.Sprintf("%.0f%%", float64(part)*100/float64(total))
+	},
+	// probability formats a probability between 0 and 1, e.g. spam scores
+	"probability": func(p float64) string {
+		return fmt.Sprintf("%.0f%%", p*100)
+	},
+	"tagPath": tagPath,
+	// postContent renders post bodies with their attachments
+	"postContent": renderContent,
+	"fileSize":    FileSize,
+	// imageSrc and srcset reference the resized variants of an uploaded image
+	"imageSrc": func(key string) string {
+		return images.Path(key, images.DefaultWidth)
+	},
+	"srcset":    images.Srcset,
+	"resizable": images.Resizable,
+	// indent prefixes nested categories in selects
+	"indent": func(depth int) string {
+		return strings.Repeat("— ", depth)
+	},
+	// asset and integrity reference the embedded static files
+	"asset":     assets.Path,
+	"integrity": assets.Integrity,
+	// theme is the branding of the pages, see SetTheme
+	"theme": func() *theme.Theme {
+		return currentTheme
+	},
+	// cspNonce marks the nonce attribute of inline scripts
+	"cspNonce": func() string {
+		return NoncePlaceholder
+	},
+}
+
+// currentTheme - branding of the pages, the built-in theme until SetTheme is called
+var currentTheme = theme.Default()
+
+// SetTheme brands the pages rendered from now on with the theme.
+func SetTheme(t *theme.Theme) {
+	currentTheme = t
+}
+
+// tagPath returns the path of the listing page of the tag.
+func tagPath(tag string) string {
+	return "/tags/" + url.PathEscape(tag)
+}
+
+// render executes the page of the layouts, see layouts.go.
+func render(page string, data any) (string, error) {
+	tmpl, err := layouts.page(page)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse templates: %w", err)
+	}
+
+	return execute(tmpl, data)
+}
+
+func execute(tmpl *template.Template, data any) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return buf.String(), nil
 }
@@ -0,0 +1,91 @@
+package templates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"newsteller/internal/assets"
+	"newsteller/internal/models"
+	"newsteller/internal/theme"
+)
+
+// setTheme brands the pages of the test, the built-in theme is restored after it.
+func setTheme(t *testing.T, th *theme.Theme) {
+	t.Helper()
+	previous := currentTheme
+	t.Cleanup(func() { currentTheme = previous })
+
+	SetTheme(th)
+}
+
+func TestTheme_BuiltIn(t *testing.T) {
+	html, err := NewMain([]models.Post{}, nil).GeneratePage()
+	require.NoError(t, err)
+
+	assert.Contains(t, html, `<meta name="theme-mode" content="auto">`)
+	assert.Contains(t, html, `<link rel="stylesheet" href="/static/theme.`)
+	assert.Contains(t, html, `<script src="/static/theme.`)
+	assert.NotContains(t, html, "/static/brand.", "the built-in palettes are not changed")
+	assert.Contains(t, html, `<header class="site-brand">
+    <a href="/home">Newsteller</a>
+</header>`)
+	assert.Contains(t, html, `<button type="button" class="theme-toggle" data-theme-toggle>`)
+}
+
+func TestTheme_Branding(t *testing.T) {
+	assets.Add("brand.css", []byte(":root { --primary: #e63946; }"))
+	setTheme(t, &theme.Theme{
+		SiteName:    "Acme News",
+		Logo:        "/static/theme-logo.svg",
+		Mode:        theme.ModeDark,
+		FooterLinks: []theme.Link{{Label: "Imprint", URL: "/imprint"}},
+		Stylesheet:  "brand.css",
+	})
+	brandPath, err := assets.Path("brand.css")
+	require.NoError(t, err)
+
+	pages := map[string]Template{
+		"layout":       NewCreatePage(nil, nil),
+		"standalone":   NewIssues(nil),
+		"error page":   NewErrorPage("Not found", "The page does not exist."),
+		"tag":          NewTagPage("go", nil, 1, 0, 10),
+		"subscription": NewSubscriptionPage(MessageSuccess, "Subscribed", "Thanks."),
+	}
+	for name, page := range pages {
+		t.Run(name, func(t *testing.T) {
+			html, err := page.GeneratePage()
+			require.NoError(t, err)
+
+			assert.Contains(t, html, `<meta name="theme-mode" content="dark">`)
+			assert.Contains(t, html, `<link rel="stylesheet" href="`+brandPath+`"`)
+			assert.Contains(t, html, `<a href="/home"><img class="site-logo" src="/static/theme-logo.svg" alt="">Acme News</a>`)
+			assert.Contains(t, html, `<a href="/imprint">Imprint</a>`)
+		})
+	}
+}
+
+func TestTheme_DefaultTitle(t *testing.T) {
+	setTheme(t, &theme.Theme{SiteName: "Acme News", Mode: theme.ModeAuto})
+
+	html, err := renderPage("untitled", `{{template "base" .}}`, nil)
+	require.NoError(t, err)
+	assert.Contains(t, html, "<title>Acme News</title>", "pages without a title of their own are titled by the site name")
+}
+
+func TestTheme_FooterLinksEscaped(t *testing.T) {
+	setTheme(t, &theme.Theme{
+		SiteName:    "Newsteller",
+		Mode:        theme.ModeAuto,
+		FooterLinks: []theme.Link{{Label: "<b>Imprint</b>", URL: "javascript:alert(1)"}},
+	})
+
+	html, err := NewList(nil, 1, 0, 10).GeneratePage()
+	require.NoError(t, err)
+	assert.NotContains(t, html, "site-footer", "fragments are not branded")
+
+	html, err = NewMain([]models.Post{}, nil).GeneratePage()
+	require.NoError(t, err)
+	assert.Contains(t, html, `<a href="#ZgotmplZ">&lt;b&gt;Imprint&lt;/b&gt;</a>`)
+}
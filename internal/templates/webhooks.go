@@ -0,0 +1,60 @@
+package templates
+
+import (
+	"newsteller/internal/models"
+)
+
+type webhooksData struct {
+	// Webhook - webhook the "events" block shows the filter of, none for new webhooks
+	Webhook    *models.Webhook
+	Webhooks   []models.Webhook
+	Deliveries []models.WebhookDelivery
+	Events     []models.WebhookEvent
+}
+
+type Webhooks struct {
+	webhooks []models.Webhook
+}
+
+// NewWebhooks renders the list of webhooks with the form adding one.
+func NewWebhooks(webhooks []models.Webhook) *Webhooks {
+	return &Webhooks{webhooks: webhooks}
+}
+
+func (w *Webhooks) GeneratePage() (string, error) {
+	return render("webhooks", webhooksData{
+		Webhooks: w.webhooks,
+		Events:   models.WebhookEvents,
+	})
+}
+
+type WebhookPage struct {
+	webhook    *models.Webhook
+	deliveries []models.WebhookDelivery
+}
+
+// NewWebhookPage renders the settings of the webhook and its recent deliveries.
+func NewWebhookPage(webhook *models.Webhook, deliveries []models.WebhookDelivery) *WebhookPage {
+	return &WebhookPage{webhook: webhook, deliveries: deliveries}
+}
+
+func (p *WebhookPage) GeneratePage() (string, error) {
+	return render("webhook", webhooksData{
+		Webhook:    p.webhook,
+		Deliveries: p.deliveries,
+		Events:     models.WebhookEvents,
+	})
+}
+
+// WebhookMessage is the fragment swapped into the forms of the webhook pages.
+type WebhookMessage struct {
+	message
+}
+
+func NewWebhookMessage(kind MessageKind, text string) *WebhookMessage {
+	return &WebhookMessage{message{Kind: kind, Text: text}}
+}
+
+func (m *WebhookMessage) GeneratePage() (string, error) {
+	return renderMessage(m.message)
+}
@@ -0,0 +1,82 @@
+package templates
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"newsteller/internal/models"
+)
+
+func TestWebhooks_GeneratePage(t *testing.T) {
+	all := models.Webhook{ID: primitive.NewObjectID(), URL: "https://example.com/all", Active: true}
+	filtered := models.Webhook{
+		ID:          primitive.NewObjectID(),
+		URL:         "https://example.com/deleted",
+		Description: "Cleans <the> cache",
+		Events:      []models.WebhookEvent{models.WebhookPostCreated, models.WebhookPostDeleted},
+	}
+
+	html, err := NewWebhooks([]models.Webhook{all, filtered}).GeneratePage()
+	require.NoError(t, err)
+	html = strings.Join(strings.Fields(html), " ")
+
+	assert.Contains(t, html, "<title>Webhooks</title>")
+	assert.Contains(t, html, `<form hx-post="/webhooks" hx-target="#webhook-message">`)
+	assert.Contains(t, html, `<input type="checkbox" name="events" value="post.published" >`, "New webhooks should have no events checked")
+	assert.Contains(t, html, fmt.Sprintf(`<a href="/webhooks/%s">https://example.com/all</a>`, all.ID.Hex()))
+	assert.Contains(t, html, "<td>All events</td>")
+	assert.Contains(t, html, "<td>post.created, post.deleted</td>")
+	assert.Contains(t, html, "Cleans &lt;the&gt; cache")
+	assert.Contains(t, html, `<span class="status status-disabled">disabled</span>`)
+}
+
+func TestWebhooks_GeneratePage_Empty(t *testing.T) {
+	html, err := NewWebhooks(nil).GeneratePage()
+	require.NoError(t, err)
+
+	assert.Contains(t, html, "No webhooks yet.")
+	assert.NotContains(t, html, "<table>")
+}
+
+func TestWebhookPage_GeneratePage(t *testing.T) {
+	webhook := &models.Webhook{
+		ID:        primitive.NewObjectID(),
+		URL:       "https://example.com/hook",
+		Secret:    "s3cr3t",
+		Events:    []models.WebhookEvent{models.WebhookPostUpdated},
+		Active:    true,
+		CreatedAt: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC),
+	}
+	delivery := models.WebhookDelivery{
+		ID:        primitive.NewObjectID(),
+		Event:     models.WebhookPostUpdated,
+		Payload:   `{"event":"post.updated"}`,
+		Status:    models.WebhookDeliveryFailed,
+		Attempts:  8,
+		LastError: "unexpected response status 500",
+		Response:  models.WebhookResponse{Status: 500, Body: "<oops>"},
+		CreatedAt: time.Date(2025, 3, 2, 10, 30, 0, 0, time.UTC),
+	}
+
+	html, err := NewWebhookPage(webhook, []models.WebhookDelivery{delivery}).GeneratePage()
+	require.NoError(t, err)
+	html = strings.Join(strings.Fields(html), " ")
+
+	assert.Contains(t, html, "<title>https://example.com/hook - Webhook</title>")
+	assert.Contains(t, html, fmt.Sprintf(`<form hx-put="/webhooks/%s" hx-target="#settings-message">`, webhook.ID.Hex()))
+	assert.Contains(t, html, `<input type="checkbox" name="events" value="post.updated" checked>`)
+	assert.Contains(t, html, `<input type="checkbox" name="events" value="post.created" >`)
+	assert.Contains(t, html, `<input type="checkbox" name="active" value="true" checked>`)
+	assert.Contains(t, html, "<code>s3cr3t</code>")
+	assert.Contains(t, html, "<td>March 2, 2025 at 10:30 AM</td>")
+	assert.Contains(t, html, `<span class="status status-failed">failed</span>`)
+	assert.Contains(t, html, "500 <br>unexpected response status 500")
+	assert.Contains(t, html, "<pre>&lt;oops&gt;</pre>")
+	assert.Contains(t, html, fmt.Sprintf(`hx-post="/webhooks/deliveries/%s/redeliver"`, delivery.ID.Hex()))
+	assert.Contains(t, html, fmt.Sprintf(`hx-delete="/webhooks/%s"`, webhook.ID.Hex()))
+}
@@ -0,0 +1,209 @@
+package theme
+
+import (
+	"fmt"
+	"newsteller/internal/assets"
+	"newsteller/internal/config"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+/**
+Branding of the pages: site name, logo, font, colours and footer links, configured by the THEME_*
+variables or a theme directory. Pages are styled with the CSS variables of the built-in light
+and dark palettes of theme.css; a theme replaces some of them, adds styles of its own and serves
+its files with the embedded assets. Readers pick a palette with the toggle in the footer, kept in
+the theme cookie by theme.js, so cached pages stay the same for everyone.
+*/
+
+const (
+	ModeAuto  = "auto"
+	ModeLight = "light"
+	ModeDark  = "dark"
+)
+
+// stylesheet - asset of the styles generated for the theme
+const stylesheet = "brand.css"
+
+// customCSS - styles of the theme directory added to the generated ones
+const customCSS = "theme.css"
+
+// Colors - names of the palette colours themes may replace, CSS variables of theme.css
+var Colors = []string{"background", "surface", "surface-alt", "text", "text-muted", "border", "primary", "primary-hover"}
+
+// cssValue - colours and fonts must not end their declaration, e.g. #e63946, rgb(0 0 0) or 'Inter', sans-serif,
+// colours are separated by commas, so they are written without
+var cssValue = regexp.MustCompile(`^[\w #%(),.'"+/-]+$`)
+
+// Link is a link in the footer of pages.
+type Link struct {
+	Label string
+	URL   string
+}
+
+// Theme is the branding applied to every page.
+type Theme struct {
+	SiteName string
+	// Logo - URL of the logo, none when empty
+	Logo string
+	// Mode - palette of readers without a preference of their own
+	Mode        string
+	FooterLinks []Link
+	// Stylesheet - asset of the styles of the theme, none when the built-in palettes are used as they are
+	Stylesheet string
+}
+
+// Default returns the built-in theme.
+func Default() *Theme {
+	return &Theme{SiteName: "Newsteller", Mode: ModeAuto}
+}
+
+// Load creates the theme of the configuration and adds its files to the assets.
+func Load(cfg *config.Config) (*Theme, error) {
+	c := cfg.Theme
+	t := &Theme{SiteName: c.SiteName, Mode: c.Mode}
+	if t.SiteName == "" {
+		t.SiteName = cfg.SiteName
+	}
+	if t.Mode == "" {
+		t.Mode = ModeAuto
+	}
+	if !slices.Contains([]string{ModeAuto, ModeLight, ModeDark}, t.Mode) {
+		return nil, fmt.Errorf("unknown theme mode: %q", t.Mode)
+	}
+
+	var err error
+	if t.FooterLinks, err = parseLinks(c.FooterLinks); err != nil {
+		return nil, err
+	}
+	if t.Logo, err = logo(c.Dir, c.Logo); err != nil {
+		return nil, err
+	}
+
+	css, err := styles(c.Dir, c.Font, c.Colors, c.DarkColors)
+	if err != nil {
+		return nil, err
+	}
+	if css != "" {
+		assets.Add(stylesheet, []byte(css))
+		t.Stylesheet = stylesheet
+	}
+
+	return t, nil
+}
+
+// logo returns the URL of the logo, files of the theme directory are served as assets.
+func logo(dir, logo string) (string, error) {
+	if logo == "" || strings.HasPrefix(logo, "/") || strings.HasPrefix(logo, "https://") || strings.HasPrefix(logo, "http://") {
+		return logo, nil
+	}
+	if dir == "" {
+		return "", fmt.Errorf("theme logo %q is not a URL and there is no theme directory", logo)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, filepath.Clean("/"+logo)))
+	if err != nil {
+		return "", fmt.Errorf("failed to read theme logo: %w", err)
+	}
+	name := "theme-" + filepath.Base(logo)
+	assets.Add(name, data)
+
+	return assets.Path(name)
+}
+
+// styles returns the styles replacing the font and colours of the built-in palettes followed
+// by those of the theme directory, none when the theme changes nothing.
+func styles(dir, font, colors, darkColors string) (string, error) {
+	light, err := parseColors(colors)
+	if err != nil {
+		return "", err
+	}
+	dark, err := parseColors(darkColors)
+	if err != nil {
+		return "", err
+	}
+	if font != "" {
+		if !cssValue.MatchString(font) {
+			return "", fmt.Errorf("invalid theme font: %q", font)
+		}
+		light = append([]string{"--font: " + font}, light...)
+	}
+
+	var b strings.Builder
+	// the same selectors as theme.css, loaded after it
+	writeRule(&b, ":root", light)
+	writeRule(&b, `:root[data-theme="dark"]`, dark)
+	if len(dark) > 0 {
+		b.WriteString("@media (prefers-color-scheme: dark) {\n")
+		writeRule(&b, `:root:not([data-theme="light"])`, dark)
+		b.WriteString("}\n")
+	}
+
+	if dir != "" {
+		custom, err := os.ReadFile(filepath.Join(dir, customCSS))
+		if err != nil && !os.IsNotExist(err) {
+			return "", fmt.Errorf("failed to read theme styles: %w", err)
+		}
+		b.Write(custom)
+	}
+
+	return b.String(), nil
+}
+
+func writeRule(b *strings.Builder, selector string, declarations []string) {
+	if len(declarations) == 0 {
+		return
+	}
+	b.WriteString(selector + " {\n")
+	for _, d := range declarations {
+		b.WriteString("    " + d + ";\n")
+	}
+	b.WriteString("}\n")
+}
+
+// parseColors parses comma separated name=value colours into CSS declarations.
+func parseColors(s string) ([]string, error) {
+	var declarations []string
+	for _, pair := range split(s) {
+		name, value, _ := strings.Cut(pair, "=")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !slices.Contains(Colors, name) {
+			return nil, fmt.Errorf("unknown theme colour: %q", name)
+		}
+		if value == "" || !cssValue.MatchString(value) {
+			return nil, fmt.Errorf("invalid theme colour %s: %q", name, value)
+		}
+		declarations = append(declarations, "--"+name+": "+value)
+	}
+
+	return declarations, nil
+}
+
+// parseLinks parses comma separated label=URL links.
+func parseLinks(s string) ([]Link, error) {
+	var links []Link
+	for _, pair := range split(s) {
+		label, url, ok := strings.Cut(pair, "=")
+		label, url = strings.TrimSpace(label), strings.TrimSpace(url)
+		if !ok || label == "" || url == "" {
+			return nil, fmt.Errorf("invalid theme footer link: %q", pair)
+		}
+		links = append(links, Link{Label: label, URL: url})
+	}
+
+	return links, nil
+}
+
+func split(s string) []string {
+	var parts []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return parts
+}
@@ -0,0 +1,118 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"newsteller/internal/assets"
+	"newsteller/internal/config"
+)
+
+func newConfig() *config.Config {
+	cfg := &config.Config{SiteName: "Newsteller"}
+	cfg.Theme.Mode = ModeAuto
+	return cfg
+}
+
+// stylesheetOf returns the content of the stylesheet asset of the theme.
+func stylesheetOf(t *testing.T, theme *Theme) string {
+	t.Helper()
+	path, err := assets.Path(theme.Stylesheet)
+	require.NoError(t, err)
+	asset, _, ok := assets.Lookup(strings.TrimPrefix(path, assets.Prefix))
+	require.True(t, ok)
+
+	return string(asset.Data)
+}
+
+func TestLoad_BuiltIn(t *testing.T) {
+	theme, err := Load(newConfig())
+	require.NoError(t, err)
+
+	assert.Equal(t, &Theme{SiteName: "Newsteller", Mode: ModeAuto}, theme)
+}
+
+func TestLoad_Branding(t *testing.T) {
+	cfg := newConfig()
+	cfg.Theme.SiteName = "Acme News"
+	cfg.Theme.Mode = ModeDark
+	cfg.Theme.Logo = "https://cdn.example.com/logo.svg"
+	cfg.Theme.Font = "'Inter', sans-serif"
+	cfg.Theme.Colors = "primary=#e63946, background = rgb(250 250 245)"
+	cfg.Theme.DarkColors = "primary=#ff8a94"
+	cfg.Theme.FooterLinks = "Imprint=/imprint, GitHub=https://github.com/acme?tab=repositories"
+
+	theme, err := Load(cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Acme News", theme.SiteName)
+	assert.Equal(t, ModeDark, theme.Mode)
+	assert.Equal(t, "https://cdn.example.com/logo.svg", theme.Logo, "URLs are used as they are")
+	assert.Equal(t, []Link{
+		{Label: "Imprint", URL: "/imprint"},
+		{Label: "GitHub", URL: "https://github.com/acme?tab=repositories"},
+	}, theme.FooterLinks)
+	assert.Equal(t, `:root {
+    --font: 'Inter', sans-serif;
+    --primary: #e63946;
+    --background: rgb(250 250 245);
+}
+:root[data-theme="dark"] {
+    --primary: #ff8a94;
+}
+@media (prefers-color-scheme: dark) {
+:root:not([data-theme="light"]) {
+    --primary: #ff8a94;
+}
+}
+`, stylesheetOf(t, theme))
+}
+
+func TestLoad_Directory(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "logo.svg"), []byte("<svg></svg>"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "theme.css"), []byte(".site-brand { font-size: 2em; }\n"), 0o644))
+	cfg := newConfig()
+	cfg.Theme.Dir = dir
+	cfg.Theme.Logo = "logo.svg"
+
+	theme, err := Load(cfg)
+	require.NoError(t, err)
+
+	assert.Regexp(t, `^/static/theme-logo\.[0-9a-f]{12}\.svg$`, theme.Logo, "files of the theme are served as assets")
+	assert.Equal(t, ".site-brand { font-size: 2em; }\n", stylesheetOf(t, theme))
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(cfg *config.Config)
+		err    string
+	}{
+		{"unknown mode", func(cfg *config.Config) { cfg.Theme.Mode = "sepia" }, `unknown theme mode: "sepia"`},
+		{"unknown colour", func(cfg *config.Config) { cfg.Theme.Colors = "link=#fff" }, `unknown theme colour: "link"`},
+		{"colour ending the declaration", func(cfg *config.Config) { cfg.Theme.Colors = "primary=red; } body { display: none" }, "invalid theme colour primary"},
+		{"empty colour", func(cfg *config.Config) { cfg.Theme.DarkColors = "text=" }, "invalid theme colour text"},
+		{"font ending the declaration", func(cfg *config.Config) { cfg.Theme.Font = "serif}" }, "invalid theme font"},
+		{"link without URL", func(cfg *config.Config) { cfg.Theme.FooterLinks = "Imprint" }, `invalid theme footer link: "Imprint"`},
+		{"logo file without directory", func(cfg *config.Config) { cfg.Theme.Logo = "logo.svg" }, "there is no theme directory"},
+		{"missing logo file", func(cfg *config.Config) {
+			cfg.Theme.Dir = t.TempDir()
+			cfg.Theme.Logo = "../logo.svg"
+		}, "failed to read theme logo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newConfig()
+			tt.change(cfg)
+
+			_, err := Load(cfg)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
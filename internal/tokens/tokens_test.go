@@ -0,0 +1,71 @@
+package tokens
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_SignAndVerify(t *testing.T) {
+	signer := NewSigner("secret")
+
+	t.Run("Positive: Valid token returns subject", func(t *testing.T) {
+		token := signer.Sign(PurposeConfirmSubscription, "subscriber-id", time.Now().Add(time.Hour))
+		subject, err := signer.Verify(PurposeConfirmSubscription, token)
+		require.NoError(t, err)
+		assert.Equal(t, "subscriber-id", subject)
+	})
+
+	t.Run("Positive: Token without expiry", func(t *testing.T) {
+		token := signer.Sign(PurposeUnsubscribe, "subscriber-id", time.Time{})
+		subject, err := signer.Verify(PurposeUnsubscribe, token)
+		require.NoError(t, err)
+		assert.Equal(t, "subscriber-id", subject)
+	})
+
+	t.Run("Positive: Subject containing the separator", func(t *testing.T) {
+		token := signer.Sign(PurposeTrackClick, "https://example.com/?a=1|2", time.Time{})
+		subject, err := signer.Verify(PurposeTrackClick, token)
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/?a=1|2", subject)
+	})
+
+	t.Run("Negative: Expired token", func(t *testing.T) {
+		token := signer.Sign(PurposeConfirmSubscription, "subscriber-id", time.Now().Add(-time.Minute))
+		_, err := signer.Verify(PurposeConfirmSubscription, token)
+		assert.ErrorIs(t, err, ErrExpiredToken)
+	})
+
+	t.Run("Negative: Token issued for another purpose", func(t *testing.T) {
+		token := signer.Sign(PurposeConfirmSubscription, "subscriber-id", time.Time{})
+		_, err := signer.Verify(PurposeUnsubscribe, token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Negative: Token signed with another secret", func(t *testing.T) {
+		token := NewSigner("other").Sign(PurposeUnsubscribe, "subscriber-id", time.Time{})
+		_, err := signer.Verify(PurposeUnsubscribe, token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Negative: Tampered or malformed token", func(t *testing.T) {
+		_, signature, _ := strings.Cut(signer.Sign(PurposeUnsubscribe, "subscriber-id", time.Time{}), ".")
+		forgedPayload, _, _ := strings.Cut(signer.Sign(PurposeUnsubscribe, "another-id", time.Time{}), ".")
+		_, err := signer.Verify(PurposeUnsubscribe, forgedPayload+"."+signature)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+
+		for _, malformed := range []string{"", "no-dot", ".", "%%%.sig"} {
+			_, err = signer.Verify(PurposeUnsubscribe, malformed)
+			assert.ErrorIs(t, err, ErrInvalidToken, malformed)
+		}
+	})
+}
+
+func TestNewSigner_RandomSecret(t *testing.T) {
+	token := NewSigner("").Sign(PurposeUnsubscribe, "subscriber-id", time.Time{})
+	_, err := NewSigner("").Verify(PurposeUnsubscribe, token)
+	assert.ErrorIs(t, err, ErrInvalidToken, "Random secrets should differ between signers")
+}
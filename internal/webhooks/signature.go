@@ -0,0 +1,48 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// Headers of every delivery
+const (
+	HeaderEvent    = "X-Newsteller-Event"
+	HeaderDelivery = "X-Newsteller-Delivery"
+	// HeaderTimestamp - unix seconds the delivery was signed at, part of the signed content
+	HeaderTimestamp = "X-Newsteller-Timestamp"
+	HeaderSignature = "X-Newsteller-Signature"
+)
+
+const signaturePrefix = "sha256="
+
+// Sign returns the signature of the body sent at the timestamp, "sha256=" followed by
+// the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the secret of the webhook.
+// Signing the timestamp lets endpoints reject replayed deliveries.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether the signature matches, the check endpoints are expected to do.
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// NewSecret generates the signing key of a new webhook.
+func NewSecret() (string, error) {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(key), nil
+}
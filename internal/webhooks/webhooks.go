@@ -0,0 +1,151 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"newsteller/internal/config"
+	"newsteller/internal/events"
+	"newsteller/internal/models"
+	"newsteller/internal/repositories"
+	"time"
+)
+
+/**
+Webhooks notify external endpoints about changes of posts. Events are never sent from
+request handlers directly, the Dispatcher subscribes to post events and queues a delivery
+for every endpoint subscribed to the event and the Worker sends them in background, retrying failed deliveries with
+exponential backoff, so endpoints being down never slow down or fail editing posts.
+*/
+
+// Payload is the JSON body of every delivery.
+type Payload struct {
+	// ID - identifier of the event, the same for redeliveries
+	ID        string              `json:"id"`
+	Event     models.WebhookEvent `json:"event"`
+	CreatedAt time.Time           `json:"created_at"`
+	Post      PostPayload         `json:"post"`
+}
+
+// PostPayload is the post the event is about, as it was when the event happened.
+type PostPayload struct {
+	ID        string    `json:"id"`
+	Slug      string    `json:"slug,omitempty"`
+	URL       string    `json:"url"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	Tags      []string  `json:"tags"`
+	Author    string    `json:"author,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewPayload(cfg *config.Config, id string, event models.WebhookEvent, post *models.Post, at time.Time) Payload {
+	tags := post.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return Payload{
+		ID:        id,
+		Event:     event,
+		CreatedAt: at.UTC(),
+		Post: PostPayload{
+			ID:        post.ID.Hex(),
+			Slug:      post.Slug,
+			URL:       cfg.BaseURL + post.Path(),
+			Title:     post.Title,
+			Content:   post.Content,
+			Tags:      tags,
+			Author:    post.Author,
+			CreatedAt: post.CreatedAt.UTC(),
+			UpdatedAt: post.UpdatedAt.UTC(),
+		},
+	}
+}
+
+// Dispatcher queues events for the subscribed endpoints.
+type Dispatcher struct {
+	cfg        *config.Config
+	webhooks   *repositories.Webhook
+	deliveries *repositories.WebhookDelivery
+}
+
+func NewDispatcher(cfg *config.Config, webhooks *mongo.Collection, deliveries *mongo.Collection) *Dispatcher {
+	return &Dispatcher{
+		cfg:        cfg,
+		webhooks:   repositories.NewWebhookRepository(webhooks),
+		deliveries: repositories.NewWebhookDeliveryRepository(deliveries),
+	}
+}
+
+// Subscribe queues deliveries of the post events.
+func (d *Dispatcher) Subscribe(bus *events.Bus) {
+	events.Subscribe(bus, "webhooks", func(ctx context.Context, event events.PostCreated) error {
+		return d.Emit(ctx, event.Meta(), models.WebhookPostCreated, &event.Post)
+	})
+	events.Subscribe(bus, "webhooks", func(ctx context.Context, event events.PostUpdated) error {
+		return d.Emit(ctx, event.Meta(), models.WebhookPostUpdated, &event.Post)
+	})
+	events.Subscribe(bus, "webhooks", func(ctx context.Context, event events.PostPublished) error {
+		return d.Emit(ctx, event.Meta(), models.WebhookPostPublished, &event.Post)
+	})
+	events.Subscribe(bus, "webhooks", func(ctx context.Context, event events.PostDeleted) error {
+		return d.Emit(ctx, event.Meta(), models.WebhookPostDeleted, &event.Post)
+	})
+}
+
+// Emit queues the event about the post for every endpoint subscribed to it. Emitting
+// the same event again queues deliveries only for the endpoints it failed for.
+func (d *Dispatcher) Emit(ctx context.Context, meta events.Metadata, event models.WebhookEvent, post *models.Post) error {
+	webhooks, err := d.webhooks.FindActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(NewPayload(d.cfg, meta.ID, event, post, meta.OccurredAt))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var errs []error
+	for i := range webhooks {
+		if !webhooks[i].Accepts(event) {
+			continue
+		}
+
+		_, err = d.deliveries.Create(ctx, &models.WebhookDelivery{
+			WebhookID:     webhooks[i].ID,
+			EventID:       meta.ID,
+			DedupeKey:     meta.ID + ":" + webhooks[i].ID.Hex(),
+			Event:         event,
+			Payload:       string(payload),
+			Status:        models.WebhookDeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Redeliver queues the payload of a previous delivery again, as a new delivery of the same event.
+func (d *Dispatcher) Redeliver(ctx context.Context, delivery *models.WebhookDelivery) (*primitive.ObjectID, error) {
+	now := time.Now()
+
+	return d.deliveries.Create(ctx, &models.WebhookDelivery{
+		WebhookID:     delivery.WebhookID,
+		EventID:       delivery.EventID,
+		Event:         delivery.Event,
+		Payload:       delivery.Payload,
+		Status:        models.WebhookDeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	})
+}
@@ -0,0 +1,74 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"newsteller/internal/config"
+	"newsteller/internal/models"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"event":"post.created"}`)
+
+	signature := Sign("secret", 1700000000, body)
+	assert.Equal(t, "sha256=ce7ebc251a37a25867cae2a4ed02967911662d8d668255c48239a28f21c35edc", signature)
+	assert.True(t, Verify("secret", 1700000000, body, signature))
+	assert.False(t, Verify("other", 1700000000, body, signature), "Signature should depend on the secret")
+	assert.False(t, Verify("secret", 1700000001, body, signature), "Signature should depend on the timestamp")
+	assert.False(t, Verify("secret", 1700000000, []byte(`{}`), signature), "Signature should depend on the body")
+}
+
+func TestNewSecret(t *testing.T) {
+	first, err := NewSecret()
+	require.NoError(t, err)
+	second, err := NewSecret()
+	require.NoError(t, err)
+
+	assert.Len(t, first, 64)
+	assert.NotEqual(t, first, second)
+}
+
+func TestNewPayload(t *testing.T) {
+	cfg := &config.Config{BaseURL: "https://blog.example.com"}
+	created := time.Date(2025, 3, 1, 9, 0, 0, 0, time.FixedZone("CET", 3600))
+	post := &models.Post{
+		ID:        primitive.NewObjectID(),
+		Slug:      "hello-world",
+		Title:     "Hello, World",
+		Content:   "First post",
+		Author:    "Jane",
+		CreatedAt: created,
+		UpdatedAt: created,
+	}
+
+	data, err := json.Marshal(NewPayload(cfg, "event-1", models.WebhookPostCreated, post, created))
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"id": "event-1",
+		"event": "post.created",
+		"created_at": "2025-03-01T08:00:00Z",
+		"post": {
+			"id": "`+post.ID.Hex()+`",
+			"slug": "hello-world",
+			"url": "https://blog.example.com/posts/hello-world",
+			"title": "Hello, World",
+			"content": "First post",
+			"tags": [],
+			"author": "Jane",
+			"created_at": "2025-03-01T08:00:00Z",
+			"updated_at": "2025-03-01T08:00:00Z"
+		}
+	}`, string(data))
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, RetryDelay(30*time.Second, 1))
+	assert.Equal(t, 4*time.Minute, RetryDelay(30*time.Second, 4))
+	assert.Equal(t, maxRetryDelay, RetryDelay(30*time.Second, 50), "Delay should be capped")
+}
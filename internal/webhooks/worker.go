@@ -0,0 +1,237 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"newsteller/internal/config"
+	"newsteller/internal/models"
+	"newsteller/internal/repositories"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// maxRetryDelay - upper bound of the exponential backoff
+	maxRetryDelay = 12 * time.Hour
+	// maxResponseBody - bytes of endpoint responses kept in the delivery log
+	maxResponseBody = 1024
+	userAgent       = "Newsteller-Webhooks/1.0"
+)
+
+// ErrPrivateAddress - the endpoint resolved to an address of the internal network
+var ErrPrivateAddress = errors.New("webhook endpoint address is not public")
+
+// Worker sends queued deliveries to their endpoints.
+type Worker struct {
+	cfg        *config.Config
+	webhooks   *repositories.Webhook
+	deliveries *repositories.WebhookDelivery
+	client     *http.Client
+}
+
+func NewWorker(cfg *config.Config, webhooks *mongo.Collection, deliveries *mongo.Collection) *Worker {
+	return &Worker{
+		cfg:        cfg,
+		webhooks:   repositories.NewWebhookRepository(webhooks),
+		deliveries: repositories.NewWebhookDeliveryRepository(deliveries),
+		client:     newClient(cfg.Webhooks.AllowPrivate),
+	}
+}
+
+// newClient returns the client of deliveries, redirects are reported as failures
+// instead of being followed, so payloads never end up at unexpected endpoints.
+// Anybody may register a webhook and read the responses in the delivery log, so unless
+// allowPrivate is set, the client only connects to public addresses. They are checked
+// once resolved, so names resolving to internal addresses later are refused too.
+func newClient(allowPrivate bool) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !allowPrivate {
+		// a proxy would be the address checked, not the endpoint
+		transport.Proxy = nil
+		transport.DialContext = (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Control: func(_, address string, _ syscall.RawConn) error {
+				addrPort, err := netip.ParseAddrPort(address)
+				if err != nil {
+					return err
+				}
+				if !isPublic(addrPort.Addr()) {
+					return ErrPrivateAddress
+				}
+				return nil
+			},
+		}).DialContext
+	}
+
+	return &http.Client{
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// isPublic reports whether the address is outside loopback, private, link-local and
+// unspecified ranges, like the cloud metadata address 169.254.169.254.
+func isPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return !addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsUnspecified()
+}
+
+// Run sends due deliveries every poll interval until the context is cancelled.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.Webhooks.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		w.deliverDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Worker) deliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		delivery, err := w.deliveries.ClaimDue(ctx, time.Now(), 2*w.cfg.Webhooks.Timeout)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return
+		}
+		if err != nil {
+			zap.L().Error("could not claim webhook delivery", zap.Error(err))
+			return
+		}
+
+		w.deliver(ctx, delivery)
+	}
+}
+
+func (w *Worker) deliver(ctx context.Context, delivery *models.WebhookDelivery) {
+	attempts := delivery.Attempts + 1
+	webhook, err := w.webhooks.FindByID(ctx, delivery.WebhookID.Hex())
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		w.markFailed(ctx, delivery, attempts, models.WebhookResponse{}, "webhook was deleted")
+		return
+	}
+	if err != nil {
+		// the lease expires and the delivery is claimed again
+		zap.L().Error("could not find webhook of delivery", zap.String("id", delivery.ID.Hex()), zap.Error(err))
+		return
+	}
+	if !webhook.Active {
+		w.markFailed(ctx, delivery, attempts, models.WebhookResponse{}, "webhook is disabled")
+		return
+	}
+
+	response, err := w.send(ctx, webhook, delivery)
+	if err == nil {
+		err = w.deliveries.MarkDelivered(ctx, delivery.ID, attempts, response, time.Now())
+		if err != nil {
+			zap.L().Error("could not mark webhook delivery as delivered", zap.String("id", delivery.ID.Hex()), zap.Error(err))
+		}
+		return
+	}
+
+	zap.L().Warn(
+		"could not deliver webhook",
+		zap.String("id", delivery.ID.Hex()),
+		zap.String("url", webhook.URL),
+		zap.Int("attempt", attempts),
+		zap.Error(err),
+	)
+	if attempts >= w.cfg.Webhooks.MaxAttempts {
+		w.markFailed(ctx, delivery, attempts, response, err.Error())
+		return
+	}
+	nextAttemptAt := time.Now().Add(RetryDelay(w.cfg.Webhooks.RetryDelay, attempts))
+	err = w.deliveries.MarkRetry(ctx, delivery.ID, attempts, response, nextAttemptAt, err.Error())
+	if err != nil {
+		zap.L().Error("could not reschedule webhook delivery", zap.String("id", delivery.ID.Hex()), zap.Error(err))
+	}
+}
+
+func (w *Worker) markFailed(
+	ctx context.Context,
+	delivery *models.WebhookDelivery,
+	attempts int,
+	response models.WebhookResponse,
+	reason string,
+) {
+	err := w.deliveries.MarkFailed(ctx, delivery.ID, attempts, response, reason)
+	if err != nil {
+		zap.L().Error("could not mark webhook delivery as failed", zap.String("id", delivery.ID.Hex()), zap.Error(err))
+	}
+}
+
+// send posts the signed payload, any answer but 2xx is an error.
+func (w *Worker) send(
+	ctx context.Context,
+	webhook *models.Webhook,
+	delivery *models.WebhookDelivery,
+) (models.WebhookResponse, error) {
+	sendCtx, cancel := context.WithTimeout(ctx, w.cfg.Webhooks.Timeout)
+	defer cancel()
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(sendCtx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return models.WebhookResponse{}, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderEvent, string(delivery.Event))
+	req.Header.Set(HeaderDelivery, delivery.ID.Hex())
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, body))
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return models.WebhookResponse{}, err
+	}
+	defer res.Body.Close()
+
+	responseBody, _ := io.ReadAll(io.LimitReader(res.Body, maxResponseBody))
+	response := models.WebhookResponse{
+		Status: res.StatusCode,
+		Body:   strings.ToValidUTF8(string(responseBody), ""),
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return response, fmt.Errorf("unexpected response status %d", res.StatusCode)
+	}
+
+	return response, nil
+}
+
+// RetryDelay returns the delay before the next attempt, doubling the base delay
+// after every failed attempt.
+func RetryDelay(base time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+
+	return delay
+}
@@ -0,0 +1,130 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"newsteller/internal/config"
+	"newsteller/internal/models"
+)
+
+func newTestWorker() *Worker {
+	cfg := &config.Config{}
+	cfg.Webhooks.Timeout = time.Second
+
+	// test servers listen on loopback
+	return &Worker{cfg: cfg, client: newClient(true)}
+}
+
+func TestWorker_Send(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	webhook := &models.Webhook{URL: server.URL, Secret: "secret", Active: true}
+	delivery := &models.WebhookDelivery{
+		ID:      primitive.NewObjectID(),
+		Event:   models.WebhookPostUpdated,
+		Payload: `{"event":"post.updated"}`,
+	}
+
+	response, err := newTestWorker().send(context.Background(), webhook, delivery)
+	require.NoError(t, err)
+	assert.Equal(t, models.WebhookResponse{Status: http.StatusOK, Body: "ok"}, response)
+
+	require.NotNil(t, received)
+	assert.Equal(t, http.MethodPost, received.Method)
+	assert.Equal(t, "application/json", received.Header.Get("Content-Type"))
+	assert.Equal(t, "post.updated", received.Header.Get(HeaderEvent))
+	assert.Equal(t, delivery.ID.Hex(), received.Header.Get(HeaderDelivery))
+	assert.Equal(t, delivery.Payload, string(body))
+
+	timestamp, err := strconv.ParseInt(received.Header.Get(HeaderTimestamp), 10, 64)
+	require.NoError(t, err)
+	assert.True(t, Verify("secret", timestamp, body, received.Header.Get(HeaderSignature)))
+}
+
+func TestWorker_Send_Failures(t *testing.T) {
+	webhook := &models.Webhook{Secret: "secret", Active: true}
+	delivery := &models.WebhookDelivery{ID: primitive.NewObjectID(), Payload: "{}"}
+
+	t.Run("Negative: Error status keeps the response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(strings.Repeat("x", 2*maxResponseBody)))
+		}))
+		defer server.Close()
+		webhook.URL = server.URL
+
+		response, err := newTestWorker().send(context.Background(), webhook, delivery)
+		assert.EqualError(t, err, "unexpected response status 503")
+		assert.Equal(t, http.StatusServiceUnavailable, response.Status)
+		assert.Len(t, response.Body, maxResponseBody, "Response body should be cut")
+	})
+
+	t.Run("Negative: Redirects are not followed", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "https://example.com/elsewhere", http.StatusFound)
+		}))
+		defer server.Close()
+		webhook.URL = server.URL
+
+		response, err := newTestWorker().send(context.Background(), webhook, delivery)
+		assert.EqualError(t, err, "unexpected response status 302")
+		assert.Equal(t, http.StatusFound, response.Status)
+	})
+
+	t.Run("Negative: Private addresses are refused", func(t *testing.T) {
+		requested := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested = true
+		}))
+		defer server.Close()
+		webhook.URL = server.URL
+
+		worker := newTestWorker()
+		worker.client = newClient(false)
+		_, err := worker.send(context.Background(), webhook, delivery)
+		assert.ErrorIs(t, err, ErrPrivateAddress)
+		assert.False(t, requested)
+	})
+
+	t.Run("Negative: Slow endpoints time out", func(t *testing.T) {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer server.Close()
+		defer close(release)
+		webhook.URL = server.URL
+
+		worker := newTestWorker()
+		worker.cfg.Webhooks.Timeout = 50 * time.Millisecond
+		_, err := worker.send(context.Background(), webhook, delivery)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestIsPublic(t *testing.T) {
+	for _, addr := range []string{"127.0.0.1", "::1", "10.1.2.3", "192.168.0.10", "169.254.169.254", "fe80::1", "0.0.0.0", "::ffff:127.0.0.1"} {
+		assert.False(t, isPublic(netip.MustParseAddr(addr)), addr)
+	}
+	for _, addr := range []string{"93.184.216.34", "2606:2800:220:1::1"} {
+		assert.True(t, isPublic(netip.MustParseAddr(addr)), addr)
+	}
+}